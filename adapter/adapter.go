@@ -23,7 +23,11 @@ import (
 // WebFrameWork is a interface which is used as an adapter of
 // framework and goAdmin. It must implement two methods. Use registers
 // the routes and the corresponding handlers. Content writes the
-// response to the corresponding context of framework.
+// response to the corresponding context of framework. The handlers added
+// by AddHandler should be called with the ResponseWriter of the framework
+// set by context.Context.SetResponseWriter, so that the streams are
+// written directly, and the Response should not be written if the
+// context.Context.Written return true.
 type WebFrameWork interface {
 	Use(interface{}, []plugins.Plugin) error
 	Content(interface{}, types.GetPanelFn)
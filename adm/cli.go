@@ -0,0 +1,170 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Command adm is the GoAdmin cli tool, which generates the table models
+// from an existing database:
+//
+//	adm tables -c config.json
+//	adm generate -c config.json -tables users,posts -package tables -output ./tables
+//
+// The database is read from the config file of the engine, see config.Load.
+// The sql drivers are not registered by default, build the tool with the
+// tags of the drivers, such as:
+//
+//	go build -tags "mysql postgresql sqlite mssql" ./adm
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/system"
+	"github.com/glvd/go-admin/modules/tools"
+	"github.com/mgutz/ansi"
+	"os"
+	"strings"
+)
+
+const usage = `GoAdmin CLI %s
+
+Usage:
+
+	adm <command> [options]
+
+Commands:
+
+	tables      list the tables of the database
+	generate    generate the table models of the tables
+	version     display the version
+
+Run "adm <command> -h" for the options of the command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Printf(usage, system.Version())
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "tables":
+		err = listTables(os.Args[2:])
+	case "generate":
+		err = generate(os.Args[2:])
+	case "version", "-V", "--version":
+		fmt.Println("GoAdmin CLI " + system.Version())
+	case "help", "-h", "--help":
+		fmt.Printf(usage, system.Version())
+	default:
+		fmt.Printf(usage, system.Version())
+		os.Exit(2)
+	}
+
+	if err != nil {
+		exitWithError(err.Error())
+	}
+}
+
+type connectionFlags struct {
+	config     *string
+	connection *string
+}
+
+func addConnectionFlags(set *flag.FlagSet) connectionFlags {
+	return connectionFlags{
+		config:     set.String("c", "", "the config file of the engine, json, yaml or ini"),
+		connection: set.String("connection", "default", "the name of the connection"),
+	}
+}
+
+// open load the config and connect the database of the connection.
+func (f connectionFlags) open() (conn db.Connection, err error) {
+	if *f.config == "" {
+		return nil, errors.New("the config file is required, see -c")
+	}
+
+	cfg, err := config.Load(*f.config)
+	if err != nil {
+		return nil, err
+	}
+
+	database, ok := cfg.Databases[*f.connection]
+	if !ok {
+		return nil, fmt.Errorf("connection %s is not found in %s", *f.connection, *f.config)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("connect database: %v", r)
+		}
+	}()
+
+	driver := database.Driver
+	return db.GetConnectionByDriver(driver).InitDB(cfg.Databases.GroupByDriver()[driver]), nil
+}
+
+func listTables(args []string) error {
+	set := flag.NewFlagSet("tables", flag.ExitOnError)
+	flags := addConnectionFlags(set)
+	_ = set.Parse(args)
+
+	conn, err := flags.open()
+	if err != nil {
+		return err
+	}
+
+	tables, err := tools.Tables(conn, *flags.connection)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		fmt.Println(table)
+	}
+	return nil
+}
+
+func generate(args []string) error {
+	set := flag.NewFlagSet("generate", flag.ExitOnError)
+	flags := addConnectionFlags(set)
+	var (
+		tables = set.String("tables", "", "the tables to generate separated by commas, default all")
+		pkg    = set.String("package", "main", "the package name of the generated files")
+		output = set.String("output", ".", "the directory the files are written into")
+	)
+	_ = set.Parse(args)
+
+	conn, err := flags.open()
+	if err != nil {
+		return err
+	}
+
+	param := tools.Param{
+		Conn:       conn,
+		Connection: *flags.connection,
+		Package:    *pkg,
+		Output:     *output,
+	}
+	for _, table := range strings.Split(*tables, ",") {
+		if table = strings.TrimSpace(table); table != "" {
+			param.Tables = append(param.Tables, table)
+		}
+	}
+
+	if err := tools.Generate(param); err != nil {
+		return err
+	}
+
+	fmt.Println(ansi.Color("✔", "green") + " generate success, the generators are registered in " + *output + "/tables.go")
+	return nil
+}
+
+func exitWithError(msg string) {
+	fmt.Println()
+	fmt.Println(ansi.Color("go-admin cli error: "+msg, "red"))
+	fmt.Println()
+	os.Exit(1)
+}
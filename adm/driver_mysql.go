@@ -0,0 +1,10 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+//go:build mysql
+// +build mysql
+
+package main
+
+import _ "github.com/go-sql-driver/mysql"
@@ -0,0 +1,372 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxMultipartMemory is the max memory used to parse the multipart form,
+// the rest of the files are stored in temporary files.
+var MaxMultipartMemory int64 = 32 << 20
+
+// MaxJSONBodySize is the max size of the JSON request body read by
+// BindJSON.
+var MaxJSONBodySize int64 = 10 << 20
+
+// BindJSON decode the JSON request body into the struct pointed by v and
+// validate it. The body larger than MaxJSONBodySize is refused. See
+// Validate for the validation rules.
+func (ctx *Context) BindJSON(v interface{}) error {
+	if ctx.Request.Body == nil {
+		return errors.New("empty request body")
+	}
+	body := http.MaxBytesReader(ctx.writer, ctx.Request.Body, MaxJSONBodySize)
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return err
+	}
+	return Validate(v)
+}
+
+// BindForm decode the query and the form values into the struct pointed
+// by v and validate it. The field name is got from the "form" tag, or the
+// lower case field name if the tag is missing, and "-" skips the field.
+// The supported field types are string, bool, ints, uints, floats,
+// time.Duration and the slices of them. See Validate for the validation
+// rules.
+func (ctx *Context) BindForm(v interface{}) error {
+	if strings.HasPrefix(ctx.Headers("Content-Type"), "multipart/form-data") {
+		if err := ctx.Request.ParseMultipartForm(MaxMultipartMemory); err != nil {
+			return err
+		}
+	} else if err := ctx.Request.ParseForm(); err != nil {
+		return err
+	}
+	if err := bindValues(v, ctx.Request.Form); err != nil {
+		return err
+	}
+	return Validate(v)
+}
+
+func bindValues(v interface{}, values url.Values) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("bind: v must be a pointer to struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindValues(rv.Field(i).Addr().Interface(), values); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := fieldName(field, "form")
+		if name == "-" {
+			continue
+		}
+
+		list, ok := values[name]
+		if !ok || len(list) == 0 {
+			continue
+		}
+
+		if err := setField(rv.Field(i), list); err != nil {
+			return fmt.Errorf("bind: field %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+func fieldName(field reflect.StructField, key string) string {
+	name := strings.Split(field.Tag.Get(key), ",")[0]
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+func setField(field reflect.Value, list []string) error {
+	if field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(list), len(list))
+		for i, value := range list {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, list[0])
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		if value == "" {
+			field.SetBool(false)
+			return nil
+		}
+		if value == "on" {
+			field.SetBool(true)
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value == "" {
+			value = "0"
+		}
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value == "" {
+			value = "0"
+		}
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		if value == "" {
+			value = "0"
+		}
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// FieldError is a validation error of a field.
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+}
+
+// Error implements the error.
+func (e FieldError) Error() string {
+	if e.Param == "" {
+		return fmt.Sprintf("%s: failed on the %s rule", e.Field, e.Rule)
+	}
+	return fmt.Sprintf("%s: failed on the %s=%s rule", e.Field, e.Rule, e.Param)
+}
+
+// ValidationErrors is the validation errors of the fields.
+type ValidationErrors []FieldError
+
+// Error implements the error.
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+var emailReg = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// Validate validate the struct pointed by v according to the "validate"
+// tags, and return ValidationErrors if any rule failed. The rules are
+// separated by commas:
+//
+//	required      the value is not the zero value
+//	min=n, max=n  the length of a string or slice, or the number is in range
+//	len=n         the length of a string or slice is n
+//	oneof=a b c   the value is one of the space separated values
+//	email         the string is an email address
+//
+// The rules except required are skipped for the zero values. The field
+// name in the errors is got from the "json" tag, or the "form" tag, or the
+// lower case field name. An unknown rule or a wrong parameter of the rule
+// is returned as an error other than ValidationErrors.
+func Validate(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs, err := validateStruct(rv, make(ValidationErrors, 0))
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(rv reflect.Value, errs ValidationErrors) (ValidationErrors, error) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		value := rv.Field(i)
+
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			var err error
+			if errs, err = validateStruct(value, errs); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		tag := field.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			name = fieldName(field, "form")
+		}
+
+		for value.Kind() == reflect.Ptr && !value.IsNil() {
+			value = value.Elem()
+		}
+
+		for _, rule := range strings.Split(tag, ",") {
+			rule = strings.TrimSpace(rule)
+			param := ""
+			if i := strings.IndexByte(rule, '='); i != -1 {
+				rule, param = rule[:i], rule[i+1:]
+			}
+			ok, err := checkRule(value, rule, param)
+			if err != nil {
+				return nil, fmt.Errorf("validate: field %s: %s", name, err)
+			}
+			if !ok {
+				errs = append(errs, FieldError{Field: name, Rule: rule, Param: param})
+				break
+			}
+		}
+	}
+	return errs, nil
+}
+
+func checkRule(value reflect.Value, rule, param string) (bool, error) {
+	zero := isZero(value)
+
+	switch rule {
+	case "required":
+		return !zero, nil
+	case "min", "max", "len":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false, errors.New("wrong parameter of rule " + rule + ": " + param)
+		}
+		if zero {
+			return true, nil
+		}
+		var size float64
+		switch value.Kind() {
+		case reflect.String:
+			size = float64(len([]rune(value.String())))
+		case reflect.Slice, reflect.Map, reflect.Array:
+			size = float64(value.Len())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			size = float64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			size = float64(value.Uint())
+		case reflect.Float32, reflect.Float64:
+			size = value.Float()
+		default:
+			return true, nil
+		}
+		switch rule {
+		case "min":
+			return size >= n, nil
+		case "max":
+			return size <= n, nil
+		default:
+			return size == n, nil
+		}
+	case "oneof":
+		if zero {
+			return true, nil
+		}
+		s := fmt.Sprintf("%v", value.Interface())
+		for _, option := range strings.Fields(param) {
+			if s == option {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "email":
+		return zero || (value.Kind() == reflect.String && emailReg.MatchString(value.String())), nil
+	}
+
+	return false, errors.New("unknown rule " + rule)
+}
+
+func isZero(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return value.Len() == 0
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
@@ -0,0 +1,80 @@
+package context
+
+import (
+	"github.com/magiconair/properties/assert"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindUser struct {
+	Name    string        `json:"name" form:"name" validate:"required,max=8"`
+	Age     int           `json:"age" validate:"min=18"`
+	Email   string        `json:"email" form:"mail" validate:"email"`
+	Role    string        `json:"role" validate:"oneof=admin operator"`
+	Tags    []string      `json:"tags" form:"tag" validate:"max=2"`
+	Timeout time.Duration `json:"timeout"`
+	Active  bool          `json:"active"`
+	Ignored string        `form:"-"`
+}
+
+func TestBindJSON(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"jack","age":20,"role":"admin"}`))
+	var user bindUser
+	assert.Equal(t, NewContext(req).BindJSON(&user), nil)
+	assert.Equal(t, user.Name, "jack")
+	assert.Equal(t, user.Age, 20)
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`{"age":10,"email":"jack","role":"guest"}`))
+	err := NewContext(req).BindJSON(&bindUser{})
+	errs, ok := err.(ValidationErrors)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, len(errs), 4)
+	assert.Equal(t, errs[0].Field, "name")
+	assert.Equal(t, errs[0].Rule, "required")
+	assert.Equal(t, errs[1].Error(), "age: failed on the min=18 rule")
+	assert.Equal(t, errs[2].Rule, "email")
+	assert.Equal(t, errs[3].Rule, "oneof")
+
+	defer func(size int64) {
+		MaxJSONBodySize = size
+	}(MaxJSONBodySize)
+	MaxJSONBodySize = 16
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"jack","age":20,"role":"admin"}`))
+	assert.Equal(t, NewContext(req).BindJSON(&bindUser{}) != nil, true)
+}
+
+func TestValidate(t *testing.T) {
+	err := Validate(&struct {
+		Name string `validate:"size=2"`
+	}{})
+	_, ok := err.(ValidationErrors)
+	assert.Equal(t, err != nil, true)
+	assert.Equal(t, ok, false)
+
+	err = Validate(&struct {
+		Name string `validate:"max=x"`
+	}{Name: "a"})
+	assert.Equal(t, err.Error(), "validate: field name: wrong parameter of rule max: x")
+}
+
+func TestBindForm(t *testing.T) {
+	req := httptest.NewRequest("POST", "/?age=30",
+		strings.NewReader("name=rose&mail=rose%40example.com&tag=a&tag=b&timeout=1m&active=on&ignored=x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var user bindUser
+	assert.Equal(t, NewContext(req).BindForm(&user), nil)
+	assert.Equal(t, user.Name, "rose")
+	assert.Equal(t, user.Age, 30)
+	assert.Equal(t, user.Email, "rose@example.com")
+	assert.Equal(t, user.Tags, []string{"a", "b"})
+	assert.Equal(t, user.Timeout, time.Minute)
+	assert.Equal(t, user.Active, true)
+	assert.Equal(t, user.Ignored, "")
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader("name=rose&age=old"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Equal(t, NewContext(req).BindForm(&bindUser{}) != nil, true)
+}
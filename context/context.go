@@ -7,6 +7,7 @@ package context
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"math"
 	"net"
@@ -29,6 +30,12 @@ type Context struct {
 	UserValue map[string]interface{}
 	index     int8
 	handlers  Handlers
+	route     string
+	params    Params
+	writer    http.ResponseWriter
+	written   bool
+
+	trustedProxies TrustedProxies
 }
 
 // Path is used in the matching of request and response. Url stores the
@@ -44,6 +51,25 @@ func (ctx *Context) SetUserValue(key string, value interface{}) {
 	ctx.UserValue[key] = value
 }
 
+// Route return the registered route which the request matched, such as
+// "/admin/info/:__prefix". It is empty before the route handlers run.
+func (ctx *Context) Route() string {
+	return ctx.route
+}
+
+// Param return the value of the route param of the given name. For the
+// route "/user/:id", ctx.Param("id") of "/user/1" is "1", and for the
+// catch-all route "/assets/*path", ctx.Param("path") of "/assets/js/a.js"
+// is "/js/a.js".
+func (ctx *Context) Param(name string) string {
+	return ctx.params.ByName(name)
+}
+
+// Params return all the route params.
+func (ctx *Context) Params() Params {
+	return ctx.params
+}
+
 // Path return the url path.
 func (ctx *Context) Path() string {
 	return ctx.Request.URL.Path
@@ -97,9 +123,9 @@ func (ctx *Context) Write(code int, Header map[string]string, Body string) {
 	ctx.Response.Body = ioutil.NopCloser(strings.NewReader(Body))
 }
 
-// JSON serializes the given struct as JSON into the response body.
+// JSON serializes the given value as JSON into the response body.
 // It also sets the Content-Type as "application/json".
-func (ctx *Context) JSON(code int, Body map[string]interface{}) {
+func (ctx *Context) JSON(code int, Body interface{}) {
 	ctx.Response.StatusCode = code
 	ctx.AddHeader("Content-Type", "application/json")
 	BodyStr, err := json.Marshal(Body)
@@ -145,24 +171,118 @@ func (ctx *Context) SetContentType(contentType string) {
 	ctx.AddHeader("Content-Type", contentType)
 }
 
-// LocalIP return the request client ip.
+// LocalIP return the request client ip. The X-Forwarded-For and X-Real-Ip
+// headers are respected only when the request comes from a trusted proxy,
+// and the right-most address of X-Forwarded-For which is not a trusted
+// proxy is returned. Without the trusted proxies, the headers are ignored
+// and the remote address is returned, so the proxies in front of the server
+// must be listed, see TrustProxies.
 func (ctx *Context) LocalIP() string {
-	xForwardedFor := ctx.Request.Header.Get("X-Forwarded-For")
-	ip := strings.TrimSpace(strings.Split(xForwardedFor, ",")[0])
-	if ip != "" {
-		return ip
+	remoteIP := ctx.remoteIP()
+	if remoteIP == "" {
+		return "127.0.0.1"
 	}
 
-	ip = strings.TrimSpace(ctx.Request.Header.Get("X-Real-Ip"))
-	if ip != "" {
+	if !ctx.trustedProxies.Contains(remoteIP) {
+		return remoteIP
+	}
+	forwarded := strings.Split(ctx.Request.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwarded[i])
+		if ip != "" && !ctx.trustedProxies.Contains(ip) {
+			return ip
+		}
+	}
+	if ip := strings.TrimSpace(forwarded[0]); ip != "" {
 		return ip
 	}
+	if ip := strings.TrimSpace(ctx.Request.Header.Get("X-Real-Ip")); ip != "" {
+		return ip
+	}
+	return remoteIP
+}
 
+// IsHTTPS return true if the request is served over TLS. The
+// X-Forwarded-Proto header is respected only when the request comes from a
+// trusted proxy, see LocalIP.
+func (ctx *Context) IsHTTPS() bool {
+	if ctx.Request.TLS != nil {
+		return true
+	}
+	if !ctx.trustedProxies.Contains(ctx.remoteIP()) {
+		return false
+	}
+	proto := strings.Split(ctx.Request.Header.Get("X-Forwarded-Proto"), ",")[0]
+	return strings.EqualFold(strings.TrimSpace(proto), "https")
+}
+
+// remoteIP return the ip of the remote address of the request, which is
+// empty if the address is invalid.
+func (ctx *Context) remoteIP() string {
 	if ip, _, err := net.SplitHostPort(strings.TrimSpace(ctx.Request.RemoteAddr)); err == nil {
 		return ip
 	}
+	return ""
+}
+
+// TrustedProxies is the networks of the trusted proxies.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies return the trusted proxies of the ips or the CIDRs.
+func ParseTrustedProxies(proxies ...string) (TrustedProxies, error) {
+	list := make(TrustedProxies, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, errors.New("invalid trusted proxy: " + proxy)
+			}
+			if ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.New("invalid trusted proxy: " + proxy)
+		}
+		list = append(list, ipNet)
+	}
+	return list, nil
+}
+
+// Contains return true if the ip is of a trusted proxy.
+func (proxies TrustedProxies) Contains(s string) bool {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range proxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
 
-	return "127.0.0.1"
+// SetTrustedProxies set the trusted proxies used by LocalIP.
+func (ctx *Context) SetTrustedProxies(proxies TrustedProxies) {
+	ctx.trustedProxies = proxies
+}
+
+// TrustProxies return the middleware setting the trusted proxies of the
+// requests, which is used by the App of each engine so that the engines
+// keep their own proxies.
+func TrustProxies(proxies TrustedProxies) Handler {
+	return func(ctx *Context) {
+		ctx.SetTrustedProxies(proxies)
+		ctx.Next()
+	}
 }
 
 // SetCookie save the given cookie obj into the response Set-Cookie header.
@@ -212,6 +332,17 @@ func (ctx *Context) SetHeader(key, value string) {
 	ctx.Response.Header.Set(key, value)
 }
 
+// RequestIDKey is the key of the request id in the UserValue.
+const RequestIDKey = "request_id"
+
+// RequestID return the request id set by the request id middleware.
+func (ctx *Context) RequestID() string {
+	if id, ok := ctx.UserValue[RequestIDKey].(string); ok {
+		return id
+	}
+	return ""
+}
+
 // User return the current login user.
 func (ctx *Context) User() interface{} {
 	return ctx.UserValue["user"]
@@ -225,6 +356,7 @@ type App struct {
 	tree        *node
 	Middlewares Handlers
 	Prefix      string
+	conflicts   []error
 }
 
 // NewApp return an empty app.
@@ -244,22 +376,38 @@ type Handler func(ctx *Context)
 type Handlers []Handler
 
 // AppendReqAndResp stores the request info and handle into app.
-// support the route parameter. The route parameter will be recognized as
-// wildcard store into the RegUrl of Path struct. For example:
+// support the route parameters. The named params and the catch-all are
+// recognized, which values can be got by Context.Param. For example:
 //
-//         /user/:id      => /user/(.*)
-//         /user/:id/info => /user/(.*?)/info
+//	/user/:id          => /user/1               id = "1"
+//	/user/:id/info     => /user/1/info          id = "1"
+//	/assets/*filepath  => /assets/js/info.js    filepath = "/js/info.js"
 //
-// The RegUrl will be used to recognize the incoming path and find
-// the handler.
+// When a path matches several routes, the static segments take precedence
+// over the params, which take precedence over the catch-all. The invalid
+// and conflicting routes are ignored and can be got by Conflicts.
 func (app *App) AppendReqAndResp(url, method string, handler []Handler) {
+	app.appendRoute(join(app.Prefix, slash(url)), method, app.Middlewares, handler)
+}
+
+func (app *App) appendRoute(route, method string, middlewares Handlers, handler []Handler) {
+
+	if err := app.tree.addPath(stringToArr(route), method,
+		routeHandlers(route, middlewares, handler)); err != nil {
+		app.conflicts = append(app.conflicts, err)
+		return
+	}
 
 	app.Requests = append(app.Requests, Path{
-		URL:    join(app.Prefix, url),
+		URL:    route,
 		Method: method,
 	})
+}
 
-	app.tree.addPath(stringToArr(join(app.Prefix, slash(url))), method, append(app.Middlewares, handler...))
+// Conflicts return the errors of the invalid and conflicting routes. It
+// should be checked after the routes registered.
+func (app *App) Conflicts() []error {
+	return app.conflicts
 }
 
 // Find is public helper method for findPath of tree.
@@ -308,6 +456,13 @@ func (app *App) ANY(url string, handler ...Handler) {
 	app.AppendReqAndResp(url, "head", handler)
 }
 
+// Use add the middlewares to the App, which are used by the routes and the
+// groups added after.
+func (app *App) Use(middleware ...Handler) *App {
+	app.Middlewares = append(app.Middlewares, middleware...)
+	return app
+}
+
 // Group add middlewares and prefix for App.
 func (app *App) Group(prefix string, middleware ...Handler) *RouterGroup {
 	return &RouterGroup{
@@ -325,22 +480,9 @@ type RouterGroup struct {
 }
 
 // AppendReqAndResp stores the request info and handle into app.
-// support the route parameter. The route parameter will be recognized as
-// wildcard store into the RegUrl of Path struct. For example:
-//
-//         /user/:id      => /user/(.*)
-//         /user/:id/info => /user/(.*?)/info
-//
-// The RegUrl will be used to recognize the incoming path and find
-// the handler.
+// See App.AppendReqAndResp for the route parameters.
 func (g *RouterGroup) AppendReqAndResp(url, method string, handler []Handler) {
-
-	g.app.Requests = append(g.app.Requests, Path{
-		URL:    join(g.Prefix, url),
-		Method: method,
-	})
-
-	g.app.tree.addPath(stringToArr(join(g.Prefix, slash(url))), method, append(g.Middlewares, handler...))
+	g.app.appendRoute(join(g.Prefix, slash(url)), method, g.Middlewares, handler)
 }
 
 // POST is a shortcut for app.AppendReqAndResp(url, "post", handler).
@@ -393,6 +535,19 @@ func (g *RouterGroup) Group(prefix string, middleware ...Handler) *RouterGroup {
 	}
 }
 
+// routeHandlers return the handlers of a route, which begin with a handler
+// recording the route and the params into the Context.
+func routeHandlers(route string, middlewares Handlers, handler []Handler) Handlers {
+	handlers := make(Handlers, 0, len(middlewares)+len(handler)+1)
+	handlers = append(handlers, func(ctx *Context) {
+		ctx.route = route
+		ctx.params = parseParams(route, ctx.Path())
+		ctx.Next()
+	})
+	handlers = append(handlers, middlewares...)
+	return append(handlers, handler...)
+}
+
 // slash fix the path which has wrong format problem.
 //
 // 	 ""      => "/"
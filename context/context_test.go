@@ -1,7 +1,9 @@
 package context
 
 import (
+	"crypto/tls"
 	"github.com/magiconair/properties/assert"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -38,3 +40,112 @@ func TestTree(t *testing.T) {
 	assert.Equal(t, tree.findPath(stringToArr("/admin/me/new"), "POST") == nil, true)
 	tree.printChildren()
 }
+
+func TestTreePrecedence(t *testing.T) {
+	tree := tree()
+	assert.Equal(t, tree.addPath(stringToArr("/admin/*path"), "get", []Handler{func(ctx *Context) {}}), nil)
+	assert.Equal(t, tree.addPath(stringToArr("/admin/info/:__prefix"), "get", []Handler{func(ctx *Context) {}}), nil)
+	assert.Equal(t, tree.addPath(stringToArr("/admin/info/menu"), "get", []Handler{func(ctx *Context) {}}), nil)
+	assert.Equal(t, tree.addPath(stringToArr("/admin/info/:id/edit"), "post", []Handler{func(ctx *Context) {}}), nil)
+
+	route, _ := tree.findRoute(stringToArr("/admin/info/menu"), "get")
+	assert.Equal(t, route, "/admin/info/menu")
+	route, _ = tree.findRoute(stringToArr("/admin/info/user"), "get")
+	assert.Equal(t, route, "/admin/info/:__prefix")
+	route, _ = tree.findRoute(stringToArr("/admin/info/menu/edit"), "post")
+	assert.Equal(t, route, "/admin/info/:id/edit")
+	route, _ = tree.findRoute(stringToArr("/admin/info/user/detail"), "get")
+	assert.Equal(t, route, "/admin/*path")
+	route, _ = tree.findRoute(stringToArr("/admin/assets/js/info.js"), "get")
+	assert.Equal(t, route, "/admin/*path")
+
+	_, ok := tree.findRoute(stringToArr("/admin/info/"), "post")
+	assert.Equal(t, ok, false)
+	_, ok = tree.findRoute(stringToArr("/admin"), "get")
+	assert.Equal(t, ok, false)
+}
+
+func TestTreeConflict(t *testing.T) {
+	tree := tree()
+	assert.Equal(t, tree.addPath(stringToArr("/user/:id"), "get", []Handler{func(ctx *Context) {}}), nil)
+	assert.Equal(t, tree.addPath(stringToArr("/user/:name"), "post", []Handler{func(ctx *Context) {}}), nil)
+	assert.Equal(t, tree.addPath(stringToArr("/user/:name"), "get", []Handler{func(ctx *Context) {}}).Error(),
+		"route conflict: GET /user/:name conflicts with /user/:id")
+	assert.Equal(t, tree.addPath(stringToArr("/user/*path/info"), "get", []Handler{func(ctx *Context) {}}) != nil, true)
+	assert.Equal(t, tree.addPath(stringToArr("/user/:/info"), "get", []Handler{func(ctx *Context) {}}) != nil, true)
+
+	app := NewApp()
+	app.GET("/user/:id", func(ctx *Context) {})
+	app.GET("/user/:uid", func(ctx *Context) {})
+	assert.Equal(t, len(app.Conflicts()), 1)
+	assert.Equal(t, len(app.Requests), 1)
+}
+
+func TestParams(t *testing.T) {
+	app := NewApp()
+	var params Params
+	route := app.Group("/admin")
+	route.GET("/info/:__prefix/:id", func(ctx *Context) {
+		params = ctx.Params()
+	})
+	route.GET("/assets/*filepath", func(ctx *Context) {
+		params = ctx.Params()
+	})
+
+	serve := func(path string) {
+		ctx := NewContext(httptest.NewRequest("GET", path, nil))
+		ctx.SetHandlers(app.Find(path, "get")).Next()
+	}
+
+	serve("/admin/info/user/1")
+	assert.Equal(t, params.ByName("__prefix"), "user")
+	assert.Equal(t, params.ByName("id"), "1")
+
+	serve("/admin/assets/js/info.js")
+	assert.Equal(t, params.ByName("filepath"), "/js/info.js")
+	_, ok := params.Get("id")
+	assert.Equal(t, ok, false)
+}
+
+func TestLocalIP(t *testing.T) {
+	ctx := NewContext(httptest.NewRequest("GET", "/", nil))
+	ctx.Request.RemoteAddr = "10.0.0.1:1234"
+	ctx.Request.Header.Set("X-Forwarded-For", "1.1.1.1, 2.2.2.2")
+	assert.Equal(t, ctx.LocalIP(), "10.0.0.1")
+
+	proxies, err := ParseTrustedProxies("10.0.0.0/8", "192.168.1.1")
+	assert.Equal(t, err, nil)
+	TrustProxies(proxies)(ctx)
+	assert.Equal(t, ctx.LocalIP(), "2.2.2.2")
+
+	ctx.Request.Header.Set("X-Forwarded-For", "1.1.1.1, 192.168.1.1")
+	assert.Equal(t, ctx.LocalIP(), "1.1.1.1")
+
+	ctx.Request.RemoteAddr = "3.3.3.3:1234"
+	assert.Equal(t, ctx.LocalIP(), "3.3.3.3")
+
+	other := NewContext(httptest.NewRequest("GET", "/", nil))
+	other.Request.RemoteAddr = "10.0.0.1:1234"
+	other.Request.Header.Set("X-Forwarded-For", "1.1.1.1")
+	assert.Equal(t, other.LocalIP(), "10.0.0.1")
+
+	_, err = ParseTrustedProxies("bad")
+	assert.Equal(t, err != nil, true)
+}
+
+func TestIsHTTPS(t *testing.T) {
+	ctx := NewContext(httptest.NewRequest("GET", "/", nil))
+	ctx.Request.RemoteAddr = "10.0.0.1:1234"
+	ctx.Request.Header.Set("X-Forwarded-Proto", "https, http")
+	assert.Equal(t, ctx.IsHTTPS(), false)
+
+	proxies, _ := ParseTrustedProxies("10.0.0.0/8")
+	TrustProxies(proxies)(ctx)
+	assert.Equal(t, ctx.IsHTTPS(), true)
+
+	ctx.Request.RemoteAddr = "3.3.3.3:1234"
+	assert.Equal(t, ctx.IsHTTPS(), false)
+
+	ctx.Request.TLS = &tls.ConnectionState{}
+	assert.Equal(t, ctx.IsHTTPS(), true)
+}
@@ -0,0 +1,327 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrNoResponseWriter is returned by Stream and SSE when the ResponseWriter
+// is not set, in which case the stream could only be buffered until it
+// ends.
+var ErrNoResponseWriter = errors.New("context: the ResponseWriter is not set, see SetResponseWriter")
+
+// ErrClientGone is returned by Stream and SSE when the client is gone in
+// the middle of the stream.
+var ErrClientGone = errors.New("context: the client is gone")
+
+// SetResponseWriter set the ResponseWriter of the request. With it, the
+// streams and the files are written directly instead of being buffered in
+// the Response. It is set by App.ServeHTTP, and the adapters of the web
+// frameworks should set it with the writer of the framework and skip
+// writing the Response if Written return true, otherwise Stream and SSE
+// are not supported.
+func (ctx *Context) SetResponseWriter(w http.ResponseWriter) *Context {
+	ctx.writer = w
+	return ctx
+}
+
+// Written return true if the response has been written into the
+// ResponseWriter directly, then the Response should not be written again.
+func (ctx *Context) Written() bool {
+	return ctx.written
+}
+
+// responseWriter return the writer which the streams and the files are
+// written into. The headers of the Response are copied to the
+// ResponseWriter if it is set, otherwise a buffer filling the Response is
+// returned, which should be closed after the response written.
+func (ctx *Context) responseWriter() responseWriter {
+	if ctx.writer != nil {
+		ctx.written = true
+		for key, values := range ctx.Response.Header {
+			for _, value := range values {
+				ctx.writer.Header().Add(key, value)
+			}
+		}
+		return directWriter{ctx.writer}
+	}
+	return &bufferWriter{ctx: ctx, code: http.StatusOK}
+}
+
+type responseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	io.Closer
+}
+
+type directWriter struct {
+	http.ResponseWriter
+}
+
+func (w directWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w directWriter) Close() error {
+	return nil
+}
+
+// bufferWriter is a ResponseWriter which fill the Response of the Context
+// when it is closed.
+type bufferWriter struct {
+	ctx  *Context
+	code int
+	buf  bytes.Buffer
+}
+
+func (w *bufferWriter) Header() http.Header {
+	return w.ctx.Response.Header
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+func (w *bufferWriter) WriteHeader(code int) {
+	w.code = code
+}
+
+func (w *bufferWriter) Flush() {}
+
+func (w *bufferWriter) Close() error {
+	w.ctx.Response.StatusCode = w.code
+	w.ctx.Response.Body = ioutil.NopCloser(&w.buf)
+	return nil
+}
+
+// Stream write the response by calling step until it returns false or the
+// client is gone, the writer is flushed after each step. It return
+// ErrClientGone if the client is gone in the middle of the stream, and
+// ErrNoResponseWriter without calling step if the ResponseWriter is not
+// set.
+func (ctx *Context) Stream(step func(w io.Writer) bool) error {
+	if ctx.writer == nil {
+		return ErrNoResponseWriter
+	}
+
+	w := ctx.responseWriter()
+	defer func() {
+		_ = w.Close()
+	}()
+
+	code := ctx.Response.StatusCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+	w.WriteHeader(code)
+
+	done := ctx.Request.Context().Done()
+	for {
+		select {
+		case <-done:
+			return ErrClientGone
+		default:
+			keepOpen := step(w)
+			w.Flush()
+			if !keepOpen {
+				return nil
+			}
+		}
+	}
+}
+
+// SSEvent is an event of the server-sent events.
+type SSEvent struct {
+	ID    string
+	Event string
+	// Data is written as it is if it is a string or []byte, otherwise it
+	// is encoded as JSON.
+	Data interface{}
+	// Retry is the reconnection time in milliseconds, zero means not set.
+	Retry int
+}
+
+// WriteTo write the event in the text/event-stream format.
+func (e SSEvent) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	if e.ID != "" {
+		buf.WriteString("id: " + singleLine(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		buf.WriteString("event: " + singleLine(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		buf.WriteString("retry: " + strconv.Itoa(e.Retry) + "\n")
+	}
+
+	var data string
+	switch d := e.Data.(type) {
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		b, err := json.Marshal(d)
+		if err != nil {
+			return 0, err
+		}
+		data = string(b)
+	}
+	for _, line := range strings.Split(strings.Replace(data, "\r\n", "\n", -1), "\n") {
+		buf.WriteString("data: " + line + "\n")
+	}
+	buf.WriteString("\n")
+
+	return buf.WriteTo(w)
+}
+
+func singleLine(s string) string {
+	return strings.NewReplacer("\n", "", "\r", "").Replace(s)
+}
+
+// SSE stream the server-sent events. The step is called with a function
+// sending an event until it returns false or the client is gone, for example:
+//
+//	ctx.SSE(func(send func(context.SSEvent) error) bool {
+//		msg, ok := <-messages
+//		if !ok {
+//			return false
+//		}
+//		return send(context.SSEvent{Event: "message", Data: msg}) == nil
+//	})
+//
+// It return the errors as Stream does.
+func (ctx *Context) SSE(step func(send func(SSEvent) error) bool) error {
+	ctx.SetHeader("Content-Type", "text/event-stream")
+	ctx.SetHeader("Cache-Control", "no-cache")
+	ctx.SetHeader("Connection", "keep-alive")
+	ctx.SetHeader("X-Accel-Buffering", "no")
+	return ctx.Stream(func(w io.Writer) bool {
+		return step(func(e SSEvent) error {
+			_, err := e.WriteTo(w)
+			return err
+		})
+	})
+}
+
+// File serve the file of the path. The Range, If-Range, If-None-Match and
+// If-Modified-Since requests are supported, and a weak ETag is generated
+// from the size and the modification time of the file.
+func (ctx *Context) File(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		ctx.fileError(err)
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		ctx.fileError(err)
+		return
+	}
+	if info.IsDir() {
+		ctx.fileError(os.ErrNotExist)
+		return
+	}
+
+	if ctx.Response.Header.Get("ETag") == "" {
+		ctx.SetHeader("ETag", fmt.Sprintf(`W/"%x-%x"`, info.Size(), info.ModTime().UnixNano()))
+	}
+
+	w := ctx.responseWriter()
+	http.ServeContent(w, ctx.Request, info.Name(), info.ModTime(), f)
+	_ = w.Close()
+}
+
+// Attachment serve the file of the path as an attachment, which is
+// downloaded and saved as the filename by the browsers. The base name of
+// the path is used if the filename is empty.
+func (ctx *Context) Attachment(path, filename string) {
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	ctx.SetHeader("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`,
+		strings.NewReplacer(`"`, `\"`, `\`, `\\`).Replace(filename), url.PathEscape(filename)))
+	ctx.File(path)
+}
+
+func (ctx *Context) fileError(err error) {
+	switch {
+	case os.IsNotExist(err):
+		ctx.Data(http.StatusNotFound, "text/plain; charset=utf-8", []byte("404 page not found"))
+	case os.IsPermission(err):
+		ctx.Data(http.StatusForbidden, "text/plain; charset=utf-8", []byte("403 Forbidden"))
+	default:
+		ctx.Data(http.StatusInternalServerError, "text/plain; charset=utf-8", []byte("500 Internal Server Error"))
+	}
+}
+
+// FormFile return the first uploaded file of the given form key. The
+// multipart form is parsed with MaxMultipartMemory.
+func (ctx *Context) FormFile(name string) (*multipart.FileHeader, error) {
+	if ctx.Request.MultipartForm == nil {
+		if err := ctx.Request.ParseMultipartForm(MaxMultipartMemory); err != nil {
+			return nil, err
+		}
+	}
+	if files := ctx.Request.MultipartForm.File[name]; len(files) > 0 {
+		return files[0], nil
+	}
+	return nil, http.ErrMissingFile
+}
+
+// MultipartForm return the parsed multipart form, including the uploaded
+// files.
+func (ctx *Context) MultipartForm() (*multipart.Form, error) {
+	if ctx.Request.MultipartForm == nil {
+		if err := ctx.Request.ParseMultipartForm(MaxMultipartMemory); err != nil {
+			return nil, err
+		}
+	}
+	return ctx.Request.MultipartForm, nil
+}
+
+// SaveUploadedFile save the uploaded file to the dst path.
+func (ctx *Context) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
+	if file == nil {
+		return errors.New("empty file")
+	}
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = out.Close()
+	}()
+
+	_, err = io.Copy(out, src)
+	return err
+}
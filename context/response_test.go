@@ -0,0 +1,125 @@
+package context
+
+import (
+	"bytes"
+	"github.com/magiconair/properties/assert"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestContext_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goadmin-context")
+	assert.Equal(t, err, nil)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "report.txt")
+	assert.Equal(t, ioutil.WriteFile(path, []byte("0123456789"), 0644), nil)
+
+	app := NewApp()
+	app.GET("/file", func(ctx *Context) {
+		ctx.File(path)
+	})
+	app.GET("/download", func(ctx *Context) {
+		ctx.Attachment(path, "报表.txt")
+	})
+	app.GET("/missing", func(ctx *Context) {
+		ctx.File(filepath.Join(dir, "missing.txt"))
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/file", nil))
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), "0123456789")
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, etag != "", true)
+
+	req := httptest.NewRequest("GET", "/file", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusPartialContent)
+	assert.Equal(t, w.Body.String(), "234")
+
+	req = httptest.NewRequest("GET", "/file", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusNotModified)
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/download", nil))
+	assert.Equal(t, w.Header().Get("Content-Disposition"),
+		`attachment; filename="报表.txt"; filename*=UTF-8''%E6%8A%A5%E8%A1%A8.txt`)
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, w.Code, http.StatusNotFound)
+
+	// without the ResponseWriter, the file is buffered in the Response
+	ctx := NewContext(httptest.NewRequest("GET", "/file", nil))
+	ctx.File(path)
+	body, _ := ioutil.ReadAll(ctx.Response.Body)
+	assert.Equal(t, ctx.Response.StatusCode, http.StatusOK)
+	assert.Equal(t, string(body), "0123456789")
+}
+
+func TestContext_SSE(t *testing.T) {
+	app := NewApp()
+	app.GET("/events", func(ctx *Context) {
+		count := 0
+		ctx.SSE(func(send func(SSEvent) error) bool {
+			count++
+			_ = send(SSEvent{ID: "1", Event: "tick", Data: map[string]int{"count": count}})
+			return count < 2
+		})
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+	assert.Equal(t, w.Header().Get("Content-Type"), "text/event-stream")
+	assert.Equal(t, w.Body.String(), "id: 1\nevent: tick\ndata: {\"count\":1}\n\n"+
+		"id: 1\nevent: tick\ndata: {\"count\":2}\n\n")
+	assert.Equal(t, w.Flushed, true)
+
+	ctx := NewContext(httptest.NewRequest("GET", "/events", nil))
+	err := ctx.SSE(func(send func(SSEvent) error) bool {
+		t.Fatal("the step is called without the ResponseWriter")
+		return false
+	})
+	assert.Equal(t, err, ErrNoResponseWriter)
+}
+
+func TestContext_FormFile(t *testing.T) {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	fw, _ := mw.CreateFormFile("avatar", "avatar.png")
+	_, _ = io.WriteString(fw, "png")
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	ctx := NewContext(req)
+
+	file, err := ctx.FormFile("avatar")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, file.Filename, "avatar.png")
+
+	_, err = ctx.FormFile("missing")
+	assert.Equal(t, err, http.ErrMissingFile)
+
+	dst := filepath.Join(os.TempDir(), "goadmin-avatar.png")
+	defer func() {
+		_ = os.Remove(dst)
+	}()
+	assert.Equal(t, ctx.SaveUploadedFile(file, dst), nil)
+	content, _ := ioutil.ReadFile(dst)
+	assert.Equal(t, string(content), "png")
+}
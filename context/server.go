@@ -0,0 +1,150 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ServeHTTP implements the http.Handler, so that the App can be served
+// without an adapter, for example:
+//
+//	http.ListenAndServe(":9033", app)
+//
+// The request is handled as follows:
+//
+//  1. The handlers of the route matching the path and method are called.
+//  2. A HEAD request is handled by the GET route if there is no HEAD route.
+//  3. If the path with or without the trailing slash matches a route, the
+//     request is redirected to it, with 301 for GET and HEAD, and 308 for
+//     the other methods.
+//  4. If the path matches routes of other methods, 405 is responded with
+//     the Allow header, except OPTIONS, which is responded with 204 and the
+//     Allow header after the middlewares of the App.
+//  5. Otherwise, 404 is responded.
+func (app *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	var (
+		path   = r.URL.Path
+		method = strings.ToLower(r.Method)
+		head   = method == "head"
+	)
+
+	if handlers := app.lookup(path, method); handlers != nil {
+		ctx := NewContext(r).SetResponseWriter(w)
+		ctx.SetHandlers(handlers).Next()
+		if !ctx.Written() {
+			writeResponse(w, ctx, head)
+		}
+		return
+	}
+
+	if path != "/" {
+		to := path + "/"
+		if strings.HasSuffix(path, "/") {
+			to = strings.TrimRight(path, "/")
+			if to == "" {
+				to = "/"
+			}
+		}
+		// The path starting with "//" or "/\" is not redirected, which the
+		// browsers take as the url of another host.
+		if app.lookup(to, method) != nil && !strings.HasPrefix(to, "//") && !strings.HasPrefix(to, "/\\") {
+			if r.URL.RawQuery != "" {
+				to += "?" + r.URL.RawQuery
+			}
+			code := http.StatusPermanentRedirect
+			if method == "get" || head {
+				code = http.StatusMovedPermanently
+			}
+			http.Redirect(w, r, to, code)
+			return
+		}
+	}
+
+	if allowed := app.Allowed(path); len(allowed) > 0 {
+		if method == "options" {
+			// the middlewares of the App handle the preflight requests,
+			// such as the CORS.
+			ctx := NewContext(r).SetResponseWriter(w)
+			ctx.SetHandlers(append(append(Handlers{}, app.Middlewares...), func(ctx *Context) {
+				ctx.SetHeader("Allow", strings.Join(allowed, ", "))
+				ctx.SetStatusCode(http.StatusNoContent)
+			})).Next()
+			if !ctx.Written() {
+				writeResponse(w, ctx, false)
+			}
+			return
+		}
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+// lookup return the handlers of the path and method, HEAD falls back to GET.
+func (app *App) lookup(path, method string) Handlers {
+	paths := stringToArr(path)
+	if handlers := app.tree.findPath(paths, method); handlers != nil {
+		return handlers
+	}
+	if method == "head" {
+		return app.tree.findPath(paths, "get")
+	}
+	return nil
+}
+
+// Allowed return the sorted upper case methods of the routes matching the
+// path. HEAD is included if GET is allowed.
+func (app *App) Allowed(path string) []string {
+	var (
+		methods = app.tree.allowed(stringToArr(path))
+		allowed = make([]string, 0, len(methods)+1)
+		exist   = make(map[string]bool)
+	)
+	for _, method := range methods {
+		method = strings.ToUpper(method)
+		if method == http.MethodGet && !exist[http.MethodHead] {
+			exist[http.MethodHead] = true
+			allowed = append(allowed, http.MethodHead)
+		}
+		if !exist[method] {
+			exist[method] = true
+			allowed = append(allowed, method)
+		}
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// writeResponse write the response of the Context into the ResponseWriter.
+func writeResponse(w http.ResponseWriter, ctx *Context, head bool) {
+	for key, values := range ctx.Response.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	code := ctx.Response.StatusCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+	w.WriteHeader(code)
+
+	if ctx.Response.Body == nil {
+		return
+	}
+	defer func() {
+		_ = ctx.Response.Body.Close()
+	}()
+	if !head {
+		_, _ = io.Copy(w, ctx.Response.Body)
+	}
+}
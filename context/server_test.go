@@ -0,0 +1,83 @@
+package context
+
+import (
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApp_ServeHTTP(t *testing.T) {
+	app := NewApp()
+	route := app.Group("/admin")
+	route.GET("/info/:__prefix", func(ctx *Context) {
+		ctx.HTML(http.StatusOK, "info "+ctx.Param("__prefix"))
+	})
+	route.POST("/info/:__prefix", func(ctx *Context) {
+		ctx.JSON(http.StatusCreated, map[string]interface{}{"prefix": ctx.Param("__prefix")})
+	})
+	route.PUT("/menu", func(ctx *Context) {
+		ctx.SetStatusCode(http.StatusNoContent)
+	})
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve("GET", "/admin/info/user")
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), "info user")
+	assert.Equal(t, w.Header().Get("Content-Type"), "text/html; charset=utf-8")
+
+	w = serve("POST", "/admin/info/user")
+	assert.Equal(t, w.Code, http.StatusCreated)
+	assert.Equal(t, w.Body.String(), `{"prefix":"user"}`)
+
+	w = serve("HEAD", "/admin/info/user")
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Body.String(), "")
+
+	w = serve("GET", "/admin/info/user/?page=2")
+	assert.Equal(t, w.Code, http.StatusMovedPermanently)
+	assert.Equal(t, w.Header().Get("Location"), "/admin/info/user?page=2")
+
+	w = serve("PUT", "/admin/menu/")
+	assert.Equal(t, w.Code, http.StatusPermanentRedirect)
+	assert.Equal(t, w.Header().Get("Location"), "/admin/menu")
+
+	w = serve("DELETE", "/admin/info/user")
+	assert.Equal(t, w.Code, http.StatusMethodNotAllowed)
+	assert.Equal(t, w.Header().Get("Allow"), "GET, HEAD, POST")
+
+	w = serve("GET", "/admin/menu")
+	assert.Equal(t, w.Code, http.StatusMethodNotAllowed)
+	assert.Equal(t, w.Header().Get("Allow"), "PUT")
+
+	w = serve("GET", "/admin/unknown")
+	assert.Equal(t, w.Code, http.StatusNotFound)
+
+	app.GET("/evil.com", func(ctx *Context) {
+		ctx.HTML(http.StatusOK, "evil")
+	})
+	w = serve("GET", "/evil.com/")
+	assert.Equal(t, w.Code, http.StatusMovedPermanently)
+	assert.Equal(t, w.Header().Get("Location"), "/evil.com")
+
+	// the routes with an empty first segment are never redirected to, which
+	// the browsers take as the urls of another host
+	app.GET("//evil.com", func(ctx *Context) {
+		ctx.HTML(http.StatusOK, "evil")
+	})
+	app.GET("/\\evil.com", func(ctx *Context) {
+		ctx.HTML(http.StatusOK, "evil")
+	})
+	w = serve("GET", "//evil.com/")
+	assert.Equal(t, w.Code, http.StatusNotFound)
+	assert.Equal(t, w.Header().Get("Location"), "")
+
+	w = serve("GET", "/\\evil.com/")
+	assert.Equal(t, w.Code, http.StatusNotFound)
+	assert.Equal(t, w.Header().Get("Location"), "")
+}
@@ -4,13 +4,29 @@
 
 package context
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// nodeType is the type of a path segment. When a path is matched, the
+// static segments take precedence over the params, which take precedence
+// over the catch-all.
+type nodeType uint8
+
+const (
+	static   nodeType = iota // /user
+	param                    // /:id
+	catchAll                 // /*path
+)
 
 type node struct {
 	children []*node
 	value    string
+	kind     nodeType
 	method   []string
 	handle   [][]Handler
+	route    []string
 }
 
 func tree() *node {
@@ -21,6 +37,19 @@ func tree() *node {
 	}
 }
 
+// segmentType return the type of the path segment.
+func segmentType(segment string) nodeType {
+	if len(segment) > 0 {
+		switch segment[0] {
+		case ':':
+			return param
+		case '*':
+			return catchAll
+		}
+	}
+	return static
+}
+
 func (n *node) hasMethod(method string) int {
 	for k, m := range n.method {
 		if m == method {
@@ -30,58 +59,152 @@ func (n *node) hasMethod(method string) int {
 	return -1
 }
 
-func (n *node) addMethodAndHandler(method string, handler []Handler) {
+func (n *node) addMethodAndHandler(method, route string, handler []Handler) error {
+	if index := n.hasMethod(method); index != -1 {
+		return fmt.Errorf("route conflict: %s %s conflicts with %s", strings.ToUpper(method),
+			route, n.route[index])
+	}
 	n.method = append(n.method, method)
 	n.handle = append(n.handle, handler)
+	n.route = append(n.route, route)
+	return nil
 }
 
 func (n *node) addChild(child *node) {
 	n.children = append(n.children, child)
 }
 
+// addContent return the child of the segment, a new child is added if not
+// exist. The params of the same position share a child no matter what
+// their names are, so are the catch-all.
 func (n *node) addContent(value string) *node {
-	var child = n.search(value)
+	var child = n.child(value)
 	if child == nil {
 		child = &node{
 			children: make([]*node, 0),
 			value:    value,
+			kind:     segmentType(value),
 		}
 		n.addChild(child)
 	}
 	return child
 }
 
-func (n *node) search(value string) *node {
+func (n *node) child(value string) *node {
+	kind := segmentType(value)
 	for _, child := range n.children {
-		if child.value == value || child.value == "*" {
+		if child.kind == kind && (kind != static || child.value == value) {
 			return child
 		}
 	}
 	return nil
 }
 
-func (n *node) addPath(paths []string, method string, handler []Handler) {
+// addPath add the handler of the method to the path. It return an error if
+// the path is invalid or the method of the path has been registered, and
+// the handler will be ignored.
+func (n *node) addPath(paths []string, method string, handler []Handler) error {
+	route := "/" + strings.Join(paths, "/")
+	for i, path := range paths {
+		if segmentType(path) == catchAll && i != len(paths)-1 {
+			return fmt.Errorf("invalid route: %s, the catch-all must be at the end", route)
+		}
+		if segmentType(path) == param && len(path) == 1 {
+			return fmt.Errorf("invalid route: %s, the param must be named", route)
+		}
+	}
 	child := n
 	for i := 0; i < len(paths); i++ {
 		child = child.addContent(paths[i])
 	}
-	child.addMethodAndHandler(method, handler)
+	return child.addMethodAndHandler(method, route, handler)
 }
 
 func (n *node) findPath(paths []string, method string) []Handler {
-	child := n
-	for i := 0; i < len(paths); i++ {
-		child = child.search(paths[i])
-		if child == nil {
-			return nil
-		}
+	leaf := n.match(paths, method)
+	if leaf == nil {
+		return nil
 	}
+	return leaf.handle[leaf.hasMethod(method)]
+}
+
+// findRoute return the registered route of the path and method.
+func (n *node) findRoute(paths []string, method string) (string, bool) {
+	leaf := n.match(paths, method)
+	if leaf == nil {
+		return "", false
+	}
+	return leaf.route[leaf.hasMethod(method)], true
+}
 
-	methodIndex := child.hasMethod(method)
-	if methodIndex == -1 {
+// match return the node which matches the paths and has the method. The
+// static children are tried first, then the param and the catch-all.
+func (n *node) match(paths []string, method string) *node {
+	if len(paths) == 0 {
+		if n.hasMethod(method) != -1 {
+			return n
+		}
 		return nil
 	}
-	return child.handle[methodIndex]
+
+	segment := paths[0]
+
+	for _, kind := range []nodeType{static, param, catchAll} {
+		for _, child := range n.children {
+			if child.kind != kind {
+				continue
+			}
+			switch kind {
+			case static:
+				if child.value != segment {
+					continue
+				}
+			case param:
+				if segment == "" {
+					continue
+				}
+			case catchAll:
+				if child.hasMethod(method) != -1 {
+					return child
+				}
+				continue
+			}
+			if leaf := child.match(paths[1:], method); leaf != nil {
+				return leaf
+			}
+		}
+	}
+
+	return nil
+}
+
+// allowed return the methods of all the routes matching the paths.
+func (n *node) allowed(paths []string) []string {
+	if len(paths) == 0 {
+		return n.method
+	}
+
+	var (
+		segment = paths[0]
+		methods = make([]string, 0)
+	)
+
+	for _, child := range n.children {
+		switch child.kind {
+		case static:
+			if child.value == segment {
+				methods = append(methods, child.allowed(paths[1:])...)
+			}
+		case param:
+			if segment != "" {
+				methods = append(methods, child.allowed(paths[1:])...)
+			}
+		case catchAll:
+			methods = append(methods, child.method...)
+		}
+	}
+
+	return methods
 }
 
 func (n *node) print() {
@@ -95,39 +218,63 @@ func (n *node) printChildren() {
 	}
 }
 
+// stringToArr split the path into segments. A trailing slash results in
+// an empty last segment, so "/admin/" does not match "/admin".
 func stringToArr(path string) []string {
+	if i := strings.IndexByte(path, '?'); i != -1 {
+		path = path[:i]
+	}
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, "/")
+}
+
+// Param is a route parameter.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params is the route parameters of a request.
+type Params []Param
+
+// Get return the value of the param with the given name.
+func (ps Params) Get(name string) (string, bool) {
+	for _, p := range ps {
+		if p.Key == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// ByName return the value of the param with the given name, or empty
+// string if not exist.
+func (ps Params) ByName(name string) string {
+	value, _ := ps.Get(name)
+	return value
+}
+
+// parseParams extract the params of the path according to the route.
+func parseParams(route, path string) Params {
 	var (
-		paths      = make([]string, 0)
-		start      = 0
-		end        int
-		isWildcard = false
+		routes = stringToArr(route)
+		paths  = stringToArr(path)
+		params Params
 	)
-	for i := 0; i < len(path); i++ {
-		if i == 0 && path[0] == '/' {
-			start = 1
-			continue
-		}
-		if path[i] == ':' {
-			isWildcard = true
+	for i, segment := range routes {
+		if i >= len(paths) {
+			break
 		}
-		if i == len(path)-1 {
-			end = i + 1
-			if isWildcard {
-				paths = append(paths, "*")
-			} else {
-				paths = append(paths, path[start:end])
-			}
-		}
-		if path[i] == '/' {
-			end = i
-			if isWildcard {
-				paths = append(paths, "*")
-			} else {
-				paths = append(paths, path[start:end])
-			}
-			start = i + 1
-			isWildcard = false
+		switch segmentType(segment) {
+		case param:
+			params = append(params, Param{Key: segment[1:], Value: paths[i]})
+		case catchAll:
+			params = append(params, Param{Key: segment[1:], Value: "/" + strings.Join(paths[i:], "/")})
+			return params
 		}
 	}
-	return paths
+	return params
 }
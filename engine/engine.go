@@ -5,13 +5,23 @@
 package engine
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/glvd/go-admin/adapter"
+	gctx "github.com/glvd/go-admin/context"
 	"github.com/glvd/go-admin/modules/config"
 	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/logger"
 	"github.com/glvd/go-admin/modules/service"
 	"github.com/glvd/go-admin/plugins"
 	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/glvd/go-admin/plugins/admin/modules/resource"
+	"github.com/glvd/go-admin/template"
 	"github.com/glvd/go-admin/template/types"
+	"net/http"
+	"strings"
+	"time"
 )
 
 // Engine is the core component of goAdmin. It has two attributes.
@@ -24,15 +34,51 @@ type Engine struct {
 	Adapter    adapter.WebFrameWork
 	Services   service.List
 
-	config config.Config
+	config  *config.Service
+	plugins *plugins.Manager
+	watcher *config.Watcher
+	// global is true if the engine uses the global config and the
+	// default adapter, which the package level functions work with.
+	global bool
+	// pruneResources is true if the resources of the removed plugins are
+	// removed, see PruneResources.
+	pruneResources bool
 }
 
-// Default return the default engine instance.
+// Default return the default engine instance, which sets the global config
+// and the default adapter.
 func Default() *Engine {
-	return &Engine{
+	eng := &Engine{
 		Adapter:  defaultAdapter,
 		Services: service.GetServices(),
+		plugins:  plugins.GlobalManager(),
+		global:   true,
 	}
+	eng.Services[plugins.ManagerName] = eng.plugins
+	return eng
+}
+
+// New return an engine instance which keeps the config, the services and
+// the adapter of its own, so that several engines with the different
+// prefixes and databases can be hosted in one process. The plugins should
+// be instances of their own too, such as admin.New. The sessions and the
+// languages are still shared in the process.
+func New() *Engine {
+	eng := &Engine{
+		Services: service.GetServices(),
+		plugins:  plugins.NewManager(),
+	}
+	eng.Services[plugins.ManagerName] = eng.plugins
+	return eng
+}
+
+// manager return the plugin manager of the engine, which keeps the
+// installed plugins and their states.
+func (eng *Engine) manager() *plugins.Manager {
+	if eng.plugins == nil {
+		eng.plugins = plugins.NewManager()
+	}
+	return eng.plugins
 }
 
 // Use enable the adapter.
@@ -41,31 +87,285 @@ func (eng *Engine) Use(router interface{}) error {
 		panic("adapter is nil, import the default adapter or use AddAdapter method add the adapter")
 	}
 
-	return eng.Adapter.Use(router, eng.PluginList)
+	eng.syncResources()
+
+	if _, err := eng.app(); err != nil {
+		return err
+	}
+
+	return eng.Adapter.Use(router, eng.servedPlugins())
 }
 
-// AddPlugins add the plugins and initialize them.
+// Handler return the http.Handler serving the routes of the plugins, with
+// which the engine is mounted on any server without an adapter, such as:
+//
+//	eng := engine.New().AddConfig(cfg).AddPlugins(admin.New())
+//	http.Handle("/admin2/", eng.Handler())
+//
+// The plugins should be added before. It panics if the routes of the
+// plugins conflict.
+func (eng *Engine) Handler() http.Handler {
+	eng.syncResources()
+
+	app, err := eng.app()
+	if err != nil {
+		panic(err)
+	}
+	return app
+}
+
+// app return the app of the routes of the served plugins. The invalid and
+// conflicting routes of the plugins, and the routes of the plugins
+// conflicting with each other, are logged and returned as an error.
+func (eng *Engine) app() (*gctx.App, error) {
+	app := gctx.NewApp()
+	for _, plug := range eng.servedPlugins() {
+		for _, req := range plug.GetRequest() {
+			app.AppendReqAndResp(req.URL, req.Method, plug.GetHandler(req.URL, req.Method))
+		}
+	}
+
+	conflicts := make([]string, 0)
+	for _, plug := range eng.PluginList {
+		if reporter, ok := plug.(plugins.ConflictReporter); ok {
+			for _, err := range reporter.Conflicts() {
+				conflicts = append(conflicts, pluginName(plug)+": "+err.Error())
+			}
+		}
+	}
+	for _, err := range app.Conflicts() {
+		conflicts = append(conflicts, err.Error())
+	}
+	if len(conflicts) == 0 {
+		return app, nil
+	}
+
+	for _, conflict := range conflicts {
+		logger.Error(conflict)
+	}
+	return app, errors.New(strings.Join(conflicts, "; "))
+}
+
+// servedPlugins return the plugins which can be disabled at runtime with
+// the plugin serving their assets.
+func (eng *Engine) servedPlugins() []plugins.Plugin {
+	list := make([]plugins.Plugin, 0, len(eng.PluginList)+1)
+	for _, plug := range eng.PluginList {
+		list = append(list, eng.manager().Switchable(plug))
+	}
+	if assets := plugins.AssetPlugin(eng.PluginList, eng.Config()); assets != nil {
+		list = append(list, assets)
+	}
+	return list
+}
+
+// syncResources sync the menu items and the permissions declared by the
+// plugins into the admin tables, and load the saved states of the plugins.
+// See the package resource.
+func (eng *Engine) syncResources() {
+	driver := config.GetFromServices(eng.Services).Databases.GetDefault().Driver
+	if _, ok := eng.Services[driver].(db.Connection); !ok {
+		return
+	}
+	conn := db.GetConnection(eng.Services)
+	if err := resource.Sync(conn, eng.PluginList); err != nil {
+		logger.Error("sync plugin resources error: ", err)
+		return
+	}
+	if eng.pruneResources {
+		if err := resource.Prune(conn, eng.PluginList); err != nil {
+			logger.Error("prune plugin resources error: ", err)
+		}
+	}
+	states, err := resource.States(conn)
+	if err != nil {
+		logger.Error("load plugin states error: ", err)
+		return
+	}
+	for name, enabled := range states {
+		eng.manager().SetEnabled(name, enabled)
+	}
+}
+
+// PruneResources remove the menu items and the permissions of the plugins
+// which are not added to the engine any more, when the resources of the
+// plugins are synced by Use or Handler. It should only be set when no
+// other engines with other plugins share the database, see resource.Prune.
+func (eng *Engine) PruneResources() *Engine {
+	eng.pruneResources = true
+	return eng
+}
+
+// AddPlugins add the plugins and initialize them. The plugins are
+// initialized after the plugins and the services they depend on, and the
+// plugins implementing plugins.Starter are started after initialized. It
+// panics if the dependencies are missing or circular, or a plugin fails to
+// start.
 func (eng *Engine) AddPlugins(plugs ...plugins.Plugin) *Engine {
 
-	for _, plug := range plugs {
+	sorted, err := plugins.Sort(plugs, eng.PluginList, eng.Services)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, plug := range sorted {
 		plug.InitPlugin(eng.Services)
+		eng.PluginList = append(eng.PluginList, plug)
+		eng.manager().Add(plug)
+		if starter, ok := plug.(plugins.Starter); ok {
+			if err := starter.Start(context.Background()); err != nil {
+				panic(fmt.Sprintf("start plugin %s error: %s", pluginName(plug), err))
+			}
+		}
 	}
 
-	eng.PluginList = append(eng.PluginList, plugs...)
 	return eng
 }
 
-// AddConfig set the global config.
+// Health return the health errors of the plugins implementing
+// plugins.HealthChecker by the plugin names, the healthy plugins are not
+// included.
+func (eng *Engine) Health() map[string]error {
+	errs := make(map[string]error)
+	for _, plug := range eng.PluginList {
+		if checker, ok := plug.(plugins.HealthChecker); ok {
+			if err := checker.Health(); err != nil {
+				errs[pluginName(plug)] = err
+			}
+		}
+	}
+	return errs
+}
+
+// Shutdown stop the plugins implementing plugins.Stopper in the reverse
+// order of the initialization, and then close the database connections.
+// The ctx is passed to the plugins, which should return when it is done.
+// It return the errors joined if any.
+func (eng *Engine) Shutdown(ctx context.Context) error {
+	msgs := make([]string, 0)
+
+	if eng.watcher != nil {
+		eng.watcher.Stop()
+	}
+
+	for i := len(eng.PluginList) - 1; i >= 0; i-- {
+		if stopper, ok := eng.PluginList[i].(plugins.Stopper); ok {
+			if err := stopper.Stop(ctx); err != nil {
+				msgs = append(msgs, fmt.Sprintf("stop plugin %s error: %s", pluginName(eng.PluginList[i]), err))
+			}
+		}
+	}
+
+	for key, srv := range eng.Services {
+		conn, ok := srv.(db.Connection)
+		if !ok {
+			continue
+		}
+		for _, err := range conn.Close() {
+			if err != nil {
+				msgs = append(msgs, fmt.Sprintf("close connection %s error: %s", key, err))
+			}
+		}
+	}
+
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+func pluginName(plug plugins.Plugin) string {
+	if name := plugins.Name(plug); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%T", plug)
+}
+
+// AddConfig set the config of the engine, which is the global config of
+// the default engine.
 func (eng *Engine) AddConfig(cfg config.Config) *Engine {
 	return eng.setConfig(cfg).InitDatabase()
 }
 
-// setConfig set the config of engine.
+// setConfig set the config of engine, and add it as the config service
+// with which the plugins get the config.
 func (eng *Engine) setConfig(cfg config.Config) *Engine {
-	eng.config = config.Set(cfg)
+	if eng.global {
+		config.Set(cfg)
+		eng.config = config.GlobalService()
+	} else {
+		eng.config = config.NewService(config.Normalize(cfg))
+	}
+	eng.Services[config.ServiceName] = eng.config
 	return eng
 }
 
+// Config return the current config of the engine.
+func (eng *Engine) Config() config.Config {
+	if eng.config == nil {
+		return config.Config{}
+	}
+	return eng.config.Get()
+}
+
+// AddConfigFromFile load the config from the file with config.Load, which
+// overrides the options with the environment variables and validates
+// them, and set it as the config of the engine. The format of the file is
+// got from the extension.
+func (eng *Engine) AddConfigFromFile(path string) (*Engine, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return eng, err
+	}
+	return eng.setConfig(cfg).InitDatabase(), nil
+}
+
+// ReloadConfig replace the options of the config which are safe to change
+// at runtime with the given config, see config.Reloadable. The following
+// requests are served with the new titles, logos, theme and color scheme,
+// and the loggers are reset if the engine is the default one.
+func (eng *Engine) ReloadConfig(cfg config.Config) error {
+	if eng.config == nil {
+		return errors.New("the config is not set")
+	}
+	next := config.Reloadable(eng.config.Get(), cfg)
+	if !hasTheme(next.Theme) {
+		return fmt.Errorf("reload config: unknown theme %q", next.Theme)
+	}
+	if eng.global {
+		config.Set(next)
+	} else {
+		eng.config.Set(next)
+	}
+	return nil
+}
+
+// WatchConfig watch the config file and reload the config with
+// ReloadConfig when it is changed, the file is checked every interval.
+// The watching is stopped by Shutdown.
+func (eng *Engine) WatchConfig(path string, interval time.Duration) *Engine {
+	if eng.watcher != nil {
+		eng.watcher.Stop()
+	}
+	eng.watcher = config.Watch(path, interval, func(cfg config.Config) {
+		if err := eng.ReloadConfig(cfg); err != nil {
+			logger.Error(err)
+			return
+		}
+		logger.Info("config reloaded from ", path)
+	})
+	return eng
+}
+
+func hasTheme(theme string) bool {
+	for _, name := range template.Themes() {
+		if name == theme {
+			return true
+		}
+	}
+	return false
+}
+
 // AddConfigFromJSON set the global config from json file.
 func (eng *Engine) AddConfigFromJSON(path string) *Engine {
 	return eng.setConfig(config.ReadFromJson(path)).InitDatabase()
@@ -83,22 +383,32 @@ func (eng *Engine) AddConfigFromINI(path string) *Engine {
 
 // InitDatabase initialize all database connection.
 func (eng *Engine) InitDatabase() *Engine {
-	for driver, databaseCfg := range eng.config.Databases.GroupByDriver() {
-		eng.Services.Add(driver, db.GetConnectionByDriver(driver).InitDB(databaseCfg))
-	}
-	if defaultAdapter == nil {
-		panic("adapter is nil")
+	threshold := db.SlowQueryThreshold(eng.Config().SlowQueryThreshold)
+	for driver, databaseCfg := range eng.Config().Databases.GroupByDriver() {
+		conn := db.GetConnectionByDriver(driver).InitDB(databaseCfg)
+		conn.SetSlowQueryThreshold(threshold)
+		eng.Services.Add(driver, conn)
 	}
 	defaultConnection := db.GetConnection(eng.Services)
-	defaultAdapter.SetConnection(defaultConnection)
-	eng.Adapter.SetConnection(defaultConnection)
+	if eng.global {
+		if defaultAdapter == nil {
+			panic("adapter is nil")
+		}
+		defaultAdapter.SetConnection(defaultConnection)
+	}
+	if eng.Adapter != nil {
+		eng.Adapter.SetConnection(defaultConnection)
+	}
 	return eng
 }
 
-// AddAdapter add the adapter of engine.
+// AddAdapter add the adapter of engine, which is also the default adapter
+// if the engine is the default one.
 func (eng *Engine) AddAdapter(ada adapter.WebFrameWork) *Engine {
 	eng.Adapter = ada
-	defaultAdapter = ada
+	if eng.global {
+		defaultAdapter = ada
+	}
 	return eng
 }
 
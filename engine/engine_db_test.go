@@ -0,0 +1,87 @@
+//go:build sqlite
+// +build sqlite
+
+package engine
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/plugins"
+	"github.com/glvd/go-admin/plugins/admin/modules/installer"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type menuPlugin struct {
+	name  string
+	menus []plugins.Menu
+}
+
+func (p menuPlugin) GetRequest() []context.Path                     { return nil }
+func (p menuPlugin) GetHandler(url, method string) context.Handlers { return nil }
+func (p menuPlugin) InitPlugin(services service.List)               {}
+func (p menuPlugin) Name() string                                   { return p.name }
+func (p menuPlugin) Version() string                                { return "v0.0.1" }
+func (p menuPlugin) Menus() []plugins.Menu                          { return p.menus }
+
+func TestEngine_PruneResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "engine")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.Config{
+		UrlPrefix: "admin",
+		Databases: config.DatabaseList{
+			"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "admin.db")},
+		},
+	}
+
+	var (
+		report = menuPlugin{name: "report", menus: []plugins.Menu{{Title: "Reports", Uri: "/reports"}}}
+		blog   = menuPlugin{name: "blog", menus: []plugins.Menu{{Title: "Posts", Uri: "/posts"}}}
+		menus  = func(eng *Engine, uri string) int {
+			rows, err := db.GetConnection(eng.Services).Query("select id from adm_menu where uri = ?", uri)
+			assert.Nil(t, err)
+			return len(rows)
+		}
+	)
+
+	first := New().AddConfig(cfg).AddPlugins(report, blog)
+	assert.Nil(t, installer.Migrate(db.GetConnection(first.Services)))
+	first.Handler()
+	assert.Equal(t, 1, menus(first, "/reports"))
+	assert.Equal(t, 1, menus(first, "/posts"))
+
+	// The resources of the removed plugins are kept by default.
+	second := New().AddConfig(cfg).AddPlugins(blog)
+	second.Handler()
+	assert.Equal(t, 1, menus(second, "/reports"))
+
+	third := New().AddConfig(cfg).PruneResources().AddPlugins(blog)
+	third.Handler()
+	assert.Equal(t, 0, menus(third, "/reports"))
+	assert.Equal(t, 1, menus(third, "/posts"))
+}
+
+func TestEngine_SlowQueryThreshold(t *testing.T) {
+	dir, err := ioutil.TempDir("", "engine")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	eng := New().AddConfig(config.Config{
+		UrlPrefix:          "admin",
+		SlowQueryThreshold: 1500,
+		Databases: config.DatabaseList{
+			"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "admin.db")},
+		},
+	})
+	assert.Equal(t, 1500*time.Millisecond, db.GetConnection(eng.Services).Stats().SlowQueryThreshold)
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/plugins"
+	"github.com/glvd/go-admin/template"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+)
+
+type blogPlugin struct {
+	app *context.App
+}
+
+func (p blogPlugin) GetRequest() []context.Path {
+	return p.app.Requests
+}
+
+func (p blogPlugin) GetHandler(url, method string) context.Handlers {
+	return plugins.GetHandler(url, method, p.app)
+}
+
+func (p blogPlugin) InitPlugin(services service.List) {}
+
+func (p blogPlugin) Name() string {
+	return "blog"
+}
+
+func (p blogPlugin) Version() string {
+	return "v0.0.1"
+}
+
+func newBlogPlugin(prefix, body string) blogPlugin {
+	app := context.NewApp()
+	app.GET(prefix+"/posts", func(ctx *context.Context) {
+		ctx.WriteString(body)
+	})
+	return blogPlugin{app: app}
+}
+
+func TestEngine_Handler(t *testing.T) {
+	first := &Engine{PluginList: []plugins.Plugin{newBlogPlugin("/first", "first")}}
+	second := &Engine{PluginList: []plugins.Plugin{newBlogPlugin("/second", "second")}}
+
+	serve := func(eng *Engine, path string) (int, string) {
+		w := httptest.NewRecorder()
+		eng.Handler().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		return w.Code, string(body)
+	}
+
+	code, body := serve(first, "/first/posts")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "first", body)
+
+	code, body = serve(second, "/second/posts")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "second", body)
+
+	code, _ = serve(first, "/second/posts")
+	assert.Equal(t, 404, code)
+}
+
+type conflictPlugin struct {
+	blogPlugin
+}
+
+func (p conflictPlugin) Conflicts() []error {
+	return p.app.Conflicts()
+}
+
+func TestEngine_Conflicts(t *testing.T) {
+	eng := &Engine{PluginList: []plugins.Plugin{newBlogPlugin("/blog", "first"), newBlogPlugin("/blog", "second")}}
+	_, err := eng.app()
+	assert.Equal(t, "route conflict: GET /blog/posts conflicts with /blog/posts", err.Error())
+	assert.Panics(t, func() { eng.Handler() })
+
+	plug := conflictPlugin{newBlogPlugin("/blog", "first")}
+	plug.app.GET("/blog/:id", func(ctx *context.Context) {})
+	plug.app.GET("/blog/:name", func(ctx *context.Context) {})
+	_, err = (&Engine{PluginList: []plugins.Plugin{plug}}).app()
+	assert.Equal(t, "blog: route conflict: GET /blog/:name conflicts with /blog/:id", err.Error())
+
+	_, err = (&Engine{PluginList: []plugins.Plugin{newBlogPlugin("/blog", "first")}}).app()
+	assert.Nil(t, err)
+}
+
+func TestEngine_Plugins(t *testing.T) {
+	first, second := New(), New()
+	first.AddPlugins(newBlogPlugin("/blog", "first"))
+	second.AddPlugins(newBlogPlugin("/blog", "second"))
+	first.manager().SetEnabled("blog", false)
+
+	serve := func(eng *Engine) int {
+		w := httptest.NewRecorder()
+		eng.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/blog/posts", nil))
+		return w.Code
+	}
+
+	assert.Equal(t, 404, serve(first))
+	assert.Equal(t, 200, serve(second))
+	assert.Equal(t, 1, len(plugins.ManagerFrom(second.Services).All()))
+	assert.True(t, plugins.Enabled("blog"))
+}
+
+func TestEngine_SetConfig(t *testing.T) {
+	global := Default().setConfig(config.Config{UrlPrefix: "admin"})
+	other := New().setConfig(config.Config{UrlPrefix: "admin2"})
+
+	assert.Equal(t, "/admin", global.Config().Prefix())
+	assert.Equal(t, "/admin2", other.Config().Prefix())
+	assert.Equal(t, "/admin", config.Get().Prefix())
+
+	assert.Equal(t, "/admin", config.GetFromServices(global.Services).Prefix())
+	assert.Equal(t, "/admin2", config.GetFromServices(other.Services).Prefix())
+}
+
+type reloadTheme struct {
+	template.Template
+}
+
+func TestEngine_ReloadConfig(t *testing.T) {
+	template.Add("reload-theme", reloadTheme{})
+
+	eng := New().setConfig(config.Config{UrlPrefix: "admin", Title: "Old", Theme: "reload-theme"})
+
+	assert.NoError(t, eng.ReloadConfig(config.Config{
+		UrlPrefix: "other",
+		Title:     "New",
+		Theme:     "reload-theme",
+	}))
+	assert.Equal(t, "New", eng.Config().Title)
+	assert.Equal(t, "/admin", eng.Config().Prefix())
+	assert.Equal(t, "New", config.GetFromServices(eng.Services).Title)
+
+	assert.Error(t, eng.ReloadConfig(config.Config{Theme: "unknown"}))
+	assert.Equal(t, "reload-theme", eng.Config().Theme)
+}
@@ -4,8 +4,9 @@ go 1.13
 
 require (
 	github.com/360EntSecGroup-Skylar/excelize v1.4.1
-	github.com/GoAdminGroup/go-admin v1.1.6 // indirect
+	github.com/GoAdminGroup/go-admin v1.1.6
 	github.com/NebulousLabs/fastrand v0.0.0-20181203155948-6fb6489aac4e
+	github.com/andybalholm/brotli v1.0.4
 	github.com/dustin/go-humanize v1.0.0
 	github.com/glvd/themes v0.0.15
 	github.com/gogf/gf v1.11.0
@@ -13,6 +14,7 @@ require (
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/magiconair/properties v1.8.1
 	github.com/mattn/go-isatty v0.0.9 // indirect
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/mgutz/ansi v0.0.0-20170206155736-9520e82c474b
 	github.com/satori/go.uuid v1.2.0
 	github.com/sirupsen/logrus v1.4.2
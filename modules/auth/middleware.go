@@ -26,6 +26,7 @@ type Invoker struct {
 	authFailCallback       MiddlewareCallback
 	permissionDenyCallback MiddlewareCallback
 	conn                   db.Connection
+	config                 *config.Service
 }
 
 // Middleware is the default auth middleware of plugins.
@@ -35,16 +36,22 @@ func Middleware(conn db.Connection) context.Handler {
 
 // DefaultInvoker return a default Invoker.
 func DefaultInvoker(conn db.Connection) *Invoker {
+	return NewInvoker(config.GlobalService(), conn)
+}
+
+// NewInvoker return a default Invoker of the config service, with which the
+// login url and the permissions are resolved.
+func NewInvoker(srv *config.Service, conn db.Connection) *Invoker {
 	return &Invoker{
-		prefix: config.Get().Prefix(),
+		prefix: srv.Get().Prefix(),
 		authFailCallback: func(ctx *context.Context) {
 			ctx.Write(302, map[string]string{
-				"Location": config.Get().Url("/login"),
+				"Location": srv.Get().Url("/login"),
 			}, ``)
 		},
 		permissionDenyCallback: func(ctx *context.Context) {
 			page.SetPageContent(ctx, Auth(ctx), func(ctx interface{}) (types.Panel, error) {
-				alert := template2.Get(config.Get().Theme).Alert().
+				alert := template2.Get(srv.Get().Theme).Alert().
 					SetTitle(template.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 					SetTheme("warning").SetContent(template.HTML("permission denied")).GetContent()
 
@@ -55,7 +62,8 @@ func DefaultInvoker(conn db.Connection) *Invoker {
 				}, nil
 			}, conn)
 		},
-		conn: conn,
+		conn:   conn,
+		config: srv,
 	}
 }
 
@@ -84,7 +92,7 @@ type MiddlewareCallback func(ctx *context.Context)
 // Middleware get the auth middleware from Invoker.
 func (invoker *Invoker) Middleware() context.Handler {
 	return func(ctx *context.Context) {
-		user, authOk, permissionOk := Filter(ctx, invoker.conn)
+		user, authOk, permissionOk := filter(ctx, invoker.conn, invoker.config.Get())
 
 		if authOk && permissionOk {
 			ctx.SetUserValue("user", user)
@@ -110,6 +118,10 @@ func (invoker *Invoker) Middleware() context.Handler {
 // Filter retrieve the user model from Context and check the permission
 // at the same time.
 func Filter(ctx *context.Context, conn db.Connection) (models.UserModel, bool, bool) {
+	return filter(ctx, conn, config.Get())
+}
+
+func filter(ctx *context.Context, conn db.Connection, cfg config.Config) (models.UserModel, bool, bool) {
 	var (
 		id   float64
 		ok   bool
@@ -126,7 +138,7 @@ func Filter(ctx *context.Context, conn db.Connection) (models.UserModel, bool, b
 		return user, false, false
 	}
 
-	return user, true, CheckPermissions(user, ctx.Request.URL.String(), ctx.Method())
+	return user, true, checkPermissions(cfg, user, ctx.Request.URL.String(), ctx.Method())
 }
 
 const defaultUserIDSesKey = "user_id"
@@ -181,8 +193,12 @@ func GetCurUserByID(id int64, conn db.Connection) (user models.UserModel, ok boo
 
 // CheckPermissions check the permission of the user.
 func CheckPermissions(user models.UserModel, path string, method string) bool {
+	return checkPermissions(config.Get(), user, path, method)
+}
+
+func checkPermissions(cfg config.Config, user models.UserModel, path string, method string) bool {
 
-	logoutCheck, _ := regexp.Compile(config.Get().Url("/logout") + "(.*?)")
+	logoutCheck, _ := regexp.Compile(cfg.Url("/logout") + "(.*?)")
 
 	if logoutCheck.MatchString(path) {
 		return true
@@ -204,7 +220,7 @@ func CheckPermissions(user models.UserModel, path string, method string) bool {
 
 			for i := 0; i < len(v.HttpPath); i++ {
 
-				matchPath := config.Get().Url(strings.TrimSpace(v.HttpPath[i]))
+				matchPath := cfg.Url(strings.TrimSpace(v.HttpPath[i]))
 
 				if len(pathArr) > 1 {
 					if pathArr[0] == matchPath && !strings.Contains(matchPath, "?") {
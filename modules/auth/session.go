@@ -152,7 +152,7 @@ func deleteOverdueSession(conn db.Connection) {
 
 	var (
 		duration = strconv.Itoa(config.Get().SessionLifeTime + 1000)
-		driver   = config.Get().Databases.GetDefault().Driver
+		driver   = conn.Name()
 		cmd      = ``
 	)
 
@@ -169,6 +169,48 @@ func deleteOverdueSession(conn db.Connection) {
 	_, _ = conn.Query(cmd)
 }
 
+// CountActiveSessions return the number of the sessions which are not
+// expired yet.
+func CountActiveSessions(conn db.Connection) (int64, error) {
+
+	var (
+		duration = strconv.Itoa(config.Get().SessionLifeTime)
+		cmd      = `select count(*) as num from adm_session`
+	)
+
+	switch conn.Name() {
+	case db.DriverPostgresql:
+		cmd += ` where extract(epoch from now()) - ` + duration + ` <= extract(epoch from created_at)`
+	case db.DriverMysql:
+		cmd += ` where unix_timestamp(created_at) >= unix_timestamp() - ` + duration
+	case db.DriverSqlite:
+		cmd += ` where strftime('%s', created_at) >= strftime('%s', 'now') - ` + duration
+	case db.DriverMssql:
+		cmd += ` where datediff(second, created_at, getdate()) <= ` + duration
+	}
+
+	res, err := conn.Query(cmd)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, nil
+	}
+
+	switch num := res[0]["num"].(type) {
+	case int64:
+		return num, nil
+	case []uint8:
+		return strconv.ParseInt(string(num), 10, 64)
+	case string:
+		return strconv.ParseInt(num, 10, 64)
+	case float64:
+		return int64(num), nil
+	}
+
+	return 0, nil
+}
+
 // Update implements the PersistenceDriver.Update.
 func (driver *DBDriver) Update(sid string, values map[string]interface{}) {
 
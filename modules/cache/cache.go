@@ -0,0 +1,151 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package cache provides a pluggable cache of the query results. The cached
+// values are tagged, usually with the table names which the values are
+// read from, so that they can be invalidated when the tables are modified.
+//
+// The cache is disabled until a Store is set by Use.
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the ttl of the cached values used when Use is called with
+// a zero ttl.
+const DefaultTTL = time.Minute
+
+// Store is a cache store.
+type Store interface {
+	// Get return the value of the key and true if the value exists and is
+	// not expired.
+	Get(key string) (interface{}, bool)
+	// Set set the value of the key with the ttl and the tags.
+	Set(key string, value interface{}, ttl time.Duration, tags ...string)
+	// Delete delete the value of the key.
+	Delete(key string)
+	// Invalidate delete all the values tagged with any of the tags.
+	Invalidate(tags ...string)
+	// Flush delete all the values.
+	Flush()
+}
+
+var (
+	lock  sync.RWMutex
+	store Store
+	ttl   = DefaultTTL
+)
+
+// Use set the store and the ttl of the cache. A nil store disables the cache.
+func Use(s Store, t time.Duration) {
+	lock.Lock()
+	defer lock.Unlock()
+	if t <= 0 {
+		t = DefaultTTL
+	}
+	store = s
+	ttl = t
+}
+
+// Enabled return true if a store is set.
+func Enabled() bool {
+	return current() != nil
+}
+
+func current() Store {
+	lock.RLock()
+	defer lock.RUnlock()
+	return store
+}
+
+// Get return the value of the key from the store.
+func Get(key string) (interface{}, bool) {
+	if s := current(); s != nil {
+		return s.Get(key)
+	}
+	return nil, false
+}
+
+// Set set the value of the key with the default ttl and the tags.
+func Set(key string, value interface{}, tags ...string) {
+	if s := current(); s != nil {
+		lock.RLock()
+		t := ttl
+		lock.RUnlock()
+		s.Set(key, value, t, tags...)
+	}
+}
+
+// Delete delete the value of the key.
+func Delete(key string) {
+	if s := current(); s != nil {
+		s.Delete(key)
+	}
+}
+
+// Invalidate delete all the values tagged with any of the tags.
+func Invalidate(tags ...string) {
+	if s := current(); s != nil && len(tags) > 0 {
+		s.Invalidate(tags...)
+	}
+}
+
+// Flush delete all the values.
+func Flush() {
+	if s := current(); s != nil {
+		s.Flush()
+	}
+}
+
+// Remember return the cached value of the key. If it is missing, fn is
+// called and the result is cached with the tags unless fn returns an error.
+func Remember(key string, tags []string, fn func() (interface{}, error)) (interface{}, error) {
+	if value, ok := Get(key); ok {
+		return value, nil
+	}
+	value, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	Set(key, value, tags...)
+	return value, nil
+}
+
+// Key join the parts into a cache key.
+func Key(parts ...interface{}) string {
+	s := make([]string, len(parts))
+	for i, part := range parts {
+		s[i] = fmt.Sprintf("%v", part)
+	}
+	return strings.Join(s, "\x00")
+}
+
+// TableTag return the tag of the values read from the table of the
+// connection. The connection is a namespace of the tables, so the same
+// table name of different connections are tagged differently.
+func TableTag(conn, table string) string {
+	return "table:" + conn + ":" + strings.ToLower(strings.TrimSpace(table))
+}
+
+// TableTags return the tags of the values read from the tables of the
+// connection.
+func TableTags(conn string, tables ...string) []string {
+	tags := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if table != "" {
+			tags = append(tags, TableTag(conn, table))
+		}
+	}
+	return tags
+}
+
+// InvalidateTables delete all the values read from any of the tables of
+// the connection.
+func InvalidateTables(conn string, tables ...string) {
+	Invalidate(TableTags(conn, tables...)...)
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	m := NewMemoryStore()
+
+	m.Set("a", 1, time.Minute, TableTag("default", "users"))
+	m.Set("b", 2, time.Minute, TableTag("default", "users"), TableTag("default", "roles"))
+	m.Set("c", 3, time.Minute, TableTag("default", "menu"))
+	m.Set("d", 4, -time.Second)
+
+	v, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	_, ok = m.Get("d")
+	assert.False(t, ok)
+
+	m.Invalidate(TableTag("default", "roles"))
+	_, ok = m.Get("b")
+	assert.False(t, ok)
+	_, ok = m.Get("a")
+	assert.True(t, ok)
+
+	m.Invalidate(TableTag("default", "Users"))
+	_, ok = m.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.Len())
+
+	m.Flush()
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestRemember(t *testing.T) {
+	Use(nil, 0)
+	assert.False(t, Enabled())
+
+	calls := 0
+	fn := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	v, _ := Remember("key", TableTags("default", "users"), fn)
+	assert.Equal(t, 1, v)
+	v, _ = Remember("key", TableTags("default", "users"), fn)
+	assert.Equal(t, 2, v)
+
+	Use(NewMemoryStore(), time.Minute)
+	defer Use(nil, 0)
+
+	v, _ = Remember("key", TableTags("default", "users"), fn)
+	assert.Equal(t, 3, v)
+	v, _ = Remember("key", TableTags("default", "users"), fn)
+	assert.Equal(t, 3, v)
+
+	InvalidateTables("other", "users")
+	v, _ = Remember("key", TableTags("default", "users"), fn)
+	assert.Equal(t, 3, v)
+
+	InvalidateTables("default", "users")
+	v, _ = Remember("key", TableTags("default", "users"), fn)
+	assert.Equal(t, 4, v)
+
+	_, err := Remember("error", nil, func() (interface{}, error) {
+		return nil, errors.New("query error")
+	})
+	assert.NotNil(t, err)
+	_, ok := Get("error")
+	assert.False(t, ok)
+}
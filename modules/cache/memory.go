@@ -0,0 +1,134 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store keeping the values in memory. The expired values
+// are removed when they are got or when the store is swept.
+type MemoryStore struct {
+	lock  sync.Mutex
+	items map[string]memoryItem
+	tags  map[string]map[string]struct{}
+	sets  int
+}
+
+type memoryItem struct {
+	value   interface{}
+	expires time.Time
+	tags    []string
+}
+
+// sweepEvery is the number of the Set calls between the sweeps.
+const sweepEvery = 1000
+
+// NewMemoryStore return an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		items: make(map[string]memoryItem),
+		tags:  make(map[string]map[string]struct{}),
+	}
+}
+
+// Get implements the Store.Get.
+func (m *MemoryStore) Get(key string) (interface{}, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(item.expires) {
+		m.delete(key)
+		return nil, false
+	}
+	return item.value, true
+}
+
+// Set implements the Store.Set.
+func (m *MemoryStore) Set(key string, value interface{}, ttl time.Duration, tags ...string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.delete(key)
+	m.items[key] = memoryItem{
+		value:   value,
+		expires: time.Now().Add(ttl),
+		tags:    tags,
+	}
+	for _, tag := range tags {
+		keys, ok := m.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			m.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	if m.sets++; m.sets >= sweepEvery {
+		m.sets = 0
+		m.sweep()
+	}
+}
+
+// Delete implements the Store.Delete.
+func (m *MemoryStore) Delete(key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.delete(key)
+}
+
+// Invalidate implements the Store.Invalidate.
+func (m *MemoryStore) Invalidate(tags ...string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, tag := range tags {
+		for key := range m.tags[tag] {
+			m.delete(key)
+		}
+	}
+}
+
+// Flush implements the Store.Flush.
+func (m *MemoryStore) Flush() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.items = make(map[string]memoryItem)
+	m.tags = make(map[string]map[string]struct{})
+}
+
+// Len return the number of the values, including the expired ones which
+// are not removed yet.
+func (m *MemoryStore) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.items)
+}
+
+func (m *MemoryStore) delete(key string) {
+	item, ok := m.items[key]
+	if !ok {
+		return
+	}
+	delete(m.items, key)
+	for _, tag := range item.tags {
+		if keys, ok := m.tags[tag]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(m.tags, tag)
+			}
+		}
+	}
+}
+
+func (m *MemoryStore) sweep() {
+	now := time.Now()
+	for key, item := range m.items {
+		if now.After(item.expires) {
+			m.delete(key)
+		}
+	}
+}
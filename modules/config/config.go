@@ -5,17 +5,13 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/glvd/go-admin/modules/logger"
 	"github.com/glvd/go-admin/plugins/admin/modules/constant"
-	"gopkg.in/ini.v1"
-	"gopkg.in/yaml.v2"
 	"html/template"
-	"io/ioutil"
+	"sort"
 	"strings"
 	"sync"
-	"sync/atomic"
 )
 
 // Database is a type of database connection config.
@@ -28,6 +24,9 @@ import (
 // If the Dsn is configured, when driver is mysql/postgresql/
 // mssql, the other configurations will be ignored, except for
 // MaxIdleCon and MaxOpenCon.
+//
+// ReplicaOf marks the connection as a read replica of the named
+// connection, the selections of the primary will be routed to it.
 type Database struct {
 	Host       string `json:"host",yaml:"host",ini:"host"`
 	Port       string `json:"port",yaml:"port",ini:"port"`
@@ -39,6 +38,7 @@ type Database struct {
 	Driver     string `json:"driver",yaml:"driver",ini:"driver"`
 	File       string `json:"file",yaml:"file",ini:"file"`
 	Dsn        string `json:"dsn",yaml:"dsn",ini:"dsn"`
+	ReplicaOf  string `json:"replica_of" yaml:"replica_of" ini:"replica_of"`
 }
 
 // DatabaseList is a map of Database.
@@ -54,6 +54,18 @@ func (d DatabaseList) Add(key string, db Database) {
 	d[key] = db
 }
 
+// Replicas return the sorted names of the replicas of the given connection.
+func (d DatabaseList) Replicas(name string) []string {
+	replicas := make([]string, 0)
+	for key, item := range d {
+		if item.ReplicaOf == name && key != name {
+			replicas = append(replicas, key)
+		}
+	}
+	sort.Strings(replicas)
+	return replicas
+}
+
 // GroupByDriver group the Databases with the drivers.
 func (d DatabaseList) GroupByDriver() map[string]DatabaseList {
 	drivers := make(map[string]DatabaseList)
@@ -93,6 +105,58 @@ type Store struct {
 	Prefix string
 }
 
+// Middleware is the config of the built-in middleware, see the package
+// modules/middleware. The middleware are disabled by default.
+type Middleware struct {
+	// Recover the panics and log them with the stack.
+	Recovery bool `json:"recovery" yaml:"recovery" ini:"recovery"`
+
+	// Accept or generate the X-Request-Id of the requests, which is
+	// responded and written into the logs.
+	RequestID bool `json:"request_id" yaml:"request_id" ini:"request_id"`
+
+	// Compress the responses with brotli, gzip, deflate or the registered
+	// encoders.
+	Compress bool `json:"compress" yaml:"compress" ini:"compress"`
+
+	// The compression level, zero means the default level.
+	CompressLevel int `json:"compress_level" yaml:"compress_level" ini:"compress_level"`
+
+	// Set the security headers of the responses.
+	SecurityHeaders bool `json:"security_headers" yaml:"security_headers" ini:"security_headers"`
+
+	// The Content-Security-Policy header, empty means not set.
+	ContentSecurityPolicy string `json:"content_security_policy" yaml:"content_security_policy" ini:"content_security_policy"`
+
+	// The max age of the Strict-Transport-Security header in seconds, which
+	// is set for the https requests only. Zero means not set.
+	HSTSMaxAge int `json:"hsts_max_age" yaml:"hsts_max_age" ini:"hsts_max_age"`
+
+	// The X-Frame-Options header, default "SAMEORIGIN".
+	FrameOptions string `json:"frame_options" yaml:"frame_options" ini:"frame_options"`
+
+	// Handle the cross-origin requests.
+	CORS CORS `json:"cors" yaml:"cors" ini:"cors"`
+
+	// The ips or the CIDRs of the trusted proxies, whose X-Forwarded-For
+	// headers are respected when getting the client ip. Empty means none:
+	// the headers are ignored and the remote address is used, so the
+	// proxies in front of the server must be listed.
+	TrustedProxies []string `json:"trusted_proxies" yaml:"trusted_proxies" ini:"trusted_proxies"`
+}
+
+// CORS is the config of the cross-origin resource sharing. It is enabled
+// when AllowOrigins is not empty, "*" allows all the origins.
+type CORS struct {
+	AllowOrigins     []string `json:"allow_origins" yaml:"allow_origins" ini:"allow_origins"`
+	AllowMethods     []string `json:"allow_methods" yaml:"allow_methods" ini:"allow_methods"`
+	AllowHeaders     []string `json:"allow_headers" yaml:"allow_headers" ini:"allow_headers"`
+	ExposeHeaders    []string `json:"expose_headers" yaml:"expose_headers" ini:"expose_headers"`
+	AllowCredentials bool     `json:"allow_credentials" yaml:"allow_credentials" ini:"allow_credentials"`
+	// The max age of the preflight results in seconds.
+	MaxAge int `json:"max_age" yaml:"max_age" ini:"max_age"`
+}
+
 // Config type is the global config of goAdmin. It will be
 // initialized in the engine.
 type Config struct {
@@ -148,6 +212,10 @@ type Config struct {
 	// Sql operator record log switch.
 	SqlLog bool `json:"sql_log",yaml:"sql_log",ini:"sql_log"`
 
+	// The statements which take longer than the threshold are recorded
+	// as slow queries, units are milliseconds.
+	SlowQueryThreshold int `json:"slow_query_threshold" yaml:"slow_query_threshold" ini:"slow_query_threshold"`
+
 	AccessLogOff bool `json:"access_log_off",yaml:"access_log_off",ini:"access_log_off"`
 	InfoLogOff   bool `json:"info_log_off",yaml:"info_log_off",ini:"info_log_off"`
 	ErrorLogOff  bool `json:"error_log_off",yaml:"error_log_off",ini:"error_log_off"`
@@ -176,6 +244,9 @@ type Config struct {
 	// Login page logo
 	LoginLogo template.HTML `json:"login_logo",yaml:"login_logo",ini:"login_logo"`
 
+	// The built-in middleware of the routes.
+	Middleware Middleware `json:"middleware" yaml:"middleware" ini:"middleware"`
+
 	prefix string
 }
 
@@ -267,84 +338,43 @@ func (c Config) PrefixFixSlash() string {
 	return c.UrlPrefix
 }
 
-var (
-	globalCfg Config
-	declare   sync.Once
-)
+var declare sync.Once
 
-// ReadFromJson read the Config from a JSON file.
+// ReadFromJson read the Config from a JSON file, it panics on errors.
+// See LoadJSON.
 func ReadFromJson(path string) Config {
-	jsonByte, err := ioutil.ReadFile(path)
-
-	if err != nil {
-		panic(err)
-	}
-
-	var cfg Config
-
-	err = json.Unmarshal(jsonByte, &cfg)
-
+	cfg, err := LoadJSON(path)
 	if err != nil {
 		panic(err)
 	}
-
 	return cfg
 }
 
-// ReadFromYaml read the Config from a YAML file.
+// ReadFromYaml read the Config from a YAML file, it panics on errors.
+// See LoadYAML.
 func ReadFromYaml(path string) Config {
-	jsonByte, err := ioutil.ReadFile(path)
-
+	cfg, err := LoadYAML(path)
 	if err != nil {
 		panic(err)
 	}
-
-	var cfg Config
-
-	err = yaml.Unmarshal(jsonByte, &cfg)
-
-	if err != nil {
-		panic(err)
-	}
-
 	return cfg
 }
 
-// ReadFromINI read the Config from a INI file.
+// ReadFromINI read the Config from a INI file, it panics on errors.
+// See LoadINI.
 func ReadFromINI(path string) Config {
-	iniCfg, err := ini.Load(path)
-
-	if err != nil {
-		panic(err)
-	}
-
-	var cfg Config
-
-	err = iniCfg.MapTo(&cfg)
-
+	cfg, err := LoadINI(path)
 	if err != nil {
 		panic(err)
 	}
-
 	return cfg
 }
 
-var (
-	count uint32
-	lock  sync.Mutex
-)
-
-// Set sets the config.
-func Set(cfg Config) Config {
-
-	lock.Lock()
-	defer lock.Unlock()
-
-	if atomic.LoadUint32(&count) != 0 {
-		panic("can not set config twice")
-	}
-	atomic.StoreUint32(&count, 1)
+var lock sync.Mutex
 
+// Normalize fill the empty options of the config with the defaults and
+// return it, without changing the global config.
+func Normalize(cfg Config) Config {
 	cfg.Title = setDefault(cfg.Title, "", constant.Title)
 	cfg.LoginTitle = setDefault(cfg.LoginTitle, "", constant.Title)
 	cfg.Logo = template.HTML(setDefault(string(cfg.Logo), "", "<b>Go</b>Admin"))
@@ -367,6 +397,20 @@ func Set(cfg Config) Config {
 		cfg.prefix = cfg.UrlPrefix
 	}
 
+	return cfg
+}
+
+// Set normalize the config and set it as the global config, which is used
+// by the default engine, and the loggers are set up with it. Setting it
+// again replaces the global config, the other engines keep their configs
+// without the global one, see Normalize.
+func Set(cfg Config) Config {
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	cfg = Normalize(cfg)
+
 	logger.SetInfoLogger(cfg.InfoLogPath, cfg.Debug, cfg.InfoLogOff)
 	logger.SetErrorLogger(cfg.ErrorLogPath, cfg.Debug, cfg.ErrorLogOff)
 	logger.SetAccessLogger(cfg.AccessLogPath, cfg.Debug, cfg.AccessLogOff)
@@ -383,7 +427,7 @@ Running in "debug" mode. Switch to "release" mode in production.`)
 		})
 	}
 
-	globalCfg = cfg
+	global.Set(cfg)
 	eraseSens()
 
 	return cfg
@@ -391,12 +435,12 @@ Running in "debug" mode. Switch to "release" mode in production.`)
 
 // Get gets the config.
 func Get() Config {
-	return globalCfg
+	return global.Get()
 }
 
 // eraseSens erase sensitive info.
 func eraseSens() {
-	for _, d := range globalCfg.Databases {
+	for _, d := range Get().Databases {
 		d.Host = ""
 		d.Port = ""
 		d.User = ""
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/glvd/go-admin/modules/service"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -105,5 +106,37 @@ func TestSetDefault(t *testing.T) {
 func TestSet(t *testing.T) {
 	Set(Config{Theme: "abc"})
 	Set(Config{Theme: "bcd"})
-	assert.Equal(t, Get().Theme, "abc")
+	assert.Equal(t, Get().Theme, "bcd")
+}
+
+func TestNormalize(t *testing.T) {
+	Set(Config{Theme: "abc"})
+
+	cfg := Normalize(Config{UrlPrefix: "admin2"})
+
+	assert.Equal(t, "/admin2", cfg.Prefix())
+	assert.Equal(t, "adminlte", cfg.Theme)
+	assert.Equal(t, "/info/manager", cfg.IndexUrl)
+	assert.Equal(t, "abc", Get().Theme)
+}
+
+func TestGetFromServices(t *testing.T) {
+	Set(Config{UrlPrefix: "admin"})
+
+	assert.Equal(t, "/admin", GetFromServices(service.List{}).Prefix())
+
+	srv := service.List{ServiceName: NewService(Normalize(Config{UrlPrefix: "other"}))}
+	assert.Equal(t, "/other", GetFromServices(srv).Prefix())
+}
+
+func TestDatabaseList_Replicas(t *testing.T) {
+	list := DatabaseList{
+		"default":   {Driver: "mysql"},
+		"replica_b": {Driver: "mysql", ReplicaOf: "default"},
+		"replica_a": {Driver: "mysql", ReplicaOf: "default"},
+		"other":     {Driver: "mysql"},
+	}
+
+	assert.Equal(t, []string{"replica_a", "replica_b"}, list.Replicas("default"))
+	assert.Equal(t, []string{}, list.Replicas("other"))
 }
@@ -0,0 +1,478 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/glvd/go-admin/modules/logger"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the
+// options of the config, see FromEnv.
+var EnvPrefix = "GOADMIN"
+
+// LoadJSON read the Config from a JSON file.
+func LoadJSON(path string) (Config, error) {
+	var cfg Config
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config file %s: %s", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadYAML read the Config from a YAML file.
+func LoadYAML(path string) (Config, error) {
+	var cfg Config
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config file %s: %s", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadINI read the Config from a INI file.
+func LoadINI(path string) (Config, error) {
+	var cfg Config
+	iniCfg, err := ini.Load(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := iniCfg.MapTo(&cfg); err != nil {
+		return cfg, fmt.Errorf("parse config file %s: %s", path, err)
+	}
+	return cfg, nil
+}
+
+// Load read the Config from the file of the path, whose format is got from
+// the extension: .json, .yml, .yaml or .ini. The options are overridden
+// by the environment variables, see FromEnv, and then the Config is
+// validated, see Config.Validate.
+func Load(path string) (Config, error) {
+	var (
+		cfg Config
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		cfg, err = LoadJSON(path)
+	case ".yml", ".yaml":
+		cfg, err = LoadYAML(path)
+	case ".ini":
+		cfg, err = LoadINI(path)
+	default:
+		return cfg, fmt.Errorf("unknown config file format: %s", path)
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if cfg, err = FromEnv(cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// FromEnv override the options of the config with the environment
+// variables. The name of a variable is the EnvPrefix and the upper case
+// json names of the options joined by "_", such as GOADMIN_TITLE,
+// GOADMIN_MIDDLEWARE_CORS_ALLOW_ORIGINS and GOADMIN_DATABASE_DEFAULT_HOST,
+// in which "default" is the name of the connection. The string slices are
+// separated by commas.
+func FromEnv(cfg Config) (Config, error) {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 && strings.HasPrefix(kv[:i], EnvPrefix+"_") {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+	if len(env) == 0 {
+		return cfg, nil
+	}
+	err := setFromEnv(reflect.ValueOf(&cfg).Elem(), EnvPrefix, env)
+	return cfg, err
+}
+
+var databaseListType = reflect.TypeOf(DatabaseList{})
+
+func setFromEnv(v reflect.Value, prefix string, env map[string]string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = envName(field.Name)
+		}
+		key := prefix + "_" + strings.ToUpper(name)
+
+		value := v.Field(i)
+		switch {
+		case value.Type() == databaseListType:
+			list, err := databasesFromEnv(value.Interface().(DatabaseList), key, env)
+			if err != nil {
+				return err
+			}
+			value.Set(reflect.ValueOf(list))
+		case value.Kind() == reflect.Struct:
+			if err := setFromEnv(value, key, env); err != nil {
+				return err
+			}
+		default:
+			s, ok := env[key]
+			if !ok {
+				continue
+			}
+			if err := setEnvValue(value, s); err != nil {
+				return fmt.Errorf("environment variable %s: %s", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// envName return the snake case name of the field without the tag, such
+// as FileUploadEngine to file_upload_engine.
+func envName(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func setEnvValue(value reflect.Value, s string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", value.Type())
+		}
+		list := make([]string, 0)
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		value.Set(reflect.ValueOf(list).Convert(value.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// databaseFields is the fields of Database sorted by the length of the
+// names in descending order, so that the longest field suffix matches.
+var databaseFields = func() []reflect.StructField {
+	t := reflect.TypeOf(Database{})
+	fields := make([]reflect.StructField, t.NumField())
+	for i := range fields {
+		fields[i] = t.Field(i)
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return len(fields[i].Tag.Get("json")) > len(fields[j].Tag.Get("json"))
+	})
+	return fields
+}()
+
+func databasesFromEnv(list DatabaseList, prefix string, env map[string]string) (DatabaseList, error) {
+	keys := make([]string, 0)
+	for key := range env {
+		if strings.HasPrefix(key, prefix+"_") {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return list, nil
+	}
+	sort.Strings(keys)
+
+	res := make(DatabaseList, len(list))
+	for name, d := range list {
+		res[name] = d
+	}
+
+	for _, key := range keys {
+		rest := strings.TrimPrefix(key, prefix+"_")
+		matched := false
+		for _, field := range databaseFields {
+			suffix := "_" + strings.ToUpper(strings.Split(field.Tag.Get("json"), ",")[0])
+			if !strings.HasSuffix(rest, suffix) || len(rest) == len(suffix) {
+				continue
+			}
+			name := databaseName(res, strings.TrimSuffix(rest, suffix))
+			d := res[name]
+			if err := setEnvValue(reflect.ValueOf(&d).Elem().FieldByIndex(field.Index), env[key]); err != nil {
+				return list, fmt.Errorf("environment variable %s: %s", key, err)
+			}
+			res[name] = d
+			matched = true
+			break
+		}
+		if !matched {
+			return list, fmt.Errorf("environment variable %s: unknown database option", key)
+		}
+	}
+	return res, nil
+}
+
+// databaseName return the name of the connection matching the upper case
+// name case-insensitively, or the lower case name for a new connection.
+func databaseName(list DatabaseList, name string) string {
+	for key := range list {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return strings.ToLower(name)
+}
+
+// ValidationError is an invalid option of the config.
+type ValidationError struct {
+	Option  string
+	Message string
+}
+
+// Error implements the error.
+func (e ValidationError) Error() string {
+	return e.Option + ": " + e.Message
+}
+
+// ValidationErrors is the invalid options of the config.
+type ValidationErrors []ValidationError
+
+// Error implements the error.
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// Validate check the options of the config, and return ValidationErrors
+// if any of them is invalid. The empty options are valid, which are filled
+// with the defaults.
+func (c Config) Validate() error {
+	errs := make(ValidationErrors, 0)
+	add := func(option, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Option: option, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(c.Databases) > 0 {
+		if _, ok := c.Databases["default"]; !ok {
+			add("database", `the "default" connection is missing`)
+		}
+	}
+	names := make([]string, 0, len(c.Databases))
+	for name := range c.Databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d := c.Databases[name]
+		option := "database." + name
+		switch d.Driver {
+		case DriverMysql, DriverPostgresql, DriverMssql:
+			if d.Name == "" && d.Dsn == "" {
+				add(option+".name", "the database name or the dsn is required")
+			}
+		case DriverSqlite:
+			if d.File == "" && d.Dsn == "" {
+				add(option+".file", "the file or the dsn is required by sqlite")
+			}
+		case "":
+			add(option+".driver", "the driver is required")
+		default:
+			add(option+".driver", "unknown driver %q, should be one of mysql, postgresql, sqlite and mssql", d.Driver)
+		}
+		if d.MaxIdleCon < 0 {
+			add(option+".max_idle_con", "should not be negative")
+		}
+		if d.MaxOpenCon < 0 {
+			add(option+".max_open_con", "should not be negative")
+		}
+		if d.ReplicaOf != "" {
+			if d.ReplicaOf == name {
+				add(option+".replica_of", "the connection can not be a replica of itself")
+			} else if _, ok := c.Databases[d.ReplicaOf]; !ok {
+				add(option+".replica_of", "the connection %q is not found", d.ReplicaOf)
+			}
+		}
+	}
+
+	switch c.Env {
+	case "", EnvTest, EnvLocal, EnvProd:
+	default:
+		add("env", "unknown environment %q, should be one of test, local and prod", c.Env)
+	}
+	if strings.ContainsAny(c.UrlPrefix, "?# ") {
+		add("prefix", "the url prefix %q should not contain '?', '#' or spaces", c.UrlPrefix)
+	}
+	if c.SessionLifeTime < 0 {
+		add("session_life_time", "should not be negative")
+	}
+	if c.SlowQueryThreshold < 0 {
+		add("slow_query_threshold", "should not be negative")
+	}
+	if level := c.Middleware.CompressLevel; level < -2 || level > 9 {
+		add("middleware.compress_level", "should be between -2 and 9")
+	}
+	if c.Middleware.HSTSMaxAge < 0 {
+		add("middleware.hsts_max_age", "should not be negative")
+	}
+	if c.Middleware.CORS.MaxAge < 0 {
+		add("middleware.cors.max_age", "should not be negative")
+	}
+	for _, proxy := range c.Middleware.TrustedProxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			add("middleware.trusted_proxies", "%q is neither an ip nor a CIDR", proxy)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Reloadable return the current config with the options which are safe to
+// change at runtime replaced by the next: the titles, the logos, the theme,
+// the color scheme, the log paths and the session lifetime. The other
+// options take effect after restarting.
+func Reloadable(current, next Config) Config {
+	current.Title = next.Title
+	current.LoginTitle = next.LoginTitle
+	current.Logo = next.Logo
+	current.MiniLogo = next.MiniLogo
+	current.LoginLogo = next.LoginLogo
+	current.Theme = next.Theme
+	current.ColorScheme = next.ColorScheme
+	current.InfoLogPath = next.InfoLogPath
+	current.ErrorLogPath = next.ErrorLogPath
+	current.AccessLogPath = next.AccessLogPath
+	current.SessionLifeTime = next.SessionLifeTime
+	return Normalize(current)
+}
+
+// Watcher watch the config file and call the callback with the loaded
+// config when the file is changed.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	callback func(Config)
+	stop     chan struct{}
+	once     sync.Once
+
+	modTime time.Time
+	size    int64
+}
+
+// Watch start to check the modification time and the size of the config
+// file every interval, and call the callback with the config loaded by
+// Load when they are changed. If the file can not be loaded, the error is
+// logged and the callback is not called.
+func Watch(path string, interval time.Duration, callback func(Config)) *Watcher {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	w := &Watcher{
+		path:     path,
+		interval: interval,
+		callback: callback,
+		stop:     make(chan struct{}),
+	}
+	w.changed()
+	go w.run()
+	return w
+}
+
+// Stop stop watching the file.
+func (w *Watcher) Stop() {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+}
+
+func (w *Watcher) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if !w.changed() {
+				continue
+			}
+			cfg, err := Load(w.path)
+			if err != nil {
+				logger.Error("reload config error: ", err)
+				continue
+			}
+			w.callback(cfg)
+		}
+	}
+}
+
+// changed return true if the modification time or the size of the file is
+// changed since the last check.
+func (w *Watcher) changed() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	return true
+}
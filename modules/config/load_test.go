@@ -0,0 +1,182 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "goadmin-config")
+	assert.NoError(t, err)
+	return dir
+}
+
+func writeConfigFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	path := writeConfigFile(t, dir, "config.json", `{
+		"database": {"default": {"driver": "sqlite", "file": "admin.db"}},
+		"title": "GoAdmin",
+		"prefix": "admin"
+	}`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "GoAdmin", cfg.Title)
+	assert.Equal(t, "admin.db", cfg.Databases["default"].File)
+
+	_, err = Load(writeConfigFile(t, dir, "config.json", `{"title": `))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfigFile(t, dir, "config.toml", ``))
+	assert.Error(t, err)
+
+	_, err = Load(filepath.Join(os.TempDir(), "not-exist.json"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfigFile(t, dir, "config.json", `{"database": {"default": {"driver": "oracle"}}}`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "database.default.driver")
+}
+
+func TestFromEnv(t *testing.T) {
+	envs := map[string]string{
+		"GOADMIN_TITLE":                         "From Env",
+		"GOADMIN_DEBUG":                         "true",
+		"GOADMIN_SESSION_LIFE_TIME":             "60",
+		"GOADMIN_DATABASE_DEFAULT_HOST":         "db.local",
+		"GOADMIN_DATABASE_DEFAULT_MAX_IDLE_CON": "5",
+		"GOADMIN_DATABASE_READER_REPLICA_OF":    "default",
+		"GOADMIN_MIDDLEWARE_CORS_ALLOW_ORIGINS": "https://a.com, https://b.com",
+		"GOADMIN_STORE_PATH":                    "./uploads",
+	}
+	for key, value := range envs {
+		assert.NoError(t, os.Setenv(key, value))
+	}
+	defer func() {
+		for key := range envs {
+			_ = os.Unsetenv(key)
+		}
+	}()
+
+	cfg, err := FromEnv(Config{
+		Title:     "GoAdmin",
+		Databases: DatabaseList{"default": {Driver: DriverMysql, Host: "127.0.0.1", Name: "goadmin"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "From Env", cfg.Title)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 60, cfg.SessionLifeTime)
+	assert.Equal(t, "db.local", cfg.Databases["default"].Host)
+	assert.Equal(t, "goadmin", cfg.Databases["default"].Name)
+	assert.Equal(t, 5, cfg.Databases["default"].MaxIdleCon)
+	assert.Equal(t, "default", cfg.Databases["reader"].ReplicaOf)
+	assert.Equal(t, []string{"https://a.com", "https://b.com"}, cfg.Middleware.CORS.AllowOrigins)
+	assert.Equal(t, "./uploads", cfg.Store.Path)
+
+	assert.NoError(t, os.Setenv("GOADMIN_SESSION_LIFE_TIME", "one hour"))
+	_, err = FromEnv(Config{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "GOADMIN_SESSION_LIFE_TIME")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{
+		Databases: DatabaseList{
+			"default": {Driver: DriverMysql, Name: "goadmin"},
+			"reader":  {Driver: DriverMysql, Name: "goadmin", ReplicaOf: "default"},
+		},
+		Env: EnvLocal,
+		Middleware: Middleware{
+			TrustedProxies: []string{"10.0.0.1", "192.168.0.0/16"},
+		},
+	}.Validate())
+
+	err := Config{
+		Databases: DatabaseList{
+			"main":   {Driver: DriverSqlite},
+			"reader": {Driver: DriverMysql, Name: "goadmin", ReplicaOf: "reader"},
+		},
+		Env:             "dev",
+		SessionLifeTime: -1,
+		Middleware: Middleware{
+			CompressLevel:  10,
+			TrustedProxies: []string{"proxy"},
+		},
+	}.Validate()
+
+	errs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+
+	options := make([]string, len(errs))
+	for i, e := range errs {
+		options[i] = e.Option
+	}
+	assert.Equal(t, []string{
+		"database",
+		"database.main.file",
+		"database.reader.replica_of",
+		"env",
+		"session_life_time",
+		"middleware.compress_level",
+		"middleware.trusted_proxies",
+	}, options)
+}
+
+func TestReloadable(t *testing.T) {
+	current := Normalize(Config{
+		UrlPrefix: "admin",
+		Title:     "Old",
+		Theme:     "adminlte",
+		Databases: DatabaseList{"default": {Driver: DriverSqlite, File: "admin.db"}},
+	})
+
+	cfg := Reloadable(current, Config{
+		UrlPrefix:       "other",
+		Title:           "New",
+		ColorScheme:     "skin-blue",
+		SessionLifeTime: 60,
+		Databases:       DatabaseList{"default": {Driver: DriverMysql}},
+	})
+
+	assert.Equal(t, "New", cfg.Title)
+	assert.Equal(t, "skin-blue", cfg.ColorScheme)
+	assert.Equal(t, 60, cfg.SessionLifeTime)
+	assert.Equal(t, "adminlte", cfg.Theme)
+	assert.Equal(t, "/admin", cfg.Prefix())
+	assert.Equal(t, DriverSqlite, cfg.Databases["default"].Driver)
+}
+
+func TestWatch(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	path := writeConfigFile(t, dir, "config.json", `{"title": "Old"}`)
+
+	reloaded := make(chan Config, 1)
+	w := Watch(path, 10*time.Millisecond, func(cfg Config) {
+		reloaded <- cfg
+	})
+	defer w.Stop()
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"title": "New Title"}`), 0644))
+
+	select {
+	case cfg := <-reloaded:
+		assert.Equal(t, "New Title", cfg.Title)
+	case <-time.After(2 * time.Second):
+		t.Fatal("config is not reloaded")
+	}
+}
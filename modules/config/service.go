@@ -0,0 +1,77 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"github.com/glvd/go-admin/modules/service"
+	"sync/atomic"
+)
+
+// ServiceName is the key of the config service in the service list.
+const ServiceName = "config"
+
+// Service is the service carrying the config of an engine, with which the
+// plugins get the config of the engine they are added to. The config can
+// be replaced when it is reloaded, so the holders of the service should
+// get the config with Get when they use it.
+type Service struct {
+	value atomic.Value
+}
+
+// Name implements the service.Service.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// Get return the current config.
+func (s *Service) Get() Config {
+	if cfg, ok := s.value.Load().(Config); ok {
+		return cfg
+	}
+	return Config{}
+}
+
+// Set replace the config.
+func (s *Service) Set(cfg Config) {
+	s.value.Store(cfg)
+}
+
+// NewService return the service of the config.
+func NewService(cfg Config) *Service {
+	s := new(Service)
+	s.Set(cfg)
+	return s
+}
+
+// global is the service of the global config.
+var global = new(Service)
+
+// GlobalService return the service of the global config, see Set.
+func GlobalService() *Service {
+	return global
+}
+
+// GetService return the config of the service.
+func GetService(s interface{}) Config {
+	if srv, ok := s.(*Service); ok {
+		return srv.Get()
+	}
+	panic("wrong service")
+}
+
+// ServiceFrom return the config service of the services, or the service
+// of the global config if the services do not carry one.
+func ServiceFrom(l service.List) *Service {
+	if srv, ok := l[ServiceName].(*Service); ok {
+		return srv
+	}
+	return global
+}
+
+// GetFromServices return the config of the services, or the global config
+// if the services do not carry one.
+func GetFromServices(l service.List) Config {
+	return ServiceFrom(l).Get()
+}
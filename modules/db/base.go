@@ -2,17 +2,48 @@ package db
 
 import (
 	"database/sql"
+	"strconv"
 	"sync"
+	"sync/atomic"
 )
 
 // Base is a common Connection.
 type Base struct {
 	DbList map[string]*sql.DB
 	Once   sync.Once
+
+	replicas map[string]*replicaSet
+	stopLock sync.Mutex
+	stop     chan struct{}
+
+	statsOnce     sync.Once
+	statsRecorder *statsRecorder
+
+	cacheOnce sync.Once
+	cacheID   string
+}
+
+// connectionCount is the count of the connections which have got a cache id.
+var connectionCount uint64
+
+// CacheID return the identity of the connection in the cache, so that the
+// cached values of the connections of different engines are told apart
+// even if the connections have the same names.
+func (db *Base) CacheID() string {
+	db.cacheOnce.Do(func() {
+		db.cacheID = strconv.FormatUint(atomic.AddUint64(&connectionCount, 1), 10)
+	})
+	return db.cacheID
 }
 
 // Close implements the method Connection.Close.
 func (db *Base) Close() []error {
+	db.stopLock.Lock()
+	if db.stop != nil {
+		close(db.stop)
+		db.stop = nil
+	}
+	db.stopLock.Unlock()
 	errs := make([]error, 0)
 	for _, d := range db.DbList {
 		errs = append(errs, d.Close())
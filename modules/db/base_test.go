@@ -0,0 +1,27 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestBase_Close(t *testing.T) {
+	db := &Base{DbList: map[string]*sql.DB{}, stop: make(chan struct{})}
+	stop := db.stop
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db.Close()
+		}()
+	}
+	wg.Wait()
+
+	_, open := <-stop
+	assert.False(t, open)
+	assert.Nil(t, db.stop)
+}
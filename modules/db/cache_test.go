@@ -0,0 +1,56 @@
+package db
+
+import (
+	dbsql "database/sql"
+	"github.com/glvd/go-admin/modules/cache"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTxTags(t *testing.T) {
+	cache.Use(cache.NewMemoryStore(), time.Minute)
+	defer cache.Use(nil, 0)
+
+	var (
+		tags = &txTags{tags: make(map[*dbsql.Tx][]string)}
+		tx   = new(dbsql.Tx)
+		tag  = cache.TableTag("mysql/default", "users")
+	)
+
+	assert.False(t, tags.add(tx, tag))
+
+	cache.Set("users", 1, tag)
+	cache.Set("other", 1, cache.TableTag("mysql/other", "users"))
+
+	tags.begin(tx)
+	assert.True(t, tags.add(tx, tag))
+	_, ok := cache.Get("users")
+	assert.True(t, ok)
+
+	tags.end(tx, false)
+	_, ok = cache.Get("users")
+	assert.True(t, ok)
+	assert.False(t, tags.add(tx, tag))
+
+	tags.begin(tx)
+	tags.add(tx, tag)
+	tags.end(tx, true)
+	_, ok = cache.Get("users")
+	assert.False(t, ok)
+	_, ok = cache.Get("other")
+	assert.True(t, ok)
+}
+
+func TestCacheConn(t *testing.T) {
+	var (
+		a = GetMysqlDB()
+		b = GetMysqlDB()
+	)
+
+	assert.Equal(t, CacheConn(a, "default"), CacheConn(a, "default"))
+	assert.NotEqual(t, CacheConn(a, "default"), CacheConn(b, "default"))
+	assert.NotEqual(t, CacheConn(a, "default"), CacheConn(a, "other"))
+	assert.NotEqual(t, cache.TableTag(CacheConn(a, "default"), "users"),
+		cache.TableTag(CacheConn(b, "default"), "users"))
+}
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"github.com/glvd/go-admin/modules/config"
 	"github.com/glvd/go-admin/modules/service"
+	"time"
 )
 
 const (
@@ -63,6 +64,22 @@ type Connection interface {
 	GetDelimiter() string
 
 	GetDB(key string) *sql.DB
+
+	// ReadConnection return the name of a healthy replica of the given
+	// connection, or the connection itself when it has no replica.
+	ReadConnection(conn string) string
+
+	// Stats return the pool statistics, the statement latency and the slow
+	// queries of the connection.
+	Stats() Stats
+
+	// ResetStats clear the statement latency and the slow queries.
+	ResetStats()
+
+	// SetSlowQueryThreshold set the latency above which the statements are
+	// recorded as the slow queries, DefaultSlowQueryThreshold is used if it
+	// is not positive.
+	SetSlowQueryThreshold(threshold time.Duration)
 }
 
 // GetConnectionByDriver return the Connection by given driver name.
@@ -88,8 +105,10 @@ func GetConnectionFromService(srv interface{}) Connection {
 	panic("wrong service")
 }
 
+// GetConnection return the connection of the default database of the
+// config service in the services, or the global config if it is missing.
 func GetConnection(srvs service.List) Connection {
-	if v, ok := srvs.Get(config.Get().Databases.GetDefault().Driver).(Connection); ok {
+	if v, ok := srvs.Get(config.GetFromServices(srvs).Databases.GetDefault().Driver).(Connection); ok {
 		return v
 	}
 	panic("wrong service")
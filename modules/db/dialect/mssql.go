@@ -15,7 +15,7 @@ func (mssql) GetName() string {
 }
 
 func (mssql) ShowColumns(table string) string {
-	return fmt.Sprintf("select column_name, data_type from information_schema.columns where table_name = '%s'", table)
+	return fmt.Sprintf("select column_name, data_type, is_nullable, column_default from information_schema.columns where table_name = '%s'", table)
 }
 
 func (mssql) ShowTables() string {
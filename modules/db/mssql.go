@@ -13,6 +13,7 @@ import (
 
 	"github.com/glvd/go-admin/modules/config"
 	"github.com/gogf/gf/text/gregex"
+	"time"
 )
 
 // Mssql is a Connection of mssql.
@@ -143,25 +144,29 @@ func (db *Mssql) parseSql(sql string) string {
 }
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
-func (db *Mssql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Mssql) QueryWithConnection(con string, query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record(con, query, args, time.Now(), &err)
 	query = db.handleSqlBeforeExec(query)
 	return CommonQuery(db.DbList[con], query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
-func (db *Mssql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
+func (db *Mssql) ExecWithConnection(con string, query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record(con, query, args, time.Now(), &err)
 	query = db.handleSqlBeforeExec(query)
 	return CommonExec(db.DbList[con], query, args...)
 }
 
 // Query implements the method Connection.Query.
-func (db *Mssql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Mssql) Query(query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record("default", query, args, time.Now(), &err)
 	query = db.handleSqlBeforeExec(query)
 	return CommonQuery(db.DbList["default"], query, args...)
 }
 
 // Exec implements the method Connection.Exec.
-func (db *Mssql) Exec(query string, args ...interface{}) (sql.Result, error) {
+func (db *Mssql) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record("default", query, args, time.Now(), &err)
 	query = db.handleSqlBeforeExec(query)
 	return CommonExec(db.DbList["default"], query, args...)
 }
@@ -193,6 +198,7 @@ func (db *Mssql) InitDB(cfglist map[string]config.Database) Connection {
 				db.DbList[conn] = sqlDB
 			}
 		}
+		db.initReplicas(cfglist)
 	})
 	return db
 }
@@ -248,13 +254,15 @@ func (db *Mssql) BeginTxWithLevelAndConnection(conn string, level sql.IsolationL
 }
 
 // QueryWithTx is query method within the transaction.
-func (db *Mssql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Mssql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record(TxConnection, query, args, time.Now(), &err)
 	query = db.handleSqlBeforeExec(query)
 	return CommonQueryWithTx(tx, query, args...)
 }
 
 // ExecWithTx is exec method within the transaction.
-func (db *Mssql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+func (db *Mssql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record(TxConnection, query, args, time.Now(), &err)
 	query = db.handleSqlBeforeExec(query)
 	return CommonExecWithTx(tx, query, args...)
 }
@@ -7,6 +7,7 @@ package db
 import (
 	"database/sql"
 	"github.com/glvd/go-admin/modules/config"
+	"time"
 )
 
 // SQLTx is an in-progress database transaction.
@@ -62,27 +63,32 @@ func (db *Mysql) InitDB(cfgs map[string]config.Database) Connection {
 				db.DbList[conn] = sqlDB
 			}
 		}
+		db.initReplicas(cfgs)
 	})
 	return db
 }
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
-func (db *Mysql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Mysql) QueryWithConnection(con string, query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record(con, query, args, time.Now(), &err)
 	return CommonQuery(db.DbList[con], query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
-func (db *Mysql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
+func (db *Mysql) ExecWithConnection(con string, query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record(con, query, args, time.Now(), &err)
 	return CommonExec(db.DbList[con], query, args...)
 }
 
 // Query implements the method Connection.Query.
-func (db *Mysql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Mysql) Query(query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record("default", query, args, time.Now(), &err)
 	return CommonQuery(db.DbList["default"], query, args...)
 }
 
 // Exec implements the method Connection.Exec.
-func (db *Mysql) Exec(query string, args ...interface{}) (sql.Result, error) {
+func (db *Mysql) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record("default", query, args, time.Now(), &err)
 	return CommonExec(db.DbList["default"], query, args...)
 }
 
@@ -137,11 +143,13 @@ func (db *Mysql) BeginTxWithLevelAndConnection(conn string, level sql.IsolationL
 }
 
 // QueryWithTx is query method within the transaction.
-func (db *Mysql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Mysql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record(TxConnection, query, args, time.Now(), &err)
 	return CommonQueryWithTx(tx, query, args...)
 }
 
 // ExecWithTx is exec method within the transaction.
-func (db *Mysql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+func (db *Mysql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record(TxConnection, query, args, time.Now(), &err)
 	return CommonExecWithTx(tx, query, args...)
 }
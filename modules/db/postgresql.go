@@ -10,6 +10,7 @@ import (
 	"github.com/glvd/go-admin/modules/config"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Postgresql is a Connection of postgresql.
@@ -37,22 +38,26 @@ func (db *Postgresql) GetDelimiter() string {
 }
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
-func (db *Postgresql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Postgresql) QueryWithConnection(con string, query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record(con, query, args, time.Now(), &err)
 	return CommonQuery(db.DbList[con], filterQuery(query), args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
-func (db *Postgresql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
+func (db *Postgresql) ExecWithConnection(con string, query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record(con, query, args, time.Now(), &err)
 	return CommonExec(db.DbList[con], filterQuery(query), args...)
 }
 
 // Query implements the method Connection.Query.
-func (db *Postgresql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Postgresql) Query(query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record("default", query, args, time.Now(), &err)
 	return CommonQuery(db.DbList["default"], filterQuery(query), args...)
 }
 
 // Exec implements the method Connection.Exec.
-func (db *Postgresql) Exec(query string, args ...interface{}) (sql.Result, error) {
+func (db *Postgresql) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record("default", query, args, time.Now(), &err)
 	return CommonExec(db.DbList["default"], filterQuery(query), args...)
 }
 
@@ -83,6 +88,7 @@ func (db *Postgresql) InitDB(cfgList map[string]config.Database) Connection {
 
 			db.DbList[conn] = sqlDB
 		}
+		db.initReplicas(cfgList)
 	})
 	return db
 }
@@ -138,11 +144,13 @@ func (db *Postgresql) BeginTxWithLevelAndConnection(conn string, level sql.Isola
 }
 
 // QueryWithTx is query method within the transaction.
-func (db *Postgresql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Postgresql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record(TxConnection, query, args, time.Now(), &err)
 	return CommonQueryWithTx(tx, filterQuery(query), args...)
 }
 
 // ExecWithTx is exec method within the transaction.
-func (db *Postgresql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+func (db *Postgresql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record(TxConnection, query, args, time.Now(), &err)
 	return CommonExecWithTx(tx, filterQuery(query), args...)
 }
@@ -0,0 +1,107 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/logger"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicaCheckInterval is the interval of the replica health check.
+var ReplicaCheckInterval = 10 * time.Second
+
+// replicaSet is the read replicas of a primary connection.
+type replicaSet struct {
+	names   []string
+	healthy []int32
+	next    uint32
+}
+
+// pick return a healthy replica in round-robin order.
+func (set *replicaSet) pick() (string, bool) {
+	size := uint32(len(set.names))
+	for i := uint32(0); i < size; i++ {
+		index := (atomic.AddUint32(&set.next, 1) - 1) % size
+		if atomic.LoadInt32(&set.healthy[index]) == 1 {
+			return set.names[index], true
+		}
+	}
+	return "", false
+}
+
+// initReplicas group the replicas by the primary connections and start
+// the health check. It should be called in the InitDB of drivers.
+func (db *Base) initReplicas(cfgs map[string]config.Database) {
+	list := config.DatabaseList(cfgs)
+	db.replicas = make(map[string]*replicaSet)
+
+	for name := range cfgs {
+		if _, ok := db.DbList[name]; !ok {
+			continue
+		}
+		names := make([]string, 0)
+		for _, replica := range list.Replicas(name) {
+			if _, ok := db.DbList[replica]; ok {
+				names = append(names, replica)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		set := &replicaSet{names: names, healthy: make([]int32, len(names))}
+		for i := range set.healthy {
+			set.healthy[i] = 1
+		}
+		db.replicas[name] = set
+	}
+
+	if len(db.replicas) > 0 {
+		db.stopLock.Lock()
+		db.stop = make(chan struct{})
+		go db.checkReplicas(db.stop)
+		db.stopLock.Unlock()
+	}
+}
+
+func (db *Base) checkReplicas(stop chan struct{}) {
+	ticker := time.NewTicker(ReplicaCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, set := range db.replicas {
+				for i, name := range set.names {
+					var healthy int32 = 1
+					err := db.DbList[name].Ping()
+					if err != nil {
+						healthy = 0
+					}
+					if atomic.SwapInt32(&set.healthy[i], healthy) != healthy {
+						if healthy == 0 {
+							logger.Error("replica ", name, " is unavailable: ", err)
+						} else {
+							logger.Info("replica ", name, " is available again")
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// ReadConnection implements the method Connection.ReadConnection.
+func (db *Base) ReadConnection(conn string) string {
+	if set, ok := db.replicas[conn]; ok {
+		if name, ok := set.pick(); ok {
+			return name
+		}
+	}
+	return conn
+}
@@ -7,6 +7,7 @@ package db
 import (
 	"database/sql"
 	"github.com/glvd/go-admin/modules/config"
+	"time"
 )
 
 // Sqlite is a Connection of sqlite.
@@ -34,22 +35,26 @@ func (db *Sqlite) GetDelimiter() string {
 }
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
-func (db *Sqlite) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Sqlite) QueryWithConnection(con string, query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record(con, query, args, time.Now(), &err)
 	return CommonQuery(db.DbList[con], query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
-func (db *Sqlite) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
+func (db *Sqlite) ExecWithConnection(con string, query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record(con, query, args, time.Now(), &err)
 	return CommonExec(db.DbList[con], query, args...)
 }
 
 // Query implements the method Connection.Query.
-func (db *Sqlite) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Sqlite) Query(query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record("default", query, args, time.Now(), &err)
 	return CommonQuery(db.DbList["default"], query, args...)
 }
 
 // Exec implements the method Connection.Exec.
-func (db *Sqlite) Exec(query string, args ...interface{}) (sql.Result, error) {
+func (db *Sqlite) Exec(query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record("default", query, args, time.Now(), &err)
 	return CommonExec(db.DbList["default"], query, args...)
 }
 
@@ -65,6 +70,7 @@ func (db *Sqlite) InitDB(cfgList map[string]config.Database) Connection {
 				db.DbList[conn] = sqlDB
 			}
 		}
+		db.initReplicas(cfgList)
 	})
 	return db
 }
@@ -120,11 +126,13 @@ func (db *Sqlite) BeginTxWithLevelAndConnection(conn string, level sql.Isolation
 }
 
 // QueryWithTx is query method within the transaction.
-func (db *Sqlite) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+func (db *Sqlite) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) (res []map[string]interface{}, err error) {
+	defer db.record(TxConnection, query, args, time.Now(), &err)
 	return CommonQueryWithTx(tx, query, args...)
 }
 
 // ExecWithTx is exec method within the transaction.
-func (db *Sqlite) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+func (db *Sqlite) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (res sql.Result, err error) {
+	defer db.record(TxConnection, query, args, time.Now(), &err)
 	return CommonExecWithTx(tx, query, args...)
 }
@@ -8,6 +8,7 @@ import (
 	dbsql "database/sql"
 	"errors"
 	"fmt"
+	"github.com/glvd/go-admin/modules/cache"
 	"github.com/glvd/go-admin/modules/db/dialect"
 	"github.com/glvd/go-admin/modules/logger"
 	"regexp"
@@ -23,6 +24,8 @@ type SQL struct {
 	dialect dialect.Dialect
 	conn    string
 	tx      *dbsql.Tx
+	primary bool
+	cache   bool
 }
 
 // SQLPool is a object pool of SQL.
@@ -104,6 +107,29 @@ func (sql *SQL) WithTx(tx *dbsql.Tx) *SQL {
 	return sql
 }
 
+// UsePrimary make the selections of SQL read from the primary connection
+// instead of the replicas.
+func (sql *SQL) UsePrimary() *SQL {
+	sql.primary = true
+	return sql
+}
+
+// Cache make the results of the selections of SQL cached, tagged with the
+// table and the joined tables. It takes no effect when the cache is
+// disabled or within a transaction.
+func (sql *SQL) Cache() *SQL {
+	sql.cache = true
+	return sql
+}
+
+// readConn return the connection name which the selections read from.
+func (sql *SQL) readConn() string {
+	if sql.primary {
+		return sql.conn
+	}
+	return sql.diver.ReadConnection(sql.conn)
+}
+
 // TableName set table of SQL.
 func (sql *SQL) Table(table string) *SQL {
 	sql.TableName = table
@@ -336,17 +362,22 @@ func (sql *SQL) WithTransaction(fn TxFn) (res map[string]interface{}, err error)
 
 	tx := sql.diver.BeginTxAndConnection(sql.conn)
 
+	pendingTags.begin(tx)
+
 	defer func() {
 		if p := recover(); p != nil {
 			// a panic occurred, rollback and repanic
 			_ = tx.Rollback()
+			pendingTags.end(tx, false)
 			panic(p)
 		} else if err != nil {
 			// something went wrong, rollback
 			_ = tx.Rollback()
+			pendingTags.end(tx, false)
 		} else {
 			// all good, commit
 			err = tx.Commit()
+			pendingTags.end(tx, err == nil)
 		}
 	}()
 
@@ -360,17 +391,22 @@ func (sql *SQL) WithTransactionByLevel(level dbsql.IsolationLevel, fn TxFn) (res
 
 	tx := sql.diver.BeginTxWithLevelAndConnection(sql.conn, level)
 
+	pendingTags.begin(tx)
+
 	defer func() {
 		if p := recover(); p != nil {
 			// a panic occurred, rollback and repanic
 			_ = tx.Rollback()
+			pendingTags.end(tx, false)
 			panic(p)
 		} else if err != nil {
 			// something went wrong, rollback
 			_ = tx.Rollback()
+			pendingTags.end(tx, false)
 		} else {
 			// all good, commit
 			err = tx.Commit()
+			pendingTags.end(tx, err == nil)
 		}
 	}()
 
@@ -399,7 +435,7 @@ func (sql *SQL) First() (map[string]interface{}, error) {
 	if sql.tx != nil {
 		res, err = sql.diver.QueryWithTx(sql.tx, sql.Statement, sql.Args...)
 	} else {
-		res, err = sql.diver.QueryWithConnection(sql.conn, sql.Statement, sql.Args...)
+		res, err = sql.query(sql.readConn(), sql.Statement, sql.Args...)
 	}
 
 	if err != nil {
@@ -421,14 +457,116 @@ func (sql *SQL) All() ([]map[string]interface{}, error) {
 	if sql.tx != nil {
 		return sql.diver.QueryWithTx(sql.tx, sql.Statement, sql.Args...)
 	}
-	return sql.diver.QueryWithConnection(sql.conn, sql.Statement, sql.Args...)
+	return sql.query(sql.readConn(), sql.Statement, sql.Args...)
 }
 
 // ShowColumns show columns info.
 func (sql *SQL) ShowColumns() ([]map[string]interface{}, error) {
 	defer RecycleSQL(sql)
 
-	return sql.diver.QueryWithConnection(sql.conn, sql.dialect.ShowColumns(sql.TableName))
+	return sql.query(sql.conn, sql.dialect.ShowColumns(sql.TableName))
+}
+
+// query query the statement with the connection, the result is got from
+// and saved into the cache if the SQL is cached.
+func (sql *SQL) query(conn, statement string, args ...interface{}) ([]map[string]interface{}, error) {
+	if !sql.cache || !cache.Enabled() {
+		return sql.diver.QueryWithConnection(conn, statement, args...)
+	}
+
+	tables := []string{sql.TableName}
+	for _, join := range sql.Leftjoins {
+		tables = append(tables, join.Table)
+	}
+
+	res, err := cache.Remember(cache.Key("sql", sql.cacheConn(), statement, args),
+		cache.TableTags(sql.cacheConn(), tables...), func() (interface{}, error) {
+			return sql.diver.QueryWithConnection(conn, statement, args...)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return CopyRows(res.([]map[string]interface{})), nil
+}
+
+// CopyRows return a copy of the rows, so that the cached rows will not be
+// modified by the callers.
+func CopyRows(rows []map[string]interface{}) []map[string]interface{} {
+	list := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		list[i] = make(map[string]interface{}, len(row))
+		for key, value := range row {
+			list[i][key] = value
+		}
+	}
+	return list
+}
+
+// CacheConn return the namespace of the cache keys and tags of the tables
+// of the connection name of the driver. The namespace contains the cache
+// id of the connection, see Base.CacheID.
+func CacheConn(conn Connection, name string) string {
+	if c, ok := conn.(interface{ CacheID() string }); ok {
+		return conn.Name() + "#" + c.CacheID() + "/" + name
+	}
+	return conn.Name() + "/" + name
+}
+
+// cacheConn return the namespace of the cache tags of the connection.
+func (sql *SQL) cacheConn() string {
+	return CacheConn(sql.diver, sql.conn)
+}
+
+// invalidate delete the cached results read from the table of SQL. Within
+// a transaction begun by WithTransaction, the tags are collected and the
+// results are deleted after the transaction is committed.
+func (sql *SQL) invalidate() {
+	tag := cache.TableTag(sql.cacheConn(), sql.TableName)
+	if sql.tx != nil && pendingTags.add(sql.tx, tag) {
+		return
+	}
+	cache.Invalidate(tag)
+}
+
+// txTags is the cache tags to invalidate of the transactions, which are
+// invalidated after the transactions are committed.
+type txTags struct {
+	lock sync.Mutex
+	tags map[*dbsql.Tx][]string
+}
+
+var pendingTags = &txTags{tags: make(map[*dbsql.Tx][]string)}
+
+// begin start to collect the tags of the transaction.
+func (t *txTags) begin(tx *dbsql.Tx) {
+	t.lock.Lock()
+	t.tags[tx] = make([]string, 0)
+	t.lock.Unlock()
+}
+
+// add add the tag to the transaction, false if the transaction is not
+// begun by WithTransaction.
+func (t *txTags) add(tx *dbsql.Tx, tag string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tags, ok := t.tags[tx]
+	if ok {
+		t.tags[tx] = append(tags, tag)
+	}
+	return ok
+}
+
+// end stop collecting the tags of the transaction and invalidate them if
+// the transaction is committed.
+func (t *txTags) end(tx *dbsql.Tx, committed bool) {
+	t.lock.Lock()
+	tags := t.tags[tx]
+	delete(t.tags, tx)
+	t.lock.Unlock()
+	if committed {
+		cache.Invalidate(tags...)
+	}
 }
 
 // ShowTables show table info.
@@ -461,6 +599,8 @@ func (sql *SQL) Update(values dialect.H) (int64, error) {
 		return 0, err
 	}
 
+	sql.invalidate()
+
 	if affectRow, _ := res.RowsAffected(); affectRow < 1 {
 		return 0, errors.New("no affect row")
 	}
@@ -489,6 +629,8 @@ func (sql *SQL) Delete() error {
 		return err
 	}
 
+	sql.invalidate()
+
 	if affectRow, _ := res.RowsAffected(); affectRow < 1 {
 		return errors.New("no affect row")
 	}
@@ -517,6 +659,8 @@ func (sql *SQL) Exec() (int64, error) {
 		return 0, err
 	}
 
+	sql.invalidate()
+
 	if affectRow, _ := res.RowsAffected(); affectRow < 1 {
 		return 0, errors.New("no affect row")
 	}
@@ -554,6 +698,8 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 				return 0, err
 			}
 
+			sql.invalidate()
+
 			if len(resMap) == 0 {
 				return 0, errors.New("no affect row")
 			}
@@ -571,6 +717,8 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 		return 0, err
 	}
 
+	sql.invalidate()
+
 	if affectRow, _ := res.RowsAffected(); affectRow < 1 {
 		return 0, errors.New("no affect row")
 	}
@@ -578,6 +726,72 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 	return res.LastInsertId()
 }
 
+// InsertReturning exec the insert method of given key/value pairs and
+// return the value of the key column of the inserted row. The key is read
+// by RETURNING with postgresql and by SCOPE_IDENTITY in the same batch with
+// mssql, which are both scoped to the statement, and by LastInsertId with
+// the other drivers.
+func (sql *SQL) InsertReturning(values dialect.H, key string) (string, error) {
+	defer RecycleSQL(sql)
+
+	sql.Values = values
+
+	sql.dialect.Insert(&sql.SQLComponent)
+
+	var suffix string
+	switch sql.diver.Name() {
+	case DriverPostgresql:
+		suffix = " RETURNING " + sql.wrap(key) + " as id"
+	case DriverMssql:
+		suffix = "; select convert(bigint, SCOPE_IDENTITY()) as id"
+	default:
+		var (
+			res dbsql.Result
+			err error
+		)
+		if sql.tx != nil {
+			res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
+		} else {
+			res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
+		}
+		if err != nil {
+			return "", err
+		}
+		sql.invalidate()
+		if affectRow, _ := res.RowsAffected(); affectRow < 1 {
+			return "", errors.New("no affect row")
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(id, 10), nil
+	}
+
+	var (
+		resMap []map[string]interface{}
+		err    error
+	)
+	if sql.tx != nil {
+		resMap, err = sql.diver.QueryWithTx(sql.tx, sql.Statement+suffix, sql.Args...)
+	} else {
+		resMap, err = sql.diver.QueryWithConnection(sql.conn, sql.Statement+suffix, sql.Args...)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sql.invalidate()
+
+	if len(resMap) == 0 || resMap[0]["id"] == nil {
+		return "", errors.New("no affect row")
+	}
+	if v, ok := resMap[0]["id"].([]byte); ok {
+		return string(v), nil
+	}
+	return fmt.Sprintf("%v", resMap[0]["id"]), nil
+}
+
 func (sql *SQL) wrap(field string) string {
 	if sql.diver.Name() == "mssql" {
 		return fmt.Sprintf(`[%s]`, field)
@@ -602,6 +816,8 @@ func RecycleSQL(sql *SQL) {
 	sql.UpdateRaws = make([]dialect.RawUpdate, 0)
 	sql.Statement = ""
 	sql.tx = nil
+	sql.primary = false
+	sql.cache = false
 
 	SQLPool.Put(sql)
 }
@@ -0,0 +1,271 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"database/sql"
+	"github.com/glvd/go-admin/modules/logger"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSlowQueryThreshold is the slow query threshold used when the
+// threshold of the connection is not set, see Base.SetSlowQueryThreshold.
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+// SlowQueryLogSize is the max number of the slow queries kept in memory.
+var SlowQueryLogSize = 100
+
+// StatementStatsSize is the max number of the statements of which the
+// statistics are kept in memory, the least executed one is dropped for a
+// new statement when it is exceeded.
+var StatementStatsSize = 1000
+
+var (
+	stringLiteralReg   = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numberLiteralReg   = regexp.MustCompile(`\b[0-9]+(?:\.[0-9]+)?\b`)
+	placeholderListReg = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	spaceReg           = regexp.MustCompile(`\s+`)
+)
+
+// LatencyBuckets are the upper bounds of the statement latency histogram.
+var LatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// TxConnection is the connection name of the statements executed within
+// a transaction.
+const TxConnection = "transaction"
+
+// StatementStats is the latency statistics of a statement.
+type StatementStats struct {
+	Statement string
+	Count     int64
+	Errors    int64
+	Total     time.Duration
+	Max       time.Duration
+	// Buckets[i] is the number of executions which latency is less than or
+	// equal to LatencyBuckets[i], the last one counts the rest.
+	Buckets []int64
+}
+
+// Avg return the average latency of the statement.
+func (s StatementStats) Avg() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// SlowQuery is a statement which latency exceeds the threshold. Only the
+// number of the arguments is kept, as they can be the passwords or the
+// sessions.
+type SlowQuery struct {
+	Connection string
+	Statement  string
+	ArgCount   int
+	Latency    time.Duration
+	Time       time.Time
+}
+
+// Stats is a snapshot of the statistics of a Connection.
+type Stats struct {
+	Pools              map[string]sql.DBStats
+	Statements         []StatementStats
+	SlowQueries        []SlowQuery
+	SlowQueryThreshold time.Duration
+}
+
+// statsRecorder records the statement latency and slow queries.
+type statsRecorder struct {
+	lock       sync.Mutex
+	statements map[string]*StatementStats
+	slow       []SlowQuery
+	threshold  time.Duration
+}
+
+func newStatsRecorder() *statsRecorder {
+	return &statsRecorder{
+		statements: make(map[string]*StatementStats),
+		slow:       make([]SlowQuery, 0),
+		threshold:  DefaultSlowQueryThreshold,
+	}
+}
+
+// SlowQueryThreshold return the slow query threshold of the milliseconds
+// of config.Config.SlowQueryThreshold.
+func SlowQueryThreshold(ms int) time.Duration {
+	if ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return DefaultSlowQueryThreshold
+}
+
+// setThreshold set the slow query threshold, DefaultSlowQueryThreshold is
+// used if it is not positive.
+func (r *statsRecorder) setThreshold(threshold time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if threshold <= 0 {
+		threshold = DefaultSlowQueryThreshold
+	}
+	r.threshold = threshold
+}
+
+// normalizeStatement return the statement of which the literals are
+// replaced with "?" and the lists of them are collapsed, so that the
+// statements differing in the inlined values are recorded as one.
+func normalizeStatement(statement string) string {
+	statement = stringLiteralReg.ReplaceAllString(statement, "?")
+	statement = numberLiteralReg.ReplaceAllString(statement, "?")
+	statement = placeholderListReg.ReplaceAllString(statement, "(?)")
+	return strings.TrimSpace(spaceReg.ReplaceAllString(statement, " "))
+}
+
+func (r *statsRecorder) record(conn, statement string, args []interface{}, latency time.Duration, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	statement = normalizeStatement(statement)
+
+	item, ok := r.statements[statement]
+	if !ok {
+		if len(r.statements) >= StatementStatsSize {
+			r.evict()
+		}
+		item = &StatementStats{
+			Statement: statement,
+			Buckets:   make([]int64, len(LatencyBuckets)+1),
+		}
+		r.statements[statement] = item
+	}
+
+	item.Count++
+	item.Total += latency
+	if latency > item.Max {
+		item.Max = latency
+	}
+	if err != nil {
+		item.Errors++
+	}
+	index := sort.Search(len(LatencyBuckets), func(i int) bool {
+		return latency <= LatencyBuckets[i]
+	})
+	item.Buckets[index]++
+
+	if latency >= r.threshold {
+		logger.Warn("slow query: [", latency.String(), "] ", statement, " (", len(args), " args)")
+		r.slow = append(r.slow, SlowQuery{
+			Connection: conn,
+			Statement:  statement,
+			ArgCount:   len(args),
+			Latency:    latency,
+			Time:       time.Now(),
+		})
+		if len(r.slow) > SlowQueryLogSize {
+			r.slow = r.slow[len(r.slow)-SlowQueryLogSize:]
+		}
+	}
+}
+
+// evict drop the statistics of the least executed statement.
+func (r *statsRecorder) evict() {
+	var (
+		key   string
+		count int64 = -1
+	)
+	for statement, item := range r.statements {
+		if count < 0 || item.Count < count {
+			key, count = statement, item.Count
+		}
+	}
+	delete(r.statements, key)
+}
+
+func (r *statsRecorder) snapshot() ([]StatementStats, []SlowQuery, time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	statements := make([]StatementStats, 0, len(r.statements))
+	for _, item := range r.statements {
+		s := *item
+		s.Buckets = append([]int64{}, item.Buckets...)
+		statements = append(statements, s)
+	}
+	sort.Slice(statements, func(i, j int) bool {
+		return statements[i].Total > statements[j].Total
+	})
+
+	slow := make([]SlowQuery, len(r.slow))
+	for i := range r.slow {
+		slow[i] = r.slow[len(r.slow)-1-i]
+	}
+
+	return statements, slow, r.threshold
+}
+
+func (r *statsRecorder) reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.statements = make(map[string]*StatementStats)
+	r.slow = make([]SlowQuery, 0)
+}
+
+// recorder return the stats recorder of the connection.
+func (db *Base) recorder() *statsRecorder {
+	db.statsOnce.Do(func() {
+		db.statsRecorder = newStatsRecorder()
+	})
+	return db.statsRecorder
+}
+
+// record records the execution of the statement began at the given time.
+// It is used with defer, so the error is got from the pointer.
+func (db *Base) record(conn, statement string, args []interface{}, begin time.Time, err *error) {
+	var e error
+	if err != nil {
+		e = *err
+	}
+	db.recorder().record(conn, statement, args, time.Since(begin), e)
+}
+
+// Stats implements the method Connection.Stats.
+func (db *Base) Stats() Stats {
+	pools := make(map[string]sql.DBStats, len(db.DbList))
+	for name, d := range db.DbList {
+		pools[name] = d.Stats()
+	}
+	statements, slow, threshold := db.recorder().snapshot()
+	return Stats{
+		Pools:              pools,
+		Statements:         statements,
+		SlowQueries:        slow,
+		SlowQueryThreshold: threshold,
+	}
+}
+
+// SetSlowQueryThreshold implements the method Connection.SetSlowQueryThreshold.
+func (db *Base) SetSlowQueryThreshold(threshold time.Duration) {
+	db.recorder().setThreshold(threshold)
+}
+
+// ResetStats implements the method Connection.ResetStats.
+func (db *Base) ResetStats() {
+	db.recorder().reset()
+}
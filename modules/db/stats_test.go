@@ -0,0 +1,51 @@
+package db
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNormalizeStatement(t *testing.T) {
+	assert.Equal(t, "select * from users where id in (?) and name = ? and t2.`age` > ?",
+		normalizeStatement("select * from users\n  where id in (1, 2,3) and name = 'o''neil' and t2.`age` > 1.5"))
+	assert.Equal(t, normalizeStatement("select * from users where id in (4,5)"),
+		normalizeStatement("select * from users where id in (?, ?, ?)"))
+}
+
+func TestStatsRecorder(t *testing.T) {
+	size := StatementStatsSize
+	StatementStatsSize = 2
+	defer func() { StatementStatsSize = size }()
+
+	r := newStatsRecorder()
+	r.record("default", "select * from a where id = 1", nil, time.Millisecond, nil)
+	r.record("default", "select * from a where id = 2", nil, time.Millisecond, nil)
+	r.record("default", "select * from b", nil, time.Millisecond, nil)
+	r.record("default", "select * from c", nil, time.Millisecond, nil)
+	r.record("default", "update adm_users set password = ? where id = ?", []interface{}{"$2a$10$hash", 1},
+		time.Minute, nil)
+
+	statements, slow, threshold := r.snapshot()
+	assert.Equal(t, 2, len(statements))
+	assert.Equal(t, "select * from a where id = ?", statements[1].Statement)
+	assert.Equal(t, int64(2), statements[1].Count)
+
+	assert.Equal(t, 1, len(slow))
+	assert.Equal(t, 2, slow[0].ArgCount)
+	assert.Equal(t, DefaultSlowQueryThreshold, threshold)
+
+	r.setThreshold(time.Hour)
+	r.record("default", "select * from d", nil, time.Minute, nil)
+	_, slow, threshold = r.snapshot()
+	assert.Equal(t, 1, len(slow))
+	assert.Equal(t, time.Hour, threshold)
+
+	r.setThreshold(0)
+	r.record("default", "select * from d", nil, time.Minute, nil)
+	_, slow, _ = r.snapshot()
+	assert.Equal(t, 2, len(slow))
+
+	assert.Equal(t, 50*time.Millisecond, SlowQueryThreshold(50))
+	assert.Equal(t, DefaultSlowQueryThreshold, SlowQueryThreshold(0))
+}
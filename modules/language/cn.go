@@ -92,4 +92,117 @@ var cn = map[string]string{
 	"a path a line":                                 "一行一个路径，换行输入新路径",
 	"slug or http_path or name should not be empty": "标志或路径或权限名不能为空",
 	"no roles":                                      "无角色",
+
+	"install":                       "安装",
+	"database":                      "数据库",
+	"driver":                        "驱动",
+	"host":                          "地址",
+	"port":                          "端口",
+	"username":                      "用户名",
+	"database name":                 "数据库名",
+	"database file":                 "数据库文件",
+	"test connection":               "测试连接",
+	"create tables":                 "创建数据表",
+	"super administrator":           "超级管理员",
+	"create":                        "创建",
+	"config file":                   "配置文件",
+	"url prefix":                    "路由前缀",
+	"title":                         "标题",
+	"language":                      "语言",
+	"finish":                        "完成",
+	"installer is locked":           "安装程序已锁定",
+	"check the database parameters": "请检查数据库参数是否设置正确",
+	"create tables fail":            "创建数据表失败",
+	"create administrator fail":     "创建管理员失败",
+	"write config file fail":        "写入配置文件失败",
+	"driver not supported":          "不支持的驱动",
+
+	"database status":      "数据库状态",
+	"connection pool":      "连接池",
+	"statements":           "语句",
+	"slow queries":         "慢查询",
+	"connection":           "连接",
+	"health":               "健康状态",
+	"open connections":     "打开连接数",
+	"in use":               "使用中",
+	"idle":                 "空闲",
+	"wait count":           "等待次数",
+	"wait duration":        "等待时长",
+	"max idle closed":      "空闲超限关闭",
+	"max lifetime closed":  "超时关闭",
+	"statement":            "语句",
+	"count":                "次数",
+	"errors":               "错误",
+	"avg":                  "平均",
+	"max":                  "最大",
+	"latency distribution": "耗时分布",
+	"time":                 "时间",
+	"latency":              "耗时",
+
+	"plugins":           "插件",
+	"installed plugins": "已安装插件",
+	"version":           "版本",
+	"routes":            "路由",
+	"status":            "状态",
+	"enabled":           "已启用",
+	"disabled":          "已停用",
+	"enable":            "启用",
+	"disable":           "停用",
+
+	"dynamic tables":    "动态表",
+	"new dynamic table": "新建动态表",
+	"choose a table":    "选择数据表",
+	"prefix":            "前缀",
+	"table":             "数据表",
+	"description":       "描述",
+	"primary key":       "主键",
+	"can add":           "可新增",
+	"editable":          "可编辑",
+	"deletable":         "可删除",
+	"exportable":        "可导出",
+	"include":           "包含",
+	"column":            "字段",
+	"head":              "标题",
+	"type":              "类型",
+	"hide":              "隐藏",
+	"sortable":          "可排序",
+	"operator":          "操作符",
+	"form type":         "表单类型",
+	"must":              "必填",
+	"not allow add":     "不可新增",
+	"not allow edit":    "不可编辑",
+	"default":           "默认值",
+	"options":           "选项",
+
+	"views":            "视图",
+	"no view":          "不使用视图",
+	"view name":        "视图名称",
+	"share with roles": "共享给角色",
+	"set as default":   "设为默认",
+	"unset default":    "取消默认",
+	"save view":        "保存视图",
+
+	"advanced filter":          "高级筛选",
+	"and":                      "并且",
+	"or":                       "或者",
+	"add condition":            "条件",
+	"add group":                "条件组",
+	"separated by commas":      "以逗号分隔",
+	"equal to":                 "等于",
+	"not equal to":             "不等于",
+	"greater than":             "大于",
+	"greater than or equal to": "大于等于",
+	"less than":                "小于",
+	"less than or equal to":    "小于等于",
+	"contains":                 "包含",
+	"starts with":              "开头是",
+	"ends with":                "结尾是",
+	"between":                  "介于",
+	"in list":                  "在列表中",
+	"not in list":              "不在列表中",
+	"is null":                  "为空",
+	"is not null":              "不为空",
+	"today":                    "今天",
+	"in the last days":         "最近几天",
+	"in the next days":         "未来几天",
 }
@@ -73,4 +73,121 @@ var en = map[string]string{
 	"menu":      "Menu",
 	"dashboard": "Dashboard",
 	"home":      "Home",
+
+	"install":                                "Install",
+	"database":                               "Database",
+	"driver":                                 "Driver",
+	"host":                                   "Host",
+	"port":                                   "Port",
+	"username":                               "Username",
+	"database name":                          "Database Name",
+	"database file":                          "Database File",
+	"test connection":                        "Test Connection",
+	"create tables":                          "Create Tables",
+	"super administrator":                    "Super Administrator",
+	"create":                                 "Create",
+	"config file":                            "Config File",
+	"url prefix":                             "Url Prefix",
+	"title":                                  "Title",
+	"language":                               "Language",
+	"finish":                                 "Finish",
+	"installer is locked":                    "Installer is locked",
+	"check the database parameters":          "Please check the database parameters",
+	"create tables fail":                     "Create tables fail",
+	"create administrator fail":              "Create administrator fail",
+	"write config file fail":                 "Write config file fail",
+	"driver not supported":                   "Driver not supported",
+	"username and password can not be empty": "Username and password can not be empty",
+
+	"database status":      "Database Status",
+	"connection pool":      "Connection Pool",
+	"statements":           "Statements",
+	"slow queries":         "Slow Queries",
+	"connection":           "Connection",
+	"health":               "Health",
+	"open connections":     "Open",
+	"in use":               "In Use",
+	"idle":                 "Idle",
+	"wait count":           "Wait Count",
+	"wait duration":        "Wait Duration",
+	"max idle closed":      "Max Idle Closed",
+	"max lifetime closed":  "Max Lifetime Closed",
+	"statement":            "Statement",
+	"count":                "Count",
+	"errors":               "Errors",
+	"avg":                  "Avg",
+	"max":                  "Max",
+	"latency distribution": "Latency Distribution",
+	"time":                 "Time",
+	"latency":              "Latency",
+
+	"plugins":           "Plugins",
+	"installed plugins": "Installed plugins",
+	"version":           "Version",
+	"routes":            "Routes",
+	"status":            "Status",
+	"enabled":           "Enabled",
+	"disabled":          "Disabled",
+	"enable":            "Enable",
+	"disable":           "Disable",
+
+	"dynamic tables":    "Dynamic tables",
+	"new dynamic table": "New dynamic table",
+	"choose a table":    "Choose a table",
+	"prefix":            "Prefix",
+	"table":             "Table",
+	"description":       "Description",
+	"primary key":       "Primary key",
+	"can add":           "Can add",
+	"editable":          "Editable",
+	"deletable":         "Deletable",
+	"exportable":        "Exportable",
+	"include":           "Include",
+	"column":            "Column",
+	"head":              "Head",
+	"type":              "Type",
+	"hide":              "Hide",
+	"sortable":          "Sortable",
+	"filter":            "Filter",
+	"operator":          "Operator",
+	"form type":         "Form type",
+	"must":              "Must",
+	"not allow add":     "Not allow add",
+	"not allow edit":    "Not allow edit",
+	"default":           "Default",
+	"options":           "Options",
+
+	"permission denied": "Permission denied",
+
+	"views":            "Views",
+	"no view":          "No view",
+	"view name":        "View name",
+	"share with roles": "Share with roles",
+	"set as default":   "Set as default",
+	"unset default":    "Unset default",
+	"save view":        "Save view",
+
+	"advanced filter":          "Advanced filter",
+	"and":                      "And",
+	"or":                       "Or",
+	"add condition":            "Condition",
+	"add group":                "Group",
+	"separated by commas":      "Separated by commas",
+	"equal to":                 "Equal to",
+	"not equal to":             "Not equal to",
+	"greater than":             "Greater than",
+	"greater than or equal to": "Greater than or equal to",
+	"less than":                "Less than",
+	"less than or equal to":    "Less than or equal to",
+	"contains":                 "Contains",
+	"starts with":              "Starts with",
+	"ends with":                "Ends with",
+	"between":                  "Between",
+	"in list":                  "In list",
+	"not in list":              "Not in list",
+	"is null":                  "Is null",
+	"is not null":              "Is not null",
+	"today":                    "Today",
+	"in the last days":         "In the last days",
+	"in the next days":         "In the next days",
 }
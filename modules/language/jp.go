@@ -64,4 +64,121 @@ var jp = map[string]string{
 
 	"username and password can not be empty": "アカウントのパスワードは空にできません",
 	"operation not allow":                    "許可されていない操作",
+
+	"install":                       "インストール",
+	"database":                      "データベース",
+	"driver":                        "ドライバ",
+	"host":                          "ホスト",
+	"port":                          "ポート",
+	"username":                      "ユーザー名",
+	"database name":                 "データベース名",
+	"database file":                 "データベースファイル",
+	"test connection":               "接続テスト",
+	"create tables":                 "テーブルを作成",
+	"super administrator":           "スーパー管理者",
+	"create":                        "作成",
+	"config file":                   "設定ファイル",
+	"url prefix":                    "URLプレフィックス",
+	"title":                         "タイトル",
+	"language":                      "言語",
+	"finish":                        "完了",
+	"installer is locked":           "インストーラーはロックされています",
+	"check the database parameters": "データベースのパラメータを確認してください",
+	"create tables fail":            "テーブルの作成に失敗しました",
+	"create administrator fail":     "管理者の作成に失敗しました",
+	"write config file fail":        "設定ファイルの書き込みに失敗しました",
+	"driver not supported":          "サポートされていないドライバ",
+
+	"database status":      "データベースの状態",
+	"connection pool":      "コネクションプール",
+	"statements":           "ステートメント",
+	"slow queries":         "スロークエリ",
+	"connection":           "接続",
+	"health":               "ヘルス",
+	"open connections":     "オープン",
+	"in use":               "使用中",
+	"idle":                 "アイドル",
+	"wait count":           "待機回数",
+	"wait duration":        "待機時間",
+	"max idle closed":      "アイドル上限クローズ",
+	"max lifetime closed":  "寿命切れクローズ",
+	"statement":            "ステートメント",
+	"count":                "回数",
+	"errors":               "エラー",
+	"avg":                  "平均",
+	"max":                  "最大",
+	"latency distribution": "レイテンシ分布",
+	"time":                 "時間",
+	"latency":              "レイテンシ",
+
+	"plugins":           "プラグイン",
+	"installed plugins": "インストール済みプラグイン",
+	"version":           "バージョン",
+	"routes":            "ルート",
+	"status":            "ステータス",
+	"enabled":           "有効",
+	"disabled":          "無効",
+	"enable":            "有効にする",
+	"disable":           "無効にする",
+
+	"dynamic tables":    "動的テーブル",
+	"new dynamic table": "動的テーブルの新規作成",
+	"choose a table":    "テーブルを選択",
+	"prefix":            "プレフィックス",
+	"table":             "テーブル",
+	"description":       "説明",
+	"primary key":       "主キー",
+	"can add":           "追加可能",
+	"editable":          "編集可能",
+	"deletable":         "削除可能",
+	"exportable":        "エクスポート可能",
+	"include":           "含む",
+	"column":            "カラム",
+	"head":              "見出し",
+	"type":              "タイプ",
+	"hide":              "非表示",
+	"sortable":          "ソート可能",
+	"operator":          "演算子",
+	"form type":         "フォームタイプ",
+	"must":              "必須",
+	"not allow add":     "追加不可",
+	"not allow edit":    "編集不可",
+	"default":           "デフォルト",
+	"options":           "オプション",
+
+	"permission denied": "権限がありません",
+
+	"search": "検索",
+
+	"views":            "ビュー",
+	"no view":          "ビューなし",
+	"view name":        "ビュー名",
+	"share with roles": "ロールと共有",
+	"set as default":   "デフォルトに設定",
+	"unset default":    "デフォルトを解除",
+	"save view":        "ビューを保存",
+
+	"advanced filter":          "高度なフィルター",
+	"and":                      "かつ",
+	"or":                       "または",
+	"add condition":            "条件",
+	"add group":                "グループ",
+	"separated by commas":      "カンマ区切り",
+	"equal to":                 "等しい",
+	"not equal to":             "等しくない",
+	"greater than":             "より大きい",
+	"greater than or equal to": "以上",
+	"less than":                "より小さい",
+	"less than or equal to":    "以下",
+	"contains":                 "含む",
+	"starts with":              "で始まる",
+	"ends with":                "で終わる",
+	"between":                  "範囲内",
+	"in list":                  "リストに含まれる",
+	"not in list":              "リストに含まれない",
+	"is null":                  "空である",
+	"is not null":              "空でない",
+	"today":                    "今日",
+	"in the last days":         "過去の日数",
+	"in the next days":         "今後の日数",
 }
@@ -55,4 +55,122 @@ var tc = map[string]string{
 	"roles":     "角色",
 	"menu":      "菜單",
 	"dashboard": "儀表盤",
+
+	"install":                                "安裝",
+	"database":                               "數據庫",
+	"driver":                                 "驅動",
+	"host":                                   "地址",
+	"port":                                   "端口",
+	"username":                               "用戶名",
+	"database name":                          "數據庫名",
+	"database file":                          "數據庫文件",
+	"test connection":                        "測試連接",
+	"create tables":                          "創建數據表",
+	"super administrator":                    "超級管理員",
+	"create":                                 "創建",
+	"config file":                            "配置文件",
+	"url prefix":                             "路由前綴",
+	"title":                                  "標題",
+	"language":                               "語言",
+	"finish":                                 "完成",
+	"installer is locked":                    "安裝程序已鎖定",
+	"check the database parameters":          "請檢查數據庫參數是否設置正確",
+	"create tables fail":                     "創建數據表失敗",
+	"create administrator fail":              "創建管理員失敗",
+	"write config file fail":                 "寫入配置文件失敗",
+	"driver not supported":                   "不支持的驅動",
+	"username and password can not be empty": "用戶名和密碼不能為空",
+
+	"database status":      "數據庫狀態",
+	"connection pool":      "連接池",
+	"statements":           "語句",
+	"slow queries":         "慢查詢",
+	"connection":           "連接",
+	"health":               "健康狀態",
+	"open connections":     "打開連接數",
+	"in use":               "使用中",
+	"idle":                 "空閒",
+	"wait count":           "等待次數",
+	"wait duration":        "等待時長",
+	"max idle closed":      "空閒超限關閉",
+	"max lifetime closed":  "超時關閉",
+	"statement":            "語句",
+	"count":                "次數",
+	"errors":               "錯誤",
+	"avg":                  "平均",
+	"max":                  "最大",
+	"latency distribution": "耗時分佈",
+	"time":                 "時間",
+	"latency":              "耗時",
+
+	"plugins":           "插件",
+	"installed plugins": "已安裝插件",
+	"version":           "版本",
+	"routes":            "路由",
+	"status":            "狀態",
+	"enabled":           "已啟用",
+	"disabled":          "已停用",
+	"enable":            "啟用",
+	"disable":           "停用",
+
+	"dynamic tables":    "動態表",
+	"new dynamic table": "新建動態表",
+	"choose a table":    "選擇數據表",
+	"prefix":            "前綴",
+	"table":             "數據表",
+	"description":       "描述",
+	"primary key":       "主鍵",
+	"can add":           "可新增",
+	"editable":          "可編輯",
+	"deletable":         "可刪除",
+	"exportable":        "可導出",
+	"include":           "包含",
+	"column":            "字段",
+	"head":              "標題",
+	"type":              "類型",
+	"hide":              "隱藏",
+	"sortable":          "可排序",
+	"operator":          "操作符",
+	"form type":         "表單類型",
+	"must":              "必填",
+	"not allow add":     "不可新增",
+	"not allow edit":    "不可編輯",
+	"default":           "默認值",
+	"options":           "選項",
+
+	"permission denied": "沒有權限",
+
+	"search": "搜尋",
+
+	"views":            "視圖",
+	"no view":          "不使用視圖",
+	"view name":        "視圖名稱",
+	"share with roles": "共享給角色",
+	"set as default":   "設為默認",
+	"unset default":    "取消默認",
+	"save view":        "保存視圖",
+
+	"advanced filter":          "高級篩選",
+	"and":                      "並且",
+	"or":                       "或者",
+	"add condition":            "條件",
+	"add group":                "條件組",
+	"separated by commas":      "以逗號分隔",
+	"equal to":                 "等於",
+	"not equal to":             "不等於",
+	"greater than":             "大於",
+	"greater than or equal to": "大於等於",
+	"less than":                "小於",
+	"less than or equal to":    "小於等於",
+	"contains":                 "包含",
+	"starts with":              "開頭是",
+	"ends with":                "結尾是",
+	"between":                  "介於",
+	"in list":                  "在列表中",
+	"not in list":              "不在列表中",
+	"is null":                  "為空",
+	"is not null":              "不為空",
+	"today":                    "今天",
+	"in the last days":         "最近幾天",
+	"in the next days":         "未來幾天",
 }
@@ -100,6 +100,13 @@ func Warn(info ...interface{}) {
 // Access print the access message.
 func Access(ctx *context.Context) {
 	if !accessLogOff {
+		if id := ctx.RequestID(); id != "" {
+			manager["access"].Println("["+constant.Title+"]",
+				ansi.Color(" "+strconv.Itoa(ctx.Response.StatusCode)+" ", "white:blue"),
+				ansi.Color(" "+string(ctx.Method()[:])+"   ", "white:blue+h"),
+				ctx.Path(), "request_id="+id)
+			return
+		}
 		manager["access"].Println("["+constant.Title+"]",
 			ansi.Color(" "+strconv.Itoa(ctx.Response.StatusCode)+" ", "white:blue"),
 			ansi.Color(" "+string(ctx.Method()[:])+"   ", "white:blue+h"),
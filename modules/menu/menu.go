@@ -114,6 +114,7 @@ func GetGlobalMenu(user models.UserModel, conn db.Connection) *Menu {
 		menus, _ = db.WithDriver(conn).Table("adm_menu").
 			Where("id", ">", 0).
 			OrderBy("order", "asc").
+			Cache().
 			All()
 	} else {
 
@@ -125,6 +126,7 @@ func GetGlobalMenu(user models.UserModel, conn db.Connection) *Menu {
 		menus, _ = db.WithDriver(conn).Table("adm_menu").
 			WhereIn("id", ids).
 			OrderBy("order", "asc").
+			Cache().
 			All()
 	}
 
@@ -0,0 +1,67 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"github.com/glvd/go-admin/context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The metrics of the engine.
+var (
+	// HTTPRequests counts the handled requests by the method, the matched
+	// route and the status code.
+	HTTPRequests = NewCounterVec("goadmin_http_requests_total",
+		"Total number of the handled HTTP requests.", "method", "route", "status")
+
+	// HTTPDuration is the latency of the handled requests in seconds.
+	HTTPDuration = NewHistogramVec("goadmin_http_request_duration_seconds",
+		"Latency of the handled HTTP requests in seconds.", nil, "method", "route")
+
+	// Logins counts the login attempts by the result, "success" or "failure".
+	Logins = NewCounterVec("goadmin_logins_total",
+		"Total number of the login attempts.", "result")
+
+	// TableOperations counts the operations of the data tables, the
+	// operation is one of "read", "create", "update" and "delete".
+	TableOperations = NewCounterVec("goadmin_table_operations_total",
+		"Total number of the data table operations.", "table", "operation")
+)
+
+// The operations of TableOperations.
+const (
+	OperationRead   = "read"
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// The results of Logins.
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+)
+
+// Middleware records the count and the latency of the requests. It should
+// be the first handler of the routes, so that the status code set by the
+// error handlers is recorded.
+func Middleware(ctx *context.Context) {
+	var (
+		begin     = time.Now()
+		completed = false
+	)
+	defer func() {
+		status := http.StatusInternalServerError
+		if completed && ctx.Response != nil {
+			status = ctx.Response.StatusCode
+		}
+		HTTPRequests.Inc(ctx.Method(), ctx.Route(), strconv.Itoa(status))
+		HTTPDuration.Observe(time.Since(begin).Seconds(), ctx.Method(), ctx.Route())
+	}()
+	ctx.Next()
+	completed = true
+}
@@ -0,0 +1,350 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package metrics collects the metrics of the engine and writes them in
+// the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the default upper bounds of histograms, in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Sample is a value of a metric with the label values.
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// Collector is a metric which can be written in the exposition format.
+type Collector interface {
+	// Name return the metric name.
+	Name() string
+	// Write writes the metric with the HELP and TYPE lines.
+	Write(w io.Writer) error
+}
+
+var (
+	lock       sync.RWMutex
+	collectors = make(map[string]Collector)
+)
+
+// Register registers the collector. If Register is called twice with the
+// same name, it panics.
+func Register(c Collector) {
+	lock.Lock()
+	defer lock.Unlock()
+	if _, dup := collectors[c.Name()]; dup {
+		panic("metric registered twice " + c.Name())
+	}
+	collectors[c.Name()] = c
+}
+
+// Unregister removes the collector of given name.
+func Unregister(name string) {
+	lock.Lock()
+	defer lock.Unlock()
+	delete(collectors, name)
+}
+
+// WriteText writes all the registered metrics in the Prometheus text
+// exposition format, sorted by the metric names.
+func WriteText(w io.Writer) error {
+	lock.RLock()
+	names := make([]string, 0, len(collectors))
+	for name := range collectors {
+		names = append(names, name)
+	}
+	list := make([]Collector, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		list = append(list, collectors[name])
+	}
+	lock.RUnlock()
+
+	buf := bufio.NewWriter(w)
+	for _, c := range list {
+		if err := c.Write(buf); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
+
+// ContentType is the content type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// *******************************
+// counter
+// *******************************
+
+// CounterVec is a counter partitioned by the labels.
+type CounterVec struct {
+	vec
+}
+
+// NewCounterVec create and register a counter.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec: newVec(name, help, "counter", labels)}
+	Register(c)
+	return c
+}
+
+// Inc increases the counter of the label values by 1.
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add increases the counter of the label values by the given value.
+func (c *CounterVec) Add(v float64, values ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.series(values).value += v
+}
+
+// Get return the counter value of the label values.
+func (c *CounterVec) Get(values ...string) float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.series(values).value
+}
+
+// Write implements the Collector.Write.
+func (c *CounterVec) Write(w io.Writer) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.header(w); err != nil {
+		return err
+	}
+	for _, key := range c.keys() {
+		s := c.values[key]
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", c.name, labelString(c.labels, s.labels, "", ""),
+			formatFloat(s.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// *******************************
+// histogram
+// *******************************
+
+// HistogramVec is a histogram partitioned by the labels.
+type HistogramVec struct {
+	vec
+	buckets []float64
+}
+
+// NewHistogramVec create and register a histogram. The DefaultBuckets is
+// used when the buckets are empty.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	h := &HistogramVec{vec: newVec(name, help, "histogram", labels), buckets: buckets}
+	Register(h)
+	return h
+}
+
+// Observe adds an observation to the histogram of the label values.
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	s := h.series(values)
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(h.buckets))
+	}
+	for i, bound := range h.buckets {
+		if v <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.count++
+	s.value += v
+}
+
+// Write implements the Collector.Write.
+func (h *HistogramVec) Write(w io.Writer) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if err := h.header(w); err != nil {
+		return err
+	}
+	for _, key := range h.keys() {
+		s := h.values[key]
+		for i, bound := range h.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name,
+				labelString(h.labels, s.labels, "le", formatFloat(bound)), s.buckets[i]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name,
+			labelString(h.labels, s.labels, "le", "+Inf"), s.count); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n", h.name,
+			labelString(h.labels, s.labels, "", ""), formatFloat(s.value)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_count%s %d\n", h.name,
+			labelString(h.labels, s.labels, "", ""), s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// *******************************
+// gauge
+// *******************************
+
+// GaugeFunc is a gauge which samples are collected by the function when
+// the metrics are written.
+type GaugeFunc struct {
+	name   string
+	help   string
+	labels []string
+	fn     func() []Sample
+}
+
+// NewGaugeFunc create and register a gauge.
+func NewGaugeFunc(name, help string, fn func() []Sample, labels ...string) *GaugeFunc {
+	g := &GaugeFunc{name: name, help: help, labels: labels, fn: fn}
+	Register(g)
+	return g
+}
+
+// Name implements the Collector.Name.
+func (g *GaugeFunc) Name() string {
+	return g.name
+}
+
+// Write implements the Collector.Write.
+func (g *GaugeFunc) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", g.name, escapeHelp(g.help), g.name); err != nil {
+		return err
+	}
+	samples := g.fn()
+	sort.Slice(samples, func(i, j int) bool {
+		return strings.Join(samples[i].Labels, "\xff") < strings.Join(samples[j].Labels, "\xff")
+	})
+	for _, s := range samples {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", g.name, labelString(g.labels, s.Labels, "", ""),
+			formatFloat(s.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// *******************************
+// helper
+// *******************************
+
+type series struct {
+	labels  []string
+	value   float64
+	count   uint64
+	buckets []uint64
+}
+
+type vec struct {
+	lock   sync.Mutex
+	name   string
+	help   string
+	typ    string
+	labels []string
+	values map[string]*series
+}
+
+func newVec(name, help, typ string, labels []string) vec {
+	return vec{
+		name:   name,
+		help:   help,
+		typ:    typ,
+		labels: labels,
+		values: make(map[string]*series),
+	}
+}
+
+// Name implements the Collector.Name.
+func (v *vec) Name() string {
+	return v.name
+}
+
+func (v *vec) series(values []string) *series {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := v.values[key]
+	if !ok {
+		s = &series{labels: append([]string{}, values...)}
+		v.values[key] = s
+	}
+	return s
+}
+
+func (v *vec) keys() []string {
+	keys := make([]string, 0, len(v.values))
+	for key := range v.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (v *vec) header(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, escapeHelp(v.help), v.name, v.typ)
+	return err
+}
+
+func labelString(names, values []string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+escapeLabel(extraValue)+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var (
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
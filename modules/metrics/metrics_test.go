@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"bytes"
+	"github.com/glvd/go-admin/context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCounterVec(t *testing.T) {
+	c := NewCounterVec("test_counter_total", "A test counter.", "name")
+	defer Unregister(c.Name())
+
+	c.Inc("b")
+	c.Add(2, "a")
+	c.Inc("a")
+	c.Inc(`x"y`)
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, c.Write(buf))
+	assert.Equal(t, `# HELP test_counter_total A test counter.
+# TYPE test_counter_total counter
+test_counter_total{name="a"} 3
+test_counter_total{name="b"} 1
+test_counter_total{name="x\"y"} 1
+`, buf.String())
+
+	assert.Panics(t, func() { c.Inc() })
+}
+
+func TestHistogramVec(t *testing.T) {
+	h := NewHistogramVec("test_duration_seconds", "A test histogram.", []float64{0.1, 1})
+	defer Unregister(h.Name())
+
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(2)
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, h.Write(buf))
+	assert.Equal(t, `# HELP test_duration_seconds A test histogram.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="0.1"} 1
+test_duration_seconds_bucket{le="1"} 2
+test_duration_seconds_bucket{le="+Inf"} 3
+test_duration_seconds_sum 2.55
+test_duration_seconds_count 3
+`, buf.String())
+}
+
+func TestGaugeFunc(t *testing.T) {
+	g := NewGaugeFunc("test_gauge", "A test gauge.", func() []Sample {
+		return []Sample{{Labels: []string{"z"}, Value: 2}, {Labels: []string{"a"}, Value: 1.5}}
+	}, "conn")
+	defer Unregister(g.Name())
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, WriteText(buf))
+	assert.Contains(t, buf.String(), `# TYPE test_gauge gauge
+test_gauge{conn="a"} 1.5
+test_gauge{conn="z"} 2
+`)
+
+	assert.Panics(t, func() { NewGaugeFunc("test_gauge", "", nil) })
+}
+
+func TestMiddleware(t *testing.T) {
+	app := context.NewApp()
+	route := app.Group("/admin", Middleware)
+	route.GET("/info/:__prefix", func(ctx *context.Context) {
+		ctx.HTML(http.StatusOK, "ok")
+	})
+	route.GET("/panic", func(ctx *context.Context) {
+		panic("oops")
+	})
+
+	serve := func(url string) {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		ctx := context.NewContext(req)
+		func() {
+			defer func() { _ = recover() }()
+			ctx.SetHandlers(app.Find(url, "get")).Next()
+		}()
+	}
+
+	serve("/admin/info/user")
+	serve("/admin/info/posts")
+	serve("/admin/panic")
+
+	assert.Equal(t, float64(2), HTTPRequests.Get(http.MethodGet, "/admin/info/:__prefix", "200"))
+	assert.Equal(t, float64(1), HTTPRequests.Get(http.MethodGet, "/admin/panic", "500"))
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, WriteText(buf))
+	assert.True(t, strings.Contains(buf.String(),
+		`goadmin_http_request_duration_seconds_count{method="GET",route="/admin/info/:__prefix"} 2`))
+}
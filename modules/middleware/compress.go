@@ -0,0 +1,186 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"github.com/glvd/go-admin/context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MinCompressLength is the min length of the bodies to be compressed.
+var MinCompressLength = 1024
+
+// EncoderFunc return a writer compressing into w with the level.
+type EncoderFunc func(w io.Writer, level int) (io.WriteCloser, error)
+
+type encoder struct {
+	name string
+	fn   EncoderFunc
+}
+
+var (
+	encoderLock sync.RWMutex
+	encoders    = []encoder{
+		{"br", func(w io.Writer, level int) (io.WriteCloser, error) {
+			if level <= 0 {
+				level = brotli.DefaultCompression
+			} else if level > brotli.BestCompression {
+				level = brotli.BestCompression
+			}
+			return brotli.NewWriterLevel(w, level), nil
+		}},
+		{"gzip", func(w io.Writer, level int) (io.WriteCloser, error) {
+			if level == 0 {
+				level = gzip.DefaultCompression
+			}
+			return gzip.NewWriterLevel(w, level)
+		}},
+		{"deflate", func(w io.Writer, level int) (io.WriteCloser, error) {
+			if level == 0 {
+				level = flate.DefaultCompression
+			}
+			return flate.NewWriter(w, level)
+		}},
+	}
+)
+
+// RegisterEncoder register an encoder of the Content-Encoding, the built-in
+// one of the same name, such as "br", "gzip" or "deflate", is replaced. The
+// encoders registered later are preferred when the client accepts several
+// ones with the same quality, and the built-in ones are preferred in the
+// order of br, gzip and deflate.
+func RegisterEncoder(name string, fn EncoderFunc) {
+	encoderLock.Lock()
+	defer encoderLock.Unlock()
+	list := []encoder{{name, fn}}
+	for _, e := range encoders {
+		if e.name != name {
+			list = append(list, e)
+		}
+	}
+	encoders = list
+}
+
+// Compress return a middleware compressing the buffered responses with the
+// encoder accepted by the client. The responses which are written directly,
+// such as the streams, the partial contents, the small bodies and the
+// bodies of the types not compressible are not compressed.
+func Compress(level int) context.Handler {
+	return func(ctx *context.Context) {
+		ctx.Next()
+
+		if ctx.Written() || ctx.Response.Body == nil {
+			return
+		}
+
+		code := ctx.Response.StatusCode
+		if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusPartialContent ||
+			code == http.StatusNotModified {
+			return
+		}
+
+		header := ctx.Response.Header
+		if header.Get("Content-Encoding") != "" || !compressible(header.Get("Content-Type")) {
+			return
+		}
+
+		ctx.Response.Header.Add("Vary", "Accept-Encoding")
+
+		name, fn := chooseEncoder(ctx.Headers("Accept-Encoding"))
+		if fn == nil {
+			return
+		}
+
+		body, err := ioutil.ReadAll(ctx.Response.Body)
+		_ = ctx.Response.Body.Close()
+		if err != nil || len(body) < MinCompressLength {
+			ctx.Response.Body = ioutil.NopCloser(bytes.NewReader(body))
+			return
+		}
+
+		buf := new(bytes.Buffer)
+		w, err := fn(buf, level)
+		if err == nil {
+			_, err = w.Write(body)
+			if closeErr := w.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			ctx.Response.Body = ioutil.NopCloser(bytes.NewReader(body))
+			return
+		}
+
+		header.Set("Content-Encoding", name)
+		header.Set("Content-Length", strconv.Itoa(buf.Len()))
+		ctx.Response.Body = ioutil.NopCloser(buf)
+	}
+}
+
+func compressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	if contentType == "" {
+		return false
+	}
+	for _, prefix := range []string{"text/", "application/json", "application/javascript",
+		"application/xml", "application/x-javascript", "image/svg+xml"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// chooseEncoder return the registered encoder of the highest quality in the
+// Accept-Encoding header.
+func chooseEncoder(accept string) (string, EncoderFunc) {
+	if accept == "" {
+		return "", nil
+	}
+
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		items := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(items[0]))
+		q := 1.0
+		for _, param := range items[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		qualities[name] = q
+	}
+
+	encoderLock.RLock()
+	defer encoderLock.RUnlock()
+
+	var (
+		best     encoder
+		bestQ    = 0.0
+		wildcard = qualities["*"]
+	)
+	for _, e := range encoders {
+		q, ok := qualities[e.name]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best.name, best.fn
+}
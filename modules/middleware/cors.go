@@ -0,0 +1,90 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD"}
+
+// CORS return a middleware handling the cross-origin requests. The
+// preflight requests are responded with 204 and not passed to the
+// following handlers. The requests from the disallowed origins are passed
+// without the CORS headers, so that the browsers block them.
+func CORS(cfg config.CORS) context.Handler {
+	var (
+		allowAll = false
+		origins  = make(map[string]bool)
+		methods  = strings.Join(upper(cfg.AllowMethods, defaultCORSMethods), ", ")
+		headers  = strings.Join(cfg.AllowHeaders, ", ")
+		expose   = strings.Join(cfg.ExposeHeaders, ", ")
+	)
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		origins[strings.ToLower(origin)] = true
+	}
+
+	return func(ctx *context.Context) {
+		origin := ctx.Headers("Origin")
+		if origin == "" {
+			ctx.Next()
+			return
+		}
+
+		ctx.AddHeader("Vary", "Origin")
+
+		if !allowAll && !origins[strings.ToLower(origin)] {
+			ctx.Next()
+			return
+		}
+
+		if allowAll && !cfg.AllowCredentials {
+			ctx.SetHeader("Access-Control-Allow-Origin", "*")
+		} else {
+			ctx.SetHeader("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			ctx.SetHeader("Access-Control-Allow-Credentials", "true")
+		}
+
+		if ctx.Method() == http.MethodOptions && ctx.Headers("Access-Control-Request-Method") != "" {
+			ctx.SetHeader("Access-Control-Allow-Methods", methods)
+			if headers != "" {
+				ctx.SetHeader("Access-Control-Allow-Headers", headers)
+			} else if requested := ctx.Headers("Access-Control-Request-Headers"); requested != "" {
+				ctx.SetHeader("Access-Control-Allow-Headers", requested)
+			}
+			if cfg.MaxAge > 0 {
+				ctx.SetHeader("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+			}
+			ctx.SetStatusCode(http.StatusNoContent)
+			ctx.Abort()
+			return
+		}
+
+		if expose != "" {
+			ctx.SetHeader("Access-Control-Expose-Headers", expose)
+		}
+		ctx.Next()
+	}
+}
+
+func upper(list, def []string) []string {
+	if len(list) == 0 {
+		return def
+	}
+	res := make([]string, len(list))
+	for i, item := range list {
+		res[i] = strings.ToUpper(item)
+	}
+	return res
+}
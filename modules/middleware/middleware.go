@@ -0,0 +1,38 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package middleware provides the reusable middleware of context.App, which
+// can be attached to App.Use, App.Group and RouterGroup.Group.
+package middleware
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+)
+
+// FromConfig return the middleware enabled in the config, in the order of
+// request id, recovery, security headers, CORS and compression.
+func FromConfig(cfg config.Middleware) context.Handlers {
+	handlers := make(context.Handlers, 0)
+	if cfg.RequestID {
+		handlers = append(handlers, RequestID())
+	}
+	if cfg.Recovery {
+		handlers = append(handlers, Recovery())
+	}
+	if cfg.SecurityHeaders {
+		handlers = append(handlers, SecurityHeaders(SecurityConfig{
+			ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+			HSTSMaxAge:            cfg.HSTSMaxAge,
+			FrameOptions:          cfg.FrameOptions,
+		}))
+	}
+	if len(cfg.CORS.AllowOrigins) > 0 {
+		handlers = append(handlers, CORS(cfg.CORS))
+	}
+	if cfg.Compress {
+		handlers = append(handlers, Compress(cfg.CompressLevel))
+	}
+	return handlers
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"github.com/andybalholm/brotli"
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(app *context.App, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+func TestRecoveryAndRequestID(t *testing.T) {
+	app := context.NewApp().Use(RequestID(), Recovery())
+	app.GET("/panic", func(ctx *context.Context) {
+		panic("oops")
+	})
+	app.GET("/id", func(ctx *context.Context) {
+		ctx.WriteString(ctx.RequestID())
+	})
+
+	w := serve(app, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Len(t, w.Header().Get(RequestIDHeader), 32)
+
+	req := httptest.NewRequest("GET", "/id", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	w = serve(app, req)
+	assert.Equal(t, "abc-123", w.Body.String())
+	assert.Equal(t, "abc-123", w.Header().Get(RequestIDHeader))
+
+	req = httptest.NewRequest("GET", "/id", nil)
+	req.Header.Set(RequestIDHeader, "bad id\n")
+	w = serve(app, req)
+	assert.NotEqual(t, "bad id\n", w.Body.String())
+}
+
+func TestCORS(t *testing.T) {
+	app := context.NewApp().Use(CORS(config.CORS{
+		AllowOrigins:     []string{"https://example.com"},
+		AllowHeaders:     []string{"Content-Type"},
+		ExposeHeaders:    []string{"X-Request-Id"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}))
+	app.POST("/api", func(ctx *context.Context) {
+		ctx.WriteString("ok")
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/api", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := serve(app, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, POST, PUT, PATCH, DELETE, HEAD", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+	req = httptest.NewRequest("POST", "/api", nil)
+	req.Header.Set("Origin", "https://example.com")
+	w = serve(app, req)
+	assert.Equal(t, "ok", w.Body.String())
+	assert.Equal(t, "X-Request-Id", w.Header().Get("Access-Control-Expose-Headers"))
+
+	req = httptest.NewRequest("POST", "/api", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	w = serve(app, req)
+	assert.Equal(t, "", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCompress(t *testing.T) {
+	body := strings.Repeat("GoAdmin ", 512)
+
+	app := context.NewApp().Use(Compress(0))
+	app.GET("/page", func(ctx *context.Context) {
+		ctx.HTML(http.StatusOK, body)
+	})
+	app.GET("/small", func(ctx *context.Context) {
+		ctx.HTML(http.StatusOK, "small")
+	})
+
+	req := httptest.NewRequest("GET", "/page", nil)
+	req.Header.Set("Accept-Encoding", "deflate;q=0.5, gzip")
+	w := serve(app, req)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	r, err := gzip.NewReader(w.Body)
+	assert.Nil(t, err)
+	plain, _ := ioutil.ReadAll(r)
+	assert.Equal(t, body, string(plain))
+
+	req = httptest.NewRequest("GET", "/small", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w = serve(app, req)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "small", w.Body.String())
+
+	w = serve(app, httptest.NewRequest("GET", "/page", nil))
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+
+	req = httptest.NewRequest("GET", "/page", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	w = serve(app, req)
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	plain, _ = ioutil.ReadAll(brotli.NewReader(w.Body))
+	assert.Equal(t, body, string(plain))
+
+	saved := encoders
+	RegisterEncoder("br", func(w io.Writer, level int) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	})
+	defer func() {
+		encoders = saved
+	}()
+	req = httptest.NewRequest("GET", "/page", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	w = serve(app, req)
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	assert.True(t, bytes.Equal([]byte(body), w.Body.Bytes()))
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestSecurityHeaders(t *testing.T) {
+	app := context.NewApp().Use(FromConfig(config.Middleware{
+		SecurityHeaders:       true,
+		ContentSecurityPolicy: "default-src 'self'",
+		HSTSMaxAge:            31536000,
+	})...)
+	app.GET("/", func(ctx *context.Context) {})
+
+	w := serve(app, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "default-src 'self'", w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "SAMEORIGIN", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "", w.Header().Get("Strict-Transport-Security"))
+
+	// The X-Forwarded-Proto header of the clients is ignored.
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = serve(app, req)
+	assert.Equal(t, "", w.Header().Get("Strict-Transport-Security"))
+
+	proxies, err := context.ParseTrustedProxies("192.0.2.0/24")
+	assert.Nil(t, err)
+	trusted := context.NewApp().Use(context.TrustProxies(proxies)).Use(FromConfig(config.Middleware{
+		SecurityHeaders: true,
+		HSTSMaxAge:      31536000,
+	})...)
+	trusted.GET("/", func(ctx *context.Context) {})
+	w = serve(trusted, req)
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.TLS = &tls.ConnectionState{}
+	w = serve(app, req)
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
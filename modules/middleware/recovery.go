@@ -0,0 +1,32 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"fmt"
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/logger"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recovery return a middleware which recovers the panics of the following
+// handlers, logs them with the stack and the request id, and responds 500.
+func Recovery() context.Handler {
+	return func(ctx *context.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				logger.Error(fmt.Sprintf("panic recovered: %v, request_id=%s, %s %s\n%s",
+					err, ctx.RequestID(), ctx.Method(), ctx.Path(), debug.Stack()))
+				if !ctx.Written() {
+					ctx.Data(http.StatusInternalServerError, "text/plain; charset=utf-8",
+						[]byte(http.StatusText(http.StatusInternalServerError)))
+				}
+				ctx.Abort()
+			}
+		}()
+		ctx.Next()
+	}
+}
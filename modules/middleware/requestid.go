@@ -0,0 +1,48 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/glvd/go-admin/context"
+)
+
+// RequestIDHeader is the header of the request id.
+const RequestIDHeader = "X-Request-Id"
+
+// RequestID return a middleware which takes the request id from the
+// X-Request-Id header, or generates one, and saves it into the Context
+// and the response header. The request id is written into the access
+// logs and the panic logs.
+func RequestID() context.Handler {
+	return func(ctx *context.Context) {
+		id := ctx.Headers(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		ctx.SetUserValue(context.RequestIDKey, id)
+		ctx.SetHeader(RequestIDHeader, id)
+		ctx.Next()
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"github.com/glvd/go-admin/context"
+	"strconv"
+)
+
+// SecurityConfig is the config of SecurityHeaders.
+type SecurityConfig struct {
+	// The Content-Security-Policy header, empty means not set.
+	ContentSecurityPolicy string
+	// The max age of the Strict-Transport-Security header in seconds, which
+	// is set for the https requests only, see context.Context.IsHTTPS. Zero
+	// means not set.
+	HSTSMaxAge int
+	// The X-Frame-Options header, default "SAMEORIGIN".
+	FrameOptions string
+}
+
+// SecurityHeaders return a middleware which sets the security headers:
+// Content-Security-Policy, Strict-Transport-Security, X-Frame-Options,
+// X-Content-Type-Options, X-XSS-Protection and Referrer-Policy.
+func SecurityHeaders(cfg SecurityConfig) context.Handler {
+	if cfg.FrameOptions == "" {
+		cfg.FrameOptions = "SAMEORIGIN"
+	}
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge) + "; includeSubDomains"
+	}
+	return func(ctx *context.Context) {
+		if cfg.ContentSecurityPolicy != "" {
+			ctx.SetHeader("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		if hsts != "" && ctx.IsHTTPS() {
+			ctx.SetHeader("Strict-Transport-Security", hsts)
+		}
+		ctx.SetHeader("X-Frame-Options", cfg.FrameOptions)
+		ctx.SetHeader("X-Content-Type-Options", "nosniff")
+		ctx.SetHeader("X-XSS-Protection", "1; mode=block")
+		ctx.SetHeader("Referrer-Policy", "strict-origin-when-cross-origin")
+		ctx.Next()
+	}
+}
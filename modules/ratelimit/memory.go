@@ -0,0 +1,93 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store keeping the buckets in memory. The buckets which
+// are refilled full are removed when the store is swept.
+type MemoryStore struct {
+	lock    sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+// sweepEvery is the number of the Take calls between the sweeps.
+const sweepEvery = 1000
+
+// NewMemoryStore return an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take implements the Store.Take.
+func (m *MemoryStore) Take(key string, rate float64, burst int) (Result, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+
+	if m.takes++; m.takes >= sweepEvery {
+		m.takes = 0
+		m.sweep(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		m.buckets[key] = b
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+elapsed*rate)
+		b.last = now
+	}
+
+	if rate > 0 {
+		b.full = now.Add(time.Duration((float64(burst) - b.tokens + 1) / rate * float64(time.Second)))
+	} else {
+		b.full = time.Time{}
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+	}
+
+	res := Result{Allowed: false, Remaining: 0}
+	if rate > 0 {
+		res.RetryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	} else {
+		res.RetryAfter = time.Duration(math.MaxInt64)
+	}
+	return res, nil
+}
+
+// Len return the number of the buckets.
+func (m *MemoryStore) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.buckets)
+}
+
+func (m *MemoryStore) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if !b.full.IsZero() && now.After(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package ratelimit limits the rate of the requests with the token buckets,
+// which are keyed by the user, the client ip or the route.
+package ratelimit
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result is the result of taking a token from a bucket.
+type Result struct {
+	// Allowed is true if a token is taken.
+	Allowed bool
+	// Remaining is the number of the tokens left in the bucket.
+	Remaining int
+	// RetryAfter is the duration until a token is available, when the
+	// token is not allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps the token buckets. The buckets hold at most burst tokens and
+// are refilled at rate tokens per second. A shared store, such as a redis
+// store, makes the limits work across the instances.
+type Store interface {
+	// Take take a token from the bucket of the key, the bucket is created
+	// full if not exists.
+	Take(key string, rate float64, burst int) (Result, error)
+}
+
+// KeyFunc return the key of the bucket of the request. The request is not
+// limited if the key is empty.
+type KeyFunc func(ctx *context.Context) string
+
+// ByIP key the buckets by the client ip, see Context.LocalIP for the
+// trusted proxies.
+func ByIP(ctx *context.Context) string {
+	return "ip:" + ctx.LocalIP()
+}
+
+// ByUser key the buckets by the id of the login user, or the client ip if
+// no user logged in. The limit should be added after the auth middleware.
+func ByUser(ctx *context.Context) string {
+	if user, ok := ctx.User().(models.UserModel); ok && user.Id != 0 {
+		return "user:" + strconv.FormatInt(user.Id, 10)
+	}
+	return ByIP(ctx)
+}
+
+// ByRoute key the buckets by the method and the matched route, so that all
+// the clients share the buckets.
+func ByRoute(ctx *context.Context) string {
+	return "route:" + ctx.Method() + " " + ctx.Route()
+}
+
+// Keys combine the key functions, for example Keys(ByUser, ByRoute) limits
+// each user on each route.
+func Keys(fns ...KeyFunc) KeyFunc {
+	return func(ctx *context.Context) string {
+		keys := make([]string, len(fns))
+		for i, fn := range fns {
+			if keys[i] = fn(ctx); keys[i] == "" {
+				return ""
+			}
+		}
+		return strings.Join(keys, "|")
+	}
+}
+
+// Every return the rate of one token per interval, for example
+// Every(time.Minute / 10) is 10 requests per minute.
+func Every(interval time.Duration) float64 {
+	if interval <= 0 {
+		return math.Inf(1)
+	}
+	return float64(time.Second) / float64(interval)
+}
+
+// Limit is the config of a limiter.
+type Limit struct {
+	// Rate is the number of the tokens refilled per second.
+	Rate float64
+	// Burst is the max number of the tokens in a bucket, it is the ceiling
+	// of Rate if zero.
+	Burst int
+	// Key return the bucket key of the request, default ByIP.
+	Key KeyFunc
+	// Store keeps the buckets, default a new MemoryStore.
+	Store Store
+	// Name prefixes the bucket keys, which should be set to tell the
+	// limiters apart when they share a store.
+	Name string
+}
+
+// New return a middleware which limits the rate of the requests. If the
+// bucket of the request is empty, it responds 429 Too Many Requests with
+// the Retry-After header and aborts. The errors of the store are logged
+// and the requests are allowed.
+//
+// The middleware can be added to a RouterGroup:
+//
+//	group := app.Group("/api", ratelimit.New(ratelimit.Limit{
+//		Rate:  ratelimit.Every(time.Second),
+//		Burst: 10,
+//		Key:   ratelimit.ByUser,
+//	}))
+func New(limit Limit) context.Handler {
+	if limit.Burst <= 0 {
+		limit.Burst = int(math.Ceil(limit.Rate))
+		if limit.Burst <= 0 {
+			limit.Burst = 1
+		}
+	}
+	if limit.Key == nil {
+		limit.Key = ByIP
+	}
+	if limit.Store == nil {
+		limit.Store = NewMemoryStore()
+	}
+	burst := strconv.Itoa(limit.Burst)
+
+	return func(ctx *context.Context) {
+		key := limit.Key(ctx)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		res, err := limit.Store.Take(limit.Name+key, limit.Rate, limit.Burst)
+		if err != nil {
+			logger.Error("rate limit error: ", err)
+			ctx.Next()
+			return
+		}
+
+		ctx.SetHeader("X-RateLimit-Limit", burst)
+		ctx.SetHeader("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+
+		if !res.Allowed {
+			ctx.SetHeader("Retry-After", strconv.FormatInt(int64(math.Ceil(res.RetryAfter.Seconds())), 10))
+			ctx.Data(http.StatusTooManyRequests, "text/plain; charset=utf-8", []byte("429 Too Many Requests"))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(1000, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		res, err := store.Take("a", 1, 3)
+		assert.Nil(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2-i, res.Remaining)
+	}
+
+	res, _ := store.Take("a", 1, 3)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	res, _ = store.Take("b", 1, 3)
+	assert.True(t, res.Allowed)
+
+	now = now.Add(1500 * time.Millisecond)
+	res, _ = store.Take("a", 1, 3)
+	assert.True(t, res.Allowed)
+	res, _ = store.Take("a", 1, 3)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	now = now.Add(time.Hour)
+	store.sweep(now)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestNew(t *testing.T) {
+	app := context.NewApp()
+	app.GET("/export", New(Limit{Rate: Every(time.Minute), Burst: 2}), func(ctx *context.Context) {
+		ctx.WriteString("ok")
+	})
+
+	serve := func(remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/export", nil)
+		req.RemoteAddr = remote
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, serve("10.0.0.1:1234").Code)
+	w := serve("10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = serve("10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, serve("10.0.0.2:1234").Code)
+}
+
+func TestKeys(t *testing.T) {
+	ctx := context.NewContext(httptest.NewRequest("POST", "/signin", nil))
+	ctx.Request.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "ip:10.0.0.1", ByUser(ctx))
+
+	ctx.SetUserValue("user", models.UserModel{Id: 7})
+	assert.Equal(t, "user:7", ByUser(ctx))
+	assert.Equal(t, "user:7|route:POST ", Keys(ByUser, ByRoute)(ctx))
+	assert.Equal(t, "", Keys(ByUser, func(ctx *context.Context) string { return "" })(ctx))
+}
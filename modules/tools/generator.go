@@ -0,0 +1,554 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package tools
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/template/types/form"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// SystemTablePrefix is the prefix of the tables of the admin, which are
+// skipped by Tables.
+const SystemTablePrefix = "adm_"
+
+// Param is the parameters of the generation.
+type Param struct {
+	// The connection of the database.
+	Conn db.Connection
+	// The name of the connection, default "default".
+	Connection string
+	// The driver of the connection, default the name of the Conn.
+	Driver string
+	// The package name of the generated files, default "main".
+	Package string
+	// The directory the files are written into, default the current
+	// directory.
+	Output string
+	// The tables to generate, empty means all the tables except the
+	// tables of the admin.
+	Tables []string
+}
+
+func (param Param) connection() string {
+	if param.Connection == "" {
+		return "default"
+	}
+	return param.Connection
+}
+
+func (param Param) driver() string {
+	if param.Driver == "" && param.Conn != nil {
+		return param.Conn.Name()
+	}
+	return param.Driver
+}
+
+func (param Param) pkg() string {
+	if param.Package == "" {
+		return "main"
+	}
+	return param.Package
+}
+
+// Column is a column of the table schema.
+type Column struct {
+	Name          string
+	Type          db.DatabaseType
+	Primary       bool
+	AutoIncrement bool
+	Nullable      bool
+	HasDefault    bool
+}
+
+// Schema is the schema of a table.
+type Schema struct {
+	Table   string
+	Columns []Column
+}
+
+// PrimaryKey return the primary key column of the table, the column named
+// "id" is the primary key if none is marked.
+func (s Schema) PrimaryKey() (Column, bool) {
+	for _, col := range s.Columns {
+		if col.Primary {
+			return col, true
+		}
+	}
+	for _, col := range s.Columns {
+		if col.Name == "id" {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// Generate inspect the tables of the param and write a file of the table
+// generator for each of them, and the tables.go registering them into a
+// GeneratorList.
+func Generate(param Param) error {
+	if param.Conn == nil {
+		return errors.New("generate: the connection is nil")
+	}
+
+	tables := param.Tables
+	if len(tables) == 0 {
+		var err error
+		if tables, err = Tables(param.Conn, param.connection()); err != nil {
+			return err
+		}
+	}
+	if len(tables) == 0 {
+		return errors.New("generate: no tables")
+	}
+
+	output := param.Output
+	if output == "" {
+		output = "."
+	}
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		schema, err := Inspect(param.Conn, param.connection(), table)
+		if err != nil {
+			return err
+		}
+		content, err := GenerateTable(param, schema)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(output, table+".go"), content, 0644); err != nil {
+			return err
+		}
+	}
+
+	content, err := GenerateList(param, tables)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(output, "tables.go"), content, 0644)
+}
+
+// Tables return the sorted tables of the connection, except the tables of
+// the admin and the system tables of sqlite.
+func Tables(conn db.Connection, connection string) (tables []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("show tables: %v", r)
+		}
+	}()
+
+	models, err := db.WithDriverAndConnection(connection, conn).ShowTables()
+	if err != nil {
+		return nil, err
+	}
+
+	tables = make([]string, 0, len(models))
+	for _, model := range models {
+		name := tableName(model)
+		if name == "" || name == "sqlite_sequence" || strings.HasPrefix(name, SystemTablePrefix) {
+			continue
+		}
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+func tableName(model map[string]interface{}) string {
+	for _, key := range []string{"tablename", "TABLE_NAME", "table_name"} {
+		if v, ok := model[key]; ok {
+			return toString(v)
+		}
+	}
+	// mysql: Tables_in_{database}
+	for key, v := range model {
+		if strings.HasPrefix(key, "Tables_in_") || len(model) == 1 {
+			return toString(v)
+		}
+	}
+	return ""
+}
+
+// Inspect return the schema of the table read from the connection.
+func Inspect(conn db.Connection, connection, table string) (schema Schema, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("show columns of %s: %v", table, r)
+		}
+	}()
+
+	models, err := db.WithDriverAndConnection(connection, conn).Table(table).ShowColumns()
+	if err != nil {
+		return schema, err
+	}
+	if len(models) == 0 {
+		return schema, fmt.Errorf("table %s is not found", table)
+	}
+
+	schema.Table = table
+	schema.Columns = make([]Column, len(models))
+
+	switch conn.Name() {
+	case db.DriverMysql:
+		for i, model := range models {
+			schema.Columns[i] = Column{
+				Name:          toString(model["Field"]),
+				Type:          GetType(toString(model["Type"])),
+				Primary:       toString(model["Key"]) == "PRI",
+				AutoIncrement: strings.Contains(toString(model["Extra"]), "auto_increment"),
+				Nullable:      toString(model["Null"]) == "YES",
+				HasDefault:    model["Default"] != nil,
+			}
+		}
+	case db.DriverSqlite:
+		for i, model := range models {
+			primary := toString(model["pk"]) != "0" && toString(model["pk"]) != ""
+			typ := toString(model["type"])
+			schema.Columns[i] = Column{
+				Name:          toString(model["name"]),
+				Type:          GetType(typ),
+				Primary:       primary,
+				AutoIncrement: primary && strings.EqualFold(typ, "integer"),
+				Nullable:      toString(model["notnull"]) == "0",
+				HasDefault:    model["dflt_value"] != nil,
+			}
+		}
+	default:
+		typeField := "data_type"
+		if conn.Name() == db.DriverPostgresql {
+			typeField = "udt_name"
+		}
+		primaries, err := primaryKeys(conn, connection, table)
+		if err != nil {
+			return schema, err
+		}
+		for i, model := range models {
+			name := toString(model["column_name"])
+			def := toString(model["column_default"])
+			schema.Columns[i] = Column{
+				Name:          name,
+				Type:          GetType(toString(model[typeField])),
+				Primary:       primaries[name],
+				AutoIncrement: strings.Contains(def, "nextval") || (conn.Name() == db.DriverMssql && primaries[name]),
+				Nullable:      toString(model["is_nullable"]) == "YES",
+				HasDefault:    def != "",
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+// primaryKeys return the primary key columns of the table from the
+// information schema.
+func primaryKeys(conn db.Connection, connection, table string) (map[string]bool, error) {
+	models, err := conn.QueryWithConnection(connection, "select kcu.column_name as column_name "+
+		"from information_schema.table_constraints tc "+
+		"join information_schema.key_column_usage kcu on tc.constraint_name = kcu.constraint_name "+
+		"and tc.table_name = kcu.table_name "+
+		"where tc.table_name = '"+strings.Replace(table, "'", "''", -1)+"' and tc.constraint_type = 'PRIMARY KEY'")
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(models))
+	for _, model := range models {
+		keys[toString(model["column_name"])] = true
+	}
+	return keys, nil
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	default:
+		return fmt.Sprintf("%v", s)
+	}
+}
+
+var lengthReg = regexp.MustCompile(`\(.*?\)`)
+
+// typeAlias is the column types of the drivers which are not the names of
+// the DatabaseType.
+var typeAlias = map[string]db.DatabaseType{
+	"int2":                        db.Smallint,
+	"int8":                        db.Bigint,
+	"float4":                      db.Real,
+	"float8":                      db.Doubleprecision,
+	"double precision":            db.Doubleprecision,
+	"bool":                        db.Boolean,
+	"bpchar":                      db.Char,
+	"character varying":           db.Varchar,
+	"varying character":           db.Varyingcharacter,
+	"native character":            db.Nativecharacter,
+	"timestamp without time zone": db.Timestamp,
+	"timestamp with time zone":    db.Timestamptz,
+	"time without time zone":      db.Time,
+	"time with time zone":         db.Time,
+	"timetz":                      db.Time,
+	"datetime2":                   db.Datetime,
+	"smalldatetime":               db.Datetime,
+	"datetimeoffset":              db.Datetime,
+	"jsonb":                       db.JSON,
+	"bytea":                       db.Blob,
+	"image":                       db.Blob,
+	"ntext":                       db.Text,
+	"uniqueidentifier":            db.UUID,
+	"smallmoney":                  db.Money,
+}
+
+// GetType return the DatabaseType of the column type of the drivers, such
+// as "int(11) unsigned" and "character varying". The unknown types are
+// treated as varchar.
+func GetType(typeName string) db.DatabaseType {
+	typeName = strings.ToLower(strings.TrimSpace(lengthReg.ReplaceAllString(typeName, "")))
+	for _, suffix := range []string{" unsigned", " zerofill"} {
+		typeName = strings.TrimSpace(strings.Replace(typeName, suffix, "", -1))
+	}
+	if typ, ok := typeAlias[typeName]; ok {
+		return typ
+	}
+	typ := db.DatabaseType(strings.ToUpper(typeName))
+	if db.Contains(typ, db.BoolTypeList) || db.Contains(typ, db.IntTypeList) ||
+		db.Contains(typ, db.FloatTypeList) || db.Contains(typ, db.UintTypeList) ||
+		db.Contains(typ, db.StringTypeList) {
+		return typ
+	}
+	return db.Varchar
+}
+
+// typeIdent return the identifier of the DatabaseType in the package db.
+func typeIdent(typ db.DatabaseType) string {
+	switch typ {
+	case db.JSON:
+		return "db.JSON"
+	case db.UUID:
+		return "db.UUID"
+	}
+	return "db." + strings.Title(strings.ToLower(string(typ)))
+}
+
+// Camel return the camel case of the snake case name, such as user_posts
+// to UserPosts.
+func Camel(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' ' || r == '.'
+	}) {
+		b.WriteString(strings.Title(word))
+	}
+	return b.String()
+}
+
+// Label return the label of the column, such as created_at to Created at.
+func Label(name string) string {
+	if strings.ToLower(name) == "id" {
+		return "ID"
+	}
+	words := strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-'
+	}), " ")
+	if words == "" {
+		return name
+	}
+	return strings.ToUpper(words[:1]) + words[1:]
+}
+
+type fieldData struct {
+	Head       string
+	Name       string
+	Type       string
+	FormType   string
+	InfoOption string
+	FormOption string
+}
+
+type tableData struct {
+	Package    string
+	Func       string
+	Var        string
+	Table      string
+	Title      string
+	Config     string
+	InfoFields []fieldData
+	FormFields []fieldData
+}
+
+var tableTmpl = template.Must(template.New("table").Parse(`package {{.Package}}
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/plugins/admin/modules/table"
+	"github.com/glvd/go-admin/template/types/form"
+)
+
+// {{.Func}} return the table of {{.Table}}.
+func {{.Func}}() table.Table {
+
+	{{.Var}} := table.NewDefaultTable({{.Config}})
+
+	info := {{.Var}}.GetInfo()
+{{range .InfoFields}}
+	info.AddField({{printf "%q" .Head}}, {{printf "%q" .Name}}, {{.Type}}){{.InfoOption}}{{end}}
+
+	info.SetTable({{printf "%q" .Table}}).SetTitle({{printf "%q" .Title}}).SetDescription({{printf "%q" .Title}})
+
+	formList := {{.Var}}.GetForm()
+{{range .FormFields}}
+	formList.AddField({{printf "%q" .Head}}, {{printf "%q" .Name}}, {{.Type}}, {{.FormType}}){{.FormOption}}{{end}}
+
+	formList.SetTable({{printf "%q" .Table}}).SetTitle({{printf "%q" .Title}}).SetDescription({{printf "%q" .Title}})
+
+	return {{.Var}}
+}
+`))
+
+// GenerateTable return the formatted source of the table generator of the
+// schema. The primary key is sortable and can not be edited, the auto
+// increment primary key can not be added, the numeric and the time
+// columns are sortable, the short string columns are filterable, and the
+// columns which are not null without a default are required.
+func GenerateTable(param Param, schema Schema) ([]byte, error) {
+	camel := Camel(schema.Table)
+	if camel == "" {
+		return nil, fmt.Errorf("generate: invalid table name %q", schema.Table)
+	}
+
+	cfg := fmt.Sprintf("table.DefaultConfigWithDriver(%q)", param.driver())
+	if param.connection() != "default" {
+		cfg = fmt.Sprintf("table.DefaultConfigWithDriverAndConnection(%q, %q)", param.driver(), param.connection())
+	}
+	pk, hasPK := schema.PrimaryKey()
+	if hasPK && (pk.Name != "id" || pk.Type != db.Int) {
+		cfg += fmt.Sprintf(".SetPrimaryKey(%q, %s)", pk.Name, typeIdent(pk.Type))
+	}
+
+	variable := strings.ToLower(camel[:1]) + camel[1:] + "Table"
+	if camel[0] >= '0' && camel[0] <= '9' {
+		variable = "table" + camel
+	}
+
+	data := tableData{
+		Package:    param.pkg(),
+		Func:       "Get" + camel + "Table",
+		Var:        variable,
+		Table:      schema.Table,
+		Title:      Label(schema.Table),
+		Config:     cfg,
+		InfoFields: make([]fieldData, len(schema.Columns)),
+		FormFields: make([]fieldData, len(schema.Columns)),
+	}
+
+	for i, col := range schema.Columns {
+		field := fieldData{
+			Head:     Label(col.Name),
+			Name:     col.Name,
+			Type:     typeIdent(col.Type),
+			FormType: form.GetFormTypeFromFieldType(col.Type, col.Name),
+		}
+
+		isPK := hasPK && col.Name == pk.Name
+
+		switch {
+		case isPK, sortable(col.Type):
+			field.InfoOption = ".FieldSortable()"
+		case filterable(col.Type):
+			field.InfoOption = ".FieldFilterable()"
+		}
+		data.InfoFields[i] = field
+
+		switch {
+		case isPK && col.AutoIncrement:
+			field.FormType = "form.Default"
+			field.FormOption = ".FieldNotAllowEdit().FieldNotAllowAdd()"
+		case isPK:
+			field.FormOption = ".FieldNotAllowEdit().FieldMust()"
+		case !col.Nullable && !col.HasDefault:
+			field.FormOption = ".FieldMust()"
+		}
+		data.FormFields[i] = field
+	}
+
+	return execute(tableTmpl, data)
+}
+
+func sortable(typ db.DatabaseType) bool {
+	switch typ {
+	case db.Date, db.Time, db.Year, db.Datetime, db.Timestamp, db.Timestamptz:
+		return true
+	}
+	return db.Contains(typ, db.IntTypeList) || db.Contains(typ, db.FloatTypeList) ||
+		db.Contains(typ, db.UintTypeList)
+}
+
+func filterable(typ db.DatabaseType) bool {
+	switch typ {
+	case db.Varchar, db.Char, db.Nvarchar, db.Nchar, db.Character, db.Varyingcharacter,
+		db.Nativecharacter, db.Enum, db.UUID, db.Inet:
+		return true
+	}
+	return db.Contains(typ, db.BoolTypeList)
+}
+
+var listTmpl = template.Must(template.New("list").Parse(`package {{.Package}}
+
+import "github.com/glvd/go-admin/plugins/admin/modules/table"
+
+// Generators is the generators of the tables, the key is the prefix of
+// the table info url, such as:
+//
+{{range .Tables}}//	"{{.Name}}" => {prefix}/info/{{.Name}}
+{{end}}var Generators = table.GeneratorList{ {{range .Tables}}
+	{{printf "%q" .Name}}: {{.Func}},{{end}}
+}
+`))
+
+// GenerateList return the formatted source of the GeneratorList which
+// registers the generators of the tables.
+func GenerateList(param Param, tables []string) ([]byte, error) {
+	type item struct {
+		Name string
+		Func string
+	}
+	data := struct {
+		Package string
+		Tables  []item
+	}{Package: param.pkg()}
+
+	for _, table := range tables {
+		data.Tables = append(data.Tables, item{Name: table, Func: "Get" + Camel(table) + "Table"})
+	}
+
+	return execute(listTmpl, data)
+}
+
+func execute(tmpl *template.Template, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generate: format source: %s", err)
+	}
+	return src, nil
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGetType(t *testing.T) {
+	assert.Equal(t, db.Int, GetType("int(11) unsigned"))
+	assert.Equal(t, db.Varchar, GetType("varchar(255)"))
+	assert.Equal(t, db.Varchar, GetType("character varying"))
+	assert.Equal(t, db.Timestamp, GetType("timestamp without time zone"))
+	assert.Equal(t, db.Doubleprecision, GetType("float8"))
+	assert.Equal(t, db.Tinyint, GetType("TINYINT(1)"))
+	assert.Equal(t, db.Varchar, GetType("unknown_type"))
+}
+
+func TestCamelAndLabel(t *testing.T) {
+	assert.Equal(t, "UserPosts", Camel("user_posts"))
+	assert.Equal(t, "ID", Label("id"))
+	assert.Equal(t, "Created at", Label("created_at"))
+}
+
+func TestGenerateTable(t *testing.T) {
+	src, err := GenerateTable(Param{Driver: "mysql", Package: "tables"}, Schema{
+		Table: "user_posts",
+		Columns: []Column{
+			{Name: "id", Type: db.Int, Primary: true, AutoIncrement: true},
+			{Name: "title", Type: db.Varchar},
+			{Name: "content", Type: db.Text, Nullable: true},
+			{Name: "created_at", Type: db.Timestamp, HasDefault: true},
+		},
+	})
+	assert.NoError(t, err)
+
+	code := string(src)
+	assert.True(t, strings.HasPrefix(code, "package tables\n"))
+	assert.Contains(t, code, "func GetUserPostsTable() table.Table {")
+	assert.Contains(t, code, `userPostsTable := table.NewDefaultTable(table.DefaultConfigWithDriver("mysql"))`)
+	assert.Contains(t, code, `info.AddField("ID", "id", db.Int).FieldSortable()`)
+	assert.Contains(t, code, `info.AddField("Title", "title", db.Varchar).FieldFilterable()`)
+	assert.Contains(t, code, `info.AddField("Content", "content", db.Text)`+"\n")
+	assert.Contains(t, code, `info.AddField("Created at", "created_at", db.Timestamp).FieldSortable()`)
+	assert.Contains(t, code, `formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()`)
+	assert.Contains(t, code, `formList.AddField("Title", "title", db.Varchar, form.Text).FieldMust()`)
+	assert.Contains(t, code, `formList.AddField("Content", "content", db.Text, form.RichText)`+"\n")
+	assert.Contains(t, code, `info.SetTable("user_posts").SetTitle("User posts").SetDescription("User posts")`)
+}
+
+func TestGenerateTable_PrimaryKey(t *testing.T) {
+	src, err := GenerateTable(Param{Driver: "postgresql", Connection: "blog"}, Schema{
+		Table: "tags",
+		Columns: []Column{
+			{Name: "code", Type: db.Varchar, Primary: true},
+			{Name: "name", Type: db.Varchar, Nullable: true},
+		},
+	})
+	assert.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, `table.DefaultConfigWithDriverAndConnection("postgresql", "blog").SetPrimaryKey("code", db.Varchar)`)
+	assert.Contains(t, code, `formList.AddField("Code", "code", db.Varchar, form.Text).FieldNotAllowEdit().FieldMust()`)
+}
+
+func TestGenerateList(t *testing.T) {
+	src, err := GenerateList(Param{}, []string{"posts", "user_posts"})
+	assert.NoError(t, err)
+
+	code := string(src)
+	assert.True(t, strings.HasPrefix(code, "package main\n"))
+	assert.Contains(t, code, `"posts":      GetPostsTable,`)
+	assert.Contains(t, code, `"user_posts": GetUserPostsTable,`)
+}
@@ -2,47 +2,84 @@ package admin
 
 import (
 	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/cache"
 	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/modules/ratelimit"
 	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/modules/system"
 	"github.com/glvd/go-admin/plugins"
 	"github.com/glvd/go-admin/plugins/admin/controller"
+	"github.com/glvd/go-admin/plugins/admin/modules/dynamic"
 	"github.com/glvd/go-admin/plugins/admin/modules/table"
+	"github.com/glvd/go-admin/plugins/admin/modules/views"
 	"github.com/glvd/go-admin/template/types"
+	"net/http"
+	"time"
 )
 
 // Admin is a GoAdmin plugin.
 type Admin struct {
-	app      *context.App
-	tableCfg table.GeneratorList
+	app        *context.App
+	tableCfg   table.GeneratorList
+	tables     *table.List
+	handler    *controller.Handler
+	dynamic    *dynamic.Store
+	rateLimits map[string]context.Handler
 }
 
-// InitPlugin implements Plugin.InitPlugin.
+// InitPlugin implements Plugin.InitPlugin. The config is got from the
+// config service of the engine, or the global config if it is missing.
 func (admin *Admin) InitPlugin(services service.List) {
 
-	cfg := config.Get()
+	cfg := config.GetFromServices(services)
+
+	admin.tables.SetGenerators(table.NewBuiltin(cfg, services).Generators())
+	admin.tables.SetServices(services)
+	admin.tables.SetGenerators(admin.tableCfg)
+	admin.tables.Init()
+
+	admin.handler.SetConfigService(config.ServiceFrom(services)).SetServices(services)
+
+	// The tables defined from the UI are resolved after the compiled ones.
+	if admin.dynamic == nil {
+		admin.dynamic = dynamic.NewStore(admin.handler.Conn())
+		admin.tables.AddSource(admin.dynamic)
+	}
+	if err := admin.dynamic.Load(); err != nil {
+		logger.Error("load dynamic tables error: ", err)
+	}
+	admin.handler.SetDynamicStore(admin.dynamic)
+
+	if err := views.Migrate(admin.handler.Conn()); err != nil {
+		logger.Error("migrate views error: ", err)
+	}
+	admin.handler.SetViewStore(views.NewStore(admin.handler.Conn()))
 
 	// Init router
-	App.app = InitRouter(cfg.Prefix(), services)
+	admin.app = admin.initRouter(cfg, services)
+
+	if inst := admin.handler.Installer(); inst.Allow(admin.handler.Conn(), inst.Token()) {
+		logger.Warn("the installer is enabled: ", cfg.Url("/install?__token="+inst.Token()))
+	}
+}
 
-	table.SetGenerators(table.GeneratorList{
-		"manager":        table.GetManagerTable,
-		"permission":     table.GetPermissionTable,
-		"roles":          table.GetRolesTable,
-		"op":             table.GetOpTable,
-		"menu":           table.GetMenuTable,
-		"normal_manager": table.GetNormalManagerTable,
-	})
-	table.SetServices(services)
-	table.SetGenerators(admin.tableCfg)
-	table.InitTableList()
+// Name implements plugins.Info.Name.
+func (admin *Admin) Name() string {
+	return "admin"
+}
 
-	controller.SetConfig(cfg)
-	controller.SetServices(services)
+// Version implements plugins.Info.Version.
+func (admin *Admin) Version() string {
+	return system.Version()
 }
 
-// App is the global Admin plugin.
+// App is the global Admin plugin, which serves the default table list.
 var App = &Admin{
-	tableCfg: make(table.GeneratorList),
+	tableCfg:   make(table.GeneratorList),
+	tables:     table.DefaultList(),
+	handler:    controller.Default(),
+	rateLimits: make(map[string]context.Handler),
 }
 
 // NewAdmin return the global Admin plugin.
@@ -51,9 +88,107 @@ func NewAdmin(tableCfg ...table.GeneratorList) *Admin {
 	return App
 }
 
+// New return a new Admin plugin with its own tables and controllers, which
+// can be added to an engine besides the global one, such as an engine
+// created by engine.New with another prefix and databases.
+func New(tableCfg ...table.GeneratorList) *Admin {
+	tables := table.NewList()
+	admin := &Admin{
+		tableCfg:   make(table.GeneratorList),
+		tables:     tables,
+		handler:    controller.New(tables),
+		rateLimits: make(map[string]context.Handler),
+	}
+	admin.tableCfg.CombineAll(tableCfg)
+	return admin
+}
+
+// Tables return the table list of the admin.
+func (admin *Admin) Tables() *table.List {
+	return admin.tables
+}
+
 // SetCaptcha set captcha driver.
 func (admin *Admin) SetCaptcha(captcha map[string]string) *Admin {
-	controller.SetCaptcha(captcha)
+	admin.handler.SetCaptcha(captcha)
+	return admin
+}
+
+// EnableCache cache the data sets, the menus and the permissions in memory
+// with the ttl. The cached values are invalidated when the tables are
+// modified by the engine. The store is shared by the engines, in which the
+// values are keyed by the connections, see db.CacheConn.
+func (admin *Admin) EnableCache(ttl time.Duration) *Admin {
+	cache.Use(cache.NewMemoryStore(), ttl)
+	return admin
+}
+
+// SetCacheStore set the store of the cache, a nil store disables the cache.
+func (admin *Admin) SetCacheStore(store cache.Store, ttl time.Duration) *Admin {
+	cache.Use(store, ttl)
+	return admin
+}
+
+// EnableMetrics register the route "/metrics" which exports the metrics
+// of the engine in the Prometheus text exposition format. The route
+// requires a login, see SetMetricsToken for the scrapers.
+func (admin *Admin) EnableMetrics() *Admin {
+	admin.handler.SetMetrics(true)
+	return admin
+}
+
+// SetMetricsToken enable the metrics and let the route "/metrics" be
+// requested with the header "Authorization: Bearer <token>" instead of a
+// login.
+func (admin *Admin) SetMetricsToken(token string) *Admin {
+	admin.handler.SetMetrics(true).SetMetricsToken(token)
+	return admin
+}
+
+// AllowDynamicSystemTables allow the tables of the admin, such as adm_users,
+// to be defined as the dynamic tables from the UI. They are refused by
+// default.
+func (admin *Admin) AllowDynamicSystemTables() *Admin {
+	admin.handler.SetDynamicSystemTables(true)
+	return admin
+}
+
+// EnableInstaller enable the installer at "/install" until it is locked
+// or the users of the admin exist in the database. The url of the
+// installer with its token is printed when the plugin is initialized.
+func (admin *Admin) EnableInstaller() *Admin {
+	admin.handler.Installer().Enable()
+	return admin
+}
+
+// SetInstallConfigPath set the path of the config file written by the installer.
+func (admin *Admin) SetInstallConfigPath(path string) *Admin {
+	admin.handler.Installer().SetConfigPath(path)
+	return admin
+}
+
+// SetInstallLockPath set the path of the lock file of the installer.
+func (admin *Admin) SetInstallLockPath(path string) *Admin {
+	admin.handler.Installer().SetLockPath(path)
+	return admin
+}
+
+// SetReadYourWritesWindow set the duration during which the data sets are
+// read from the primary connection after a write of the user.
+func (admin *Admin) SetReadYourWritesWindow(window time.Duration) *Admin {
+	admin.handler.SetReadYourWritesWindow(window)
+	return admin
+}
+
+// SetRateLimit limit the rate of the requests of the routes, which are
+// relative to the url prefix, such as "/signin" and "/export/:__prefix".
+// The routes share the buckets when the Key of the limit does not tell
+// the routes apart, see ratelimit.ByRoute.
+func (admin *Admin) SetRateLimit(limit ratelimit.Limit, routes ...string) *Admin {
+	limiter := ratelimit.New(limit)
+	for _, route := range routes {
+		admin.rateLimits[route] = limiter
+	}
 	return admin
 }
 
@@ -73,6 +208,20 @@ func (admin *Admin) GetHandler(url, method string) context.Handlers {
 	return plugins.GetHandler(url, method, admin.app)
 }
 
+// Conflicts implements plugins.ConflictReporter.Conflicts.
+func (admin *Admin) Conflicts() []error {
+	if admin.app == nil {
+		return nil
+	}
+	return admin.app.Conflicts()
+}
+
+// ServeHTTP implements the http.Handler, so that the admin can be mounted
+// on any server without an adapter after InitPlugin.
+func (admin *Admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	admin.app.ServeHTTP(w, r)
+}
+
 // AddGlobalDisplayProcessFn call types.AddGlobalDisplayProcessFn
 func (admin *Admin) AddGlobalDisplayProcessFn(f types.DisplayProcessFn) *Admin {
 	types.AddGlobalDisplayProcessFn(f)
@@ -7,7 +7,7 @@ import (
 )
 
 // Update update the table row of given id.
-func Update(ctx *context.Context) {
+func (h *Handler) Update(ctx *context.Context) {
 
 	param := guard.GetUpdateParam(ctx)
 
@@ -20,6 +20,8 @@ func Update(ctx *context.Context) {
 		return
 	}
 
+	h.markWrite(ctx)
+
 	ctx.JSON(http.StatusOK, map[string]interface{}{
 		"msg": "ok",
 	})
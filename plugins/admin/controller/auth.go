@@ -6,6 +6,7 @@ import (
 	"github.com/glvd/go-admin/modules/auth"
 	"github.com/glvd/go-admin/modules/db"
 	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/modules/metrics"
 	"github.com/glvd/go-admin/modules/system"
 	"github.com/glvd/go-admin/plugins/admin/modules/captcha"
 	"github.com/glvd/go-admin/plugins/admin/modules/response"
@@ -16,47 +17,50 @@ import (
 )
 
 // Auth check the input password and username for authentication.
-func Auth(ctx *context.Context) {
+func (h *Handler) Auth(ctx *context.Context) {
 
 	password := ctx.FormValue("password")
 	username := ctx.FormValue("username")
 
 	if password == "" || username == "" {
+		metrics.Logins.Inc(metrics.LoginFailure)
 		response.BadRequest(ctx, "wrong password or username")
 		return
 	}
 
-	conn := db.GetConnection(services)
+	if user, ok := auth.Check(password, username, h.conn); ok {
 
-	if user, ok := auth.Check(password, username, conn); ok {
-
-		cd, ok := captcha.Get(captchaConfig["driver"])
+		cd, ok := captcha.Get(h.captchaConfig["driver"])
 
 		if ok {
 			if !cd.Validate(ctx.FormValue("token")) {
+				metrics.Logins.Inc(metrics.LoginFailure)
 				response.BadRequest(ctx, "wrong captcha")
+				return
 			}
 		}
 
-		auth.SetCookie(ctx, user, conn)
+		auth.SetCookie(ctx, user, h.conn)
+		metrics.Logins.Inc(metrics.LoginSuccess)
 
 		response.OkWithData(ctx, map[string]interface{}{
-			"url": config.GetIndexURL(),
+			"url": h.Config().GetIndexURL(),
 		})
 		return
 	}
+	metrics.Logins.Inc(metrics.LoginFailure)
 	response.BadRequest(ctx, "fail")
 }
 
 // Logout delete the cookie.
-func Logout(ctx *context.Context) {
-	auth.DelCookie(ctx, db.GetConnection(services))
-	ctx.AddHeader("Location", config.Url("/login"))
+func (h *Handler) Logout(ctx *context.Context) {
+	auth.DelCookie(ctx, db.GetConnection(h.services))
+	ctx.AddHeader("Location", h.Config().Url("/login"))
 	ctx.SetStatusCode(302)
 }
 
 // ShowLogin show the login page.
-func ShowLogin(ctx *context.Context) {
+func (h *Handler) ShowLogin(ctx *context.Context) {
 
 	tmpl, name := template.GetComp("login").GetTemplate()
 	buf := new(bytes.Buffer)
@@ -67,13 +71,13 @@ func ShowLogin(ctx *context.Context) {
 		CdnUrl    string
 		System    types.SystemInfo
 	}{
-		UrlPrefix: config.AssertPrefix(),
-		Title:     config.LoginTitle,
-		Logo:      config.LoginLogo,
+		UrlPrefix: h.Config().AssertPrefix(),
+		Title:     h.Config().LoginTitle,
+		Logo:      h.Config().LoginLogo,
 		System: types.SystemInfo{
 			Version: system.Version(),
 		},
-		CdnUrl: config.AssetUrl,
+		CdnUrl: h.Config().AssetUrl,
 	}); err == nil {
 		ctx.HTML(http.StatusOK, buf.String())
 	} else {
@@ -7,111 +7,241 @@ import (
 	"github.com/glvd/go-admin/modules/db"
 	"github.com/glvd/go-admin/modules/language"
 	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/plugins/admin/models"
 	"github.com/glvd/go-admin/plugins/admin/modules/constant"
+	"github.com/glvd/go-admin/plugins/admin/modules/dynamic"
+	"github.com/glvd/go-admin/plugins/admin/modules/installer"
+	"github.com/glvd/go-admin/plugins/admin/modules/table"
+	"github.com/glvd/go-admin/plugins/admin/modules/views"
 	"github.com/glvd/go-admin/template"
 	"github.com/glvd/go-admin/template/types"
 	template2 "html/template"
+	"net/http"
+	"time"
 )
 
-var (
-	config        c.Config
+// Handler is the controllers of an admin instance, which serve the
+// requests with the config, the services and the tables of the instance.
+type Handler struct {
+	config        *c.Service
 	captchaConfig map[string]string
 	services      service.List
 	conn          db.Connection
+	tables        *table.List
+	dynamic       *dynamic.Store
+	views         *views.Store
+	installer     *installer.Installer
+
+	readYourWritesWindow time.Duration
+	metricsEnabled       bool
+	metricsToken         string
+
+	// dynamicSystemTables allows the tables of the admin to be defined as
+	// the dynamic tables.
+	dynamicSystemTables bool
+}
+
+// New return the Handler of an admin instance serving the tables, the
+// config and the services are set when the instance is initialized.
+func New(tables *table.List) *Handler {
+	return &Handler{
+		config:               c.GlobalService(),
+		tables:               tables,
+		installer:            installer.New(),
+		readYourWritesWindow: defaultReadYourWritesWindow,
+	}
+}
+
+const (
+	readPrimaryCookieKey = "go_admin_read_primary"
+
+	defaultReadYourWritesWindow = 5 * time.Second
 )
 
-// SetConfig set the config.
+// defaultHandler is the Handler of the package level functions, which
+// serves the default table list.
+var defaultHandler = New(table.DefaultList())
+
+// Default return the Handler of the package level functions.
+func Default() *Handler {
+	return defaultHandler
+}
+
+// SetCaptcha set the captcha config of the default Handler.
 func SetCaptcha(cap map[string]string) {
-	captchaConfig = cap
+	defaultHandler.SetCaptcha(cap)
 }
 
-// SetConfig set the config.
+// SetConfig set the config of the default Handler.
 func SetConfig(cfg c.Config) {
-	config = cfg
+	defaultHandler.SetConfig(cfg)
 }
 
-// SetServices set the services.
+// SetReadYourWritesWindow set the read your writes window of the default
+// Handler, see Handler.SetReadYourWritesWindow.
+func SetReadYourWritesWindow(window time.Duration) {
+	defaultHandler.SetReadYourWritesWindow(window)
+}
+
+// SetServices set the services of the default Handler.
 func SetServices(l service.List) {
-	services = l
-	conn = db.GetConnection(services)
+	defaultHandler.SetServices(l)
+}
+
+// SetConfig set the config.
+func (h *Handler) SetConfig(cfg c.Config) *Handler {
+	h.config = c.NewService(cfg)
+	return h
+}
+
+// SetConfigService set the service of the config, with which the reloaded
+// config is used by the following requests.
+func (h *Handler) SetConfigService(srv *c.Service) *Handler {
+	h.config = srv
+	return h
+}
+
+// SetServices set the services.
+func (h *Handler) SetServices(l service.List) *Handler {
+	h.services = l
+	h.conn = db.GetConnection(l)
+	return h
 }
 
-func authSrv() *auth.Service {
-	return auth.GetService(services.Get("auth"))
+// SetDynamicStore set the store of the tables defined from the UI.
+func (h *Handler) SetDynamicStore(store *dynamic.Store) *Handler {
+	h.dynamic = store
+	return h
 }
 
-func aAlert() types.AlertAttribute {
-	return aTemplate().Alert()
+// SetDynamicSystemTables allow or refuse the tables of the admin, such as
+// adm_users, to be defined as the dynamic tables.
+func (h *Handler) SetDynamicSystemTables(allow bool) *Handler {
+	h.dynamicSystemTables = allow
+	return h
 }
 
-func aForm() types.FormAttribute {
-	return aTemplate().Form()
+// SetViewStore set the store of the views of the lists.
+func (h *Handler) SetViewStore(store *views.Store) *Handler {
+	h.views = store
+	return h
 }
 
-func aRow() types.RowAttribute {
-	return aTemplate().Row()
+// SetCaptcha set the captcha config.
+func (h *Handler) SetCaptcha(cap map[string]string) *Handler {
+	h.captchaConfig = cap
+	return h
 }
 
-func aCol() types.ColAttribute {
-	return aTemplate().Col()
+// SetReadYourWritesWindow set the duration during which the data sets are
+// read from the primary connection after a write of the user. Zero means
+// only the request which makes the write.
+func (h *Handler) SetReadYourWritesWindow(window time.Duration) *Handler {
+	h.readYourWritesWindow = window
+	return h
 }
 
-func aButton() types.ButtonAttribute {
-	return aTemplate().Button()
+// Config return the current config of the Handler.
+func (h *Handler) Config() c.Config {
+	return h.config.Get()
 }
 
-func aTree() types.TreeAttribute {
-	return aTemplate().Tree()
+// Installer return the installer of the Handler.
+func (h *Handler) Installer() *installer.Installer {
+	return h.installer
 }
 
-func aDataTable() types.DataTableAttribute {
-	return aTemplate().DataTable()
+// Conn return the default connection of the Handler.
+func (h *Handler) Conn() db.Connection {
+	return h.conn
 }
 
-func aBox() types.BoxAttribute {
-	return aTemplate().Box()
+func (h *Handler) authSrv() *auth.Service {
+	return auth.GetService(h.services.Get("auth"))
 }
 
-func aTab() types.TabsAttribute {
-	return aTemplate().Tabs()
+func (h *Handler) aAlert() types.AlertAttribute {
+	return h.aTemplate().Alert()
 }
 
-func aTemplate() template.Template {
-	return template.Get(config.Theme)
+func (h *Handler) aForm() types.FormAttribute {
+	return h.aTemplate().Form()
+}
+
+func (h *Handler) aRow() types.RowAttribute {
+	return h.aTemplate().Row()
+}
+
+func (h *Handler) aCol() types.ColAttribute {
+	return h.aTemplate().Col()
+}
+
+func (h *Handler) aButton() types.ButtonAttribute {
+	return h.aTemplate().Button()
+}
+
+func (h *Handler) aTree() types.TreeAttribute {
+	return h.aTemplate().Tree()
+}
+
+func (h *Handler) aDataTable() types.DataTableAttribute {
+	return h.aTemplate().DataTable()
+}
+
+func (h *Handler) aBox() types.BoxAttribute {
+	return h.aTemplate().Box()
+}
+
+func (h *Handler) aTab() types.TabsAttribute {
+	return h.aTemplate().Tabs()
+}
+
+func (h *Handler) aTemplate() template.Template {
+	return template.Get(h.Config().Theme)
+}
+
+// table return the table of the prefix restricted to the user of the
+// request, see table.DefaultTable.WithUser.
+func (h *Handler) table(ctx *context.Context, prefix string) table.Table {
+	tb := h.tables.Get(prefix)
+	if user, ok := ctx.User().(models.UserModel); ok && tb != nil {
+		return tb.WithUser(user)
+	}
+	return tb
 }
 
 func isPjax(ctx *context.Context) bool {
 	return ctx.Headers(constant.PjaxHeader) == "true"
 }
 
-func formFooter() template2.HTML {
-	col1 := aCol().SetSize(map[string]string{"md": "2"}).GetContent()
-	btn1 := aButton().SetType("submit").
+func (h *Handler) formFooter() template2.HTML {
+	col1 := h.aCol().SetSize(map[string]string{"md": "2"}).GetContent()
+	btn1 := h.aButton().SetType("submit").
 		SetContent(language.GetFromHtml("Save")).
 		SetThemePrimary().
 		SetOrientationRight().
 		SetLoadingText(`<i class='fa fa-spinner fa-spin '></i> Save`).
 		GetContent()
-	btn2 := aButton().SetType("reset").
+	btn2 := h.aButton().SetType("reset").
 		SetContent(language.GetFromHtml("Reset")).
 		SetThemeWarning().
 		SetOrientationLeft().
 		GetContent()
-	col2 := aCol().SetSize(map[string]string{"md": "8"}).
+	col2 := h.aCol().SetSize(map[string]string{"md": "8"}).
 		SetContent(btn1 + btn2).GetContent()
 	return col1 + col2
 }
 
-func filterFormFooter(infoUrl string) template2.HTML {
-	col1 := aCol().SetSize(map[string]string{"md": "2"}).GetContent()
-	btn1 := aButton().SetType("submit").
+func (h *Handler) filterFormFooter(infoUrl string) template2.HTML {
+	col1 := h.aCol().SetSize(map[string]string{"md": "2"}).GetContent()
+	btn1 := h.aButton().SetType("submit").
 		SetContent(`<i class="fa fa-search"></i>&nbsp;&nbsp;` + language.GetFromHtml("search")).
 		SetThemePrimary().
 		SetSmallSize().
 		SetOrientationLeft().
 		SetLoadingText(`<i class='fa fa-spinner fa-spin '></i> ` + language.GetFromHtml("search")).
 		GetContent()
-	btn2 := aButton().SetType("reset").
+	btn2 := h.aButton().SetType("reset").
 		SetContent(`<i class="fa fa-undo"></i>&nbsp;&nbsp;` + language.GetFromHtml("reset")).
 		SetThemeDefault().
 		SetOrientationLeft().
@@ -119,31 +249,56 @@ func filterFormFooter(infoUrl string) template2.HTML {
 		SetHref(infoUrl).
 		SetMarginLeft(12).
 		GetContent()
-	col2 := aCol().SetSize(map[string]string{"md": "8"}).
+	col2 := h.aCol().SetSize(map[string]string{"md": "8"}).
 		SetContent(btn1 + btn2).GetContent()
 	return col1 + col2
 }
 
-func formContent(form types.FormAttribute) template2.HTML {
-	return aBox().
+func (h *Handler) formContent(form types.FormAttribute) template2.HTML {
+	return h.aBox().
 		SetHeader(form.GetBoxHeader()).
 		WithHeadBorder().
 		SetBody(form.GetContent()).
 		GetContent()
 }
 
-func detailContent(form types.FormAttribute, editUrl, deleteUrl string) template2.HTML {
-	return aBox().
+func (h *Handler) detailContent(form types.FormAttribute, editUrl, deleteUrl string) template2.HTML {
+	return h.aBox().
 		SetHeader(form.GetDetailBoxHeader(editUrl, deleteUrl)).
 		WithHeadBorder().
 		SetBody(form.GetContent()).
 		GetContent()
 }
 
-func menuFormContent(form types.FormAttribute) template2.HTML {
-	return aBox().
+func (h *Handler) menuFormContent(form types.FormAttribute) template2.HTML {
+	return h.aBox().
 		SetHeader(form.GetBoxHeaderNoButton()).
 		WithHeadBorder().
 		SetBody(form.GetContent()).
 		GetContent()
 }
+
+// markWrite mark the request has written the database, the following reads
+// will go to the primary connection.
+func (h *Handler) markWrite(ctx *context.Context) {
+	ctx.SetUserValue(readPrimaryCookieKey, true)
+	if h.readYourWritesWindow > 0 {
+		ctx.SetCookie(&http.Cookie{
+			Name:     readPrimaryCookieKey,
+			Value:    "1",
+			Path:     h.Config().Url("/"),
+			Expires:  time.Now().Add(h.readYourWritesWindow),
+			MaxAge:   int(h.readYourWritesWindow / time.Second),
+			HttpOnly: true,
+		})
+	}
+}
+
+// readPrimary check the request should read from the primary connection.
+func readPrimary(ctx *context.Context) bool {
+	if primary, ok := ctx.UserValue[readPrimaryCookieKey].(bool); ok && primary {
+		return true
+	}
+	cookie, err := ctx.Request.Cookie(readPrimaryCookieKey)
+	return err == nil && cookie.Value == "1"
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/auth"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/modules/menu"
+	"github.com/glvd/go-admin/template"
+	"github.com/glvd/go-admin/template/types"
+	template2 "html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ShowDatabaseStatus show the pool statistics, statement latency and slow
+// queries of the database connections.
+func (h *Handler) ShowDatabaseStatus(ctx *context.Context) {
+
+	var content template2.HTML
+
+	drivers := make([]string, 0)
+	for driver := range h.Config().Databases.GroupByDriver() {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+
+	for _, driver := range drivers {
+		connection := db.GetConnectionFromService(h.services.Get(driver))
+		stats := connection.Stats()
+		content += h.aBox().
+			WithHeadBorder().
+			SetHeader(template2.HTML(driver)).
+			SetBody(h.poolTable(connection, stats) + h.statementTable(stats) + h.slowQueryTable(stats)).
+			GetContent()
+	}
+
+	user := auth.Auth(ctx)
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
+	buf := template.Execute(tmpl, tmplName, user, types.Panel{
+		Content:     content,
+		Description: language.Get("database status"),
+		Title:       language.Get("database status"),
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
+	ctx.HTML(http.StatusOK, buf.String())
+}
+
+func (h *Handler) poolTable(connection db.Connection, stats db.Stats) template2.HTML {
+	names := make([]string, 0, len(stats.Pools))
+	for name := range stats.Pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	heads := []string{"connection", "health", "open connections", "in use", "idle",
+		"wait count", "wait duration", "max idle closed", "max lifetime closed"}
+
+	infoList := make([]map[string]template2.HTML, 0, len(names))
+	for _, name := range names {
+		pool := stats.Pools[name]
+		health := template2.HTML(`<span class="label label-success">ok</span>`)
+		if err := connection.GetDB(name).Ping(); err != nil {
+			health = template2.HTML(`<span class="label label-danger">` +
+				template2.HTMLEscapeString(err.Error()) + `</span>`)
+		}
+		infoList = append(infoList, statusRow(heads,
+			template2.HTML(template2.HTMLEscapeString(name)),
+			health,
+			statusInt(pool.OpenConnections),
+			statusInt(pool.InUse),
+			statusInt(pool.Idle),
+			template2.HTML(strconv.FormatInt(pool.WaitCount, 10)),
+			template2.HTML(pool.WaitDuration.String()),
+			template2.HTML(strconv.FormatInt(pool.MaxIdleClosed, 10)),
+			template2.HTML(strconv.FormatInt(pool.MaxLifetimeClosed, 10))))
+	}
+
+	return h.statusTable(language.Get("connection pool"), heads, infoList)
+}
+
+func (h *Handler) statementTable(stats db.Stats) template2.HTML {
+	heads := []string{"statement", "count", "errors", "avg", "max", "latency distribution"}
+
+	infoList := make([]map[string]template2.HTML, 0, len(stats.Statements))
+	for _, item := range stats.Statements {
+		infoList = append(infoList, statusRow(heads,
+			template2.HTML(template2.HTMLEscapeString(item.Statement)),
+			template2.HTML(strconv.FormatInt(item.Count, 10)),
+			template2.HTML(strconv.FormatInt(item.Errors, 10)),
+			template2.HTML(item.Avg().String()),
+			template2.HTML(item.Max.String()),
+			histogram(item.Buckets)))
+	}
+
+	return h.statusTable(language.Get("statements"), heads, infoList)
+}
+
+func (h *Handler) slowQueryTable(stats db.Stats) template2.HTML {
+	heads := []string{"time", "connection", "latency", "statement"}
+
+	infoList := make([]map[string]template2.HTML, 0, len(stats.SlowQueries))
+	for _, item := range stats.SlowQueries {
+		infoList = append(infoList, statusRow(heads,
+			template2.HTML(item.Time.Format("2006-01-02 15:04:05")),
+			template2.HTML(template2.HTMLEscapeString(item.Connection)),
+			template2.HTML(item.Latency.String()),
+			template2.HTML(template2.HTMLEscapeString(fmt.Sprintf("%s (%d args)", item.Statement, item.ArgCount)))))
+	}
+
+	return h.statusTable(fmt.Sprintf("%s (> %s)", language.Get("slow queries"), stats.SlowQueryThreshold),
+		heads, infoList)
+}
+
+func histogram(buckets []int64) template2.HTML {
+	parts := make([]string, 0, len(buckets))
+	for i, count := range buckets {
+		if count == 0 {
+			continue
+		}
+		if i < len(db.LatencyBuckets) {
+			parts = append(parts, "&le;"+db.LatencyBuckets[i].String()+": "+strconv.FormatInt(count, 10))
+		} else {
+			parts = append(parts, "&gt;"+db.LatencyBuckets[i-1].String()+": "+strconv.FormatInt(count, 10))
+		}
+	}
+	return template2.HTML(strings.Join(parts, "<br>"))
+}
+
+func (h *Handler) statusTable(title string, heads []string, infoList []map[string]template2.HTML) template2.HTML {
+	thead := make([]map[string]string, len(heads))
+	for i, head := range heads {
+		thead[i] = map[string]string{
+			"head":     language.Get(head),
+			"sortable": "0",
+		}
+	}
+	return template2.HTML("<h4>"+template2.HTMLEscapeString(title)+"</h4>") +
+		h.aTemplate().Table().SetType("table").SetThead(thead).SetInfoList(infoList).GetContent()
+}
+
+func statusRow(heads []string, values ...template2.HTML) map[string]template2.HTML {
+	m := make(map[string]template2.HTML, len(heads))
+	for i, head := range heads {
+		m[language.Get(head)] = values[i]
+	}
+	return m
+}
+
+func statusInt(i int) template2.HTML {
+	return template2.HTML(strconv.Itoa(i))
+}
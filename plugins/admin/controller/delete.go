@@ -5,11 +5,10 @@ import (
 	"github.com/glvd/go-admin/modules/logger"
 	"github.com/glvd/go-admin/plugins/admin/modules/guard"
 	"github.com/glvd/go-admin/plugins/admin/modules/response"
-	"github.com/glvd/go-admin/plugins/admin/modules/table"
 )
 
 // Delete delete the row from database.
-func Delete(ctx *context.Context) {
+func (h *Handler) Delete(ctx *context.Context) {
 
 	param := guard.GetDeleteParam(ctx)
 
@@ -21,13 +20,15 @@ func Delete(ctx *context.Context) {
 	//	return
 	//}
 
-	if err := table.Get(param.Prefix).DeleteDataFromDatabase(param.Id); err != nil {
+	if err := param.Panel.DeleteDataFromDatabase(param.Id); err != nil {
 		logger.Error(err)
 		response.Error(ctx, "删除失败")
 		return
 	}
 
-	newToken := authSrv().AddToken()
+	h.markWrite(ctx)
+
+	newToken := h.authSrv().AddToken()
 
 	response.OkWithData(ctx, map[string]interface{}{
 		"token": newToken,
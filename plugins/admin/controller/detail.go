@@ -15,46 +15,20 @@ import (
 	"github.com/glvd/go-admin/template/types/form"
 	template2 "html/template"
 	"net/http"
+	"strings"
 )
 
-func ShowDetail(ctx *context.Context) {
+func (h *Handler) ShowDetail(ctx *context.Context) {
 	prefix := ctx.Query("__prefix")
 	id := ctx.Query("__goadmin_detail_pk")
-	panel := table.Get(prefix)
+	panel := h.table(ctx, prefix)
 	user := auth.Auth(ctx)
 
-	newPanel := panel.Copy()
-
-	formModel := newPanel.GetForm()
-
-	formModel.FieldList = make([]types.FormField, len(panel.GetInfo().FieldList))
-
-	for i, field := range panel.GetInfo().FieldList {
-		formModel.FieldList[i] = types.FormField{
-			Field:        field.Field,
-			TypeName:     field.TypeName,
-			Head:         field.Head,
-			FormType:     form.Default,
-			FieldDisplay: field.FieldDisplay,
-		}
-	}
-
-	formData, _, _, _, _, err := newPanel.GetDataFromDatabaseWithId(id)
-
-	var alert template2.HTML
-
-	if err != nil && alert == "" {
-		alert = aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
-			SetTheme("warning").
-			SetContent(template2.HTML(err.Error())).
-			GetContent()
-	}
-
 	params := parameter.GetParam(ctx.Request.URL.Query(), panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name,
 		panel.GetInfo().GetSort())
 
-	editUrl := modules.AorB(panel.GetEditable(), config.Url("/info/"+prefix+"/edit"+params.GetRouteParamStr()), "")
-	deleteUrl := modules.AorB(panel.GetDeletable(), config.Url("/delete/"+prefix), "")
+	editUrl := modules.AorB(panel.GetEditable(), h.Config().Url("/info/"+prefix+"/edit"+params.GetRouteParamStr()), "")
+	deleteUrl := modules.AorB(panel.GetDeletable(), h.Config().Url("/delete/"+prefix), "")
 	infoUrl := config2.Get().Url("/info/" + prefix + params.GetRouteParamStr())
 
 	deleteJs := ""
@@ -99,19 +73,134 @@ $('.delete-btn').on('click', function (event) {
 </script>`, language.Get("are you sure to delete"), language.Get("yes"), language.Get("cancel"), deleteUrl, infoUrl, id)
 	}
 
-	title := language.Get("Detail")
+	var (
+		title   = language.Get("Detail")
+		content template2.HTML
+		alert   template2.HTML
+	)
+
+	if detail := panel.GetDetail(); detail.Valid() {
+		title = modules.AorB(detail.Title != "", detail.Title, title)
+		content, alert = h.detailPanelContent(panel, id, title, editUrl, deleteUrl, infoUrl, deleteJs)
+	} else {
+		content, alert = h.detailFormContent(panel, id, title, editUrl, deleteUrl, infoUrl, deleteJs)
+	}
 
-	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 	buf := template.Execute(tmpl, tmplName, user, types.Panel{
-		Content: alert + detailContent(aForm().
-			SetTitle(template.HTML(title)).
-			SetContent(formData).
-			SetFooter(template.HTML(deleteJs)).
-			SetInfoUrl(infoUrl).
-			SetPrefix(config.PrefixFixSlash()), editUrl, deleteUrl),
+		Content:     alert + content,
 		Description: title,
 		Title:       title,
-	}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 
 	ctx.HTML(http.StatusOK, buf.String())
 }
+
+// detailFormContent render the row with the fields of the info panel as a
+// read-only form.
+func (h *Handler) detailFormContent(panel table.Table, id, title, editUrl, deleteUrl, infoUrl,
+	deleteJs string) (template2.HTML, template2.HTML) {
+	newPanel := panel.Copy()
+
+	formModel := newPanel.GetForm()
+
+	formModel.FieldList = make([]types.FormField, len(panel.GetInfo().FieldList))
+
+	for i, field := range panel.GetInfo().FieldList {
+		formModel.FieldList[i] = types.FormField{
+			Field:        field.Field,
+			TypeName:     field.TypeName,
+			Head:         field.Head,
+			FormType:     form.Default,
+			FieldDisplay: field.FieldDisplay,
+		}
+	}
+
+	formData, _, _, _, _, err := newPanel.GetDataFromDatabaseWithId(id)
+
+	var alert template2.HTML
+
+	if err != nil {
+		alert = h.aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
+			SetTheme("warning").
+			SetContent(template2.HTML(err.Error())).
+			GetContent()
+	}
+
+	return h.detailContent(h.aForm().
+		SetTitle(template.HTML(title)).
+		SetContent(formData).
+		SetFooter(template.HTML(deleteJs)).
+		SetInfoUrl(infoUrl).
+		SetPrefix(h.Config().PrefixFixSlash()), editUrl, deleteUrl), alert
+}
+
+// detailPanelContent render the row with the detail panel, the fields are
+// grouped into tabs if set, and the related lists follow in boxes.
+func (h *Handler) detailPanelContent(panel table.Table, id, title, editUrl, deleteUrl, infoUrl,
+	deleteJs string) (template2.HTML, template2.HTML) {
+
+	info, err := panel.GetDetailFromDatabase(id)
+	if err != nil {
+		return "", h.aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
+			SetTheme("warning").
+			SetContent(template2.HTML(template2.HTMLEscapeString(err.Error()))).
+			GetContent()
+	}
+
+	var body template2.HTML
+	if len(info.Groups) > 0 {
+		tabs := make([]map[string]template2.HTML, len(info.Groups))
+		for key, group := range info.Groups {
+			tabs[key] = map[string]template2.HTML{
+				"title":   template2.HTML(template2.HTMLEscapeString(language.Get(info.GroupHeaders[key]))),
+				"content": detailFields(group),
+			}
+		}
+		body = h.aTab().SetData(tabs).GetContent()
+	} else {
+		body = detailFields(info.Fields)
+	}
+
+	var (
+		pk        = template2.HTMLEscapeString(id)
+		btns, js  = panel.GetDetail().Buttons.Content()
+		header    = h.aForm().SetTitle(template.HTML(title)).SetInfoUrl(infoUrl).GetDetailBoxHeader(editUrl, deleteUrl)
+		boxTools  = `<div class="box-tools">`
+		actionBtn = strings.Replace(string(btns), "{id}", pk, -1)
+	)
+	header = template2.HTML(strings.Replace(string(header), boxTools, boxTools+actionBtn, 1))
+
+	content := h.aBox().
+		SetHeader(header).
+		WithHeadBorder().
+		SetBody(body).
+		SetFooter(template2.HTML(deleteJs + `<script>` + strings.Replace(string(js), "{id}", pk, -1) + `</script>`)).
+		GetContent()
+
+	for _, related := range info.Related {
+		content += h.aBox().
+			SetHeader(template2.HTML(`<h3 class="box-title">` + template2.HTMLEscapeString(language.Get(related.Title)) + `</h3>`)).
+			WithHeadBorder().
+			SetNoPadding().
+			SetBody(h.aTemplate().Table().
+				SetType("table").
+				SetMinWidth(10).
+				SetThead(related.Thead).
+				SetInfoList(related.InfoList).
+				GetContent()).
+			GetContent()
+	}
+
+	return content, ""
+}
+
+// detailFields render the fields as rows of heads and values.
+func detailFields(fields []table.DetailField) template2.HTML {
+	html := `<table class="table table-bordered" style="margin-bottom:0">`
+	for _, field := range fields {
+		html += `<tr><th style="width:20%">` + template2.HTMLEscapeString(language.Get(field.Head)) + `</th><td>` +
+			string(field.Content) + `</td></tr>`
+	}
+	return template2.HTML(html + `</table>`)
+}
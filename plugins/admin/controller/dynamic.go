@@ -0,0 +1,479 @@
+package controller
+
+import (
+	"errors"
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/auth"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/modules/menu"
+	"github.com/glvd/go-admin/modules/tools"
+	"github.com/glvd/go-admin/plugins/admin/modules/dynamic"
+	"github.com/glvd/go-admin/plugins/admin/modules/response"
+	"github.com/glvd/go-admin/template"
+	"github.com/glvd/go-admin/template/types"
+	template2 "html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ShowDynamicTables show the tables defined from the UI.
+func (h *Handler) ShowDynamicTables(ctx *context.Context) {
+
+	heads := []string{"prefix", "table", "connection", "title", "operation"}
+
+	infoList := make([]map[string]template2.HTML, 0)
+	for _, d := range h.dynamic.All() {
+		infoList = append(infoList, statusRow(heads,
+			template2.HTML(`<a href="`+h.Config().Url("/info/"+url.PathEscape(d.Prefix))+`">`+
+				template2.HTMLEscapeString(d.Prefix)+`</a>`),
+			escape(d.Table),
+			escape(d.Connection),
+			escape(d.Title),
+			h.dynamicOperations(d.Prefix)))
+	}
+
+	body := template2.HTML(`<a class="btn btn-sm btn-success" href="` + h.Config().Url("/dynamic/new") + `">` +
+		`<i class="fa fa-plus"></i> ` + language.Get("new") + `</a>`)
+
+	h.renderDynamic(ctx, language.Get("dynamic tables"),
+		body+h.statusTable(language.Get("dynamic tables"), heads, infoList))
+}
+
+// ShowNewDynamicTable show the tables of the connection to choose from, and
+// the columns of the chosen table to define a new dynamic table.
+func (h *Handler) ShowNewDynamicTable(ctx *context.Context) {
+	var (
+		connection = ctx.QueryDefault("connection", "default")
+		table      = ctx.Query("table")
+	)
+
+	conn, driver, err := h.dynamicConnection(connection)
+	if err != nil {
+		response.Alert(ctx, h.Config(), language.Get("new dynamic table"), language.Get("dynamic tables"),
+			template2.HTMLEscapeString(err.Error()), h.conn)
+		return
+	}
+
+	if table == "" {
+		h.renderDynamic(ctx, language.Get("new dynamic table"), h.dynamicTableChooser(conn, connection))
+		return
+	}
+
+	schema, err := tools.Inspect(conn, connection, table)
+	if err != nil {
+		response.Alert(ctx, h.Config(), language.Get("new dynamic table"), language.Get("dynamic tables"),
+			template2.HTMLEscapeString(err.Error()), h.conn)
+		return
+	}
+
+	d := dynamic.Definition{
+		Prefix:     table,
+		Connection: connection,
+		Driver:     driver,
+		Table:      table,
+		Title:      tools.Label(table),
+		CanAdd:     true,
+		Editable:   true,
+		Deletable:  true,
+		Exportable: true,
+	}
+	if pk, ok := schema.PrimaryKey(); ok {
+		d.PrimaryKey, d.PrimaryKeyType = pk.Name, string(pk.Type)
+	}
+	for _, column := range schema.Columns {
+		field := dynamic.Field{
+			Head:     tools.Label(column.Name),
+			Field:    column.Name,
+			Type:     string(column.Type),
+			FormType: "text",
+			Must:     !column.Nullable && !column.HasDefault,
+		}
+		if column.Primary {
+			field.Sortable = true
+			field.NotAllowEdit = true
+			if column.AutoIncrement {
+				field.FormType, field.Must, field.NotAllowAdd = "default", false, true
+			}
+		}
+		d.Fields = append(d.Fields, field)
+	}
+
+	h.renderDynamic(ctx, language.Get("new dynamic table"), h.dynamicForm(d, "", schema.Columns))
+}
+
+// ShowEditDynamicTable show the form of the dynamic table of the prefix.
+func (h *Handler) ShowEditDynamicTable(ctx *context.Context) {
+	d, ok := h.dynamic.Get(ctx.Query("prefix"))
+	if !ok {
+		response.Alert(ctx, h.Config(), language.Get("edit"), language.Get("dynamic tables"),
+			"dynamic table not found", h.conn)
+		return
+	}
+
+	// The columns added to the table after the definition are listed too.
+	var columns []tools.Column
+	if conn, _, err := h.dynamicConnection(d.Connection); err == nil {
+		if schema, err := tools.Inspect(conn, d.Connection, d.Table); err == nil {
+			columns = schema.Columns
+		}
+	}
+
+	h.renderDynamic(ctx, language.Get("edit"), h.dynamicForm(d, d.Prefix, columns))
+}
+
+// SaveDynamicTable save the definition of a dynamic table, the table is
+// served by the following requests.
+func (h *Handler) SaveDynamicTable(ctx *context.Context) {
+
+	if !h.authSrv().CheckToken(ctx.FormValue("_t")) {
+		response.BadRequest(ctx, "wrong token")
+		return
+	}
+
+	var (
+		original   = ctx.FormValue("original")
+		connection = ctx.FormValue("connection")
+	)
+
+	conn, driver, err := h.dynamicConnection(connection)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	d := dynamic.Definition{
+		Prefix:      strings.TrimSpace(ctx.FormValue("prefix")),
+		Connection:  connection,
+		Driver:      driver,
+		Table:       ctx.FormValue("table"),
+		Title:       ctx.FormValue("title"),
+		Description: ctx.FormValue("description"),
+		PrimaryKey:  ctx.FormValue("primary_key"),
+		CanAdd:      checked(ctx, "can_add"),
+		Editable:    checked(ctx, "editable"),
+		Deletable:   checked(ctx, "deletable"),
+		Exportable:  checked(ctx, "exportable"),
+	}
+	if d.PrimaryKey != "" {
+		d.PrimaryKeyType = ctx.FormValue("type_" + d.PrimaryKey)
+	}
+
+	if h.tables.Has(d.Prefix) {
+		response.BadRequest(ctx, "the prefix is used by a table already")
+		return
+	}
+	if _, exist := h.dynamic.Get(d.Prefix); exist && d.Prefix != original {
+		response.BadRequest(ctx, "the prefix is used by a dynamic table already")
+		return
+	}
+
+	for _, column := range strings.Split(ctx.FormValue("columns"), ",") {
+		if column == "" || !checked(ctx, "include_"+column) {
+			continue
+		}
+		d.Fields = append(d.Fields, dynamic.Field{
+			Head:           ctx.FormValue("head_" + column),
+			Field:          column,
+			Type:           ctx.FormValue("type_" + column),
+			Hide:           checked(ctx, "hide_"+column),
+			Sortable:       checked(ctx, "sortable_"+column),
+			Filter:         ctx.FormValue("filter_" + column),
+			FilterOperator: ctx.FormValue("operator_" + column),
+			FormType:       ctx.FormValue("form_type_" + column),
+			Must:           checked(ctx, "must_"+column),
+			NotAllowAdd:    checked(ctx, "not_allow_add_"+column),
+			NotAllowEdit:   checked(ctx, "not_allow_edit_"+column),
+			Default:        ctx.FormValue("default_" + column),
+			Options:        parseOptions(ctx.FormValue("options_" + column)),
+		})
+	}
+
+	if err := d.Validate(); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	// The identifiers are put into the statements of the table, so they
+	// must be the ones of the database.
+	schema, err := tools.Inspect(conn, connection, d.Table)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+	columns := make([]string, len(schema.Columns))
+	for i, column := range schema.Columns {
+		columns[i] = column.Name
+	}
+	if err := d.CheckSchema(columns, h.dynamicSystemTables); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	if err := h.dynamic.Save(d); err != nil {
+		logger.Error("save dynamic table error: ", err)
+		response.Error(ctx, "save dynamic table error")
+		return
+	}
+
+	// The prefix is renamed.
+	if original != "" && original != d.Prefix {
+		if err := h.dynamic.Delete(original); err != nil {
+			logger.Error("delete dynamic table error: ", err)
+		}
+	}
+
+	ctx.Redirect(h.Config().Url("/dynamic"))
+}
+
+// DeleteDynamicTable delete the dynamic table of the prefix.
+func (h *Handler) DeleteDynamicTable(ctx *context.Context) {
+
+	if !h.authSrv().CheckToken(ctx.FormValue("_t")) {
+		response.BadRequest(ctx, "wrong token")
+		return
+	}
+
+	prefix := ctx.FormValue("prefix")
+	if _, ok := h.dynamic.Get(prefix); !ok {
+		response.BadRequest(ctx, "dynamic table not found")
+		return
+	}
+
+	if err := h.dynamic.Delete(prefix); err != nil {
+		logger.Error("delete dynamic table error: ", err)
+		response.Error(ctx, "delete dynamic table error")
+		return
+	}
+
+	ctx.Redirect(h.Config().Url("/dynamic"))
+}
+
+func (h *Handler) renderDynamic(ctx *context.Context, title string, body template2.HTML) {
+	content := h.aBox().
+		WithHeadBorder().
+		SetHeader(template2.HTML(template2.HTMLEscapeString(title))).
+		SetBody(body).
+		GetContent()
+
+	user := auth.Auth(ctx)
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
+	buf := template.Execute(tmpl, tmplName, user, types.Panel{
+		Content:     content,
+		Description: title,
+		Title:       language.Get("dynamic tables"),
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
+	ctx.HTML(http.StatusOK, buf.String())
+}
+
+// dynamicConnection return the connection and the driver of the connection
+// name in the config.
+func (h *Handler) dynamicConnection(connection string) (db.Connection, string, error) {
+	database, ok := h.Config().Databases[connection]
+	if !ok {
+		return nil, "", errors.New("connection " + connection + " is not found")
+	}
+	conn, ok := h.services.Get(database.Driver).(db.Connection)
+	if !ok {
+		return nil, "", errors.New("connection " + connection + " is not initialized")
+	}
+	return conn, database.Driver, nil
+}
+
+func (h *Handler) dynamicTableChooser(conn db.Connection, connection string) template2.HTML {
+	names := make([]string, 0, len(h.Config().Databases))
+	for name := range h.Config().Databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	html := `<h4>` + language.Get("connection") + `</h4><p>`
+	for _, name := range names {
+		class := "btn-default"
+		if name == connection {
+			class = "btn-primary"
+		}
+		html += `<a class="btn btn-sm ` + class + `" style="margin-right:5px" href="` +
+			h.Config().Url("/dynamic/new?connection="+url.QueryEscape(name)) + `">` +
+			template2.HTMLEscapeString(name) + `</a>`
+	}
+	html += `</p><h4>` + language.Get("choose a table") + `</h4>`
+
+	tables, err := tools.Tables(conn, connection)
+	if err != nil {
+		return template2.HTML(html + `<p class="text-danger">` + template2.HTMLEscapeString(err.Error()) + `</p>`)
+	}
+
+	html += `<ul>`
+	for _, table := range tables {
+		html += `<li><a href="` + h.Config().Url("/dynamic/new?connection="+url.QueryEscape(connection)+
+			"&table="+url.QueryEscape(table)) + `">` + template2.HTMLEscapeString(table) + `</a></li>`
+	}
+	return template2.HTML(html + `</ul>`)
+}
+
+// dynamicForm return the form of the definition. The columns of the table
+// not in the definition are listed unchecked.
+func (h *Handler) dynamicForm(d dynamic.Definition, original string, columns []tools.Column) template2.HTML {
+	fields := make([]dynamic.Field, 0, len(d.Fields))
+	included := make(map[string]bool, len(d.Fields))
+	for _, field := range d.Fields {
+		fields = append(fields, field)
+		included[field.Field] = true
+	}
+	for _, column := range columns {
+		if !included[column.Name] {
+			fields = append(fields, dynamic.Field{
+				Head:  tools.Label(column.Name),
+				Field: column.Name,
+				Type:  string(column.Type),
+			})
+		}
+	}
+	if original == "" {
+		// The columns of a new definition are all checked.
+		for _, field := range fields {
+			included[field.Field] = true
+		}
+	}
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Field
+	}
+
+	html := `<form method="post" action="` + h.Config().Url("/dynamic/save") + `" class="form-horizontal">` +
+		hiddenInput("_t", h.authSrv().AddToken()) +
+		hiddenInput("original", original) +
+		hiddenInput("connection", d.Connection) +
+		hiddenInput("table", d.Table) +
+		hiddenInput("columns", strings.Join(names, ",")) +
+		formGroup("prefix", textInput("prefix", d.Prefix)) +
+		formGroup("table", template2.HTMLEscapeString(d.Connection+" / "+d.Table)) +
+		formGroup("title", textInput("title", d.Title)) +
+		formGroup("description", textInput("description", d.Description)) +
+		formGroup("primary key", selectInput("primary_key", d.PrimaryKey, append([]string{""}, names...))) +
+		formGroup("operation",
+			checkbox("can_add", d.CanAdd)+" "+language.Get("can add")+"&nbsp;&nbsp;"+
+				checkbox("editable", d.Editable)+" "+language.Get("editable")+"&nbsp;&nbsp;"+
+				checkbox("deletable", d.Deletable)+" "+language.Get("deletable")+"&nbsp;&nbsp;"+
+				checkbox("exportable", d.Exportable)+" "+language.Get("exportable"))
+
+	formTypes := append([]string{""}, dynamic.FormTypes()...)
+	operators := []string{""}
+	for _, operator := range dynamic.Operators() {
+		operators = append(operators, string(operator))
+	}
+
+	heads := []string{"include", "column", "head", "type", "hide", "sortable", "filter", "operator",
+		"form type", "must", "not allow add", "not allow edit", "default", "options"}
+	html += `<table class="table table-condensed"><thead><tr>`
+	for _, head := range heads {
+		html += `<th>` + language.Get(head) + `</th>`
+	}
+	html += `</tr></thead><tbody>`
+	for _, field := range fields {
+		name := field.Field
+		html += `<tr><td>` + checkbox("include_"+name, included[name]) + `</td>` +
+			`<td>` + template2.HTMLEscapeString(name) + `</td>` +
+			`<td>` + textInput("head_"+name, field.Head) + `</td>` +
+			`<td>` + textInput("type_"+name, field.Type) + `</td>` +
+			`<td>` + checkbox("hide_"+name, field.Hide) + `</td>` +
+			`<td>` + checkbox("sortable_"+name, field.Sortable) + `</td>` +
+			`<td>` + selectInput("filter_"+name, field.Filter, formTypes) + `</td>` +
+			`<td>` + selectInput("operator_"+name, field.FilterOperator, operators) + `</td>` +
+			`<td>` + selectInput("form_type_"+name, field.FormType, formTypes) + `</td>` +
+			`<td>` + checkbox("must_"+name, field.Must) + `</td>` +
+			`<td>` + checkbox("not_allow_add_"+name, field.NotAllowAdd) + `</td>` +
+			`<td>` + checkbox("not_allow_edit_"+name, field.NotAllowEdit) + `</td>` +
+			`<td>` + textInput("default_"+name, field.Default) + `</td>` +
+			`<td><textarea class="form-control input-sm" rows="1" placeholder="value:text" name="options_` +
+			template2.HTMLEscapeString(name) + `">` + template2.HTMLEscapeString(formatOptions(field.Options)) +
+			`</textarea></td></tr>`
+	}
+	html += `</tbody></table>` +
+		`<button type="submit" class="btn btn-primary">` + language.Get("save") + `</button></form>`
+
+	return template2.HTML(html)
+}
+
+func (h *Handler) dynamicOperations(prefix string) template2.HTML {
+	return template2.HTML(`<a class="btn btn-xs btn-primary" href="` +
+		h.Config().Url("/dynamic/edit?prefix="+url.QueryEscape(prefix)) + `">` + language.Get("edit") + `</a> ` +
+		`<form method="post" action="` + h.Config().Url("/dynamic/delete") + `" style="display:inline;margin:0">` +
+		hiddenInput("prefix", prefix) +
+		hiddenInput("_t", h.authSrv().AddToken()) +
+		`<button type="submit" class="btn btn-xs btn-danger">` + language.Get("delete") + `</button></form>`)
+}
+
+func escape(s string) template2.HTML {
+	return template2.HTML(template2.HTMLEscapeString(s))
+}
+
+func checked(ctx *context.Context, key string) bool {
+	return ctx.FormValue(key) == "1"
+}
+
+func hiddenInput(name, value string) string {
+	return `<input type="hidden" name="` + template2.HTMLEscapeString(name) + `" value="` +
+		template2.HTMLEscapeString(value) + `">`
+}
+
+func textInput(name, value string) string {
+	return `<input type="text" class="form-control input-sm" name="` + template2.HTMLEscapeString(name) +
+		`" value="` + template2.HTMLEscapeString(value) + `">`
+}
+
+func checkbox(name string, value bool) string {
+	html := `<input type="checkbox" name="` + template2.HTMLEscapeString(name) + `" value="1"`
+	if value {
+		html += ` checked`
+	}
+	return html + `>`
+}
+
+func selectInput(name, value string, options []string) string {
+	html := `<select class="form-control input-sm" name="` + template2.HTMLEscapeString(name) + `">`
+	for _, option := range options {
+		html += `<option value="` + template2.HTMLEscapeString(option) + `"`
+		if option == value {
+			html += ` selected`
+		}
+		html += `>` + template2.HTMLEscapeString(option) + `</option>`
+	}
+	return html + `</select>`
+}
+
+func formGroup(label, input string) string {
+	return `<div class="form-group"><label class="col-sm-2 control-label">` + language.Get(label) +
+		`</label><div class="col-sm-8">` + input + `</div></div>`
+}
+
+// parseOptions parse the options of the lines "value:text", the text is
+// the value when omitted.
+func parseOptions(s string) []dynamic.Option {
+	options := make([]dynamic.Option, 0)
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		value, text := line, line
+		if i := strings.Index(line, ":"); i >= 0 {
+			value, text = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+		options = append(options, dynamic.Option{Text: text, Value: value})
+	}
+	return options
+}
+
+func formatOptions(options []dynamic.Option) string {
+	lines := make([]string, len(options))
+	for i, option := range options {
+		lines[i] = option.Value + ":" + option.Text
+	}
+	return strings.Join(lines, "\n")
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/glvd/go-admin/plugins/admin/modules"
 	"github.com/glvd/go-admin/plugins/admin/modules/constant"
 	"github.com/glvd/go-admin/plugins/admin/modules/guard"
-	"github.com/glvd/go-admin/plugins/admin/modules/table"
 	"github.com/glvd/go-admin/template"
 	"github.com/glvd/go-admin/template/types"
 	template2 "html/template"
@@ -18,20 +17,20 @@ import (
 )
 
 // ShowForm show form page.
-func ShowForm(ctx *context.Context) {
+func (h *Handler) ShowForm(ctx *context.Context) {
 	param := guard.GetShowFormParam(ctx)
-	showForm(ctx, "", param.Prefix, param.Id, param.GetUrl(), param.GetInfoUrl(), "")
+	h.showForm(ctx, "", param.Prefix, param.Id, param.GetUrl(), param.GetInfoUrl(), "")
 }
 
-func showForm(ctx *context.Context, alert template2.HTML, prefix string, id string, url, infoUrl string, editUrl string) {
+func (h *Handler) showForm(ctx *context.Context, alert template2.HTML, prefix string, id string, url, infoUrl string, editUrl string) {
 
-	table.RefreshTableList()
-	panel := table.Get(prefix)
+	h.tables.Refresh()
+	panel := h.table(ctx, prefix)
 
 	formData, groupFormData, groupHeaders, title, description, err := panel.GetDataFromDatabaseWithId(id)
 
 	if err != nil && alert == "" {
-		alert = aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
+		alert = h.aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 			SetTheme("warning").
 			SetContent(template2.HTML(err.Error())).
 			GetContent()
@@ -45,23 +44,23 @@ func showForm(ctx *context.Context, alert template2.HTML, prefix string, id stri
 		infoUrl = referer
 	}
 
-	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 	buf := template.Execute(tmpl, tmplName, user, types.Panel{
-		Content: alert + formContent(aForm().
+		Content: alert + h.formContent(h.aForm().
 			SetContent(formData).
 			SetTabContents(groupFormData).
 			SetTabHeaders(groupHeaders).
-			SetPrefix(config.PrefixFixSlash()).
+			SetPrefix(h.Config().PrefixFixSlash()).
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
 			SetUrl(url).
-			SetToken(authSrv().AddToken()).
+			SetToken(h.authSrv().AddToken()).
 			SetInfoUrl(infoUrl).
-			SetOperationFooter(formFooter()).
+			SetOperationFooter(h.formFooter()).
 			SetHeader(panel.GetForm().HeaderHtml).
 			SetFooter(panel.GetForm().FooterHtml)),
 		Description: description,
 		Title:       title,
-	}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 
 	ctx.HTML(http.StatusOK, buf.String())
 
@@ -70,38 +69,40 @@ func showForm(ctx *context.Context, alert template2.HTML, prefix string, id stri
 	}
 }
 
-func EditForm(ctx *context.Context) {
+func (h *Handler) EditForm(ctx *context.Context) {
 
 	param := guard.GetEditFormParam(ctx)
 
 	if param.HasAlert() {
-		showForm(ctx, param.Alert, param.Prefix, param.Id, param.GetUrl(), param.GetInfoUrl(), param.GetEditUrl())
+		h.showForm(ctx, param.Alert, param.Prefix, param.Id, param.GetUrl(), param.GetInfoUrl(), param.GetEditUrl())
 		return
 	}
 
 	// process uploading files, only support local storage for now.
 	if len(param.MultiForm.File) > 0 {
-		err := file.GetFileEngine(config.FileUploadEngine.Name).Upload(param.MultiForm)
+		err := file.GetFileEngine(h.Config().FileUploadEngine.Name).Upload(param.MultiForm)
 		if err != nil {
-			alert := aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
+			alert := h.aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 				SetTheme("warning").
 				SetContent(template2.HTML(err.Error())).
 				GetContent()
-			showForm(ctx, alert, param.Prefix, param.Id, param.GetUrl(), param.GetInfoUrl(), param.GetEditUrl())
+			h.showForm(ctx, alert, param.Prefix, param.Id, param.GetUrl(), param.GetInfoUrl(), param.GetEditUrl())
 			return
 		}
 	}
 
 	err := param.Panel.UpdateDataFromDatabase(param.Value())
 	if err != nil {
-		alert := aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
+		alert := h.aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 			SetTheme("warning").
 			SetContent(template2.HTML(err.Error())).
 			GetContent()
-		showForm(ctx, alert, param.Prefix, param.Id, param.GetUrl(), param.GetInfoUrl(), param.GetEditUrl())
+		h.showForm(ctx, alert, param.Prefix, param.Id, param.GetUrl(), param.GetInfoUrl(), param.GetEditUrl())
 		return
 	}
 
+	h.markWrite(ctx)
+
 	if !param.FromList {
 		ctx.HTML(http.StatusOK, fmt.Sprintf(`<script>location.href="%s"</script>`, param.PreviousPath))
 		ctx.AddHeader(constant.PjaxUrlHeader, param.PreviousPath)
@@ -116,7 +117,7 @@ func EditForm(ctx *context.Context) {
 	updateUrl := modules.AorB(param.Panel.GetEditable(), param.GetUpdateUrl(), "")
 	detailUrl := param.GetDetailUrl()
 
-	buf := showTable(ctx, param.Panel, param.Path, param.Param, exportUrl, newUrl,
+	buf := h.showTable(ctx, param.Panel, param.Path, param.Param, exportUrl, newUrl,
 		deleteUrl, infoUrl, editUrl, updateUrl, detailUrl)
 
 	ctx.HTML(http.StatusOK, buf.String())
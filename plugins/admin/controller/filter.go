@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/modules/utils"
+	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
+	"github.com/glvd/go-admin/template/types"
+	template2 "html/template"
+)
+
+// advancedFilter return the button and the builder of the advanced filter
+// of the list if it is shown. The builder serializes the nested groups
+// into the parameter "__filter" of the list, see types.FilterGroup.
+func advancedFilter(info *types.InfoPanel, infoUrl string, params parameter.Parameters) template2.HTML {
+	if !info.IsShowAdvancedFilter {
+		return ""
+	}
+
+	fields := make([]map[string]string, 0)
+	for _, field := range info.FieldList {
+		if field.Filterable && !field.Join.Valid() && !field.Relation.Valid() {
+			fields = append(fields, map[string]string{
+				"field": field.Field,
+				"head":  field.Head,
+			})
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+
+	operators := make([]map[string]interface{}, len(types.ConditionOperators))
+	for i, op := range types.ConditionOperators {
+		operators[i] = map[string]interface{}{
+			"op":    op,
+			"label": language.Get(op.Label()),
+			"arity": op.Arity(),
+		}
+	}
+
+	group, err := types.ParseFilterGroup(params.Filter)
+	if err != nil || group.Empty() {
+		group = types.FilterGroup{Logic: "and"}
+	}
+
+	config, _ := json.Marshal(map[string]interface{}{
+		"fields":    fields,
+		"operators": operators,
+		"group":     group,
+		"maxDepth":  types.FilterMaxDepth,
+		"labels": map[string]string{
+			"and":       language.Get("and"),
+			"or":        language.Get("or"),
+			"condition": language.Get("add condition"),
+			"group":     language.Get("add group"),
+			"list":      language.Get("separated by commas"),
+		},
+	})
+
+	var (
+		id      = "advanced-filter-" + utils.Uuid(10)
+		display = "none"
+		reset   = params
+	)
+	if params.Filter != "" {
+		display = "block"
+	}
+	reset.Filter = ""
+	reset = reset.SetPage("1")
+
+	return template2.HTML(`<div class="btn-group pull-right" style="margin-right: 10px">` +
+		`<button type="button" class="btn btn-sm btn-default" onclick="$('#` + id + `').toggle()">` +
+		`<i class="fa fa-sliders"></i>&nbsp;&nbsp;` + language.Get("advanced filter") + `</button></div>` +
+		`<div id="` + id + `" style="display: ` + display + `;clear: both;padding-top: 10px;">` +
+		`<form action="` + template2.HTMLEscapeString(infoUrl) + `" method="get">` +
+		listInputs(params, "__filter") + hiddenInput("__filter", params.Filter) +
+		`<div class="advanced-filter-builder"></div>` +
+		`<button type="submit" class="btn btn-sm btn-primary"><i class="fa fa-search"></i>&nbsp;&nbsp;` +
+		language.Get("filter") + `</button> ` +
+		`<a class="btn btn-sm btn-default" href="` + template2.HTMLEscapeString(infoUrl+reset.GetRouteParamStr()) + `">` +
+		language.Get("reset") + `</a></form></div>` +
+		`<script>(function (config, id) {` + advancedFilterJs + `})(` + string(config) + `, '#` + id + `');</script>`)
+}
+
+// advancedFilterJs builds the nested groups of the conditions in the
+// element, and serializes them into the input "__filter" on submitting.
+const advancedFilterJs = `
+var root = $(id);
+
+function select(cls, options, value) {
+    var s = $('<select class="form-control input-sm ' + cls + '" style="width: auto;display: inline-block;margin-right: 5px;"></select>');
+    $.each(options, function (i, o) { s.append($('<option></option>').val(o[0]).text(o[1])); });
+    if (value !== undefined && value !== null && value !== '') { s.val(value); }
+    return s;
+}
+
+function operator(op) {
+    for (var i = 0; i < config.operators.length; i++) {
+        if (config.operators[i].op === op) { return config.operators[i]; }
+    }
+    return config.operators[0];
+}
+
+function input(value, placeholder) {
+    return $('<input type="text" class="form-control input-sm advanced-filter-value" style="width: 160px;display: inline-block;margin-right: 5px;">')
+        .val(value || '').attr('placeholder', placeholder || '');
+}
+
+function renderValues(box, op, values) {
+    box.empty();
+    values = values || [];
+    var arity = operator(op).arity;
+    if (arity < 0) {
+        box.append(input(values.join(', '), config.labels.list).attr('data-list', '1'));
+    } else {
+        for (var i = 0; i < arity; i++) { box.append(input(values[i])); }
+    }
+}
+
+function condition(c) {
+    var row = $('<div class="advanced-filter-condition" style="margin-bottom: 5px;"></div>');
+    var fields = $.map(config.fields, function (f) { return [[f.field, f.head]]; });
+    var ops = $.map(config.operators, function (o) { return [[o.op, o.label]]; });
+    var op = select('advanced-filter-op', ops, c.op);
+    var values = $('<span class="advanced-filter-values"></span>');
+    row.append(select('advanced-filter-field', fields, c.field)).append(op).append(values)
+        .append($('<button type="button" class="btn btn-xs btn-link"><i class="fa fa-times"></i></button>')
+            .click(function () { row.remove(); }));
+    renderValues(values, op.val(), c.values);
+    op.change(function () { renderValues(values, op.val(), []); });
+    return row;
+}
+
+function group(g, depth) {
+    var box = $('<div class="advanced-filter-group" style="border-left: 3px solid #d2d6de;padding: 5px 0 5px 10px;margin-bottom: 5px;"></div>');
+    var tools = $('<div class="advanced-filter-tools" style="margin-bottom: 5px;"></div>');
+    var items = $('<div class="advanced-filter-items"></div>');
+    tools.append(select('advanced-filter-logic', [['and', config.labels.and], ['or', config.labels.or]], g.logic))
+        .append($('<button type="button" class="btn btn-xs btn-default" style="margin-right: 5px;"></button>')
+            .text('+ ' + config.labels.condition).click(function () { items.append(condition({})); }));
+    if (depth < config.maxDepth) {
+        tools.append($('<button type="button" class="btn btn-xs btn-default" style="margin-right: 5px;"></button>')
+            .text('+ ' + config.labels.group).click(function () { items.append(group({logic: 'and'}, depth + 1)); }));
+    }
+    if (depth > 1) {
+        tools.append($('<button type="button" class="btn btn-xs btn-link"><i class="fa fa-times"></i></button>')
+            .click(function () { box.remove(); }));
+    }
+    $.each(g.conditions || [], function (i, c) { items.append(condition(c)); });
+    $.each(g.groups || [], function (i, sub) { items.append(group(sub, depth + 1)); });
+    return box.append(tools).append(items);
+}
+
+function collect(box) {
+    var g = {logic: box.children('.advanced-filter-tools').find('.advanced-filter-logic').val(), conditions: [], groups: []};
+    box.children('.advanced-filter-items').children().each(function () {
+        var el = $(this);
+        if (el.hasClass('advanced-filter-group')) {
+            var sub = collect(el);
+            if (sub.conditions.length > 0 || sub.groups.length > 0) { g.groups.push(sub); }
+            return;
+        }
+        var c = {field: el.find('.advanced-filter-field').val(), op: el.find('.advanced-filter-op').val(), values: []};
+        el.find('.advanced-filter-value').each(function () {
+            var value = $.trim($(this).val());
+            if ($(this).attr('data-list')) {
+                $.each(value.split(','), function (i, v) { v = $.trim(v); if (v !== '') { c.values.push(v); } });
+            } else {
+                c.values.push(value);
+            }
+        });
+        g.conditions.push(c);
+    });
+    return g;
+}
+
+var builder = root.find('.advanced-filter-builder').append(group(config.group, 1));
+root.find('form').submit(function () {
+    var g = collect(builder.children('.advanced-filter-group'));
+    root.find('input[name="__filter"]').val(g.conditions.length > 0 || g.groups.length > 0 ? JSON.stringify(g) : '');
+});
+`
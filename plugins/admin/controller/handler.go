@@ -18,12 +18,17 @@ import (
 	"strings"
 )
 
-// GlobalDeferHandler is a global error handler of admin plugin.
+// GlobalDeferHandler is the global error handler of the default Handler.
 func GlobalDeferHandler(ctx *context.Context) {
+	defaultHandler.GlobalDeferHandler(ctx)
+}
+
+// GlobalDeferHandler is a global error handler of admin plugin.
+func (h *Handler) GlobalDeferHandler(ctx *context.Context) {
 
 	logger.Access(ctx)
 
-	RecordOperationLog(ctx)
+	h.RecordOperationLog(ctx)
 
 	if err := recover(); err != nil {
 		logger.Error(err)
@@ -46,15 +51,15 @@ func GlobalDeferHandler(ctx *context.Context) {
 		}
 
 		if ok, _ = regexp.MatchString("/edit(.*)", ctx.Path()); ok {
-			setFormWithReturnErrMessage(ctx, errMsg, "edit")
+			h.setFormWithReturnErrMessage(ctx, errMsg, "edit")
 			return
 		}
 		if ok, _ = regexp.MatchString("/new(.*)", ctx.Path()); ok {
-			setFormWithReturnErrMessage(ctx, errMsg, "new")
+			h.setFormWithReturnErrMessage(ctx, errMsg, "new")
 			return
 		}
 
-		alert := aAlert().
+		alert := h.aAlert().
 			SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 			SetTheme("warning").
 			SetContent(template2.HTML(errMsg)).
@@ -62,20 +67,20 @@ func GlobalDeferHandler(ctx *context.Context) {
 
 		user := auth.Auth(ctx)
 
-		tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+		tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 		buf := template.Execute(tmpl, tmplName, user, types.Panel{
 			Content:     alert,
 			Description: "error",
 			Title:       "error",
-		}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+		}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 		ctx.HTML(http.StatusOK, buf.String())
 		return
 	}
 }
 
-func setFormWithReturnErrMessage(ctx *context.Context, errMsg string, kind string) {
+func (h *Handler) setFormWithReturnErrMessage(ctx *context.Context, errMsg string, kind string) {
 
-	alert := aAlert().
+	alert := h.aAlert().
 		SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 		SetTheme("warning").
 		SetContent(template2.HTML(errMsg)).
@@ -87,7 +92,7 @@ func setFormWithReturnErrMessage(ctx *context.Context, errMsg string, kind strin
 		groupHeaders       []string
 		title, description string
 		prefix             = ctx.Query("__prefix")
-		panel              = table.Get(prefix)
+		panel              = h.table(ctx, prefix)
 	)
 
 	if kind == "edit" {
@@ -95,7 +100,7 @@ func setFormWithReturnErrMessage(ctx *context.Context, errMsg string, kind strin
 		if id == "" {
 			id = ctx.Request.MultipartForm.Value[panel.GetPrimaryKey().Name][0]
 		}
-		formData, groupFormData, groupHeaders, title, description, _ = table.Get(prefix).GetDataFromDatabaseWithId(id)
+		formData, groupFormData, groupHeaders, title, description, _ = panel.GetDataFromDatabaseWithId(id)
 	} else {
 		formData, groupFormData, groupHeaders = table.GetNewFormList(panel.GetForm().TabHeaders, panel.GetForm().TabGroups,
 			panel.GetForm().FieldList)
@@ -108,24 +113,24 @@ func setFormWithReturnErrMessage(ctx *context.Context, errMsg string, kind strin
 
 	user := auth.Auth(ctx)
 
-	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 	buf := template.Execute(tmpl, tmplName, user, types.Panel{
-		Content: alert + formContent(aForm().
+		Content: alert + h.formContent(h.aForm().
 			SetContent(formData).
 			SetTabContents(groupFormData).
 			SetTabHeaders(groupHeaders).
 			SetTitle(template2.HTML(strings.Title(kind))).
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
-			SetPrefix(config.PrefixFixSlash()).
-			SetUrl(config.Url("/"+kind+"/"+prefix)).
-			SetToken(authSrv().AddToken()).
-			SetOperationFooter(formFooter()).
+			SetPrefix(h.Config().PrefixFixSlash()).
+			SetUrl(h.Config().Url("/"+kind+"/"+prefix)).
+			SetToken(h.authSrv().AddToken()).
+			SetOperationFooter(h.formFooter()).
 			SetHeader(panel.GetForm().HeaderHtml).
 			SetFooter(panel.GetForm().FooterHtml).
-			SetInfoUrl(config.Url("/info/"+prefix+queryParam))),
+			SetInfoUrl(h.Config().Url("/info/"+prefix+queryParam))),
 		Description: description,
 		Title:       title,
-	}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 	ctx.HTML(http.StatusOK, buf.String())
-	ctx.AddHeader(constant.PjaxUrlHeader, config.Url("/info/"+prefix+"/"+kind+queryParam))
+	ctx.AddHeader(constant.PjaxUrlHeader, h.Config().Url("/info/"+prefix+"/"+kind+queryParam))
 }
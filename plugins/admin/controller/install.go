@@ -2,70 +2,193 @@ package controller
 
 import (
 	"bytes"
-	"database/sql"
 	"github.com/glvd/go-admin/context"
+	c "github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/plugins/admin/modules/installer"
 	"github.com/glvd/go-admin/plugins/admin/modules/response"
+	"github.com/glvd/go-admin/template"
 	"net/http"
+	"strings"
 )
 
 // ShowInstall show install page.
-func ShowInstall(ctx *context.Context) {
+func (h *Handler) ShowInstall(ctx *context.Context) {
+
+	tmpl, name := template.GetComp("install").GetTemplate()
+	buf := new(bytes.Buffer)
+	if err := tmpl.ExecuteTemplate(buf, name, struct {
+		UrlPrefix  string
+		CdnUrl     string
+		Drivers    []string
+		ConfigPath string
+		Token      string
+	}{
+		UrlPrefix:  h.Config().AssertPrefix(),
+		CdnUrl:     h.Config().AssetUrl,
+		Drivers:    installer.Drivers,
+		ConfigPath: h.installer.ConfigPath(),
+		Token:      h.installer.Token(),
+	}); err == nil {
+		ctx.HTML(http.StatusOK, buf.String())
+	} else {
+		logger.Error(err)
+		ctx.HTML(http.StatusOK, "parse template error (；′⌒`)")
+	}
+}
 
-	buffer := new(bytes.Buffer)
-	//template.GetInstallPage(buffer)
+// CheckDatabase check the database connection.
+func (h *Handler) CheckDatabase(ctx *context.Context) {
 
-	//rs, _ := mysql.Query("show tables;")
-	//fmt.Println(rs[0]["Tables_in_godmin"])
+	conn, ok := installConnect(ctx)
+	if !ok {
+		return
+	}
+	defer conn.Close()
 
-	//rs2, _ := mysql.Query("show columns from users")
-	//fmt.Println(rs2[0]["Field"])
+	tables, err := installer.Tables(conn)
+	if err != nil {
+		logger.Error("install check database error: ", err)
+		response.BadRequest(ctx, "check the database parameters")
+		return
+	}
 
-	ctx.HTML(http.StatusOK, buffer.String())
+	response.OkWithData(ctx, map[string]interface{}{
+		"list": tables,
+	})
 }
 
-// CheckDatabase check the database connection.
-func CheckDatabase(ctx *context.Context) {
-
-	ip := ctx.FormValue("h")
-	port := ctx.FormValue("po")
-	username := ctx.FormValue("u")
-	password := ctx.FormValue("pa")
-	databaseName := ctx.FormValue("db")
-
-	SqlDB, err := sql.Open("mysql", username+":"+password+"@tcp("+ip+":"+port+")/"+databaseName+"?charset=utf8mb4")
-	if SqlDB != nil {
-		if SqlDB.Ping() != nil {
-			response.Error(ctx, "请检查参数是否设置正确")
-			return
-		}
+// InitDatabase create the tables and the default data of admin.
+func (h *Handler) InitDatabase(ctx *context.Context) {
+
+	conn, ok := installConnect(ctx)
+	if !ok {
+		return
 	}
+	defer conn.Close()
 
-	defer func() {
-		SqlDB.Close()
-	}()
+	if err := installer.Migrate(conn); err != nil {
+		logger.Error("install init database error: ", err)
+		response.Error(ctx, "create tables fail")
+		return
+	}
 
-	if err != nil {
-		response.Error(ctx, "请检查参数是否设置正确")
+	response.Ok(ctx)
+}
+
+// InstallAdmin create the super administrator.
+func (h *Handler) InstallAdmin(ctx *context.Context) {
+
+	conn, ok := installConnect(ctx)
+	if !ok {
 		return
+	}
+	defer conn.Close()
 
+	username := ctx.FormValue("admin_username")
+	password := ctx.FormValue("admin_password")
+
+	if username == "" || password == "" {
+		response.BadRequest(ctx, "username and password can not be empty")
+		return
 	}
 
-	//db.InitDB(username, password, port, ip, databaseName, 100, 100)
+	if err := installer.CreateSuperAdmin(conn, username, password, ctx.FormValue("admin_name")); err != nil {
+		logger.Error("install create admin error: ", err)
+		response.BadRequest(ctx, "create administrator fail")
+		return
+	}
 
-	tables := make([]map[string]interface{}, 0)
+	response.Ok(ctx)
+}
 
-	list := "["
+// InstallConfig write the config file and lock the installer.
+func (h *Handler) InstallConfig(ctx *context.Context) {
 
-	for i := 0; i < len(tables); i++ {
-		if i != len(tables)-1 {
-			list += `"` + tables[i]["Tables_in_godmin"].(string) + `",`
-		} else {
-			list += `"` + tables[i]["Tables_in_godmin"].(string) + `"`
-		}
+	dbCfg, ok := installDatabaseConfig(ctx)
+	if !ok {
+		return
+	}
+
+	prefix := strings.Trim(ctx.FormValue("prefix"), "/")
+	if prefix == "" {
+		prefix = "admin"
+	}
+
+	cfg := c.Config{
+		Databases: c.DatabaseList{
+			"default": dbCfg,
+		},
+		UrlPrefix: prefix,
+		Language:  ctx.FormValue("language"),
+		Title:     ctx.FormValue("title"),
+		Theme:     h.Config().Theme,
+		Store:     h.Config().Store,
+		IndexUrl:  "/",
+		Env:       c.EnvLocal,
+	}
+
+	if err := installer.WriteConfig(h.installer.ConfigPath(), cfg); err != nil {
+		logger.Error("install write config error: ", err)
+		response.Error(ctx, "write config file fail")
+		return
+	}
+
+	if err := h.installer.Lock(); err != nil {
+		logger.Error("install lock error: ", err)
+		response.Error(ctx, "write config file fail")
+		return
 	}
-	list += "]"
 
 	response.OkWithData(ctx, map[string]interface{}{
-		"list": list,
+		"url": "/" + prefix + "/login",
 	})
 }
+
+func installDatabaseConfig(ctx *context.Context) (c.Database, bool) {
+	cfg := c.Database{
+		Driver:     ctx.FormValue("driver"),
+		Host:       ctx.FormValue("host"),
+		Port:       ctx.FormValue("port"),
+		User:       ctx.FormValue("user"),
+		Pwd:        ctx.FormValue("pwd"),
+		Name:       ctx.FormValue("name"),
+		File:       ctx.FormValue("file"),
+		MaxIdleCon: 5,
+		MaxOpenCon: 10,
+	}
+
+	if !installer.IsSupported(cfg.Driver) {
+		response.BadRequest(ctx, "driver not supported")
+		return cfg, false
+	}
+
+	if cfg.Driver == db.DriverSqlite {
+		if cfg.File == "" {
+			response.BadRequest(ctx, "check the database parameters")
+			return cfg, false
+		}
+	} else if cfg.Host == "" || cfg.Port == "" || cfg.Name == "" {
+		response.BadRequest(ctx, "check the database parameters")
+		return cfg, false
+	}
+
+	return cfg, true
+}
+
+func installConnect(ctx *context.Context) (db.Connection, bool) {
+	cfg, ok := installDatabaseConfig(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	conn, err := installer.Connect(cfg)
+	if err != nil {
+		logger.Error("install connect database error: ", err)
+		response.BadRequest(ctx, "check the database parameters")
+		return nil, false
+	}
+
+	return conn, true
+}
@@ -19,14 +19,14 @@ import (
 )
 
 // ShowMenu show menu info page.
-func ShowMenu(ctx *context.Context) {
-	getMenuInfoPanel(ctx, "")
+func (h *Handler) ShowMenu(ctx *context.Context) {
+	h.getMenuInfoPanel(ctx, "")
 }
 
 // ShowNewMenu show new menu page.
-func ShowNewMenu(ctx *context.Context) {
+func (h *Handler) ShowNewMenu(ctx *context.Context) {
 
-	panel := table.Get("menu")
+	panel := h.tables.Get("menu")
 
 	formData, groupFormData, groupHeaders := table.GetNewFormList(panel.GetForm().TabHeaders,
 		panel.GetForm().TabGroups,
@@ -38,41 +38,41 @@ func ShowNewMenu(ctx *context.Context) {
 $('.icon').iconpicker({placement: 'bottomLeft'});
 </script>`
 
-	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 	buf := template.Execute(tmpl, tmplName, user, types.Panel{
-		Content: formContent(aForm().
+		Content: h.formContent(h.aForm().
 			SetContent(formData).
 			SetTabContents(groupFormData).
 			SetTabHeaders(groupHeaders).
-			SetPrefix(config.PrefixFixSlash()).
+			SetPrefix(h.Config().PrefixFixSlash()).
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
-			SetUrl(config.Url("/menu/edit")).
-			SetToken(authSrv().AddToken()).
-			SetOperationFooter(formFooter()).
-			SetInfoUrl(config.Url("/menu"))) +
+			SetUrl(h.Config().Url("/menu/edit")).
+			SetToken(h.authSrv().AddToken()).
+			SetOperationFooter(h.formFooter()).
+			SetInfoUrl(h.Config().Url("/menu"))) +
 			template2.HTML(js),
 		Description: panel.GetForm().Description,
 		Title:       panel.GetForm().Title,
-	}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 
 	ctx.HTML(http.StatusOK, buf.String())
 }
 
 // ShowEditMenu show edit menu page.
-func ShowEditMenu(ctx *context.Context) {
+func (h *Handler) ShowEditMenu(ctx *context.Context) {
 
 	if ctx.Query("id") == "" {
-		getMenuInfoPanel(ctx, template.Get(config.Theme).Alert().
+		h.getMenuInfoPanel(ctx, template.Get(h.Config().Theme).Alert().
 			SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> `+language.Get("error")+`!`)).
 			SetTheme("warning").
 			SetContent(template2.HTML("wrong id")).
 			GetContent())
 		ctx.AddHeader("Content-Type", "text/html; charset=utf-8")
-		ctx.AddHeader(constant.PjaxUrlHeader, config.Url("/menu"))
+		ctx.AddHeader(constant.PjaxUrlHeader, h.Config().Url("/menu"))
 		return
 	}
 
-	formData, groupFormData, groupHeaders, title, description, _ := table.Get("menu").GetDataFromDatabaseWithId(ctx.Query("id"))
+	formData, groupFormData, groupHeaders, title, description, _ := h.tables.Get("menu").GetDataFromDatabaseWithId(ctx.Query("id"))
 
 	user := auth.Auth(ctx)
 
@@ -80,147 +80,147 @@ func ShowEditMenu(ctx *context.Context) {
 $('.icon').iconpicker({placement: 'bottomLeft'});
 </script>`
 
-	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 	buf := template.Execute(tmpl, tmplName, user, types.Panel{
-		Content: formContent(aForm().
+		Content: h.formContent(h.aForm().
 			SetContent(formData).
 			SetTabContents(groupFormData).
 			SetTabHeaders(groupHeaders).
-			SetPrefix(config.PrefixFixSlash()).
-			SetPrimaryKey(table.Get("menu").GetPrimaryKey().Name).
-			SetUrl(config.Url("/menu/edit")).
-			SetOperationFooter(formFooter()).
-			SetToken(authSrv().AddToken()).
-			SetInfoUrl(config.Url("/menu"))) + template2.HTML(js),
+			SetPrefix(h.Config().PrefixFixSlash()).
+			SetPrimaryKey(h.tables.Get("menu").GetPrimaryKey().Name).
+			SetUrl(h.Config().Url("/menu/edit")).
+			SetOperationFooter(h.formFooter()).
+			SetToken(h.authSrv().AddToken()).
+			SetInfoUrl(h.Config().Url("/menu"))) + template2.HTML(js),
 		Description: description,
 		Title:       title,
-	}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 
 	ctx.HTML(http.StatusOK, buf.String())
 }
 
 // DeleteMenu delete the menu of given id.
-func DeleteMenu(ctx *context.Context) {
-	models.MenuWithId(guard.GetMenuDeleteParam(ctx).Id).SetConn(db.GetConnection(services)).Delete()
-	table.RefreshTableList()
+func (h *Handler) DeleteMenu(ctx *context.Context) {
+	models.MenuWithId(guard.GetMenuDeleteParam(ctx).Id).SetConn(db.GetConnection(h.services)).Delete()
+	h.tables.Refresh()
 	response.Ok(ctx)
 }
 
 // EditMenu edit the menu of given id.
-func EditMenu(ctx *context.Context) {
+func (h *Handler) EditMenu(ctx *context.Context) {
 
 	param := guard.GetMenuEditParam(ctx)
 
 	if param.HasAlert() {
-		getMenuInfoPanel(ctx, param.Alert)
+		h.getMenuInfoPanel(ctx, param.Alert)
 		ctx.AddHeader("Content-Type", "text/html; charset=utf-8")
-		ctx.AddHeader(constant.PjaxUrlHeader, config.Url("/menu"))
+		ctx.AddHeader(constant.PjaxUrlHeader, h.Config().Url("/menu"))
 		return
 	}
 
-	menuModel := models.MenuWithId(param.Id).SetConn(db.GetConnection(services))
+	menuModel := models.MenuWithId(param.Id).SetConn(db.GetConnection(h.services))
 
 	menuModel.DeleteRoles()
 	for _, roleId := range param.Roles {
 		menuModel.AddRole(roleId)
 	}
-	table.RefreshTableList()
+	h.tables.Refresh()
 
 	menuModel.Update(param.Title, param.Icon, param.Uri, param.Header, param.ParentId)
 
-	getMenuInfoPanel(ctx, "")
+	h.getMenuInfoPanel(ctx, "")
 	ctx.AddHeader("Content-Type", "text/html; charset=utf-8")
-	ctx.AddHeader(constant.PjaxUrlHeader, config.Url("/menu"))
+	ctx.AddHeader(constant.PjaxUrlHeader, h.Config().Url("/menu"))
 }
 
 // NewMenu create a new menu item.
-func NewMenu(ctx *context.Context) {
+func (h *Handler) NewMenu(ctx *context.Context) {
 
 	param := guard.GetMenuNewParam(ctx)
 
 	if param.HasAlert() {
-		getMenuInfoPanel(ctx, param.Alert)
+		h.getMenuInfoPanel(ctx, param.Alert)
 		ctx.AddHeader("Content-Type", "text/html; charset=utf-8")
-		ctx.AddHeader(constant.PjaxUrlHeader, config.Url("/menu"))
+		ctx.AddHeader(constant.PjaxUrlHeader, h.Config().Url("/menu"))
 		return
 	}
 
 	user := auth.Auth(ctx)
 
-	menuModel := models.Menu().SetConn(db.GetConnection(services)).
-		New(param.Title, param.Icon, param.Uri, param.Header, param.ParentId, (menu.GetGlobalMenu(user, conn)).MaxOrder+1)
+	menuModel := models.Menu().SetConn(db.GetConnection(h.services)).
+		New(param.Title, param.Icon, param.Uri, param.Header, param.ParentId, (menu.GetGlobalMenu(user, h.conn)).MaxOrder+1)
 
 	for _, roleId := range param.Roles {
 		menuModel.AddRole(roleId)
 	}
 
-	menu.GetGlobalMenu(user, conn).AddMaxOrder()
-	table.RefreshTableList()
+	menu.GetGlobalMenu(user, h.conn).AddMaxOrder()
+	h.tables.Refresh()
 
-	getMenuInfoPanel(ctx, "")
+	h.getMenuInfoPanel(ctx, "")
 	ctx.AddHeader("Content-Type", "text/html; charset=utf-8")
-	ctx.AddHeader(constant.PjaxUrlHeader, config.Url("/menu"))
+	ctx.AddHeader(constant.PjaxUrlHeader, h.Config().Url("/menu"))
 }
 
 // MenuOrder change the order of menu items.
-func MenuOrder(ctx *context.Context) {
+func (h *Handler) MenuOrder(ctx *context.Context) {
 
 	var data []map[string]interface{}
 	_ = json.Unmarshal([]byte(ctx.FormValue("_order")), &data)
 
-	models.Menu().SetConn(db.GetConnection(services)).ResetOrder(data)
+	models.Menu().SetConn(db.GetConnection(h.services)).ResetOrder(data)
 
 	response.Ok(ctx)
 }
 
-func getMenuInfoPanel(ctx *context.Context, alert template2.HTML) {
+func (h *Handler) getMenuInfoPanel(ctx *context.Context, alert template2.HTML) {
 	user := auth.Auth(ctx)
 
-	table.RefreshTableList()
+	h.tables.Refresh()
 
-	editUrl := config.Url("/menu/edit/show")
-	deleteUrl := config.Url("/menu/delete")
-	orderUrl := config.Url("/menu/order")
+	editUrl := h.Config().Url("/menu/edit/show")
+	deleteUrl := h.Config().Url("/menu/delete")
+	orderUrl := h.Config().Url("/menu/order")
 
-	tree := aTree().
-		SetTree((menu.GetGlobalMenu(user, conn)).List).
+	tree := h.aTree().
+		SetTree((menu.GetGlobalMenu(user, h.conn)).List).
 		SetEditUrl(editUrl).
-		SetUrlPrefix(config.Prefix()).
+		SetUrlPrefix(h.Config().Prefix()).
 		SetDeleteUrl(deleteUrl).
 		SetOrderUrl(orderUrl).
 		GetContent()
 
-	header := aTree().GetTreeHeader()
-	box := aBox().SetHeader(header).SetBody(tree).GetContent()
-	col1 := aCol().SetSize(map[string]string{"md": "6"}).SetContent(box).GetContent()
+	header := h.aTree().GetTreeHeader()
+	box := h.aBox().SetHeader(header).SetBody(tree).GetContent()
+	col1 := h.aCol().SetSize(map[string]string{"md": "6"}).SetContent(box).GetContent()
 
-	list := table.Get("menu")
+	list := h.tables.Get("menu")
 
 	formList, groupFormList, groupHeaders := table.GetNewFormList(list.GetForm().TabHeaders, list.GetForm().TabGroups,
 		list.GetForm().FieldList)
 
-	newForm := menuFormContent(aForm().
-		SetPrefix(config.PrefixFixSlash()).
-		SetUrl(config.Url("/menu/new")).
-		SetPrimaryKey(table.Get("menu").GetPrimaryKey().Name).
-		SetToken(authSrv().AddToken()).
-		SetInfoUrl(config.Url("/menu")).
-		SetOperationFooter(formFooter()).
+	newForm := h.menuFormContent(h.aForm().
+		SetPrefix(h.Config().PrefixFixSlash()).
+		SetUrl(h.Config().Url("/menu/new")).
+		SetPrimaryKey(h.tables.Get("menu").GetPrimaryKey().Name).
+		SetToken(h.authSrv().AddToken()).
+		SetInfoUrl(h.Config().Url("/menu")).
+		SetOperationFooter(h.formFooter()).
 		SetTitle("New").
 		SetContent(formList).
 		SetTabContents(groupFormList).
 		SetTabHeaders(groupHeaders))
 
-	col2 := aCol().SetSize(map[string]string{"md": "6"}).SetContent(newForm).GetContent()
+	col2 := h.aCol().SetSize(map[string]string{"md": "6"}).SetContent(newForm).GetContent()
 
-	row := aRow().SetContent(col1 + col2).GetContent()
+	row := h.aRow().SetContent(col1 + col2).GetContent()
 
-	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 	buf := template.Execute(tmpl, tmplName, user, types.Panel{
 		Content:     alert + row,
 		Description: "Menus Manage",
 		Title:       "Menus Manage",
-	}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 
 	ctx.HTML(http.StatusOK, buf.String())
 }
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/auth"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/modules/metrics"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// metricsHandlers are the Handlers enabling the metrics, from which the
+// gauges of the sessions and the connection pools are collected.
+var metricsHandlers = struct {
+	sync.Mutex
+	once sync.Once
+	list []*Handler
+}{}
+
+// SetMetrics enable or disable the metrics route of the default Handler.
+func SetMetrics(enable bool) {
+	defaultHandler.SetMetrics(enable)
+}
+
+// MetricsEnabled return true if the metrics route of the default Handler
+// is enabled.
+func MetricsEnabled() bool {
+	return defaultHandler.MetricsEnabled()
+}
+
+// SetMetrics enable or disable the metrics route. The gauges of the
+// sessions and the connection pools are registered once in the process,
+// they are collected from all the Handlers enabling the metrics and
+// labeled with the url prefixes of the Handlers as the instances.
+func (h *Handler) SetMetrics(enable bool) *Handler {
+	h.metricsEnabled = enable
+
+	metricsHandlers.Lock()
+	list := make([]*Handler, 0, len(metricsHandlers.list)+1)
+	for _, item := range metricsHandlers.list {
+		if item != h {
+			list = append(list, item)
+		}
+	}
+	if enable {
+		list = append(list, h)
+	}
+	metricsHandlers.list = list
+	metricsHandlers.Unlock()
+
+	if enable {
+		metricsHandlers.once.Do(registerMetrics)
+	}
+	return h
+}
+
+// MetricsEnabled return true if the metrics route is enabled.
+func (h *Handler) MetricsEnabled() bool {
+	return h.metricsEnabled
+}
+
+// SetMetricsToken set the bearer token of the metrics route, so that it can
+// be scraped without a session. The route requires a login if the token is
+// empty.
+func (h *Handler) SetMetricsToken(token string) *Handler {
+	h.metricsToken = token
+	return h
+}
+
+// MetricsToken return the bearer token of the metrics route.
+func (h *Handler) MetricsToken() string {
+	return h.metricsToken
+}
+
+// MetricsAuth check the bearer token of the request to the metrics route.
+func (h *Handler) MetricsAuth(ctx *context.Context) {
+	token := strings.TrimPrefix(ctx.Headers("Authorization"), "Bearer ")
+	if h.metricsToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.metricsToken)) != 1 {
+		ctx.SetHeader("WWW-Authenticate", "Bearer")
+		ctx.Data(http.StatusUnauthorized, "text/plain; charset=utf-8", []byte("unauthorized"))
+		ctx.Abort()
+		return
+	}
+	ctx.Next()
+}
+
+// Metrics write the metrics in the Prometheus text exposition format.
+func (h *Handler) Metrics(ctx *context.Context) {
+	buf := new(bytes.Buffer)
+	if err := metrics.WriteText(buf); err != nil {
+		logger.Error("write metrics error: ", err)
+		ctx.SetStatusCode(http.StatusInternalServerError)
+		return
+	}
+	ctx.Data(http.StatusOK, metrics.ContentType, buf.Bytes())
+}
+
+// enabledMetricsHandlers return the Handlers enabling the metrics.
+func enabledMetricsHandlers() []*Handler {
+	metricsHandlers.Lock()
+	defer metricsHandlers.Unlock()
+	return append([]*Handler{}, metricsHandlers.list...)
+}
+
+// registerMetrics register the gauges which are collected from the
+// sessions and the connection pools of the Handlers enabling the metrics
+// when the metrics are written.
+func registerMetrics() {
+	metrics.NewGaugeFunc("goadmin_active_sessions",
+		"Number of the sessions which are not expired.", func() []metrics.Sample {
+			samples := make([]metrics.Sample, 0)
+			for _, h := range enabledMetricsHandlers() {
+				samples = append(samples, h.activeSessions()...)
+			}
+			return samples
+		}, "instance")
+
+	pools := []struct {
+		name, help string
+		value      func(s db.Stats, conn string) float64
+	}{
+		{"goadmin_db_open_connections", "Number of the established connections.",
+			func(s db.Stats, conn string) float64 { return float64(s.Pools[conn].OpenConnections) }},
+		{"goadmin_db_in_use_connections", "Number of the connections currently in use.",
+			func(s db.Stats, conn string) float64 { return float64(s.Pools[conn].InUse) }},
+		{"goadmin_db_idle_connections", "Number of the idle connections.",
+			func(s db.Stats, conn string) float64 { return float64(s.Pools[conn].Idle) }},
+		{"goadmin_db_wait_count", "Total number of the connections waited for.",
+			func(s db.Stats, conn string) float64 { return float64(s.Pools[conn].WaitCount) }},
+		{"goadmin_db_wait_duration_seconds", "Total time blocked waiting for a new connection.",
+			func(s db.Stats, conn string) float64 { return s.Pools[conn].WaitDuration.Seconds() }},
+	}
+
+	for _, pool := range pools {
+		value := pool.value
+		metrics.NewGaugeFunc(pool.name, pool.help, func() []metrics.Sample {
+			samples := make([]metrics.Sample, 0)
+			for _, h := range enabledMetricsHandlers() {
+				samples = append(samples, h.poolSamples(value)...)
+			}
+			return samples
+		}, "instance", "driver", "connection")
+	}
+}
+
+// poolSamples return the samples of the value of the connection pools of
+// the Handler.
+func (h *Handler) poolSamples(value func(s db.Stats, conn string) float64) []metrics.Sample {
+	var (
+		instance = h.Config().Prefix()
+		samples  = make([]metrics.Sample, 0)
+	)
+	for driver := range h.Config().Databases.GroupByDriver() {
+		conn, ok := h.services.Get(driver).(db.Connection)
+		if !ok {
+			continue
+		}
+		stats := conn.Stats()
+		for name := range stats.Pools {
+			samples = append(samples, metrics.Sample{
+				Labels: []string{instance, driver, name},
+				Value:  value(stats, name),
+			})
+		}
+	}
+	return samples
+}
+
+func (h *Handler) activeSessions() (samples []metrics.Sample) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Error("count active sessions error: ", err)
+			samples = nil
+		}
+	}()
+
+	num, err := auth.CountActiveSessions(h.conn)
+	if err != nil {
+		logger.Error("count active sessions error: ", err)
+		return nil
+	}
+	return []metrics.Sample{{Labels: []string{h.Config().Prefix()}, Value: float64(num)}}
+}
@@ -18,17 +18,17 @@ import (
 )
 
 // ShowNewForm show a new form page.
-func ShowNewForm(ctx *context.Context) {
+func (h *Handler) ShowNewForm(ctx *context.Context) {
 	param := guard.GetShowNewFormParam(ctx)
-	showNewForm(ctx, "", param.Prefix, param.GetUrl(), param.GetInfoUrl(), "")
+	h.showNewForm(ctx, "", param.Prefix, param.GetUrl(), param.GetInfoUrl(), "")
 }
 
-func showNewForm(ctx *context.Context, alert template2.HTML, prefix string, url, infoUrl, newUrl string) {
+func (h *Handler) showNewForm(ctx *context.Context, alert template2.HTML, prefix string, url, infoUrl, newUrl string) {
 
 	user := auth.Auth(ctx)
 
-	table.RefreshTableList()
-	panel := table.Get(prefix)
+	h.tables.Refresh()
+	panel := h.table(ctx, prefix)
 
 	formList, groupFormList, groupHeaders := table.GetNewFormList(panel.GetForm().TabHeaders, panel.GetForm().TabGroups,
 		panel.GetForm().FieldList)
@@ -39,24 +39,24 @@ func showNewForm(ctx *context.Context, alert template2.HTML, prefix string, url,
 		infoUrl = referer
 	}
 
-	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 	buf := template.Execute(tmpl, tmplName, user, types.Panel{
-		Content: alert + formContent(aForm().
-			SetPrefix(config.PrefixFixSlash()).
+		Content: alert + h.formContent(h.aForm().
+			SetPrefix(h.Config().PrefixFixSlash()).
 			SetContent(formList).
 			SetTabContents(groupFormList).
 			SetTabHeaders(groupHeaders).
 			SetUrl(url).
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
-			SetToken(authSrv().AddToken()).
-			SetOperationFooter(formFooter()).
+			SetToken(h.authSrv().AddToken()).
+			SetOperationFooter(h.formFooter()).
 			SetTitle("New").
 			SetInfoUrl(infoUrl).
 			SetHeader(panel.GetForm().HeaderHtml).
 			SetFooter(panel.GetForm().FooterHtml)),
 		Description: panel.GetForm().Description,
 		Title:       panel.GetForm().Title,
-	}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 	ctx.HTML(http.StatusOK, buf.String())
 
 	if newUrl != "" {
@@ -65,38 +65,40 @@ func showNewForm(ctx *context.Context, alert template2.HTML, prefix string, url,
 }
 
 // NewForm insert a table row into database.
-func NewForm(ctx *context.Context) {
+func (h *Handler) NewForm(ctx *context.Context) {
 
 	param := guard.GetNewFormParam(ctx)
 
 	if param.HasAlert() {
-		showNewForm(ctx, param.Alert, param.Prefix, param.GetUrl(), param.GetInfoUrl(), param.GetNewUrl())
+		h.showNewForm(ctx, param.Alert, param.Prefix, param.GetUrl(), param.GetInfoUrl(), param.GetNewUrl())
 		return
 	}
 
 	// process uploading files, only support local storage
 	if len(param.MultiForm.File) > 0 {
-		err := file.GetFileEngine(config.FileUploadEngine.Name).Upload(param.MultiForm)
+		err := file.GetFileEngine(h.Config().FileUploadEngine.Name).Upload(param.MultiForm)
 		if err != nil {
-			alert := aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
+			alert := h.aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 				SetTheme("warning").
 				SetContent(template2.HTML(err.Error())).
 				GetContent()
-			showNewForm(ctx, alert, param.Prefix, param.GetUrl(), param.GetInfoUrl(), param.GetNewUrl())
+			h.showNewForm(ctx, alert, param.Prefix, param.GetUrl(), param.GetInfoUrl(), param.GetNewUrl())
 			return
 		}
 	}
 
 	err := param.Panel.InsertDataFromDatabase(param.Value())
 	if err != nil {
-		alert := aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
+		alert := h.aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 			SetTheme("warning").
 			SetContent(template2.HTML(err.Error())).
 			GetContent()
-		showNewForm(ctx, alert, param.Prefix, param.GetUrl(), param.GetInfoUrl(), param.GetNewUrl())
+		h.showNewForm(ctx, alert, param.Prefix, param.GetUrl(), param.GetInfoUrl(), param.GetNewUrl())
 		return
 	}
 
+	h.markWrite(ctx)
+
 	if !param.FromList {
 		ctx.HTML(http.StatusOK, fmt.Sprintf(`<script>location.href="%s"</script>`, param.PreviousPath))
 		ctx.AddHeader(constant.PjaxUrlHeader, param.PreviousPath)
@@ -111,7 +113,7 @@ func NewForm(ctx *context.Context) {
 	updateUrl := modules.AorB(param.Panel.GetEditable(), param.GetUpdateUrl(), "")
 	detailUrl := param.GetDetailUrl()
 
-	buf := showTable(ctx, param.Panel, param.Path, param.Param, exportUrl, newUrl, deleteUrl,
+	buf := h.showTable(ctx, param.Panel, param.Path, param.Param, exportUrl, newUrl, deleteUrl,
 		infoUrl, editUrl, updateUrl, detailUrl)
 
 	ctx.HTML(http.StatusOK, buf.String())
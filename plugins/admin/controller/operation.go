@@ -8,8 +8,13 @@ import (
 	"github.com/glvd/go-admin/context"
 )
 
-// RecordOperationLog record all operation logs, store into database.
+// RecordOperationLog record the operation log with the default Handler.
 func RecordOperationLog(ctx *context.Context) {
+	defaultHandler.RecordOperationLog(ctx)
+}
+
+// RecordOperationLog record all operation logs, store into database.
+func (h *Handler) RecordOperationLog(ctx *context.Context) {
 	if user, ok := ctx.UserValue["user"].(models.UserModel); ok {
 		var input []byte
 		form := ctx.Request.MultipartForm
@@ -17,6 +22,6 @@ func RecordOperationLog(ctx *context.Context) {
 			input, _ = json.Marshal((*form).Value)
 		}
 
-		models.OperationLog().SetConn(db.GetConnection(services)).New(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(), string(input))
+		models.OperationLog().SetConn(db.GetConnection(h.services)).New(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(), string(input))
 	}
 }
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/auth"
+	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/modules/menu"
+	"github.com/glvd/go-admin/plugins"
+	"github.com/glvd/go-admin/plugins/admin/modules/resource"
+	"github.com/glvd/go-admin/plugins/admin/modules/response"
+	"github.com/glvd/go-admin/template"
+	"github.com/glvd/go-admin/template/types"
+	template2 "html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// managerPlugin is the name of the plugin serving the plugin manager, which
+// can not be disabled.
+const managerPlugin = "admin"
+
+// ShowPlugins show the installed plugins with the versions, the routes and
+// the states.
+func (h *Handler) ShowPlugins(ctx *context.Context) {
+
+	heads := []string{"name", "version", "routes", "status", "health", "operation"}
+
+	infoList := make([]map[string]template2.HTML, 0)
+	for i, plug := range h.plugins().All() {
+		var (
+			name    = plugins.Name(plug)
+			version = "-"
+			title   = name
+		)
+		if info, ok := plug.(plugins.Info); ok {
+			version = info.Version()
+		}
+		if title == "" {
+			title = fmt.Sprintf("#%d (%T)", i, plug)
+		}
+
+		status := template2.HTML(`<span class="label label-success">` + language.Get("enabled") + `</span>`)
+		if name != "" && !h.plugins().Enabled(name) {
+			status = template2.HTML(`<span class="label label-default">` + language.Get("disabled") + `</span>`)
+		}
+
+		health := template2.HTML("-")
+		if checker, ok := plug.(plugins.HealthChecker); ok {
+			health = template2.HTML(`<span class="label label-success">ok</span>`)
+			if err := checker.Health(); err != nil {
+				health = template2.HTML(`<span class="label label-danger">` +
+					template2.HTMLEscapeString(err.Error()) + `</span>`)
+			}
+		}
+
+		infoList = append(infoList, statusRow(heads,
+			template2.HTML(template2.HTMLEscapeString(title)),
+			template2.HTML(template2.HTMLEscapeString(version)),
+			routeList(plug.GetRequest()),
+			status,
+			health,
+			h.pluginSwitch(name)))
+	}
+
+	content := h.aBox().
+		WithHeadBorder().
+		SetHeader(template2.HTML(language.Get("plugins"))).
+		SetBody(h.statusTable(language.Get("installed plugins"), heads, infoList)).
+		GetContent()
+
+	user := auth.Auth(ctx)
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
+	buf := template.Execute(tmpl, tmplName, user, types.Panel{
+		Content:     content,
+		Description: language.Get("plugins"),
+		Title:       language.Get("plugins"),
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
+	ctx.HTML(http.StatusOK, buf.String())
+}
+
+// EnablePlugin enable the plugin and save the state.
+func (h *Handler) EnablePlugin(ctx *context.Context) {
+	h.setPluginEnabled(ctx, true)
+}
+
+// DisablePlugin disable the plugin and save the state, the routes of the
+// plugin respond 404 until it is enabled.
+func (h *Handler) DisablePlugin(ctx *context.Context) {
+	h.setPluginEnabled(ctx, false)
+}
+
+// plugins return the plugin manager of the engine which the Handler is
+// added to.
+func (h *Handler) plugins() *plugins.Manager {
+	return plugins.ManagerFrom(h.services)
+}
+
+func (h *Handler) setPluginEnabled(ctx *context.Context, enabled bool) {
+	name := ctx.FormValue("name")
+
+	if !h.authSrv().CheckToken(ctx.FormValue("_t")) {
+		response.BadRequest(ctx, "wrong token")
+		return
+	}
+
+	if !h.installedPlugin(name) {
+		response.BadRequest(ctx, "plugin not found")
+		return
+	}
+
+	if name == managerPlugin && !enabled {
+		response.BadRequest(ctx, "the plugin can not be disabled")
+		return
+	}
+
+	if err := resource.SaveState(h.conn, name, enabled); err != nil {
+		logger.Error("save plugin state error: ", err)
+		response.Error(ctx, "save plugin state error")
+		return
+	}
+	h.plugins().SetEnabled(name, enabled)
+
+	ctx.Redirect(h.Config().Url("/plugins"))
+}
+
+func (h *Handler) installedPlugin(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, plug := range h.plugins().All() {
+		if plugins.Name(plug) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func routeList(paths []context.Path) template2.HTML {
+	routes := make([]string, len(paths))
+	for i, path := range paths {
+		routes[i] = template2.HTMLEscapeString(strings.ToUpper(path.Method) + " " + path.URL)
+	}
+	return template2.HTML(`<details><summary>` + strconv.Itoa(len(routes)) + `</summary>` +
+		strings.Join(routes, "<br>") + `</details>`)
+}
+
+func (h *Handler) pluginSwitch(name string) template2.HTML {
+	if name == "" || name == managerPlugin {
+		return "-"
+	}
+
+	action, label, class := "/plugins/disable", language.Get("disable"), "btn-danger"
+	if !h.plugins().Enabled(name) {
+		action, label, class = "/plugins/enable", language.Get("enable"), "btn-success"
+	}
+
+	return template2.HTML(`<form method="post" action="` + h.Config().Url(action) + `" style="margin:0">` +
+		`<input type="hidden" name="name" value="` + template2.HTMLEscapeString(name) + `">` +
+		`<input type="hidden" name="_t" value="` + h.authSrv().AddToken() + `">` +
+		`<button type="submit" class="btn btn-xs ` + class + `">` + label + `</button></form>`)
+}
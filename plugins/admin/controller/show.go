@@ -19,43 +19,48 @@ import (
 	template2 "html/template"
 	"net/http"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
 // ShowInfo show info page.
-func ShowInfo(ctx *context.Context) {
+func (h *Handler) ShowInfo(ctx *context.Context) {
 
 	prefix := ctx.Query("__prefix")
-	panel := table.Get(prefix)
+	panel := h.table(ctx, prefix)
 
-	params := parameter.GetParam(ctx.Request.URL.Query(), panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name,
+	params := parameter.GetParam(h.viewValues(ctx, prefix), panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name,
 		panel.GetInfo().GetSort())
 
-	editUrl := modules.AorB(panel.GetEditable(), config.Url("/info/"+prefix+"/edit"+params.GetRouteParamStr()), "")
-	deleteUrl := modules.AorB(panel.GetDeletable(), config.Url("/delete/"+prefix), "")
-	exportUrl := modules.AorB(panel.GetExportable(), config.Url("/export/"+prefix+params.GetRouteParamStr()), "")
-	newUrl := modules.AorB(panel.GetCanAdd(), config.Url("/info/"+prefix+"/new"+params.GetRouteParamStr()), "")
-	infoUrl := config.Url("/info/" + prefix)
-	updateUrl := config.Url("/update/" + prefix)
-	detailUrl := config.Url("/info/" + prefix + "/detail" + params.GetRouteParamStr())
+	editUrl := modules.AorB(panel.GetEditable(), h.Config().Url("/info/"+prefix+"/edit"+params.GetRouteParamStr()), "")
+	deleteUrl := modules.AorB(panel.GetDeletable(), h.Config().Url("/delete/"+prefix), "")
+	exportUrl := modules.AorB(panel.GetExportable(), h.Config().Url("/export/"+prefix+params.GetRouteParamStr()), "")
+	newUrl := modules.AorB(panel.GetCanAdd(), h.Config().Url("/info/"+prefix+"/new"+params.GetRouteParamStr()), "")
+	infoUrl := h.Config().Url("/info/" + prefix)
+	updateUrl := h.Config().Url("/update/" + prefix)
+	detailUrl := h.Config().Url("/info/" + prefix + "/detail" + params.GetRouteParamStr())
 
-	buf := showTable(ctx, panel, ctx.Path(), params, exportUrl, newUrl, deleteUrl, infoUrl, editUrl, updateUrl, detailUrl)
+	buf := h.showTable(ctx, panel, ctx.Path(), params, exportUrl, newUrl, deleteUrl, infoUrl, editUrl, updateUrl, detailUrl)
 	ctx.HTML(http.StatusOK, buf.String())
 }
 
-func showTable(ctx *context.Context, panel table.Table, path string, params parameter.Parameters,
+func (h *Handler) showTable(ctx *context.Context, panel table.Table, path string, params parameter.Parameters,
 	exportUrl, newUrl, deleteUrl, infoUrl, editUrl, updateUrl, detailUrl string) *bytes.Buffer {
 
-	table.InitTableList()
+	h.tables.Init()
+
+	if readPrimary(ctx) {
+		params = params.SetReadPrimary(true)
+	}
 
 	panelInfo, err := panel.GetDataFromDatabase(path, params, false)
 
 	if err != nil {
-		tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+		tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 		user := auth.Auth(ctx)
-		alert := aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
+		alert := h.aAlert().SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 			SetTheme("warning").
 			SetContent(template2.HTML(err.Error())).
 			GetContent()
@@ -63,7 +68,7 @@ func showTable(ctx *context.Context, panel table.Table, path string, params para
 			Content:     alert,
 			Description: language.Get("error"),
 			Title:       language.Get("error"),
-		}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+		}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
 	}
 
 	var (
@@ -75,7 +80,7 @@ func showTable(ctx *context.Context, panel table.Table, path string, params para
 
 	if panel.GetInfo().TabGroups.Valid() {
 
-		dataTable = aDataTable().
+		dataTable = h.aDataTable().
 			SetThead(panelInfo.Thead).
 			SetDeleteUrl(deleteUrl).
 			SetNewUrl(newUrl).
@@ -89,7 +94,7 @@ func showTable(ctx *context.Context, panel table.Table, path string, params para
 		for key, header := range panel.GetInfo().TabHeaders {
 			tabsHtml[key] = map[string]template2.HTML{
 				"title": template2.HTML(header),
-				"content": aDataTable().
+				"content": h.aDataTable().
 					SetInfoList(infoListArr[key]).
 					SetInfoUrl(infoUrl).
 					SetButtons(btns).
@@ -109,9 +114,9 @@ func showTable(ctx *context.Context, panel table.Table, path string, params para
 					GetContent(),
 			}
 		}
-		body = aTab().SetData(tabsHtml).GetContent()
+		body = h.aTab().SetData(tabsHtml).GetContent()
 	} else {
-		dataTable = aDataTable().
+		dataTable = h.aDataTable().
 			SetInfoList(panelInfo.InfoList).
 			SetInfoUrl(infoUrl).
 			SetButtons(btns).
@@ -131,22 +136,24 @@ func showTable(ctx *context.Context, panel table.Table, path string, params para
 		body = dataTable.GetContent()
 	}
 
-	boxModel := aBox().
+	boxModel := h.aBox().
 		SetBody(body).
 		SetNoPadding().
-		SetHeader(dataTable.GetDataTableHeader() + panel.GetInfo().HeaderHtml).
+		SetHeader(dataTable.GetDataTableHeader() + h.viewMenu(ctx, ctx.Query("__prefix"), params) +
+			searchBox(panel.GetInfo(), infoUrl, params) + advancedFilter(panel.GetInfo(), infoUrl, params) +
+			panel.GetInfo().HeaderHtml).
 		WithHeadBorder().
 		SetFooter(panelInfo.Paginator.GetContent())
 
 	if len(panelInfo.FormData) > 0 {
 		boxModel = boxModel.SetSecondHeaderClass("filter-area").
-			SetSecondHeader(aForm().
+			SetSecondHeader(h.aForm().
 				SetContent(panelInfo.FormData).
-				SetPrefix(config.PrefixFixSlash()).
+				SetPrefix(h.Config().PrefixFixSlash()).
 				SetMethod("get").
 				SetLayout(panel.GetInfo().FilterFormLayout).
 				SetUrl(infoUrl).
-				SetOperationFooter(filterFormFooter(infoUrl)).
+				SetOperationFooter(h.filterFormFooter(infoUrl)).
 				GetContent())
 	}
 
@@ -154,19 +161,57 @@ func showTable(ctx *context.Context, panel table.Table, path string, params para
 
 	user := auth.Auth(ctx)
 
-	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	tmpl, tmplName := h.aTemplate().GetTemplate(isPjax(ctx))
 
 	return template.Execute(tmpl, tmplName, user, types.Panel{
 		Content:     box,
 		Description: panelInfo.Description,
 		Title:       panelInfo.Title,
-	}, config, menu.GetGlobalMenu(user, conn).SetActiveClass(config.URLRemovePrefix(ctx.Path())))
+	}, h.Config(), menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.Config().URLRemovePrefix(ctx.Path())))
+}
+
+// searchBox return the search box of the list if the search fields of the
+// info panel are set. The other parameters of the list are kept, and the
+// page is reset.
+func searchBox(info *types.InfoPanel, infoUrl string, params parameter.Parameters) template2.HTML {
+	if len(info.SearchFields) == 0 {
+		return ""
+	}
+
+	inputs := listInputs(params, "__search")
+
+	return template2.HTML(`<form action="` + template2.HTMLEscapeString(infoUrl) + `" method="get" ` +
+		`class="pull-right" style="margin-right: 10px;width: 220px;">` + inputs +
+		`<div class="input-group input-group-sm">` +
+		`<input type="text" name="__search" class="form-control" value="` + template2.HTMLEscapeString(params.Search) +
+		`" placeholder="` + template2.HTMLEscapeString(language.Get("search")) + `">` +
+		`<span class="input-group-btn"><button type="submit" class="btn btn-default">` +
+		`<i class="fa fa-search"></i></button></span></div></form>`)
+}
+
+// listInputs return the hidden inputs of the parameters of the list except
+// the page and the key, which are kept by the forms of the list.
+func listInputs(params parameter.Parameters, except string) string {
+	values := params.Values()
+	values.Del(except)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	inputs := ""
+	for _, key := range keys {
+		inputs += hiddenInput(key, values.Get(key))
+	}
+	return inputs
 }
 
 // Assets return front-end assets according the request path.
-func Assets(ctx *context.Context) {
-	filepath := config.URLRemovePrefix(ctx.Path())
-	data, err := aTemplate().GetAsset(filepath)
+func (h *Handler) Assets(ctx *context.Context) {
+	filepath := h.Config().URLRemovePrefix(ctx.Path())
+	data, err := h.aTemplate().GetAsset(filepath)
 
 	if err != nil {
 		data, err = template.GetAsset(filepath)
@@ -193,12 +238,12 @@ func Assets(ctx *context.Context) {
 }
 
 // Export export table rows as excel object.
-func Export(ctx *context.Context) {
+func (h *Handler) Export(ctx *context.Context) {
 	param := guard.GetExportParam(ctx)
 
 	tableName := "Sheet1"
 	prefix := ctx.Query("__prefix")
-	panel := table.Get(prefix)
+	panel := h.table(ctx, prefix)
 
 	f := excelize.NewFile()
 	index := f.NewSheet(tableName)
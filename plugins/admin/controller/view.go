@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/glvd/go-admin/plugins/admin/modules"
+	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
+	"github.com/glvd/go-admin/plugins/admin/modules/response"
+	"github.com/glvd/go-admin/plugins/admin/modules/views"
+	template2 "html/template"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SaveView save the parameters of the list as a view of the user.
+func (h *Handler) SaveView(ctx *context.Context) {
+
+	if !h.authSrv().CheckToken(ctx.FormValue("_t")) {
+		response.BadRequest(ctx, "wrong token")
+		return
+	}
+
+	user, ok := ctx.User().(models.UserModel)
+	if !ok || h.views == nil {
+		response.BadRequest(ctx, "views are not available")
+		return
+	}
+
+	prefix := ctx.FormValue("prefix")
+	if h.tables.Get(prefix) == nil {
+		response.BadRequest(ctx, "table not found")
+		return
+	}
+
+	values, err := url.ParseQuery(ctx.FormValue("params"))
+	if err != nil {
+		response.BadRequest(ctx, "wrong parameters")
+		return
+	}
+
+	roles := make([]string, 0)
+	for _, slug := range ctx.PostForm()["roles"] {
+		if slug != "" && !strings.Contains(slug, ",") {
+			roles = append(roles, slug)
+		}
+	}
+
+	v := views.View{
+		UserId:    user.Id,
+		Prefix:    prefix,
+		Name:      ctx.FormValue("name"),
+		Params:    views.Params(values),
+		Roles:     roles,
+		IsDefault: checked(ctx, "is_default"),
+	}
+	if strings.TrimSpace(v.Name) == "" {
+		response.BadRequest(ctx, "the name of the view is empty")
+		return
+	}
+
+	if err := h.views.Save(v); err != nil {
+		logger.Error("save view error: ", err)
+		response.Error(ctx, "save view error")
+		return
+	}
+
+	ctx.Redirect(h.Config().Url("/info/" + url.PathEscape(prefix) + "?" + v.Params))
+}
+
+// SetDefaultView set the own view of the user as the default view of the
+// list, the id 0 unset the default view.
+func (h *Handler) SetDefaultView(ctx *context.Context) {
+
+	if !h.authSrv().CheckToken(ctx.FormValue("_t")) {
+		response.BadRequest(ctx, "wrong token")
+		return
+	}
+
+	user, ok := ctx.User().(models.UserModel)
+	if !ok || h.views == nil {
+		response.BadRequest(ctx, "views are not available")
+		return
+	}
+
+	var (
+		prefix = ctx.FormValue("prefix")
+		id, _  = strconv.ParseInt(ctx.FormValue("id"), 10, 64)
+	)
+	if err := h.views.SetDefault(user, prefix, id); err != nil {
+		logger.Error("set default view error: ", err)
+		response.Error(ctx, "set default view error")
+		return
+	}
+
+	ctx.Redirect(h.Config().Url("/info/" + url.PathEscape(prefix)))
+}
+
+// DeleteView delete the own view of the user.
+func (h *Handler) DeleteView(ctx *context.Context) {
+
+	if !h.authSrv().CheckToken(ctx.FormValue("_t")) {
+		response.BadRequest(ctx, "wrong token")
+		return
+	}
+
+	user, ok := ctx.User().(models.UserModel)
+	if !ok || h.views == nil {
+		response.BadRequest(ctx, "views are not available")
+		return
+	}
+
+	var (
+		prefix = ctx.FormValue("prefix")
+		id, _  = strconv.ParseInt(ctx.FormValue("id"), 10, 64)
+	)
+	if err := h.views.Delete(user, id); err != nil {
+		logger.Error("delete view error: ", err)
+		response.Error(ctx, "delete view error")
+		return
+	}
+
+	ctx.Redirect(h.Config().Url("/info/" + url.PathEscape(prefix)))
+}
+
+// viewValues return the parameters of the list: the parameters of the view
+// chosen by views.Key, or of the default view of the user when the list is
+// opened without parameters, otherwise the parameters of the request.
+func (h *Handler) viewValues(ctx *context.Context, prefix string) url.Values {
+	values := ctx.Request.URL.Query()
+
+	user, ok := ctx.User().(models.UserModel)
+	if !ok || h.views == nil {
+		return values
+	}
+
+	var (
+		v     views.View
+		found bool
+		err   error
+	)
+	if key := values.Get(views.Key); key != "" {
+		id, _ := strconv.ParseInt(key, 10, 64)
+		if id == 0 {
+			return values
+		}
+		v, found, err = h.views.Get(user, prefix, id)
+	} else if views.IsBare(values) {
+		v, found, err = h.views.Default(user, prefix)
+	}
+	if err != nil {
+		logger.Error("query views error: ", err)
+	}
+	if !found {
+		return values
+	}
+	return v.Values()
+}
+
+// viewMenu return the dropdown of the views of the list, from which the
+// user chooses a view, saves the parameters of the list as a view, and sets
+// the default view or deletes the own views.
+func (h *Handler) viewMenu(ctx *context.Context, prefix string, params parameter.Parameters) template2.HTML {
+	user, ok := ctx.User().(models.UserModel)
+	if !ok || h.views == nil || prefix == "" {
+		return ""
+	}
+
+	list, err := h.views.List(user, prefix)
+	if err != nil {
+		logger.Error("query views error: ", err)
+		return ""
+	}
+
+	var (
+		infoUrl = h.Config().Url("/info/" + url.PathEscape(prefix))
+		token   = hiddenInput("_t", h.authSrv().AddToken())
+		items   = `<li><a href="` + template2.HTMLEscapeString(infoUrl+"?"+views.Key+"=0") + `">` +
+			language.Get("no view") + `</a></li>`
+	)
+
+	if len(list) > 0 {
+		items += `<li class="divider"></li>`
+	}
+	for _, v := range list {
+		icon := modules.AorB(v.Own(user), "fa-bookmark-o", "fa-share-alt")
+		items += `<li><div style="padding: 3px 20px;white-space: nowrap;">` +
+			`<a href="` + template2.HTMLEscapeString(infoUrl+"?"+views.Key+"="+strconv.FormatInt(v.Id, 10)) + `">` +
+			`<i class="fa ` + icon + `"></i>&nbsp;` + template2.HTMLEscapeString(v.Name) + `</a>`
+		if v.Own(user) {
+			defaultId := v.Id
+			if v.IsDefault {
+				defaultId = 0
+			}
+			items += `<span class="pull-right">` +
+				h.viewAction("/views/default", prefix, defaultId, token, modules.AorB(v.IsDefault, "fa-star", "fa-star-o"),
+					modules.AorB(v.IsDefault, language.Get("unset default"), language.Get("set as default"))) +
+				h.viewAction("/views/delete", prefix, v.Id, token, "fa-trash", language.Get("delete")) +
+				`</span>`
+		}
+		items += `</div></li>`
+	}
+
+	roles, err := db.WithDriver(h.conn).Table("adm_roles").OrderBy("id", "asc").All()
+	if err != nil {
+		logger.Error("query roles error: ", err)
+	}
+	options := ""
+	for _, role := range roles {
+		slug, _ := role["slug"].(string)
+		name, _ := role["name"].(string)
+		options += `<option value="` + template2.HTMLEscapeString(slug) + `">` +
+			template2.HTMLEscapeString(name) + `</option>`
+	}
+
+	items += `<li class="divider"></li>` +
+		`<li><form method="post" action="` + h.Config().Url("/views/save") + `" style="padding: 3px 20px;">` +
+		hiddenInput("prefix", prefix) +
+		hiddenInput("params", views.Params(params.Values())) +
+		token +
+		`<div class="form-group"><input type="text" class="form-control input-sm" name="name" required ` +
+		`placeholder="` + template2.HTMLEscapeString(language.Get("view name")) + `"></div>` +
+		`<div class="form-group"><label>` + language.Get("share with roles") + `</label>` +
+		`<select class="form-control input-sm" name="roles" multiple>` + options + `</select></div>` +
+		`<div class="checkbox"><label>` + checkbox("is_default", false) + ` ` + language.Get("set as default") +
+		`</label></div>` +
+		`<button type="submit" class="btn btn-sm btn-primary btn-block">` + language.Get("save view") +
+		`</button></form></li>`
+
+	return template2.HTML(`<div class="btn-group pull-right" style="margin-right: 10px">` +
+		`<button type="button" class="btn btn-sm btn-default dropdown-toggle" data-toggle="dropdown">` +
+		`<i class="fa fa-bookmark"></i>&nbsp;&nbsp;` + language.Get("views") + ` <span class="caret"></span></button>` +
+		`<ul class="dropdown-menu dropdown-menu-right" style="min-width: 260px;">` + items + `</ul></div>`)
+}
+
+func (h *Handler) viewAction(path, prefix string, id int64, token, icon, title string) string {
+	return `<form method="post" action="` + h.Config().Url(path) + `" style="display:inline;margin:0">` +
+		hiddenInput("prefix", prefix) +
+		hiddenInput("id", strconv.FormatInt(id, 10)) +
+		token +
+		`<button type="submit" class="btn btn-xs btn-link" title="` + template2.HTMLEscapeString(title) + `">` +
+		`<i class="fa ` + icon + `"></i></button></form>`
+}
@@ -93,6 +93,7 @@ func (t UserModel) WithRoles() UserModel {
 		Where("user_id", "=", t.Id).
 		Select("adm_roles.id", "adm_roles.name", "adm_roles.slug",
 			"adm_roles.created_at", "adm_roles.updated_at").
+		Cache().
 		All()
 
 	for _, role := range roleModel {
@@ -132,6 +133,7 @@ func (t UserModel) WithPermissions() UserModel {
 			Select("adm_permissions.http_method", "adm_permissions.http_path",
 				"adm_permissions.id", "adm_permissions.name", "adm_permissions.slug",
 				"adm_permissions.created_at", "adm_permissions.updated_at").
+			Cache().
 			All()
 	}
 
@@ -141,6 +143,7 @@ func (t UserModel) WithPermissions() UserModel {
 		Select("adm_permissions.http_method", "adm_permissions.http_path",
 			"adm_permissions.id", "adm_permissions.name", "adm_permissions.slug",
 			"adm_permissions.created_at", "adm_permissions.updated_at").
+		Cache().
 		All()
 
 	permissions = append(permissions, userPermissions...)
@@ -171,6 +174,7 @@ func (t UserModel) WithMenus() UserModel {
 		menuIdsModel, _ = t.Table("adm_role_menu").
 			LeftJoin("adm_menu", "adm_menu.id", "=", "adm_role_menu.menu_id").
 			Select("menu_id", "parent_id").
+			Cache().
 			All()
 	} else {
 		rolesId := t.GetAllRoleId()
@@ -179,6 +183,7 @@ func (t UserModel) WithMenus() UserModel {
 				LeftJoin("adm_menu", "adm_menu.id", "=", "adm_role_menu.menu_id").
 				WhereIn("adm_role_menu.role_id", rolesId).
 				Select("menu_id", "parent_id").
+				Cache().
 				All()
 		}
 	}
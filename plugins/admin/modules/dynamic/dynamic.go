@@ -0,0 +1,297 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package dynamic manages the tables defined from the UI at runtime. The
+// definitions are saved in the table adm_dynamic_tables, and the Store
+// provides the generators of them to the table list, so that the tables
+// are served without recompiling.
+package dynamic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/tools"
+	"github.com/glvd/go-admin/plugins/admin/modules/table"
+	"github.com/glvd/go-admin/template/types"
+	"github.com/glvd/go-admin/template/types/form"
+	"regexp"
+	"strings"
+)
+
+// Option is an option of the select form types and filters.
+type Option struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+}
+
+// Field is a column of the table shown in the list and the form.
+type Field struct {
+	Head  string `json:"head"`
+	Field string `json:"field"`
+	// Type is the DatabaseType of the column, such as "VARCHAR".
+	Type string `json:"type"`
+
+	// Hide hides the column in the list.
+	Hide     bool `json:"hide"`
+	Sortable bool `json:"sortable"`
+	// Filter is the form type of the filter, such as "text", empty means
+	// the column is not filterable.
+	Filter         string `json:"filter"`
+	FilterOperator string `json:"filter_operator"`
+
+	// FormType is the form type of the column, such as "text", empty means
+	// the column is not in the form.
+	FormType     string   `json:"form_type"`
+	Must         bool     `json:"must"`
+	NotAllowAdd  bool     `json:"not_allow_add"`
+	NotAllowEdit bool     `json:"not_allow_edit"`
+	Default      string   `json:"default"`
+	Options      []Option `json:"options"`
+}
+
+// Definition is the definition of a table defined from the UI.
+type Definition struct {
+	// Prefix is the key of the table in the urls, such as /info/{prefix}.
+	Prefix      string `json:"prefix"`
+	Connection  string `json:"connection"`
+	Driver      string `json:"driver"`
+	Table       string `json:"table"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	PrimaryKey     string `json:"primary_key"`
+	PrimaryKeyType string `json:"primary_key_type"`
+
+	CanAdd     bool `json:"can_add"`
+	Editable   bool `json:"editable"`
+	Deletable  bool `json:"deletable"`
+	Exportable bool `json:"exportable"`
+
+	Fields []Field `json:"fields"`
+}
+
+var (
+	prefixReg = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	// identReg matches the names of the tables and the columns, which are
+	// put into the statements without quoting.
+	identReg = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Validate check the definition and return the first error found.
+func (d Definition) Validate() error {
+	if !prefixReg.MatchString(d.Prefix) {
+		return errors.New("the prefix should only contain letters, numbers, '_' and '-'")
+	}
+	if d.Table == "" {
+		return errors.New("the table is required")
+	}
+	if !identReg.MatchString(d.Table) {
+		return fmt.Errorf("invalid table name %q", d.Table)
+	}
+	if d.PrimaryKey != "" && !identReg.MatchString(d.PrimaryKey) {
+		return fmt.Errorf("invalid primary key %q", d.PrimaryKey)
+	}
+	if d.Driver == "" {
+		return errors.New("the driver is required")
+	}
+	if len(d.Fields) == 0 {
+		return errors.New("at least one column should be chosen")
+	}
+	if d.PrimaryKey != "" && !ValidType(d.PrimaryKeyType) {
+		return fmt.Errorf("unknown type %q of the primary key", d.PrimaryKeyType)
+	}
+
+	names := make(map[string]bool, len(d.Fields))
+	for _, field := range d.Fields {
+		if field.Field == "" {
+			return errors.New("the column name is required")
+		}
+		if !identReg.MatchString(field.Field) {
+			return fmt.Errorf("invalid column name %q", field.Field)
+		}
+		if names[field.Field] {
+			return fmt.Errorf("the column %s is duplicated", field.Field)
+		}
+		names[field.Field] = true
+
+		if !ValidType(field.Type) {
+			return fmt.Errorf("unknown type %q of the column %s", field.Type, field.Field)
+		}
+		if field.Filter != "" {
+			if _, ok := FormType(field.Filter); !ok {
+				return fmt.Errorf("unknown filter type %q of the column %s", field.Filter, field.Field)
+			}
+		}
+		if field.FilterOperator != "" && !validOperator(field.FilterOperator) {
+			return fmt.Errorf("unknown filter operator %q of the column %s", field.FilterOperator, field.Field)
+		}
+		if field.FormType != "" {
+			if _, ok := FormType(field.FormType); !ok {
+				return fmt.Errorf("unknown form type %q of the column %s", field.FormType, field.Field)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckSchema check the table, the primary key and the columns of the
+// definition against the columns read from the database. The tables of the
+// admin, such as adm_users, are refused unless allowSystem is true.
+func (d Definition) CheckSchema(columns []string, allowSystem bool) error {
+	if !allowSystem && strings.HasPrefix(d.Table, tools.SystemTablePrefix) {
+		return fmt.Errorf("the table %s of the admin can not be defined", d.Table)
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("table %s is not found", d.Table)
+	}
+
+	exist := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		exist[column] = true
+	}
+	if d.PrimaryKey != "" && !exist[d.PrimaryKey] {
+		return fmt.Errorf("the primary key %s is not a column of the table %s", d.PrimaryKey, d.Table)
+	}
+	for _, field := range d.Fields {
+		if !exist[field.Field] {
+			return fmt.Errorf("the column %s is not found in the table %s", field.Field, d.Table)
+		}
+	}
+	return nil
+}
+
+// Operators return the filter operators which can be chosen.
+func Operators() []types.FilterOperator {
+	return []types.FilterOperator{
+		types.FilterOperatorEqual,
+		types.FilterOperatorNotEqual,
+		types.FilterOperatorLike,
+		types.FilterOperatorGreater,
+		types.FilterOperatorGreaterOrEqual,
+		types.FilterOperatorLess,
+		types.FilterOperatorLessOrEqual,
+	}
+}
+
+func validOperator(operator string) bool {
+	for _, o := range Operators() {
+		if string(o) == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidType return true if the name is of a DatabaseType.
+func ValidType(name string) bool {
+	typ := db.DatabaseType(strings.ToUpper(name))
+	return db.Contains(typ, db.BoolTypeList) || db.Contains(typ, db.IntTypeList) ||
+		db.Contains(typ, db.FloatTypeList) || db.Contains(typ, db.UintTypeList) ||
+		db.Contains(typ, db.StringTypeList)
+}
+
+// FormTypes return the names of the form types which can be chosen.
+func FormTypes() []string {
+	names := make([]string, 0)
+	for t := form.Default; t <= form.Switch; t++ {
+		if t == form.Custom {
+			continue
+		}
+		names = append(names, t.String())
+	}
+	return names
+}
+
+// FormType return the form type of the name.
+func FormType(name string) (form.Type, bool) {
+	for t := form.Default; t <= form.Switch; t++ {
+		if t != form.Custom && t.String() == name {
+			return t, true
+		}
+	}
+	return form.Default, false
+}
+
+func (d Definition) connection() string {
+	if d.Connection == "" {
+		return table.DefaultConnectionName
+	}
+	return d.Connection
+}
+
+// Generator return the generator of the table of the definition.
+func (d Definition) Generator() table.Generator {
+	return func() table.Table {
+		cfg := table.DefaultConfigWithDriverAndConnection(d.Driver, d.connection()).
+			SetCanAdd(d.CanAdd).
+			SetEditable(d.Editable).
+			SetDeletable(d.Deletable).
+			SetExportable(d.Exportable)
+		if d.PrimaryKey != "" {
+			cfg = cfg.SetPrimaryKey(d.PrimaryKey, db.DatabaseType(strings.ToUpper(d.PrimaryKeyType)))
+		}
+
+		tb := table.NewDefaultTable(cfg)
+
+		info := tb.GetInfo()
+		for _, field := range d.Fields {
+			info.AddField(field.Head, field.Field, db.DatabaseType(strings.ToUpper(field.Type)))
+			if field.Hide {
+				info.FieldHide()
+			}
+			if field.Sortable {
+				info.FieldSortable()
+			}
+			if filterType, ok := FormType(field.Filter); ok {
+				info.FieldFilterable(types.FilterType{
+					FormType: filterType,
+					Operator: types.FilterOperator(field.FilterOperator),
+				})
+				if len(field.Options) > 0 {
+					info.FieldFilterOptions(options(field.Options))
+				}
+			}
+		}
+		info.SetTable(d.Table).SetTitle(d.Title).SetDescription(d.Description)
+
+		formList := tb.GetForm()
+		for _, field := range d.Fields {
+			formType, ok := FormType(field.FormType)
+			if !ok {
+				continue
+			}
+			formList.AddField(field.Head, field.Field, db.DatabaseType(strings.ToUpper(field.Type)), formType)
+			if field.Must {
+				formList.FieldMust()
+			}
+			if field.NotAllowAdd {
+				formList.FieldNotAllowAdd()
+			}
+			if field.NotAllowEdit {
+				formList.FieldNotAllowEdit()
+			}
+			if field.Default != "" {
+				formList.FieldDefault(field.Default)
+			}
+			if len(field.Options) > 0 {
+				formList.FieldOptions(options(field.Options))
+			}
+		}
+		formList.SetTable(d.Table).SetTitle(d.Title).SetDescription(d.Description)
+
+		return tb
+	}
+}
+
+func options(list []Option) []map[string]string {
+	res := make([]map[string]string, len(list))
+	for i, option := range list {
+		res[i] = map[string]string{
+			"field": option.Text,
+			"value": option.Value,
+		}
+	}
+	return res
+}
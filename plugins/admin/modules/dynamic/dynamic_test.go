@@ -0,0 +1,107 @@
+package dynamic
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/template/types"
+	"github.com/glvd/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testDefinition() Definition {
+	return Definition{
+		Prefix:         "posts",
+		Driver:         db.DriverMysql,
+		Table:          "posts",
+		Title:          "Posts",
+		PrimaryKey:     "id",
+		PrimaryKeyType: "int",
+		Editable:       true,
+		Fields: []Field{
+			{Head: "ID", Field: "id", Type: "INT", Sortable: true, FormType: "default", NotAllowEdit: true},
+			{Head: "Title", Field: "title", Type: "VARCHAR", Filter: "text", FilterOperator: "like", FormType: "text", Must: true},
+			{Head: "State", Field: "state", Type: "TINYINT", Hide: true, FormType: "radio",
+				Options: []Option{{Text: "On", Value: "1"}, {Text: "Off", Value: "0"}}},
+			{Head: "Created at", Field: "created_at", Type: "TIMESTAMP"},
+		},
+	}
+}
+
+func TestDefinition_Validate(t *testing.T) {
+	assert.NoError(t, testDefinition().Validate())
+
+	for _, change := range []func(d *Definition){
+		func(d *Definition) { d.Prefix = "a b" },
+		func(d *Definition) { d.Table = "" },
+		func(d *Definition) { d.Driver = "" },
+		func(d *Definition) { d.Fields = nil },
+		func(d *Definition) { d.PrimaryKeyType = "unknown" },
+		func(d *Definition) { d.Fields[1].Field = "id" },
+		func(d *Definition) { d.Fields[1].Type = "unknown" },
+		func(d *Definition) { d.Fields[1].Filter = "unknown" },
+		func(d *Definition) { d.Fields[1].FilterOperator = "in" },
+		func(d *Definition) { d.Fields[1].FormType = "custom" },
+		func(d *Definition) { d.Table = "posts where 1=1" },
+		func(d *Definition) { d.PrimaryKey = "id) or (1=1" },
+		func(d *Definition) { d.Fields[1].Field = "title, password" },
+	} {
+		d := testDefinition()
+		change(&d)
+		assert.Error(t, d.Validate())
+	}
+}
+
+func TestDefinition_CheckSchema(t *testing.T) {
+	columns := []string{"id", "title", "state", "created_at"}
+
+	assert.NoError(t, testDefinition().CheckSchema(columns, false))
+	assert.Error(t, testDefinition().CheckSchema(columns[:3], false))
+	assert.Error(t, testDefinition().CheckSchema(nil, false))
+
+	d := testDefinition()
+	d.PrimaryKey = "uuid"
+	assert.Error(t, d.CheckSchema(columns, false))
+
+	d = testDefinition()
+	d.Table = "adm_users"
+	assert.Error(t, d.CheckSchema(columns, false))
+	assert.NoError(t, d.CheckSchema(columns, true))
+}
+
+func TestDefinition_Generator(t *testing.T) {
+	tb := testDefinition().Generator()()
+
+	assert.True(t, tb.GetEditable())
+	assert.False(t, tb.GetCanAdd())
+	assert.Equal(t, "id", tb.GetPrimaryKey().Name)
+
+	info := tb.GetInfo()
+	assert.Equal(t, "posts", info.Table)
+	assert.Equal(t, "Posts", info.Title)
+	assert.Len(t, info.FieldList, 4)
+	assert.True(t, info.FieldList[0].Sortable)
+	assert.True(t, info.FieldList[1].Filterable)
+	assert.Equal(t, types.FilterOperatorLike, info.FieldList[1].FilterOperator)
+	assert.True(t, info.FieldList[2].Hide)
+
+	formList := tb.GetForm()
+	assert.Len(t, formList.FieldList, 3)
+	assert.Equal(t, form.Default, formList.FieldList[0].FormType)
+	assert.False(t, formList.FieldList[0].Editable)
+	assert.True(t, formList.FieldList[1].Must)
+	assert.Len(t, formList.FieldList[2].Options, 2)
+}
+
+func TestStore_Generator(t *testing.T) {
+	s := NewStore(nil)
+	s.definitions["posts"] = testDefinition()
+
+	gen, ok := s.Generator("posts")
+	assert.True(t, ok)
+	assert.Equal(t, "posts", gen().GetInfo().Table)
+
+	_, ok = s.Generator("users")
+	assert.False(t, ok)
+
+	assert.Len(t, s.All(), 1)
+}
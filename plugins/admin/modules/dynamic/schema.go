@@ -0,0 +1,45 @@
+package dynamic
+
+import "github.com/glvd/go-admin/modules/db"
+
+// schemas contains the statements which create the table saving the
+// definitions of the dynamic tables.
+var schemas = map[string][]string{
+	db.DriverMysql: {
+		"CREATE TABLE IF NOT EXISTS `adm_dynamic_tables` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`prefix` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`definition` text COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)," +
+			"UNIQUE KEY `admin_dynamic_tables_prefix_unique` (`prefix`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+	},
+	db.DriverPostgresql: {
+		`CREATE TABLE IF NOT EXISTS adm_dynamic_tables (
+    id serial PRIMARY KEY,
+    prefix character varying(100) NOT NULL UNIQUE,
+    definition text NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+	},
+	db.DriverSqlite: {
+		"CREATE TABLE IF NOT EXISTS `adm_dynamic_tables` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`prefix` varchar(100) NOT NULL UNIQUE," +
+			"`definition` text NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+	},
+	db.DriverMssql: {
+		`IF OBJECT_ID('adm_dynamic_tables', 'U') IS NULL CREATE TABLE adm_dynamic_tables (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    prefix nvarchar(100) NOT NULL UNIQUE,
+    definition nvarchar(max) NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+	},
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package dynamic
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/glvd/go-admin/modules/cache"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/plugins/admin/modules/table"
+	"sort"
+	"sync"
+	"time"
+)
+
+const tableName = "adm_dynamic_tables"
+
+// Migrate create the table adm_dynamic_tables if not exist.
+func Migrate(conn db.Connection) error {
+	statements, ok := schemas[conn.Name()]
+	if !ok {
+		return errors.New("dynamic: unsupported driver " + conn.Name())
+	}
+	for _, statement := range statements {
+		if _, err := conn.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Store keeps the definitions of the dynamic tables in memory and saves them
+// into the database. It implements table.Source, the changes take effect on
+// the next request of the table.
+type Store struct {
+	conn        db.Connection
+	lock        sync.RWMutex
+	definitions map[string]Definition
+}
+
+// NewStore return a store of the connection.
+func NewStore(conn db.Connection) *Store {
+	return &Store{
+		conn:        conn,
+		definitions: make(map[string]Definition),
+	}
+}
+
+// Load create the table if not exist and load all the definitions.
+func (s *Store) Load() error {
+	if err := Migrate(s.conn); err != nil {
+		return err
+	}
+
+	items, err := db.WithDriver(s.conn).Table(tableName).All()
+	if err != nil {
+		return err
+	}
+
+	definitions := make(map[string]Definition, len(items))
+	for _, item := range items {
+		prefix, _ := item["prefix"].(string)
+		var d Definition
+		if err := json.Unmarshal([]byte(toString(item["definition"])), &d); err != nil {
+			return errors.New("dynamic: invalid definition of " + prefix + ": " + err.Error())
+		}
+		d.Prefix = prefix
+		// The definitions saved before the identifiers were checked are
+		// not served.
+		if err := d.Validate(); err != nil {
+			logger.Error("dynamic: invalid definition of ", prefix, ": ", err)
+			continue
+		}
+		definitions[prefix] = d
+	}
+
+	s.lock.Lock()
+	s.definitions = definitions
+	s.lock.Unlock()
+	return nil
+}
+
+// All return the definitions sorted by the prefix.
+func (s *Store) All() []Definition {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	list := make([]Definition, 0, len(s.definitions))
+	for _, d := range s.definitions {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Prefix < list[j].Prefix
+	})
+	return list
+}
+
+// Get return the definition of the prefix.
+func (s *Store) Get(prefix string) (Definition, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	d, ok := s.definitions[prefix]
+	return d, ok
+}
+
+// Generator return the generator of the prefix, see table.Source.
+func (s *Store) Generator(prefix string) (table.Generator, bool) {
+	d, ok := s.Get(prefix)
+	if !ok {
+		return nil, false
+	}
+	return d.Generator(), true
+}
+
+// Save validate and save the definition, the existing one of the same
+// prefix is replaced.
+func (s *Store) Save(d Definition) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	_, exist := s.Get(d.Prefix)
+	if exist {
+		_, err = s.conn.Exec("UPDATE "+tableName+" SET definition = ?, updated_at = ? WHERE prefix = ?",
+			string(value), time.Now().Format("2006-01-02 15:04:05"), d.Prefix)
+	} else {
+		_, err = s.conn.Exec("INSERT INTO "+tableName+" (prefix, definition) VALUES (?, ?)",
+			d.Prefix, string(value))
+	}
+	if err != nil {
+		return err
+	}
+	cache.InvalidateTables(db.CacheConn(s.conn, "default"), tableName)
+
+	s.lock.Lock()
+	s.definitions[d.Prefix] = d
+	s.lock.Unlock()
+	return nil
+}
+
+// Delete delete the definition of the prefix.
+func (s *Store) Delete(prefix string) error {
+	if _, err := s.conn.Exec("DELETE FROM "+tableName+" WHERE prefix = ?", prefix); err != nil {
+		return err
+	}
+	cache.InvalidateTables(db.CacheConn(s.conn, "default"), tableName)
+
+	s.lock.Lock()
+	delete(s.definitions, prefix)
+	s.lock.Unlock()
+	return nil
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
@@ -2,7 +2,6 @@ package guard
 
 import (
 	"github.com/glvd/go-admin/context"
-	"github.com/glvd/go-admin/modules/db"
 	"github.com/glvd/go-admin/plugins/admin/modules/table"
 )
 
@@ -12,31 +11,29 @@ type DeleteParam struct {
 	Prefix string
 }
 
-func Delete(conn db.Connection) context.Handler {
-	return func(ctx *context.Context) {
+func (g *Guard) Delete(ctx *context.Context) {
 
-		prefix := ctx.Query("__prefix")
-		panel := table.Get(prefix)
-		if !panel.GetDeletable() {
-			alert(ctx, panel, "operation not allow", conn)
-			ctx.Abort()
-			return
-		}
-
-		id := ctx.FormValue("id")
-		if id == "" {
-			alert(ctx, panel, "wrong id", conn)
-			ctx.Abort()
-			return
-		}
+	prefix := ctx.Query("__prefix")
+	panel := g.table(ctx, prefix)
+	if !panel.GetDeletable() {
+		g.alert(ctx, panel, "operation not allow")
+		ctx.Abort()
+		return
+	}
 
-		ctx.SetUserValue("delete_param", &DeleteParam{
-			Panel:  panel,
-			Id:     id,
-			Prefix: prefix,
-		})
-		ctx.Next()
+	id := ctx.FormValue("id")
+	if id == "" {
+		g.alert(ctx, panel, "wrong id")
+		ctx.Abort()
+		return
 	}
+
+	ctx.SetUserValue("delete_param", &DeleteParam{
+		Panel:  panel,
+		Id:     id,
+		Prefix: prefix,
+	})
+	ctx.Next()
 }
 
 func GetDeleteParam(ctx *context.Context) *DeleteParam {
@@ -4,9 +4,7 @@ import (
 	"github.com/glvd/go-admin/context"
 	"github.com/glvd/go-admin/modules/auth"
 	"github.com/glvd/go-admin/modules/config"
-	"github.com/glvd/go-admin/modules/db"
 	"github.com/glvd/go-admin/modules/language"
-	"github.com/glvd/go-admin/modules/service"
 	"github.com/glvd/go-admin/plugins/admin/modules"
 	"github.com/glvd/go-admin/plugins/admin/modules/form"
 	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
@@ -23,44 +21,45 @@ type ShowFormParam struct {
 	Id     string
 	Prefix string
 	Param  parameter.Parameters
+
+	config config.Config
 }
 
 func (e *ShowFormParam) GetUrl() string {
-	return config.Get().Url("/edit/" + e.Prefix)
+	return e.config.Url("/edit/" + e.Prefix)
 }
 
 func (e *ShowFormParam) GetInfoUrl() string {
-	return config.Get().Url("/info/" + e.Prefix + e.Param.GetRouteParamStr())
-}
-
-func ShowForm(conn db.Connection) context.Handler {
-	return func(ctx *context.Context) {
-
-		prefix := ctx.Query("__prefix")
-		panel := table.Get(prefix)
-
-		if !panel.GetEditable() {
-			alert(ctx, panel, "operation not allow", conn)
-			ctx.Abort()
-			return
-		}
-
-		id := ctx.Query("__goadmin_edit_pk")
-		if id == "" {
-			alert(ctx, panel, "wrong "+panel.GetPrimaryKey().Name, conn)
-			ctx.Abort()
-			return
-		}
-
-		ctx.SetUserValue("show_form_param", &ShowFormParam{
-			Panel:  panel,
-			Id:     id,
-			Prefix: prefix,
-			Param: parameter.GetParam(ctx.Request.URL.Query(), panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name,
-				panel.GetInfo().GetSort()),
-		})
-		ctx.Next()
+	return e.config.Url("/info/" + e.Prefix + e.Param.GetRouteParamStr())
+}
+
+func (g *Guard) ShowForm(ctx *context.Context) {
+
+	prefix := ctx.Query("__prefix")
+	panel := g.table(ctx, prefix)
+
+	if !panel.GetEditable() {
+		g.alert(ctx, panel, "operation not allow")
+		ctx.Abort()
+		return
 	}
+
+	id := ctx.Query("__goadmin_edit_pk")
+	if id == "" {
+		g.alert(ctx, panel, "wrong "+panel.GetPrimaryKey().Name)
+		ctx.Abort()
+		return
+	}
+
+	ctx.SetUserValue("show_form_param", &ShowFormParam{
+		Panel:  panel,
+		Id:     id,
+		Prefix: prefix,
+		Param: parameter.GetParam(ctx.Request.URL.Query(), panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name,
+			panel.GetInfo().GetSort()),
+		config: g.config.Get(),
+	})
+	ctx.Next()
 }
 
 func GetShowFormParam(ctx *context.Context) *ShowFormParam {
@@ -77,6 +76,8 @@ type EditFormParam struct {
 	PreviousPath string
 	Alert        template2.HTML
 	FromList     bool
+
+	config config.Config
 }
 
 func (e EditFormParam) Value() form.Values {
@@ -88,11 +89,11 @@ func (e EditFormParam) GetEditUrl() string {
 }
 
 func (e EditFormParam) GetUpdateUrl() string {
-	return config.Get().Url("/update/" + e.Prefix)
+	return e.config.Url("/update/" + e.Prefix)
 }
 
 func (e EditFormParam) GetDetailUrl() string {
-	return config.Get().Url("/info/" + e.Prefix + "/detail" + e.Param.GetRouteParamStr())
+	return e.config.Url("/info/" + e.Prefix + "/detail" + e.Param.GetRouteParamStr())
 }
 
 func (e EditFormParam) HasAlert() bool {
@@ -104,23 +105,23 @@ func (e EditFormParam) GetNewUrl() string {
 }
 
 func (e EditFormParam) GetExportUrl() string {
-	return config.Get().Url("/export/" + e.Prefix + e.Param.GetRouteParamStr())
+	return e.config.Url("/export/" + e.Prefix + e.Param.GetRouteParamStr())
 }
 
 func (e EditFormParam) GetDeleteUrl() string {
-	return config.Get().Url("/delete/" + e.Prefix)
+	return e.config.Url("/delete/" + e.Prefix)
 }
 
 func (e *EditFormParam) GetUrl() string {
-	return config.Get().Url("/edit/" + e.Prefix)
+	return e.config.Url("/edit/" + e.Prefix)
 }
 
 func (e *EditFormParam) GetInfoUrl() string {
-	return config.Get().Url("/info/" + e.Prefix + e.Param.GetRouteParamStr())
+	return e.config.Url("/info/" + e.Prefix + e.Param.GetRouteParamStr())
 }
 
 func (e EditFormParam) getUrl(kind string) string {
-	return config.Get().Url("/info/" + e.Prefix + "/" + kind + e.Param.GetRouteParamStr())
+	return e.config.Url("/info/" + e.Prefix + "/" + kind + e.Param.GetRouteParamStr())
 }
 
 func (e EditFormParam) IsManage() bool {
@@ -131,65 +132,62 @@ func (e EditFormParam) IsRole() bool {
 	return e.Prefix == "roles"
 }
 
-func EditForm(srv service.List) context.Handler {
-	return func(ctx *context.Context) {
-		prefix := ctx.Query("__prefix")
-		previous := ctx.FormValue("_previous_")
-		panel := table.Get(prefix)
-		multiForm := ctx.Request.MultipartForm
-
-		conn := db.GetConnection(srv)
-
-		if !panel.GetEditable() {
-			alert(ctx, panel, "operation not allow", conn)
-			ctx.Abort()
-			return
-		}
-		token := ctx.FormValue("_t")
-
-		if !auth.GetService(srv.Get("auth")).CheckToken(token) {
-			alert(ctx, panel, "edit fail, wrong token", conn)
-			ctx.Abort()
-			return
-		}
-
-		fromList := modules.IsInfoUrl(previous)
-
-		param := parameter.GetParamFromUrl(previous, fromList, panel.GetInfo().DefaultPageSize,
-			panel.GetPrimaryKey().Name, panel.GetInfo().GetSort())
-
-		if fromList {
-			previous = config.Get().Url("/info/" + prefix + param.GetRouteParamStr())
-		}
-
-		ctx.SetUserValue("edit_form_param", &EditFormParam{
-			Panel:        panel,
-			Id:           multiForm.Value[panel.GetPrimaryKey().Name][0],
-			Prefix:       prefix,
-			Param:        param,
-			Path:         strings.Split(previous, "?")[0],
-			MultiForm:    multiForm,
-			PreviousPath: previous,
-			FromList:     fromList,
-		})
-		ctx.Next()
+func (g *Guard) EditForm(ctx *context.Context) {
+	prefix := ctx.Query("__prefix")
+	previous := ctx.FormValue("_previous_")
+	panel := g.table(ctx, prefix)
+	multiForm := ctx.Request.MultipartForm
+
+	if !panel.GetEditable() {
+		g.alert(ctx, panel, "operation not allow")
+		ctx.Abort()
+		return
+	}
+	token := ctx.FormValue("_t")
+
+	if !auth.GetService(g.services.Get("auth")).CheckToken(token) {
+		g.alert(ctx, panel, "edit fail, wrong token")
+		ctx.Abort()
+		return
+	}
+
+	fromList := modules.IsInfoUrl(previous)
+
+	param := parameter.GetParamFromUrl(previous, fromList, panel.GetInfo().DefaultPageSize,
+		panel.GetPrimaryKey().Name, panel.GetInfo().GetSort())
+
+	if fromList {
+		previous = g.config.Get().Url("/info/" + prefix + param.GetRouteParamStr())
 	}
+
+	ctx.SetUserValue("edit_form_param", &EditFormParam{
+		Panel:        panel,
+		Id:           multiForm.Value[panel.GetPrimaryKey().Name][0],
+		Prefix:       prefix,
+		Param:        param,
+		Path:         strings.Split(previous, "?")[0],
+		MultiForm:    multiForm,
+		PreviousPath: previous,
+		FromList:     fromList,
+		config:       g.config.Get(),
+	})
+	ctx.Next()
 }
 
 func GetEditFormParam(ctx *context.Context) *EditFormParam {
 	return ctx.UserValue["edit_form_param"].(*EditFormParam)
 }
 
-func alert(ctx *context.Context, panel table.Table, msg string, conn db.Connection) {
-	response.Alert(ctx, config.Get(), panel.GetInfo().Description, panel.GetInfo().Title, msg, conn)
+func (g *Guard) alert(ctx *context.Context, panel table.Table, msg string) {
+	response.Alert(ctx, g.config.Get(), panel.GetInfo().Description, panel.GetInfo().Title, msg, g.conn)
 }
 
-func alertWithTitleAndDesc(ctx *context.Context, title, desc, msg string, conn db.Connection) {
-	response.Alert(ctx, config.Get(), desc, title, msg, conn)
+func (g *Guard) alertWithTitleAndDesc(ctx *context.Context, title, desc, msg string) {
+	response.Alert(ctx, g.config.Get(), desc, title, msg, g.conn)
 }
 
-func getAlert(msg string) template2.HTML {
-	return template.Get(config.Get().Theme).Alert().
+func (g *Guard) getAlert(msg string) template2.HTML {
+	return template.Get(g.config.Get().Theme).Alert().
 		SetTitle(template2.HTML(`<i class="icon fa fa-warning"></i> ` + language.Get("error") + `!`)).
 		SetTheme("warning").
 		SetContent(template2.HTML(msg)).
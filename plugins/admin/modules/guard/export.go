@@ -2,7 +2,6 @@ package guard
 
 import (
 	"github.com/glvd/go-admin/context"
-	"github.com/glvd/go-admin/modules/db"
 	"github.com/glvd/go-admin/plugins/admin/modules/table"
 	"strings"
 )
@@ -14,25 +13,23 @@ type ExportParam struct {
 	IsAll  bool
 }
 
-func Export(conn db.Connection) context.Handler {
-	return func(ctx *context.Context) {
+func (g *Guard) Export(ctx *context.Context) {
 
-		prefix := ctx.Query("__prefix")
-		panel := table.Get(prefix)
-		if !panel.GetExportable() {
-			alert(ctx, panel, "operation not allow", conn)
-			ctx.Abort()
-			return
-		}
-
-		ctx.SetUserValue("export_param", &ExportParam{
-			Panel:  panel,
-			Id:     strings.Split(ctx.FormValue("id"), ","),
-			Prefix: prefix,
-			IsAll:  ctx.FormValue("is_all") == "true",
-		})
-		ctx.Next()
+	prefix := ctx.Query("__prefix")
+	panel := g.table(ctx, prefix)
+	if !panel.GetExportable() {
+		g.alert(ctx, panel, "operation not allow")
+		ctx.Abort()
+		return
 	}
+
+	ctx.SetUserValue("export_param", &ExportParam{
+		Panel:  panel,
+		Id:     strings.Split(ctx.FormValue("id"), ","),
+		Prefix: prefix,
+		IsAll:  ctx.FormValue("is_all") == "true",
+	})
+	ctx.Next()
 }
 
 func GetExportParam(ctx *context.Context) *ExportParam {
@@ -0,0 +1,107 @@
+package guard
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/glvd/go-admin/plugins/admin/modules/installer"
+	"github.com/glvd/go-admin/plugins/admin/modules/table"
+)
+
+// Guard check the requests and set the parameters for the controllers
+// with the config, the services and the tables of an admin instance.
+type Guard struct {
+	config   *config.Service
+	services service.List
+	conn     db.Connection
+	tables   *table.List
+}
+
+// New return the guard of an admin instance, the config is got from the
+// service on each request.
+func New(cfg *config.Service, srv service.List, tables *table.List) *Guard {
+	return &Guard{
+		config:   cfg,
+		services: srv,
+		conn:     db.GetConnection(srv),
+		tables:   tables,
+	}
+}
+
+// defaultGuard return the guard of the global config and the default
+// table list.
+func defaultGuard(srv service.List, conn db.Connection) *Guard {
+	return &Guard{
+		config:   config.GlobalService(),
+		services: srv,
+		conn:     conn,
+		tables:   table.DefaultList(),
+	}
+}
+
+// table return the table of the prefix restricted to the user of the
+// request, see table.DefaultTable.WithUser.
+func (g *Guard) table(ctx *context.Context, prefix string) table.Table {
+	tb := g.tables.Get(prefix)
+	if user, ok := ctx.User().(models.UserModel); ok && tb != nil {
+		return tb.WithUser(user)
+	}
+	return tb
+}
+
+// ShowForm is the guard of the edit form page, see Guard.ShowForm.
+func ShowForm(conn db.Connection) context.Handler {
+	return defaultGuard(nil, conn).ShowForm
+}
+
+// EditForm is the guard of the edit form submission, see Guard.EditForm.
+func EditForm(srv service.List) context.Handler {
+	return defaultGuard(srv, db.GetConnection(srv)).EditForm
+}
+
+// ShowNewForm is the guard of the new form page, see Guard.ShowNewForm.
+func ShowNewForm(conn db.Connection) context.Handler {
+	return defaultGuard(nil, conn).ShowNewForm
+}
+
+// NewForm is the guard of the new form submission, see Guard.NewForm.
+func NewForm(srv service.List) context.Handler {
+	return defaultGuard(srv, db.GetConnection(srv)).NewForm
+}
+
+// Delete is the guard of the deletion, see Guard.Delete.
+func Delete(conn db.Connection) context.Handler {
+	return defaultGuard(nil, conn).Delete
+}
+
+// Export is the guard of the export, see Guard.Export.
+func Export(conn db.Connection) context.Handler {
+	return defaultGuard(nil, conn).Export
+}
+
+// MenuNew is the guard of the new menu submission, see Guard.MenuNew.
+func MenuNew(srv service.List) context.Handler {
+	return defaultGuard(srv, db.GetConnection(srv)).MenuNew
+}
+
+// MenuEdit is the guard of the edit menu submission, see Guard.MenuEdit.
+func MenuEdit(srv service.List) context.Handler {
+	return defaultGuard(srv, db.GetConnection(srv)).MenuEdit
+}
+
+// MenuDelete is the guard of the menu deletion, see Guard.MenuDelete.
+func MenuDelete(conn db.Connection) context.Handler {
+	return defaultGuard(nil, conn).MenuDelete
+}
+
+// Update is the guard of the single field update, see Guard.Update.
+func Update(ctx *context.Context) {
+	defaultGuard(nil, nil).Update(ctx)
+}
+
+// Install is the guard of the installer, see Guard.Install.
+func Install(inst *installer.Installer, conn db.Connection) context.Handler {
+	return defaultGuard(nil, conn).Install(inst)
+}
@@ -0,0 +1,25 @@
+package guard
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/plugins/admin/modules/installer"
+	"github.com/glvd/go-admin/plugins/admin/modules/response"
+)
+
+// Install reject the install requests unless the installer is enabled, the
+// request carries its token and the admin is not installed yet, see
+// installer.Installer.Allow.
+func (g *Guard) Install(inst *installer.Installer) context.Handler {
+	return func(ctx *context.Context) {
+		if !inst.Allow(g.conn, ctx.FormValue("__token")) {
+			if ctx.Method() == "GET" {
+				ctx.Redirect(g.config.Get().Url("/login"))
+			} else {
+				response.BadRequest(ctx, "installer is locked")
+			}
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -2,31 +2,28 @@ package guard
 
 import (
 	"github.com/glvd/go-admin/context"
-	"github.com/glvd/go-admin/modules/db"
 )
 
 type MenuDeleteParam struct {
 	Id string
 }
 
-func MenuDelete(conn db.Connection) context.Handler {
-	return func(ctx *context.Context) {
+func (g *Guard) MenuDelete(ctx *context.Context) {
 
-		id := ctx.Query("id")
+	id := ctx.Query("id")
 
-		if id == "" {
-			alertWithTitleAndDesc(ctx, "Menu", "menu", "wrong id", conn)
-			ctx.Abort()
-			return
-		}
+	if id == "" {
+		g.alertWithTitleAndDesc(ctx, "Menu", "menu", "wrong id")
+		ctx.Abort()
+		return
+	}
 
-		// TODO: check the user permission
+	// TODO: check the user permission
 
-		ctx.SetUserValue("delete_menu_param", &MenuDeleteParam{
-			Id: id,
-		})
-		ctx.Next()
-	}
+	ctx.SetUserValue("delete_menu_param", &MenuDeleteParam{
+		Id: id,
+	})
+	ctx.Next()
 }
 
 func GetMenuDeleteParam(ctx *context.Context) *MenuDeleteParam {
@@ -3,7 +3,6 @@ package guard
 import (
 	"github.com/glvd/go-admin/context"
 	"github.com/glvd/go-admin/modules/auth"
-	"github.com/glvd/go-admin/modules/service"
 	"html/template"
 	"strconv"
 )
@@ -23,52 +22,50 @@ func (e MenuEditParam) HasAlert() bool {
 	return e.Alert != template.HTML("")
 }
 
-func MenuEdit(srv service.List) context.Handler {
-	return func(ctx *context.Context) {
+func (g *Guard) MenuEdit(ctx *context.Context) {
 
-		parentId := ctx.FormValue("parent_id")
-		if parentId == "" {
-			parentId = "0"
-		}
+	parentId := ctx.FormValue("parent_id")
+	if parentId == "" {
+		parentId = "0"
+	}
 
-		var (
-			parentIdInt, _ = strconv.Atoi(parentId)
-			token          = ctx.FormValue("_t")
-			alert          template.HTML
-		)
+	var (
+		parentIdInt, _ = strconv.Atoi(parentId)
+		token          = ctx.FormValue("_t")
+		alert          template.HTML
+	)
 
-		if !auth.GetService(srv.Get("auth")).CheckToken(token) {
-			alert = getAlert("edit fail, wrong token")
-		}
+	if !auth.GetService(g.services.Get("auth")).CheckToken(token) {
+		alert = g.getAlert("edit fail, wrong token")
+	}
 
-		if alert == "" {
-			alert = checkEmpty(ctx, "id", "title", "icon")
-		}
+	if alert == "" {
+		alert = g.checkEmpty(ctx, "id", "title", "icon")
+	}
 
-		// TODO: check the user permission
+	// TODO: check the user permission
 
-		ctx.SetUserValue("edit_menu_param", &MenuEditParam{
-			Id:       ctx.FormValue("id"),
-			Title:    ctx.FormValue("title"),
-			Header:   ctx.FormValue("header"),
-			ParentId: int64(parentIdInt),
-			Icon:     ctx.FormValue("icon"),
-			Uri:      ctx.FormValue("uri"),
-			Roles:    ctx.Request.Form["roles[]"],
-			Alert:    alert,
-		})
-		ctx.Next()
-	}
+	ctx.SetUserValue("edit_menu_param", &MenuEditParam{
+		Id:       ctx.FormValue("id"),
+		Title:    ctx.FormValue("title"),
+		Header:   ctx.FormValue("header"),
+		ParentId: int64(parentIdInt),
+		Icon:     ctx.FormValue("icon"),
+		Uri:      ctx.FormValue("uri"),
+		Roles:    ctx.Request.Form["roles[]"],
+		Alert:    alert,
+	})
+	ctx.Next()
 }
 
 func GetMenuEditParam(ctx *context.Context) *MenuEditParam {
 	return ctx.UserValue["edit_menu_param"].(*MenuEditParam)
 }
 
-func checkEmpty(ctx *context.Context, key ...string) template.HTML {
+func (g *Guard) checkEmpty(ctx *context.Context, key ...string) template.HTML {
 	for _, k := range key {
 		if ctx.FormValue(k) == "" {
-			return getAlert("wrong " + k)
+			return g.getAlert("wrong " + k)
 		}
 	}
 	return template.HTML("")
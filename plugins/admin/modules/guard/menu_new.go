@@ -3,7 +3,6 @@ package guard
 import (
 	"github.com/glvd/go-admin/context"
 	"github.com/glvd/go-admin/modules/auth"
-	"github.com/glvd/go-admin/modules/service"
 	"html/template"
 	"strconv"
 )
@@ -22,41 +21,38 @@ func (e MenuNewParam) HasAlert() bool {
 	return e.Alert != template.HTML("")
 }
 
-func MenuNew(srv service.List) context.Handler {
+func (g *Guard) MenuNew(ctx *context.Context) {
 
-	return func(ctx *context.Context) {
-
-		parentId := ctx.FormValue("parent_id")
-		if parentId == "" {
-			parentId = "0"
-		}
-
-		var (
-			alert template.HTML
-			token = ctx.FormValue("_t")
-		)
-
-		if !auth.GetService(srv.Get("auth")).CheckToken(token) {
-			alert = getAlert("edit fail, wrong token")
-		}
+	parentId := ctx.FormValue("parent_id")
+	if parentId == "" {
+		parentId = "0"
+	}
 
-		if alert == "" {
-			alert = checkEmpty(ctx, "title", "icon")
-		}
+	var (
+		alert template.HTML
+		token = ctx.FormValue("_t")
+	)
 
-		parentIdInt, _ := strconv.Atoi(parentId)
+	if !auth.GetService(g.services.Get("auth")).CheckToken(token) {
+		alert = g.getAlert("edit fail, wrong token")
+	}
 
-		ctx.SetUserValue("new_menu_param", &MenuNewParam{
-			Title:    ctx.FormValue("title"),
-			Header:   ctx.FormValue("header"),
-			ParentId: int64(parentIdInt),
-			Icon:     ctx.FormValue("icon"),
-			Uri:      ctx.FormValue("uri"),
-			Roles:    ctx.Request.Form["roles[]"],
-			Alert:    alert,
-		})
-		ctx.Next()
+	if alert == "" {
+		alert = g.checkEmpty(ctx, "title", "icon")
 	}
+
+	parentIdInt, _ := strconv.Atoi(parentId)
+
+	ctx.SetUserValue("new_menu_param", &MenuNewParam{
+		Title:    ctx.FormValue("title"),
+		Header:   ctx.FormValue("header"),
+		ParentId: int64(parentIdInt),
+		Icon:     ctx.FormValue("icon"),
+		Uri:      ctx.FormValue("uri"),
+		Roles:    ctx.Request.Form["roles[]"],
+		Alert:    alert,
+	})
+	ctx.Next()
 }
 
 func GetMenuNewParam(ctx *context.Context) *MenuNewParam {
@@ -4,8 +4,6 @@ import (
 	"github.com/glvd/go-admin/context"
 	"github.com/glvd/go-admin/modules/auth"
 	"github.com/glvd/go-admin/modules/config"
-	"github.com/glvd/go-admin/modules/db"
-	"github.com/glvd/go-admin/modules/service"
 	"github.com/glvd/go-admin/plugins/admin/modules"
 	"github.com/glvd/go-admin/plugins/admin/modules/form"
 	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
@@ -19,36 +17,37 @@ type ShowNewFormParam struct {
 	Panel  table.Table
 	Prefix string
 	Param  parameter.Parameters
+
+	config config.Config
 }
 
 func (e *ShowNewFormParam) GetUrl() string {
-	return config.Get().Url("/new/" + e.Prefix)
+	return e.config.Url("/new/" + e.Prefix)
 }
 
 func (e *ShowNewFormParam) GetInfoUrl() string {
-	return config.Get().Url("/info/" + e.Prefix + e.Param.GetRouteParamStr())
+	return e.config.Url("/info/" + e.Prefix + e.Param.GetRouteParamStr())
 }
 
-func ShowNewForm(conn db.Connection) context.Handler {
-	return func(ctx *context.Context) {
-
-		prefix := ctx.Query("__prefix")
-		panel := table.Get(prefix)
+func (g *Guard) ShowNewForm(ctx *context.Context) {
 
-		if !panel.GetCanAdd() {
-			alert(ctx, panel, "operation not allow", conn)
-			ctx.Abort()
-			return
-		}
+	prefix := ctx.Query("__prefix")
+	panel := g.table(ctx, prefix)
 
-		ctx.SetUserValue("show_new_form_param", &ShowNewFormParam{
-			Panel:  panel,
-			Prefix: prefix,
-			Param: parameter.GetParam(ctx.Request.URL.Query(), panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name,
-				panel.GetInfo().GetSort()),
-		})
-		ctx.Next()
+	if !panel.GetCanAdd() {
+		g.alert(ctx, panel, "operation not allow")
+		ctx.Abort()
+		return
 	}
+
+	ctx.SetUserValue("show_new_form_param", &ShowNewFormParam{
+		Panel:  panel,
+		Prefix: prefix,
+		Param: parameter.GetParam(ctx.Request.URL.Query(), panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name,
+			panel.GetInfo().GetSort()),
+		config: g.config.Get(),
+	})
+	ctx.Next()
 }
 
 func GetShowNewFormParam(ctx *context.Context) *ShowNewFormParam {
@@ -65,6 +64,8 @@ type NewFormParam struct {
 	PreviousPath string
 	FromList     bool
 	Alert        template.HTML
+
+	config config.Config
 }
 
 func (e NewFormParam) Value() form.Values {
@@ -80,19 +81,19 @@ func (e NewFormParam) GetNewUrl() string {
 }
 
 func (e NewFormParam) GetUpdateUrl() string {
-	return config.Get().Url("/update/" + e.Prefix)
+	return e.config.Url("/update/" + e.Prefix)
 }
 
 func (e NewFormParam) GetDeleteUrl() string {
-	return config.Get().Url("/delete/" + e.Prefix)
+	return e.config.Url("/delete/" + e.Prefix)
 }
 
 func (e NewFormParam) GetExportUrl() string {
-	return config.Get().Url("/export/" + e.Prefix + e.Param.GetRouteParamStr())
+	return e.config.Url("/export/" + e.Prefix + e.Param.GetRouteParamStr())
 }
 
 func (e NewFormParam) getUrl(kind string) string {
-	return config.Get().Url("/info/" + e.Prefix + "/" + kind + e.Param.GetRouteParamStr())
+	return e.config.Url("/info/" + e.Prefix + "/" + kind + e.Param.GetRouteParamStr())
 }
 
 func (e NewFormParam) IsManage() bool {
@@ -100,15 +101,15 @@ func (e NewFormParam) IsManage() bool {
 }
 
 func (e *NewFormParam) GetUrl() string {
-	return config.Get().Url("/new/" + e.Prefix)
+	return e.config.Url("/new/" + e.Prefix)
 }
 
 func (e *NewFormParam) GetInfoUrl() string {
-	return config.Get().Url("/info/" + e.Prefix + e.Param.GetRouteParamStr())
+	return e.config.Url("/info/" + e.Prefix + e.Param.GetRouteParamStr())
 }
 
 func (e *NewFormParam) GetDetailUrl() string {
-	return config.Get().Url("/info/" + e.Prefix + "/detail" + e.Param.GetRouteParamStr())
+	return e.config.Url("/info/" + e.Prefix + "/detail" + e.Param.GetRouteParamStr())
 }
 
 func (e NewFormParam) HasAlert() bool {
@@ -119,47 +120,44 @@ func (e NewFormParam) IsRole() bool {
 	return e.Prefix == "roles"
 }
 
-func NewForm(srv service.List) context.Handler {
-	return func(ctx *context.Context) {
-		prefix := ctx.Query("__prefix")
-		previous := ctx.FormValue("_previous_")
-		panel := table.Get(prefix)
-
-		conn := db.GetConnection(srv)
-
-		if !panel.GetCanAdd() {
-			alert(ctx, panel, "operation not allow", conn)
-			ctx.Abort()
-			return
-		}
-		token := ctx.FormValue("_t")
-
-		if !auth.GetService(srv.Get("auth")).CheckToken(token) {
-			alert(ctx, panel, "edit fail, wrong token", conn)
-			ctx.Abort()
-			return
-		}
-
-		fromList := modules.IsInfoUrl(previous)
-
-		param := parameter.GetParamFromUrl(previous, fromList, panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name, panel.GetInfo().GetSort())
-
-		if fromList {
-			previous = config.Get().Url("/info/" + prefix + param.GetRouteParamStr())
-		}
-
-		ctx.SetUserValue("new_form_param", &NewFormParam{
-			Panel:        panel,
-			Id:           "",
-			Prefix:       prefix,
-			Param:        param,
-			Path:         strings.Split(previous, "?")[0],
-			MultiForm:    ctx.Request.MultipartForm,
-			PreviousPath: previous,
-			FromList:     fromList,
-		})
-		ctx.Next()
+func (g *Guard) NewForm(ctx *context.Context) {
+	prefix := ctx.Query("__prefix")
+	previous := ctx.FormValue("_previous_")
+	panel := g.table(ctx, prefix)
+
+	if !panel.GetCanAdd() {
+		g.alert(ctx, panel, "operation not allow")
+		ctx.Abort()
+		return
+	}
+	token := ctx.FormValue("_t")
+
+	if !auth.GetService(g.services.Get("auth")).CheckToken(token) {
+		g.alert(ctx, panel, "edit fail, wrong token")
+		ctx.Abort()
+		return
+	}
+
+	fromList := modules.IsInfoUrl(previous)
+
+	param := parameter.GetParamFromUrl(previous, fromList, panel.GetInfo().DefaultPageSize, panel.GetPrimaryKey().Name, panel.GetInfo().GetSort())
+
+	if fromList {
+		previous = g.config.Get().Url("/info/" + prefix + param.GetRouteParamStr())
 	}
+
+	ctx.SetUserValue("new_form_param", &NewFormParam{
+		Panel:        panel,
+		Id:           "",
+		Prefix:       prefix,
+		Param:        param,
+		Path:         strings.Split(previous, "?")[0],
+		MultiForm:    ctx.Request.MultipartForm,
+		PreviousPath: previous,
+		FromList:     fromList,
+		config:       g.config.Get(),
+	})
+	ctx.Next()
 }
 
 func GetNewFormParam(ctx *context.Context) *NewFormParam {
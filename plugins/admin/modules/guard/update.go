@@ -13,9 +13,9 @@ type UpdateParam struct {
 	Value  form.Values
 }
 
-func Update(ctx *context.Context) {
+func (g *Guard) Update(ctx *context.Context) {
 	prefix := ctx.Query("__prefix")
-	panel := table.Get(prefix)
+	panel := g.table(ctx, prefix)
 
 	pname := panel.GetPrimaryKey().Name
 
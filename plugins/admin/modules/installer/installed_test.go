@@ -0,0 +1,40 @@
+//go:build sqlite
+// +build sqlite
+
+package installer
+
+import (
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestInstalled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "installer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "admin.db")},
+	})
+
+	inst := New().SetLockPath(filepath.Join(dir, "install.lock"))
+	token := inst.Enable()
+
+	assert.False(t, Installed(conn))
+	assert.True(t, inst.Allow(conn, token))
+
+	assert.Nil(t, Migrate(conn))
+	assert.False(t, Installed(conn))
+	assert.True(t, inst.Allow(conn, token))
+
+	assert.Nil(t, CreateSuperAdmin(conn, "admin", "admin", ""))
+	assert.True(t, Installed(conn))
+	assert.False(t, inst.Allow(conn, token))
+}
@@ -0,0 +1,439 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package installer contains the steps of the first-run setup: checking the
+// database connection, creating the adm_* schema, creating the super
+// administrator, writing the config file and locking the installer.
+package installer
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/db/dialect"
+	"github.com/glvd/go-admin/plugins/admin/modules/dynamic"
+	"github.com/glvd/go-admin/plugins/admin/modules/resource"
+	"github.com/glvd/go-admin/plugins/admin/modules/views"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Installer is the installer of an admin instance. It is disabled until
+// Enable is called, and the requests must carry the token returned by
+// Enable, see Installer.Allow.
+type Installer struct {
+	configPath string
+	lockPath   string
+	token      string
+}
+
+// New return an Installer which writes the config into "./config.json"
+// and is locked by "./install.lock".
+func New() *Installer {
+	return &Installer{
+		configPath: "./config.json",
+		lockPath:   "./install.lock",
+	}
+}
+
+// SetConfigPath set the path which the config file will be written into.
+// The extension of the path decides the format, ".yml" and ".yaml" for
+// yaml and json for others.
+func (inst *Installer) SetConfigPath(path string) *Installer {
+	inst.configPath = path
+	return inst
+}
+
+// SetLockPath set the path of the lock file.
+func (inst *Installer) SetLockPath(path string) *Installer {
+	inst.lockPath = path
+	return inst
+}
+
+// ConfigPath return the path of the config file.
+func (inst *Installer) ConfigPath() string {
+	return inst.configPath
+}
+
+// Enable enable the installer with a random token and return the token.
+func (inst *Installer) Enable() string {
+	if inst.token == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			panic(err)
+		}
+		inst.token = hex.EncodeToString(b)
+	}
+	return inst.token
+}
+
+// Token return the token of the installer, which is empty when the
+// installer is not enabled.
+func (inst *Installer) Token() string {
+	return inst.token
+}
+
+// Allow check the installer can be used with the token or not. It can not
+// be used when it is not enabled, when it is locked, or when the users of
+// the admin exist in the database of the connection.
+func (inst *Installer) Allow(conn db.Connection, token string) bool {
+	if inst.token == "" || subtle.ConstantTimeCompare([]byte(inst.token), []byte(token)) != 1 {
+		return false
+	}
+	return !inst.IsLocked() && !Installed(conn)
+}
+
+// Lock create the lock file, after which the installer is disabled.
+func (inst *Installer) Lock() error {
+	return ioutil.WriteFile(inst.lockPath, []byte(time.Now().Format("2006-01-02 15:04:05")), 0644)
+}
+
+// IsLocked check the installer is locked or not.
+func (inst *Installer) IsLocked() bool {
+	_, err := os.Stat(inst.lockPath)
+	return err == nil
+}
+
+// Installed check the table adm_users of the connection has rows or not.
+func Installed(conn db.Connection) (installed bool) {
+	if conn == nil {
+		return false
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			installed = false
+		}
+	}()
+
+	user, err := first(db.WithDriver(conn).Table("adm_users").Select("id"))
+	return err == nil && user != nil
+}
+
+// Drivers are the supported drivers of the installer.
+var Drivers = []string{db.DriverMysql, db.DriverPostgresql, db.DriverSqlite, db.DriverMssql}
+
+// IsSupported check the driver is supported or not.
+func IsSupported(driver string) bool {
+	for _, d := range Drivers {
+		if d == driver {
+			return true
+		}
+	}
+	return false
+}
+
+// Connect open a connection of given database config and ping it.
+func Connect(cfg config.Database) (conn db.Connection, err error) {
+	if !IsSupported(cfg.Driver) {
+		return nil, errors.New("driver not supported")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+			conn = nil
+		}
+	}()
+
+	conn = db.GetConnectionByDriver(cfg.Driver).InitDB(map[string]config.Database{
+		"default": cfg,
+	})
+
+	if err = conn.GetDB("default").Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// Tables return the table names of the database.
+func Tables(conn db.Connection) (tables []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	list, err := db.WithDriver(conn).ShowTables()
+	if err != nil {
+		return nil, err
+	}
+
+	tables = make([]string, 0, len(list))
+	for _, item := range list {
+		tables = append(tables, tableName(conn.Name(), item))
+	}
+
+	return tables, nil
+}
+
+func tableName(driver string, item map[string]interface{}) string {
+	switch driver {
+	case db.DriverMssql:
+		name, _ := item["TABLE_NAME"].(string)
+		return name
+	case db.DriverMysql:
+		for key, value := range item {
+			if strings.HasPrefix(key, "Tables_in_") {
+				name, _ := value.(string)
+				return name
+			}
+		}
+	default:
+		name, _ := item["tablename"].(string)
+		return name
+	}
+	return ""
+}
+
+// Migrate create the adm_* tables which not exist and seed the default
+// roles, permissions and menus when the roles table is empty.
+func Migrate(conn db.Connection) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	for _, statement := range schemas[conn.Name()] {
+		if _, err = conn.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	if err = resource.Migrate(conn); err != nil {
+		return err
+	}
+
+	if err = dynamic.Migrate(conn); err != nil {
+		return err
+	}
+
+	if err = views.Migrate(conn); err != nil {
+		return err
+	}
+
+	role, err := first(db.WithDriver(conn).Table("adm_roles"))
+	if err != nil {
+		return err
+	}
+	if role != nil {
+		return nil
+	}
+
+	return seed(conn)
+}
+
+func seed(conn db.Connection) error {
+	adminRole, err := insert(conn, "adm_roles", "slug", dialect.H{
+		"name": "Administrator",
+		"slug": "administrator",
+	})
+	if err != nil {
+		return err
+	}
+
+	operatorRole, err := insert(conn, "adm_roles", "slug", dialect.H{
+		"name": "Operator",
+		"slug": "operator",
+	})
+	if err != nil {
+		return err
+	}
+
+	allPermission, err := insert(conn, "adm_permissions", "slug", dialect.H{
+		"name":        "All permission",
+		"slug":        "*",
+		"http_method": "",
+		"http_path":   "*",
+	})
+	if err != nil {
+		return err
+	}
+
+	dashboardPermission, err := insert(conn, "adm_permissions", "slug", dialect.H{
+		"name":        "Dashboard",
+		"slug":        "dashboard",
+		"http_method": "GET,PUT,POST,DELETE",
+		"http_path":   "/",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range [][2]int64{
+		{adminRole, allPermission},
+		{adminRole, dashboardPermission},
+		{operatorRole, dashboardPermission},
+	} {
+		if err = link(conn, "adm_role_permissions", "role_id", "permission_id", item[0], item[1]); err != nil {
+			return err
+		}
+	}
+
+	menuIds := make([]int64, len(seedMenus))
+	for i, menu := range seedMenus {
+		var parentId int64
+		if menu.Parent > 0 {
+			parentId = menuIds[menu.Parent-1]
+		}
+		menuIds[i], err = insert(conn, "adm_menu", "title", dialect.H{
+			"parent_id": parentId,
+			"type":      1,
+			"order":     menu.Order,
+			"title":     menu.Title,
+			"icon":      menu.Icon,
+			"uri":       menu.Uri,
+		})
+		if err != nil {
+			return err
+		}
+		if menu.Parent == 0 {
+			if err = link(conn, "adm_role_menu", "role_id", "menu_id", adminRole, menuIds[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// insert insert the values into the table and return the id of the new row.
+// The mssql driver does not support LastInsertId, so the row is looked up
+// again by the given unique field.
+func insert(conn db.Connection, table, field string, values dialect.H) (int64, error) {
+	id, err := db.WithDriver(conn).Table(table).Insert(values)
+	if conn.Name() != db.DriverMssql {
+		return id, err
+	}
+
+	item, queryErr := first(db.WithDriver(conn).Table(table).Where(field, "=", values[field]))
+	if queryErr != nil {
+		return 0, queryErr
+	}
+	if item == nil {
+		return 0, err
+	}
+	id, _ = item["id"].(int64)
+	return id, nil
+}
+
+// first return the first row of the query, or nil when there is no row.
+func first(sql *db.SQL) (map[string]interface{}, error) {
+	item, err := sql.First()
+	if err != nil && err.Error() == "out of index" {
+		return nil, nil
+	}
+	return item, err
+}
+
+// link insert a row into the relation table. The statement is executed
+// directly because the relation tables have no id to return.
+func link(conn db.Connection, table, keyA, keyB string, valueA, valueB interface{}) error {
+	_, err := conn.Exec("INSERT INTO "+table+" ("+keyA+", "+keyB+") VALUES (?, ?)", valueA, valueB)
+	return err
+}
+
+// CreateSuperAdmin create a user which owns the administrator role and the
+// all permission.
+func CreateSuperAdmin(conn db.Connection, username, password, name string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	if username == "" || password == "" {
+		return errors.New("username and password can not be empty")
+	}
+
+	if name == "" {
+		name = username
+	}
+
+	exist, err := first(db.WithDriver(conn).Table("adm_users").Where("username", "=", username))
+	if err != nil {
+		return err
+	}
+	if exist != nil {
+		return errors.New("user already exists")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	userId, err := insert(conn, "adm_users", "username", dialect.H{
+		"username": username,
+		"password": string(hash),
+		"name":     name,
+		"avatar":   "",
+	})
+	if err != nil {
+		return err
+	}
+
+	role, err := first(db.WithDriver(conn).Table("adm_roles").Where("slug", "=", "administrator"))
+	if err != nil {
+		return err
+	}
+	if role != nil {
+		if err = link(conn, "adm_role_users", "role_id", "user_id", role["id"], userId); err != nil {
+			return err
+		}
+	}
+
+	permission, err := first(db.WithDriver(conn).Table("adm_permissions").Where("slug", "=", "*"))
+	if err != nil {
+		return err
+	}
+	if permission != nil {
+		if err = link(conn, "adm_user_permissions", "user_id", "permission_id", userId, permission["id"]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteConfig write the config into the given path, the file can be read
+// by config.ReadFromJson or config.ReadFromYaml according to the extension.
+func WriteConfig(path string, cfg config.Config) error {
+	var (
+		content []byte
+		err     error
+	)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		content, err = yaml.Marshal(cfg)
+	default:
+		content, err = json.MarshalIndent(cfg, "", "    ")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return ioutil.WriteFile(path, content, 0644)
+}
@@ -0,0 +1,76 @@
+package installer
+
+import (
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "installer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.Config{
+		Databases: config.DatabaseList{
+			"default": {
+				Host:       "127.0.0.1",
+				Port:       "3306",
+				User:       "root",
+				Pwd:        "root",
+				Name:       "godmin",
+				MaxIdleCon: 5,
+				MaxOpenCon: 10,
+				Driver:     "mysql",
+			},
+		},
+		UrlPrefix: "admin",
+		Language:  "en",
+		IndexUrl:  "/",
+	}
+
+	jsonPath := filepath.Join(dir, "config.json")
+	assert.Nil(t, WriteConfig(jsonPath, cfg))
+	assert.Equal(t, cfg.Databases, config.ReadFromJson(jsonPath).Databases)
+	assert.Equal(t, cfg.UrlPrefix, config.ReadFromJson(jsonPath).UrlPrefix)
+
+	yamlPath := filepath.Join(dir, "config.yml")
+	assert.Nil(t, WriteConfig(yamlPath, cfg))
+	assert.Equal(t, cfg.Databases, config.ReadFromYaml(yamlPath).Databases)
+	assert.Equal(t, cfg.UrlPrefix, config.ReadFromYaml(yamlPath).UrlPrefix)
+}
+
+func TestInstaller(t *testing.T) {
+	dir, err := ioutil.TempDir("", "installer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inst := New().SetLockPath(filepath.Join(dir, "install.lock"))
+
+	assert.Equal(t, "", inst.Token())
+	assert.False(t, inst.Allow(nil, ""))
+
+	token := inst.Enable()
+	assert.NotEqual(t, "", token)
+	assert.Equal(t, token, inst.Enable())
+	assert.NotEqual(t, token, New().Enable())
+
+	assert.False(t, inst.Allow(nil, ""))
+	assert.False(t, inst.Allow(nil, "wrong"))
+	assert.True(t, inst.Allow(nil, token))
+
+	assert.False(t, inst.IsLocked())
+	assert.Nil(t, inst.Lock())
+	assert.True(t, inst.IsLocked())
+	assert.False(t, inst.Allow(nil, token))
+}
+
+func TestTableName(t *testing.T) {
+	assert.Equal(t, "adm_users", tableName("mysql", map[string]interface{}{"Tables_in_godmin": "adm_users"}))
+	assert.Equal(t, "adm_users", tableName("postgresql", map[string]interface{}{"tablename": "adm_users"}))
+	assert.Equal(t, "adm_users", tableName("sqlite", map[string]interface{}{"tablename": "adm_users"}))
+	assert.Equal(t, "adm_users", tableName("mssql", map[string]interface{}{"TABLE_NAME": "adm_users"}))
+}
@@ -0,0 +1,364 @@
+package installer
+
+import "github.com/glvd/go-admin/modules/db"
+
+// schemas contains the statements which create the adm_* tables of each driver.
+var schemas = map[string][]string{
+	db.DriverMysql: {
+		"CREATE TABLE IF NOT EXISTS `adm_menu` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`parent_id` int(11) unsigned NOT NULL DEFAULT '0'," +
+			"`type` tinyint(4) unsigned NOT NULL DEFAULT '0'," +
+			"`order` int(11) unsigned NOT NULL DEFAULT '0'," +
+			"`title` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`icon` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`uri` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT ''," +
+			"`header` varchar(150) COLLATE utf8mb4_unicode_ci DEFAULT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_operation_log` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`user_id` int(11) unsigned NOT NULL," +
+			"`path` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`method` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`ip` varchar(15) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`input` text COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)," +
+			"KEY `admin_operation_log_user_id_index` (`user_id`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_permissions` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`name` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`slug` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`http_method` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL," +
+			"`http_path` text COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)," +
+			"UNIQUE KEY `admin_permissions_name_unique` (`name`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_role_menu` (" +
+			"`role_id` int(11) unsigned NOT NULL," +
+			"`menu_id` int(11) unsigned NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"KEY `admin_role_menu_role_id_menu_id_index` (`role_id`,`menu_id`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_role_permissions` (" +
+			"`role_id` int(11) unsigned NOT NULL," +
+			"`permission_id` int(11) unsigned NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"UNIQUE KEY `admin_role_permissions` (`role_id`,`permission_id`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_role_users` (" +
+			"`role_id` int(11) unsigned NOT NULL," +
+			"`user_id` int(11) unsigned NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"UNIQUE KEY `admin_user_roles` (`role_id`,`user_id`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_roles` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`name` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`slug` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)," +
+			"UNIQUE KEY `admin_roles_name_unique` (`name`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_session` (" +
+			"`id` int(11) unsigned NOT NULL AUTO_INCREMENT," +
+			"`sid` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT ''," +
+			"`values` varchar(3000) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT ''," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		"CREATE TABLE IF NOT EXISTS `adm_user_permissions` (" +
+			"`user_id` int(11) unsigned NOT NULL," +
+			"`permission_id` int(11) unsigned NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"UNIQUE KEY `admin_user_permissions` (`user_id`,`permission_id`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_users` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`username` varchar(190) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`password` varchar(80) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT ''," +
+			"`name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`avatar` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL," +
+			"`remember_token` varchar(100) COLLATE utf8mb4_unicode_ci DEFAULT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)," +
+			"UNIQUE KEY `admin_users_username_unique` (`username`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+	},
+	db.DriverPostgresql: {
+		`CREATE TABLE IF NOT EXISTS adm_menu (
+    id serial PRIMARY KEY,
+    parent_id integer DEFAULT 0 NOT NULL,
+    type integer DEFAULT 0,
+    "order" integer DEFAULT 0 NOT NULL,
+    title character varying(50) NOT NULL,
+    header character varying(150),
+    icon character varying(50) NOT NULL,
+    uri character varying(50) DEFAULT '' NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_operation_log (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL,
+    path character varying(255) NOT NULL,
+    method character varying(10) NOT NULL,
+    ip character varying(15) NOT NULL,
+    input text NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_permissions (
+    id serial PRIMARY KEY,
+    name character varying(50) NOT NULL UNIQUE,
+    slug character varying(50) NOT NULL,
+    http_method character varying(255),
+    http_path text NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_role_menu (
+    role_id integer NOT NULL,
+    menu_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_role_permissions (
+    role_id integer NOT NULL,
+    permission_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now(),
+    UNIQUE (role_id, permission_id)
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_role_users (
+    role_id integer NOT NULL,
+    user_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now(),
+    UNIQUE (role_id, user_id)
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_roles (
+    id serial PRIMARY KEY,
+    name character varying(50) NOT NULL UNIQUE,
+    slug character varying(50) NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_session (
+    id serial PRIMARY KEY,
+    sid character varying(50) DEFAULT '' NOT NULL,
+    "values" character varying(3000) DEFAULT '' NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_user_permissions (
+    user_id integer NOT NULL,
+    permission_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now(),
+    UNIQUE (user_id, permission_id)
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_users (
+    id serial PRIMARY KEY,
+    username character varying(190) NOT NULL UNIQUE,
+    password character varying(80) DEFAULT '' NOT NULL,
+    name character varying(255) NOT NULL,
+    avatar character varying(255),
+    remember_token character varying(100),
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+	},
+	db.DriverSqlite: {
+		"CREATE TABLE IF NOT EXISTS `adm_menu` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`parent_id` integer NOT NULL DEFAULT 0," +
+			"`type` integer NOT NULL DEFAULT 0," +
+			"`order` integer NOT NULL DEFAULT 0," +
+			"`title` varchar(50) NOT NULL," +
+			"`icon` varchar(50) NOT NULL," +
+			"`uri` varchar(50) NOT NULL DEFAULT ''," +
+			"`header` varchar(150) DEFAULT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+		"CREATE TABLE IF NOT EXISTS `adm_operation_log` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`user_id` integer NOT NULL," +
+			"`path` varchar(255) NOT NULL," +
+			"`method` varchar(10) NOT NULL," +
+			"`ip` varchar(15) NOT NULL," +
+			"`input` text NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+		"CREATE TABLE IF NOT EXISTS `adm_permissions` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`name` varchar(50) NOT NULL UNIQUE," +
+			"`slug` varchar(50) NOT NULL," +
+			"`http_method` varchar(255) DEFAULT NULL," +
+			"`http_path` text NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+		"CREATE TABLE IF NOT EXISTS `adm_role_menu` (" +
+			"`role_id` integer NOT NULL," +
+			"`menu_id` integer NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+		"CREATE TABLE IF NOT EXISTS `adm_role_permissions` (" +
+			"`role_id` integer NOT NULL," +
+			"`permission_id` integer NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"UNIQUE (`role_id`, `permission_id`))",
+		"CREATE TABLE IF NOT EXISTS `adm_role_users` (" +
+			"`role_id` integer NOT NULL," +
+			"`user_id` integer NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"UNIQUE (`role_id`, `user_id`))",
+		"CREATE TABLE IF NOT EXISTS `adm_roles` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`name` varchar(50) NOT NULL UNIQUE," +
+			"`slug` varchar(50) NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+		"CREATE TABLE IF NOT EXISTS `adm_session` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`sid` varchar(50) NOT NULL DEFAULT ''," +
+			"`values` varchar(3000) NOT NULL DEFAULT ''," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+		"CREATE TABLE IF NOT EXISTS `adm_user_permissions` (" +
+			"`user_id` integer NOT NULL," +
+			"`permission_id` integer NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"UNIQUE (`user_id`, `permission_id`))",
+		"CREATE TABLE IF NOT EXISTS `adm_users` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`username` varchar(190) NOT NULL UNIQUE," +
+			"`password` varchar(80) NOT NULL DEFAULT ''," +
+			"`name` varchar(255) NOT NULL," +
+			"`avatar` varchar(255) DEFAULT NULL," +
+			"`remember_token` varchar(100) DEFAULT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+	},
+	db.DriverMssql: {
+		`IF OBJECT_ID('adm_menu', 'U') IS NULL CREATE TABLE adm_menu (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    parent_id int NOT NULL DEFAULT 0,
+    type int NOT NULL DEFAULT 0,
+    [order] int NOT NULL DEFAULT 0,
+    title nvarchar(50) NOT NULL,
+    icon nvarchar(50) NOT NULL,
+    uri nvarchar(50) NOT NULL DEFAULT '',
+    header nvarchar(150) NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+		`IF OBJECT_ID('adm_operation_log', 'U') IS NULL CREATE TABLE adm_operation_log (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    user_id int NOT NULL,
+    path nvarchar(255) NOT NULL,
+    method nvarchar(10) NOT NULL,
+    ip nvarchar(15) NOT NULL,
+    input nvarchar(max) NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+		`IF OBJECT_ID('adm_permissions', 'U') IS NULL CREATE TABLE adm_permissions (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    name nvarchar(50) NOT NULL UNIQUE,
+    slug nvarchar(50) NOT NULL,
+    http_method nvarchar(255) NULL,
+    http_path nvarchar(max) NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+		`IF OBJECT_ID('adm_role_menu', 'U') IS NULL CREATE TABLE adm_role_menu (
+    role_id int NOT NULL,
+    menu_id int NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+		`IF OBJECT_ID('adm_role_permissions', 'U') IS NULL CREATE TABLE adm_role_permissions (
+    role_id int NOT NULL,
+    permission_id int NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (role_id, permission_id)
+)`,
+		`IF OBJECT_ID('adm_role_users', 'U') IS NULL CREATE TABLE adm_role_users (
+    role_id int NOT NULL,
+    user_id int NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (role_id, user_id)
+)`,
+		`IF OBJECT_ID('adm_roles', 'U') IS NULL CREATE TABLE adm_roles (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    name nvarchar(50) NOT NULL UNIQUE,
+    slug nvarchar(50) NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+		`IF OBJECT_ID('adm_session', 'U') IS NULL CREATE TABLE adm_session (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    sid nvarchar(50) NOT NULL DEFAULT '',
+    [values] nvarchar(3000) NOT NULL DEFAULT '',
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+		`IF OBJECT_ID('adm_user_permissions', 'U') IS NULL CREATE TABLE adm_user_permissions (
+    user_id int NOT NULL,
+    permission_id int NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, permission_id)
+)`,
+		`IF OBJECT_ID('adm_users', 'U') IS NULL CREATE TABLE adm_users (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    username nvarchar(190) NOT NULL UNIQUE,
+    password nvarchar(80) NOT NULL DEFAULT '',
+    name nvarchar(255) NOT NULL,
+    avatar nvarchar(255) NULL,
+    remember_token nvarchar(100) NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+	},
+}
+
+// seedMenus are the default menu items, listed in the order of insertion.
+// The parent of an item is referenced by its position in the list, start
+// with 1, and zero means a top level item.
+var seedMenus = []struct {
+	Parent int
+	Order  int
+	Title  string
+	Icon   string
+	Uri    string
+}{
+	{0, 2, "Admin", "fa-tasks", ""},
+	{1, 2, "Users", "fa-users", "/info/manager"},
+	{1, 3, "Roles", "fa-user", "/info/roles"},
+	{1, 4, "Permission", "fa-ban", "/info/permission"},
+	{1, 5, "Menu", "fa-bars", "/menu"},
+	{1, 6, "Operation log", "fa-history", "/info/op"},
+	{1, 7, "Dynamic tables", "fa-table", "/dynamic"},
+	{0, 1, "Dashboard", "fa-bar-chart", "/"},
+}
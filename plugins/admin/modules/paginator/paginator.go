@@ -188,3 +188,52 @@ func Get(path string, params parameter.Parameters, size int, pageSizeList []stri
 
 	return paginator.SetPageSizeList(pageSizeList)
 }
+
+// GetWithoutPages return the paginator which navigates to the previous and
+// the next pages only, used when the total count is unknown or the pages
+// are navigated by the cursors. The previous and the next are the route
+// parameters of the pages, the empty one disables the navigation. The total
+// is displayed as it is.
+func GetWithoutPages(path string, params parameter.Parameters, total string, pageSizeList []string,
+	previous, next string) types.PaginatorAttribute {
+
+	paginator := template2.Default().Paginator().(*components.PaginatorAttribute)
+
+	pageInt, _ := strconv.Atoi(params.Page)
+	pageSizeInt, _ := strconv.Atoi(params.PageSize)
+
+	paginator.PreviousClass = "disabled"
+	paginator.PreviousUrl = path
+	if previous != "" {
+		paginator.PreviousClass = ""
+		paginator.PreviousUrl = path + previous
+	}
+
+	paginator.NextClass = "disabled"
+	paginator.NextUrl = path
+	if next != "" {
+		paginator.NextClass = ""
+		paginator.NextUrl = path + next
+	}
+
+	paginator.Url = path + params.GetRouteParamStrWithoutPageSize()
+	paginator.CurPageEndIndex = strconv.Itoa((pageInt) * pageSizeInt)
+	paginator.CurPageStartIndex = strconv.Itoa((pageInt - 1) * pageSizeInt)
+	paginator.Total = total
+
+	paginator.Option = make(map[string]template.HTML, len(pageSizeList))
+	for i := 0; i < len(pageSizeList); i++ {
+		paginator.Option[pageSizeList[i]] = template.HTML("")
+	}
+
+	paginator.Option[params.PageSize] = template.HTML("selected")
+
+	paginator.Pages = []map[string]string{{
+		"page":    params.Page,
+		"active":  "active",
+		"isSplit": "0",
+		"url":     path + params.GetRouteParamStr(),
+	}}
+
+	return paginator.SetPageSizeList(pageSizeList)
+}
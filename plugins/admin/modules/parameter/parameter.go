@@ -15,9 +15,24 @@ type Parameters struct {
 	Columns   []string
 	SortType  string
 	Fields    map[string]string
+
+	// Search is the keywords of the search box of the list.
+	Search string
+
+	// Filter is the serialized types.FilterGroup of the advanced filter.
+	Filter string
+
+	// After and Before are the cursors of the keyset pagination, the page
+	// is after the row of After or before the row of Before.
+	After  string
+	Before string
+
+	// ReadPrimary force the query to read from the primary connection,
+	// which is used to read the writes of the request just made.
+	ReadPrimary bool
 }
 
-var keys = []string{"__page", "__pageSize", "__sort", "__columns", "__prefix", "_pjax"}
+var keys = []string{"__page", "__pageSize", "__sort", "__columns", "__prefix", "_pjax", "__search", "__filter", "__view", "__after", "__before"}
 
 const operatorSuffix = "__operator__"
 
@@ -27,6 +42,10 @@ func GetParam(values url.Values, defaultPageSize int, primaryKey, defaultSort st
 	sortField := getDefault(values, "__sort", primaryKey)
 	sortType := getDefault(values, "__sort_type", defaultSort)
 	columns := getDefault(values, "__columns", "")
+	search := strings.TrimSpace(values.Get("__search"))
+	filter := values.Get("__filter")
+	after := values.Get("__after")
+	before := values.Get("__before")
 
 	fields := make(map[string]string)
 
@@ -58,6 +77,10 @@ func GetParam(values url.Values, defaultPageSize int, primaryKey, defaultSort st
 		SortType:  sortType,
 		Fields:    fields,
 		Columns:   columnsArr,
+		Search:    search,
+		Filter:    filter,
+		After:     after,
+		Before:    before,
 	}
 }
 
@@ -87,6 +110,10 @@ func GetParamFromUrl(value string, fromList bool, defaultPageSize int, primaryKe
 		sortField = primaryKey
 		sortType  = defaultSort
 		columns   = make([]string, 0)
+		search    = ""
+		filter    = ""
+		after     = ""
+		before    = ""
 	)
 
 	for i := 0; i < len(paramArr); i++ {
@@ -102,6 +129,14 @@ func GetParamFromUrl(value string, fromList bool, defaultPageSize int, primaryKe
 			sortType = arr[1]
 		case "__columns":
 			columns = strings.Split(arr[1], ",")
+		case "__search":
+			search, _ = url.QueryUnescape(arr[1])
+		case "__filter":
+			filter, _ = url.QueryUnescape(arr[1])
+		case "__after":
+			after = arr[1]
+		case "__before":
+			before = arr[1]
 		}
 	}
 
@@ -111,14 +146,30 @@ func GetParamFromUrl(value string, fromList bool, defaultPageSize int, primaryKe
 		SortField: sortField,
 		SortType:  sortType,
 		Columns:   columns,
+		Search:    search,
+		Filter:    filter,
+		After:     after,
+		Before:    before,
 	}
 }
 
+func (param Parameters) SetReadPrimary(primary bool) Parameters {
+	param.ReadPrimary = primary
+	return param
+}
+
 func (param Parameters) SetPage(page string) Parameters {
 	param.Page = page
 	return param
 }
 
+// SetCursor set the cursors of the keyset pagination.
+func (param Parameters) SetCursor(after, before string) Parameters {
+	param.After = after
+	param.Before = before
+	return param
+}
+
 func (param Parameters) GetRouteParamStr() string {
 	return "?__page=" + param.Page + param.GetFixedParamStr()
 }
@@ -142,6 +193,18 @@ func (param Parameters) GetFixedParamStrWithoutPageSize() string {
 	for key, value := range param.Fields {
 		str += key + "=" + value + "&"
 	}
+	if param.Search != "" {
+		str += "__search=" + url.QueryEscape(param.Search) + "&"
+	}
+	if param.Filter != "" {
+		str += "__filter=" + url.QueryEscape(param.Filter) + "&"
+	}
+	if param.After != "" {
+		str += "__after=" + url.QueryEscape(param.After) + "&"
+	}
+	if param.Before != "" {
+		str += "__before=" + url.QueryEscape(param.Before) + "&"
+	}
 	if len(param.Columns) > 0 {
 		return "&__columns=" + strings.Join(param.Columns, ",") + "&__sort=" + param.SortField +
 			"&__sort_type=" + param.SortType + str[:len(str)-1]
@@ -157,6 +220,18 @@ func (param Parameters) GetFixedParamStr() string {
 			str += key + "=" + value + "&"
 		}
 	}
+	if param.Search != "" {
+		str += "__search=" + url.QueryEscape(param.Search) + "&"
+	}
+	if param.Filter != "" {
+		str += "__filter=" + url.QueryEscape(param.Filter) + "&"
+	}
+	if param.After != "" {
+		str += "__after=" + url.QueryEscape(param.After) + "&"
+	}
+	if param.Before != "" {
+		str += "__before=" + url.QueryEscape(param.Before) + "&"
+	}
 	if len(param.Columns) > 0 {
 		return "&__columns=" + strings.Join(param.Columns, ",") + "&__pageSize=" + param.PageSize + "&__sort=" +
 			param.SortField + "&__sort_type=" + param.SortType + str[:len(str)-1]
@@ -166,6 +241,29 @@ func (param Parameters) GetFixedParamStr() string {
 	}
 }
 
+// Values return the parameters of the list except the page.
+func (param Parameters) Values() url.Values {
+	values := make(url.Values)
+	for key, value := range param.Fields {
+		if key != "__goadmin_edit_pk" && key != "__goadmin_detail_pk" {
+			values.Set(key, value)
+		}
+	}
+	if len(param.Columns) > 0 {
+		values.Set("__columns", strings.Join(param.Columns, ","))
+	}
+	if param.Search != "" {
+		values.Set("__search", param.Search)
+	}
+	if param.Filter != "" {
+		values.Set("__filter", param.Filter)
+	}
+	values.Set("__pageSize", param.PageSize)
+	values.Set("__sort", param.SortField)
+	values.Set("__sort_type", param.SortType)
+	return values
+}
+
 func getDefault(values url.Values, key, def string) string {
 	value := values.Get(key)
 	if value == "" {
@@ -0,0 +1,454 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package resource syncs the menu items and the permissions declared by
+// the plugins into the admin tables. The synced rows are recorded in the
+// table adm_plugin_resources by the plugin name, so that they are updated
+// instead of duplicated on the next start, and removed when the declaration
+// is removed or the plugin is removed by Remove or Prune. Only the rows
+// inserted by the package are managed: a declared menu item or permission
+// colliding with an existing row not recorded is skipped. The enabled
+// states of the plugins are saved in the table adm_plugins.
+package resource
+
+import (
+	"errors"
+	"github.com/glvd/go-admin/modules/cache"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/db/dialect"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/plugins"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The kinds of the resources.
+const (
+	KindMenu       = "menu"
+	KindPermission = "permission"
+)
+
+const table = "adm_plugin_resources"
+
+// Migrate create the tables adm_plugin_resources and adm_plugins if not
+// exist.
+func Migrate(conn db.Connection) error {
+	statements, ok := schemas[conn.Name()]
+	if !ok {
+		return errors.New("resource: unsupported driver " + conn.Name())
+	}
+	for _, statement := range statements {
+		if _, err := conn.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// States return the saved states of the plugins, true means enabled. The
+// plugins not saved are enabled.
+func States(conn db.Connection) (map[string]bool, error) {
+	items, err := db.WithDriver(conn).Table("adm_plugins").All()
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]bool, len(items))
+	for _, item := range items {
+		name, _ := item["name"].(string)
+		enabled, _ := item["enabled"].(int64)
+		states[name] = enabled != 0
+	}
+	return states, nil
+}
+
+// SaveState save the state of the plugin.
+func SaveState(conn db.Connection, name string, enabled bool) error {
+	value := 0
+	if enabled {
+		value = 1
+	}
+	item, err := first(db.WithDriver(conn).Table("adm_plugins").Where("name", "=", name))
+	if err != nil {
+		return err
+	}
+	s := &syncer{conn: conn}
+	if item != nil {
+		return s.exec([]string{"adm_plugins"}, "UPDATE adm_plugins SET enabled = ?, updated_at = ? WHERE name = ?",
+			value, now(), name)
+	}
+	return s.exec([]string{"adm_plugins"}, "INSERT INTO adm_plugins (name, enabled) VALUES (?, ?)", name, value)
+}
+
+// Sync sync the menu items and the permissions of the plugins, see
+// plugins.MenuProvider and plugins.PermissionProvider. The resources of the
+// other plugins are kept, since the database may be shared by the engines
+// with different plugins, see Prune.
+func Sync(conn db.Connection, plugs []plugins.Plugin) error {
+	if err := Migrate(conn); err != nil {
+		return err
+	}
+
+	s := &syncer{conn: conn}
+	if err := s.loadAdminRole(); err != nil {
+		return err
+	}
+
+	for _, plug := range plugs {
+		name := plugins.Name(plug)
+		if name == "" {
+			continue
+		}
+
+		var (
+			menus []plugins.Menu
+			perms []plugins.Permission
+		)
+		if provider, ok := plug.(plugins.MenuProvider); ok {
+			menus = provider.Menus()
+		}
+		if provider, ok := plug.(plugins.PermissionProvider); ok {
+			perms = provider.Permissions()
+		}
+		if err := s.syncPlugin(name, menus, perms); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Prune remove the resources of the plugins which are not in plugs. It
+// should only be called when no other engines with other plugins share
+// the database, see engine.Engine.PruneResources.
+func Prune(conn db.Connection, plugs []plugins.Plugin) error {
+	names := make([]interface{}, 0, len(plugs))
+	for _, plug := range plugs {
+		if name := plugins.Name(plug); name != "" {
+			names = append(names, name)
+		}
+	}
+	return (&syncer{conn: conn}).prune(names)
+}
+
+// Remove remove all the resources of the plugin.
+func Remove(conn db.Connection, name string) error {
+	return (&syncer{conn: conn}).syncPlugin(name, nil, nil)
+}
+
+type record struct {
+	kind string
+	key  string
+	id   int64
+}
+
+// errSkipped is returned when the declared resource collides with an
+// existing row not inserted by the package.
+var errSkipped = errors.New("resource: skipped")
+
+type syncer struct {
+	conn      db.Connection
+	adminRole int64
+}
+
+func (s *syncer) loadAdminRole() error {
+	role, err := first(db.WithDriver(s.conn).Table("adm_roles").Where("slug", "=", "administrator"))
+	if err != nil {
+		return err
+	}
+	if role != nil {
+		s.adminRole, _ = role["id"].(int64)
+	}
+	return nil
+}
+
+func (s *syncer) records(name string) (map[string]record, error) {
+	items, err := db.WithDriver(s.conn).Table(table).Where("plugin", "=", name).All()
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]record, len(items))
+	for _, item := range items {
+		r := record{}
+		r.kind, _ = item["kind"].(string)
+		r.key, _ = item["resource_key"].(string)
+		r.id, _ = item["resource_id"].(int64)
+		records[r.kind+"\x00"+r.key] = r
+	}
+	return records, nil
+}
+
+func (s *syncer) syncPlugin(name string, menus []plugins.Menu, perms []plugins.Permission) error {
+	records, err := s.records(name)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+
+	var syncMenus func(menus []plugins.Menu, parentId int64) error
+	syncMenus = func(menus []plugins.Menu, parentId int64) error {
+		for _, menu := range menus {
+			key := KindMenu + "\x00" + menu.GetKey()
+			if seen[key] {
+				return errors.New("resource: menu " + menu.GetKey() + " of plugin " + name + " declared twice")
+			}
+			seen[key] = true
+			id, err := s.syncMenu(name, menu, parentId, records[key])
+			if err == errSkipped {
+				// The children of the skipped menu item are skipped too,
+				// and their records are kept.
+				markSeen(seen, menu.Children)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := syncMenus(menu.Children, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := syncMenus(menus, 0); err != nil {
+		return err
+	}
+
+	for _, perm := range perms {
+		key := KindPermission + "\x00" + perm.Slug
+		if seen[key] {
+			return errors.New("resource: permission " + perm.Slug + " of plugin " + name + " declared twice")
+		}
+		seen[key] = true
+		if err := s.syncPermission(name, perm, records[key]); err != nil && err != errSkipped {
+			return err
+		}
+	}
+
+	for key, r := range records {
+		if seen[key] {
+			continue
+		}
+		if err := s.remove(name, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *syncer) syncMenu(name string, menu plugins.Menu, parentId int64, r record) (int64, error) {
+	var (
+		item map[string]interface{}
+		err  error
+	)
+	if r.id != 0 {
+		item, err = first(db.WithDriver(s.conn).Table("adm_menu").Where("id", "=", r.id))
+		if err != nil {
+			return 0, err
+		}
+	}
+	if item == nil && menu.Uri != "" {
+		other, err := first(db.WithDriver(s.conn).Table("adm_menu").Where("uri", "=", menu.Uri))
+		if err != nil {
+			return 0, err
+		}
+		if other != nil {
+			logger.Warn("resource: menu ", menu.Uri, " of plugin ", name,
+				" collides with an existing menu item, skipped")
+			return 0, errSkipped
+		}
+	}
+
+	if item != nil {
+		id, _ := item["id"].(int64)
+		if err := s.exec([]string{"adm_menu"},
+			"UPDATE adm_menu SET title = ?, icon = ?, uri = ?, header = ?, updated_at = ? WHERE id = ?",
+			menu.Title, menu.Icon, menu.Uri, menu.Header, now(), id); err != nil {
+			return 0, err
+		}
+		return id, s.record(name, KindMenu, menu.GetKey(), id, r)
+	}
+
+	id, err := insert(s.conn, "adm_menu", dialect.H{
+		"parent_id": parentId,
+		"type":      1,
+		"order":     menu.Order,
+		"title":     menu.Title,
+		"icon":      menu.Icon,
+		"uri":       menu.Uri,
+		"header":    menu.Header,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if parentId == 0 && s.adminRole != 0 {
+		if err := s.exec([]string{"adm_role_menu"},
+			"INSERT INTO adm_role_menu (role_id, menu_id) VALUES (?, ?)", s.adminRole, id); err != nil {
+			return 0, err
+		}
+	}
+	return id, s.record(name, KindMenu, menu.GetKey(), id, r)
+}
+
+func (s *syncer) syncPermission(name string, perm plugins.Permission, r record) error {
+	var (
+		item map[string]interface{}
+		err  error
+	)
+	if r.id != 0 {
+		item, err = first(db.WithDriver(s.conn).Table("adm_permissions").Where("id", "=", r.id))
+		if err != nil {
+			return err
+		}
+	}
+	if item == nil {
+		other, err := first(db.WithDriver(s.conn).Table("adm_permissions").Where("slug", "=", perm.Slug))
+		if err != nil {
+			return err
+		}
+		if other != nil {
+			logger.Warn("resource: permission ", perm.Slug, " of plugin ", name,
+				" collides with an existing permission, skipped")
+			return errSkipped
+		}
+	}
+
+	var (
+		methods = strings.Join(perm.HttpMethod, ",")
+		paths   = strings.Join(perm.HttpPath, "\n")
+	)
+
+	if item != nil {
+		id, _ := item["id"].(int64)
+		if err := s.exec([]string{"adm_permissions"},
+			"UPDATE adm_permissions SET name = ?, slug = ?, http_method = ?, http_path = ?, updated_at = ? WHERE id = ?",
+			perm.Name, perm.Slug, methods, paths, now(), id); err != nil {
+			return err
+		}
+		return s.record(name, KindPermission, perm.Slug, id, r)
+	}
+
+	id, err := insert(s.conn, "adm_permissions", dialect.H{
+		"name":        perm.Name,
+		"slug":        perm.Slug,
+		"http_method": methods,
+		"http_path":   paths,
+	})
+	if err != nil {
+		return err
+	}
+	if s.adminRole != 0 {
+		if err := s.exec([]string{"adm_role_permissions"},
+			"INSERT INTO adm_role_permissions (role_id, permission_id) VALUES (?, ?)", s.adminRole, id); err != nil {
+			return err
+		}
+	}
+	return s.record(name, KindPermission, perm.Slug, id, r)
+}
+
+// markSeen mark the keys of the menu items and their children seen.
+func markSeen(seen map[string]bool, menus []plugins.Menu) {
+	for _, menu := range menus {
+		seen[KindMenu+"\x00"+menu.GetKey()] = true
+		markSeen(seen, menu.Children)
+	}
+}
+
+// record save the resource id of the key, r is the previous record.
+func (s *syncer) record(name, kind, key string, id int64, r record) error {
+	if r.kind != "" {
+		if r.id == id {
+			return nil
+		}
+		return s.exec(nil, "UPDATE "+table+" SET resource_id = ?, updated_at = ? WHERE plugin = ? AND kind = ? AND resource_key = ?",
+			id, now(), name, kind, key)
+	}
+	return s.exec(nil, "INSERT INTO "+table+" (plugin, kind, resource_key, resource_id) VALUES (?, ?, ?, ?)",
+		name, kind, key, id)
+}
+
+// remove delete the resource and its record.
+func (s *syncer) remove(name string, r record) error {
+	var err error
+	switch r.kind {
+	case KindMenu:
+		err = s.execAll([]string{"adm_menu", "adm_role_menu"},
+			[]string{"DELETE FROM adm_role_menu WHERE menu_id = ?", "DELETE FROM adm_menu WHERE id = ?"}, r.id)
+	case KindPermission:
+		err = s.execAll([]string{"adm_permissions", "adm_role_permissions", "adm_user_permissions"},
+			[]string{
+				"DELETE FROM adm_role_permissions WHERE permission_id = ?",
+				"DELETE FROM adm_user_permissions WHERE permission_id = ?",
+				"DELETE FROM adm_permissions WHERE id = ?",
+			}, r.id)
+	}
+	if err != nil {
+		return err
+	}
+	return s.exec(nil, "DELETE FROM "+table+" WHERE plugin = ? AND kind = ? AND resource_key = ?", name, r.kind, r.key)
+}
+
+// prune remove the resources of the plugins not in the names.
+func (s *syncer) prune(names []interface{}) error {
+	sql := db.WithDriver(s.conn).Table(table).Select("plugin")
+	if len(names) > 0 {
+		sql = sql.WhereNotIn("plugin", names)
+	}
+	items, err := sql.All()
+	if err != nil {
+		return err
+	}
+	pruned := make(map[string]bool)
+	for _, item := range items {
+		name, _ := item["plugin"].(string)
+		if pruned[name] {
+			continue
+		}
+		pruned[name] = true
+		if err := s.syncPlugin(name, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syncer) exec(tables []string, query string, args ...interface{}) error {
+	if _, err := s.conn.Exec(query, args...); err != nil {
+		return err
+	}
+	cache.InvalidateTables(db.CacheConn(s.conn, "default"), tables...)
+	return nil
+}
+
+func (s *syncer) execAll(tables []string, queries []string, args ...interface{}) error {
+	for _, query := range queries {
+		if err := s.exec(tables, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func now() string {
+	return time.Now().Format("2006-01-02 15:04:05")
+}
+
+// insert insert the values into the table and return the id of the new row.
+func insert(conn db.Connection, table string, values dialect.H) (int64, error) {
+	id, err := db.WithDriver(conn).Table(table).InsertReturning(values, "id")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(id, 10, 64)
+}
+
+// first return the first row of the query, or nil when there is no row.
+func first(sql *db.SQL) (map[string]interface{}, error) {
+	item, err := sql.First()
+	if err != nil && err.Error() == "out of index" {
+		return nil, nil
+	}
+	return item, err
+}
@@ -0,0 +1,97 @@
+//go:build sqlite
+// +build sqlite
+
+package resource_test
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/plugins"
+	"github.com/glvd/go-admin/plugins/admin/modules/installer"
+	"github.com/glvd/go-admin/plugins/admin/modules/resource"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type testPlugin struct {
+	name  string
+	menus []plugins.Menu
+	perms []plugins.Permission
+}
+
+func (p testPlugin) GetRequest() []context.Path                     { return nil }
+func (p testPlugin) GetHandler(url, method string) context.Handlers { return nil }
+func (p testPlugin) InitPlugin(services service.List)               {}
+func (p testPlugin) Name() string                                   { return p.name }
+func (p testPlugin) Version() string                                { return "v0.0.1" }
+func (p testPlugin) Menus() []plugins.Menu                          { return p.menus }
+func (p testPlugin) Permissions() []plugins.Permission              { return p.perms }
+
+func TestSync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resource")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "admin.db")},
+	})
+	assert.Nil(t, installer.Migrate(conn))
+
+	rows := func(query string, args ...interface{}) []map[string]interface{} {
+		res, err := conn.Query(query, args...)
+		assert.Nil(t, err)
+		return res
+	}
+
+	var (
+		report = testPlugin{
+			name: "report",
+			menus: []plugins.Menu{
+				{Title: "Report users", Uri: "/info/manager"},
+				{Title: "Reports", Uri: "/reports", Children: []plugins.Menu{
+					{Title: "Daily", Uri: "/reports/daily"},
+				}},
+			},
+			perms: []plugins.Permission{
+				{Name: "Report dashboard", Slug: "dashboard", HttpPath: []string{"/reports"}},
+				{Name: "Reports", Slug: "reports", HttpPath: []string{"/reports"}},
+			},
+		}
+		blog = testPlugin{name: "blog"}
+	)
+
+	assert.Nil(t, resource.Sync(conn, []plugins.Plugin{report}))
+
+	// The colliding rows are not adopted.
+	menus := rows("select title from adm_menu where uri = ?", "/info/manager")
+	assert.Equal(t, 1, len(menus))
+	assert.Equal(t, "Users", menus[0]["title"])
+	perms := rows("select name from adm_permissions where slug = ?", "dashboard")
+	assert.Equal(t, 1, len(perms))
+	assert.NotEqual(t, "Report dashboard", perms[0]["name"])
+
+	parent := rows("select id from adm_menu where uri = ?", "/reports")
+	assert.Equal(t, 1, len(parent))
+	child := rows("select parent_id from adm_menu where uri = ?", "/reports/daily")
+	assert.Equal(t, 1, len(child))
+	assert.Equal(t, parent[0]["id"], child[0]["parent_id"])
+	assert.Equal(t, 3, len(rows("select id from adm_plugin_resources where plugin = ?", "report")))
+
+	// The engines sharing the database keep the resources of each other.
+	assert.Nil(t, resource.Sync(conn, []plugins.Plugin{blog}))
+	assert.Equal(t, 1, len(rows("select id from adm_menu where uri = ?", "/reports")))
+
+	assert.Nil(t, resource.Prune(conn, []plugins.Plugin{blog}))
+	assert.Equal(t, 0, len(rows("select id from adm_menu where uri like ?", "/reports%")))
+	assert.Equal(t, 0, len(rows("select id from adm_permissions where slug = ?", "reports")))
+	assert.Equal(t, 1, len(rows("select id from adm_menu where uri = ?", "/info/manager")))
+	assert.Equal(t, 1, len(rows("select id from adm_permissions where slug = ?", "dashboard")))
+	assert.Equal(t, 0, len(rows("select id from adm_plugin_resources")))
+}
@@ -0,0 +1,85 @@
+package resource
+
+import "github.com/glvd/go-admin/modules/db"
+
+// schemas contains the statements which create the tables recording the
+// resources synced from the plugins and the states of the plugins.
+var schemas = map[string][]string{
+	db.DriverMysql: {
+		"CREATE TABLE IF NOT EXISTS `adm_plugin_resources` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`plugin` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`kind` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`resource_key` varchar(190) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`resource_id` int(11) unsigned NOT NULL," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)," +
+			"UNIQUE KEY `admin_plugin_resources_unique` (`plugin`,`kind`,`resource_key`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		"CREATE TABLE IF NOT EXISTS `adm_plugins` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`name` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`enabled` tinyint(4) unsigned NOT NULL DEFAULT '1'," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)," +
+			"UNIQUE KEY `admin_plugins_name_unique` (`name`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+	},
+	db.DriverPostgresql: {
+		`CREATE TABLE IF NOT EXISTS adm_plugin_resources (
+    id serial PRIMARY KEY,
+    plugin character varying(100) NOT NULL,
+    kind character varying(20) NOT NULL,
+    resource_key character varying(190) NOT NULL,
+    resource_id integer NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now(),
+    UNIQUE (plugin, kind, resource_key)
+)`,
+		`CREATE TABLE IF NOT EXISTS adm_plugins (
+    id serial PRIMARY KEY,
+    name character varying(100) NOT NULL UNIQUE,
+    enabled integer DEFAULT 1 NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+	},
+	db.DriverSqlite: {
+		"CREATE TABLE IF NOT EXISTS `adm_plugin_resources` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`plugin` varchar(100) NOT NULL," +
+			"`kind` varchar(20) NOT NULL," +
+			"`resource_key` varchar(190) NOT NULL," +
+			"`resource_id` integer NOT NULL," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"UNIQUE (`plugin`, `kind`, `resource_key`))",
+		"CREATE TABLE IF NOT EXISTS `adm_plugins` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`name` varchar(100) NOT NULL UNIQUE," +
+			"`enabled` integer NOT NULL DEFAULT 1," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+	},
+	db.DriverMssql: {
+		`IF OBJECT_ID('adm_plugin_resources', 'U') IS NULL CREATE TABLE adm_plugin_resources (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    plugin nvarchar(100) NOT NULL,
+    kind nvarchar(20) NOT NULL,
+    resource_key nvarchar(190) NOT NULL,
+    resource_id int NOT NULL,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT admin_plugin_resources_unique UNIQUE (plugin, kind, resource_key)
+)`,
+		`IF OBJECT_ID('adm_plugins', 'U') IS NULL CREATE TABLE adm_plugins (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    name nvarchar(100) NOT NULL UNIQUE,
+    enabled int NOT NULL DEFAULT 1,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+	},
+}
@@ -0,0 +1,151 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/metrics"
+	"github.com/glvd/go-admin/template/types"
+	"html/template"
+)
+
+// DetailInfo is the data of the detail page built by the DetailPanel.
+type DetailInfo struct {
+	Fields       []DetailField
+	Groups       [][]DetailField
+	GroupHeaders []string
+	Related      []DetailRelatedInfo
+	Title        string
+	Description  string
+}
+
+// DetailField is the head and the displayed value of a field.
+type DetailField struct {
+	Head    string
+	Content template.HTML
+}
+
+// DetailRelatedInfo is the list of the related rows, the rows are keyed by
+// the heads of the fields.
+type DetailRelatedInfo struct {
+	Title    string
+	Thead    []map[string]string
+	InfoList []map[string]template.HTML
+}
+
+// GetDetailFromDatabase query the row of the id and the related
+// rows of the detail panel.
+func (tb DefaultTable) GetDetailFromDatabase(id string) (DetailInfo, error) {
+	metrics.TableOperations.Inc(tb.detailTable(), metrics.OperationRead)
+
+	res, err := tb.withScope(tb.sql().Table(tb.detailTable()).
+		Where(tb.primaryKey.Name, "=", id), tb.detailTable()).
+		First()
+	if err != nil {
+		return DetailInfo{}, err
+	}
+
+	var (
+		info   = DetailInfo{Title: tb.detail.Title, Description: tb.detail.Description}
+		fields = make(map[string]DetailField, len(tb.detail.FieldList))
+	)
+
+	for _, field := range tb.detail.FieldList {
+		item := DetailField{
+			Head:    field.Head,
+			Content: displayValue(field, id, res),
+		}
+		fields[field.Field] = item
+		if !field.Hide {
+			info.Fields = append(info.Fields, item)
+		}
+	}
+
+	for key, group := range tb.detail.TabGroups {
+		list := make([]DetailField, 0, len(group))
+		for _, name := range group {
+			if item, ok := fields[name]; ok {
+				list = append(list, item)
+			}
+		}
+		info.Groups = append(info.Groups, list)
+		if key < len(tb.detail.TabHeaders) {
+			info.GroupHeaders = append(info.GroupHeaders, tb.detail.TabHeaders[key])
+		} else {
+			info.GroupHeaders = append(info.GroupHeaders, "")
+		}
+	}
+
+	for _, rel := range tb.detail.Related {
+		related, err := tb.detailRelated(rel, id)
+		if err != nil {
+			return DetailInfo{}, err
+		}
+		info.Related = append(info.Related, related)
+	}
+
+	return info, nil
+}
+
+// detailRelated query the related rows of the row of the id.
+func (tb DefaultTable) detailRelated(rel types.DetailRelated, id string) (DetailRelatedInfo, error) {
+	info := DetailRelatedInfo{
+		Title:    rel.Title,
+		Thead:    make([]map[string]string, 0),
+		InfoList: make([]map[string]template.HTML, 0),
+	}
+
+	statement := tb.sql().Table(rel.Info.Table).
+		Where(rel.ForeignKey, "=", id).
+		OrderBy(rel.GetPrimaryKey(), rel.Info.GetSort())
+	if rel.Info.DefaultPageSize > 0 {
+		statement = statement.Take(rel.Info.DefaultPageSize)
+	}
+	rows, err := statement.All()
+	if err != nil {
+		return info, err
+	}
+
+	for _, field := range rel.Info.FieldList {
+		if !field.Hide {
+			info.Thead = append(info.Thead, map[string]string{"head": field.Head})
+		}
+	}
+	for _, row := range rows {
+		item := make(map[string]template.HTML)
+		for _, field := range rel.Info.FieldList {
+			if !field.Hide {
+				item[field.Head] = displayValue(field, relationKey(row[rel.GetPrimaryKey()]), row)
+			}
+		}
+		info.InfoList = append(info.InfoList, item)
+	}
+	return info, nil
+}
+
+// detailTable return the table of the detail panel.
+func (tb DefaultTable) detailTable() string {
+	if tb.detail.Table != "" {
+		return tb.detail.Table
+	}
+	return tb.info.Table
+}
+
+// displayValue return the displayed value of the field in the row, the
+// value of a field which is not a column of the row is empty.
+func displayValue(field types.Field, id string, row map[string]interface{}) template.HTML {
+	value := ""
+	if v, ok := row[field.Field]; ok && v != nil {
+		value = db.GetValueFromDatabaseType(field.TypeName, v).String()
+	}
+	res := field.ToDisplay(types.FieldModel{
+		ID:    id,
+		Value: value,
+		Row:   row,
+	})
+	if str, ok := res.(string); ok {
+		return template.HTML(str)
+	}
+	if html, ok := res.(template.HTML); ok {
+		return html
+	}
+	return template.HTML(relationKey(res))
+}
@@ -0,0 +1,23 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/template/types"
+	"github.com/stretchr/testify/assert"
+	"html/template"
+	"testing"
+)
+
+func TestDisplayValue(t *testing.T) {
+	detail := NewDefaultTable(DefaultConfig()).GetDetail()
+	assert.False(t, detail.Valid())
+
+	detail.AddField("Name", "name", db.Varchar).
+		AddField("Full name", "full_name", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		return template.HTML(value.Row["name"].(string) + " " + value.Row["surname"].(string))
+	})
+
+	row := map[string]interface{}{"id": int64(1), "name": "John", "surname": "Smith"}
+	assert.Equal(t, template.HTML("John"), displayValue(detail.FieldList[0], "1", row))
+	assert.Equal(t, template.HTML("John Smith"), displayValue(detail.FieldList[1], "1", row))
+}
@@ -0,0 +1,157 @@
+package table
+
+import (
+	"errors"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/template/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filterTimeFormat is the format of the bounds of the relative dates.
+const filterTimeFormat = "2006-01-02 15:04:05"
+
+// advancedFilter return the condition of the advanced filter of the value
+// and its arguments. Only the filterable fields which are the columns of
+// the table can be filtered, the filter is ignored unless the advanced
+// filter of the info panel is shown.
+func (tb DefaultTable) advancedFilter(del string, columns Columns, value string) (string, []interface{}, error) {
+	if !tb.info.IsShowAdvancedFilter || value == "" {
+		return "", nil, nil
+	}
+
+	group, err := types.ParseFilterGroup(value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fields := make(map[string]string)
+	for _, field := range tb.info.FieldList {
+		if field.Filterable && !field.Join.Valid() && inArray(columns, field.Field) {
+			fields[field.Field] = tb.info.Table + "." + filterFiled(field.Field, del)
+		}
+	}
+
+	return filterGroupSQL(tb.connectionDriver, fields, group, time.Now())
+}
+
+// filterGroupSQL return the condition of the group, the fields are the
+// columns of the fields which can be filtered. The empty groups are
+// skipped.
+func filterGroupSQL(driver string, fields map[string]string, group types.FilterGroup,
+	now time.Time) (string, []interface{}, error) {
+
+	var (
+		parts = make([]string, 0, len(group.Conditions)+len(group.Groups))
+		args  = make([]interface{}, 0)
+	)
+
+	for _, c := range group.Conditions {
+		column, ok := fields[c.Field]
+		if !ok {
+			return "", nil, errors.New("invalid filter: the field " + c.Field + " can not be filtered")
+		}
+		part, partArgs := filterConditionSQL(driver, column, c, now)
+		parts = append(parts, part)
+		args = append(args, partArgs...)
+	}
+
+	for _, g := range group.Groups {
+		part, partArgs, err := filterGroupSQL(driver, fields, g, now)
+		if err != nil {
+			return "", nil, err
+		}
+		if part != "" {
+			parts = append(parts, part)
+			args = append(args, partArgs...)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", nil, nil
+	}
+	logic := " and "
+	if group.IsOr() {
+		logic = " or "
+	}
+	return "(" + strings.Join(parts, logic) + ")", args, nil
+}
+
+// filterConditionSQL return the parameterized condition of the column. The
+// relative dates are compared with the bounds of the days in the local
+// time: "today" is from the start of today to the start of tomorrow, the
+// last n days end at the start of tomorrow and the next n days start at
+// the start of today.
+func filterConditionSQL(driver, column string, c types.FilterCondition, now time.Time) (string, []interface{}) {
+	switch c.Operator {
+	case types.ConditionEqual:
+		return column + " = ?", []interface{}{c.Values[0]}
+	case types.ConditionNotEqual:
+		return column + " != ?", []interface{}{c.Values[0]}
+	case types.ConditionGreater:
+		return column + " > ?", []interface{}{c.Values[0]}
+	case types.ConditionGreaterOrEqual:
+		return column + " >= ?", []interface{}{c.Values[0]}
+	case types.ConditionLess:
+		return column + " < ?", []interface{}{c.Values[0]}
+	case types.ConditionLessOrEqual:
+		return column + " <= ?", []interface{}{c.Values[0]}
+	case types.ConditionContains:
+		return likeColumn(driver, column) + " like ? escape '!'", []interface{}{"%" + escapeLike(driver, c.Values[0]) + "%"}
+	case types.ConditionStartsWith:
+		return likeColumn(driver, column) + " like ? escape '!'", []interface{}{escapeLike(driver, c.Values[0]) + "%"}
+	case types.ConditionEndsWith:
+		return likeColumn(driver, column) + " like ? escape '!'", []interface{}{"%" + escapeLike(driver, c.Values[0])}
+	case types.ConditionBetween:
+		return column + " between ? and ?", []interface{}{c.Values[0], c.Values[1]}
+	case types.ConditionIn, types.ConditionNotIn:
+		args := make([]interface{}, len(c.Values))
+		for i, value := range c.Values {
+			args[i] = value
+		}
+		op := " in ("
+		if c.Operator == types.ConditionNotIn {
+			op = " not in ("
+		}
+		return column + op + "?" + strings.Repeat(", ?", len(args)-1) + ")", args
+	case types.ConditionNull:
+		return column + " is null", nil
+	case types.ConditionNotNull:
+		return column + " is not null", nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start, end := today, today.AddDate(0, 0, 1)
+	switch c.Operator {
+	case types.ConditionLastDays:
+		days, _ := strconv.Atoi(c.Values[0])
+		start = end.AddDate(0, 0, -days)
+	case types.ConditionNextDays:
+		days, _ := strconv.Atoi(c.Values[0])
+		end = start.AddDate(0, 0, days)
+	}
+	return column + " >= ? and " + column + " < ?",
+		[]interface{}{start.Format(filterTimeFormat), end.Format(filterTimeFormat)}
+}
+
+// likeColumn return the column compared by like, the columns of postgresql
+// are cast to text so that the numbers and the dates can be compared.
+func likeColumn(driver, column string) string {
+	if driver == db.DriverPostgresql {
+		return "cast(" + column + " as text)"
+	}
+	return column
+}
+
+// escapeLike escape the wildcards of like by "!", which is declared as the
+// escape character of the conditions.
+func escapeLike(driver, value string) string {
+	value = strings.Replace(value, "!", "!!", -1)
+	value = strings.Replace(value, "%", "!%", -1)
+	value = strings.Replace(value, "_", "!_", -1)
+	if driver == db.DriverMssql {
+		value = strings.Replace(value, "[", "![", -1)
+	}
+	return value
+}
@@ -0,0 +1,66 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/template/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFilterGroupSQL(t *testing.T) {
+	var (
+		now    = time.Date(2020, 3, 15, 10, 30, 0, 0, time.UTC)
+		fields = map[string]string{
+			"name":       "users.`name`",
+			"state":      "users.`state`",
+			"created_at": "users.`created_at`",
+		}
+		group = types.FilterGroup{
+			Logic: "or",
+			Conditions: []types.FilterCondition{
+				{Field: "name", Operator: types.ConditionStartsWith, Values: []string{"50%_off"}},
+				{Field: "state", Operator: types.ConditionIn, Values: []string{"1", "2"}},
+			},
+			Groups: []types.FilterGroup{
+				{Conditions: []types.FilterCondition{
+					{Field: "created_at", Operator: types.ConditionLastDays, Values: []string{"7"}},
+					{Field: "state", Operator: types.ConditionNotNull},
+				}},
+				{Logic: "or"},
+			},
+		}
+	)
+
+	condition, args, err := filterGroupSQL(db.DriverMysql, fields, group, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "(users.`name` like ? escape '!' or users.`state` in (?, ?) or "+
+		"(users.`created_at` >= ? and users.`created_at` < ? and users.`state` is not null))", condition)
+	assert.Equal(t, []interface{}{"50!%!_off%", "1", "2", "2020-03-09 00:00:00", "2020-03-16 00:00:00"}, args)
+
+	condition, args, err = filterGroupSQL(db.DriverPostgresql, fields, types.FilterGroup{
+		Conditions: []types.FilterCondition{
+			{Field: "state", Operator: types.ConditionContains, Values: []string{"1"}},
+			{Field: "created_at", Operator: types.ConditionNextDays, Values: []string{"2"}},
+		},
+	}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "(cast(users.`state` as text) like ? escape '!' and "+
+		"users.`created_at` >= ? and users.`created_at` < ?)", condition)
+	assert.Equal(t, []interface{}{"%1%", "2020-03-15 00:00:00", "2020-03-17 00:00:00"}, args)
+
+	_, _, err = filterGroupSQL(db.DriverMysql, fields, types.FilterGroup{
+		Conditions: []types.FilterCondition{{Field: "password", Operator: types.ConditionNull}},
+	}, now)
+	assert.NotNil(t, err)
+
+	condition, args, err = filterGroupSQL(db.DriverMysql, fields, types.FilterGroup{}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "", condition)
+	assert.Equal(t, 0, len(args))
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, "a!!b!%c!_d[e]", escapeLike(db.DriverMysql, "a!b%c_d[e]"))
+	assert.Equal(t, "a!!b!%c!_d![e]", escapeLike(db.DriverMssql, "a!b%c_d[e]"))
+}
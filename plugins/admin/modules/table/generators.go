@@ -8,6 +8,7 @@ import (
 	"github.com/glvd/go-admin/modules/db"
 	"github.com/glvd/go-admin/modules/db/dialect"
 	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/modules/service"
 	"github.com/glvd/go-admin/plugins/admin/models"
 	form2 "github.com/glvd/go-admin/plugins/admin/modules/form"
 	"github.com/glvd/go-admin/template"
@@ -19,8 +20,68 @@ import (
 	"time"
 )
 
-func GetManagerTable() (ManagerTable Table) {
-	ManagerTable = NewDefaultTable(DefaultConfigWithDriver(config.Get().Databases.GetDefault().Driver))
+// Builtin generate the builtin tables of the admin, such as the managers
+// and the roles, with the config and the services of an admin instance.
+type Builtin struct {
+	config   config.Config
+	services service.List
+}
+
+// NewBuiltin return the Builtin of the config and the services.
+func NewBuiltin(cfg config.Config, srv service.List) Builtin {
+	return Builtin{config: cfg, services: srv}
+}
+
+// defaultBuiltin return the Builtin of the global config and the services
+// of the default list.
+func defaultBuiltin() Builtin {
+	return NewBuiltin(config.Get(), defaultList.Services())
+}
+
+// Generators return the generators of the builtin tables.
+func (b Builtin) Generators() GeneratorList {
+	return GeneratorList{
+		"manager":        b.ManagerTable,
+		"permission":     b.PermissionTable,
+		"roles":          b.RolesTable,
+		"op":             b.OpTable,
+		"menu":           b.MenuTable,
+		"normal_manager": b.NormalManagerTable,
+	}
+}
+
+// GetManagerTable return the manager table of the global config.
+func GetManagerTable() Table {
+	return defaultBuiltin().ManagerTable()
+}
+
+// GetNormalManagerTable return the normal manager table of the global config.
+func GetNormalManagerTable() Table {
+	return defaultBuiltin().NormalManagerTable()
+}
+
+// GetPermissionTable return the permission table of the global config.
+func GetPermissionTable() Table {
+	return defaultBuiltin().PermissionTable()
+}
+
+// GetRolesTable return the roles table of the global config.
+func GetRolesTable() Table {
+	return defaultBuiltin().RolesTable()
+}
+
+// GetOpTable return the operation log table of the global config.
+func GetOpTable() Table {
+	return defaultBuiltin().OpTable()
+}
+
+// GetMenuTable return the menu table of the global config.
+func GetMenuTable() Table {
+	return defaultBuiltin().MenuTable()
+}
+
+func (b Builtin) ManagerTable() (ManagerTable Table) {
+	ManagerTable = b.newTable(DefaultConfigWithDriver(b.config.Databases.GetDefault().Driver))
 
 	info := ManagerTable.GetInfo().AddXssJsFilter().HideFilterArea()
 
@@ -28,29 +89,20 @@ func GetManagerTable() (ManagerTable Table) {
 	info.AddField(lg("Name"), "username", db.Varchar).FieldFilterable()
 	info.AddField(lg("Nickname"), "name", db.Varchar).FieldFilterable()
 	info.AddField(lg("role"), "roles", db.Varchar).
+		FieldRelation(types.Relation{
+			Type:       types.ManyToMany,
+			Table:      "adm_roles",
+			Field:      "name",
+			Pivot:      "adm_role_users",
+			ForeignKey: "user_id",
+			RelatedKey: "role_id",
+		}).
+		FieldFilterable().
 		FieldDisplay(func(model types.FieldModel) interface{} {
-			labelModels, _ := table("adm_role_users").
-				Select("adm_roles.name").
-				LeftJoin("adm_roles", "adm_roles.id", "=", "adm_role_users.role_id").
-				Where("user_id", "=", model.ID).
-				All()
-
-			labels := template.HTML("")
-			labelTpl := label().SetType("success")
-
-			for key, label := range labelModels {
-				if key == len(labelModels)-1 {
-					labels += labelTpl.SetContent(template.HTML(label["name"].(string))).GetContent()
-				} else {
-					labels += labelTpl.SetContent(template.HTML(label["name"].(string))).GetContent() + "<br><br>"
-				}
-			}
-
-			if labels == template.HTML("") {
+			if model.Value == "" {
 				return lg("no roles")
 			}
-
-			return labels
+			return template.HTML(model.Value)
 		})
 	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 	info.AddField(lg("updatedAt"), "updated_at", db.Timestamp)
@@ -62,9 +114,9 @@ func GetManagerTable() (ManagerTable Table) {
 
 			var ids = interfaces(idArr)
 
-			_, txErr := connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
+			_, txErr := b.connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
 
-				deleteUserRoleErr := connection().WithTx(tx).
+				deleteUserRoleErr := b.connection().WithTx(tx).
 					Table("adm_role_users").
 					WhereIn("user_id", ids).
 					Delete()
@@ -73,7 +125,7 @@ func GetManagerTable() (ManagerTable Table) {
 					return deleteUserRoleErr, map[string]interface{}{}
 				}
 
-				deleteUserPermissionErr := connection().WithTx(tx).
+				deleteUserPermissionErr := b.connection().WithTx(tx).
 					Table("adm_user_permissions").
 					WhereIn("user_id", ids).
 					Delete()
@@ -82,7 +134,7 @@ func GetManagerTable() (ManagerTable Table) {
 					return deleteUserPermissionErr, map[string]interface{}{}
 				}
 
-				deleteUserErr := connection().WithTx(tx).
+				deleteUserErr := b.connection().WithTx(tx).
 					Table("adm_users").
 					WhereIn("id", ids).
 					Delete()
@@ -98,7 +150,7 @@ func GetManagerTable() (ManagerTable Table) {
 		})
 
 	var roles, permissions []map[string]string
-	rolesModel, _ := table("adm_roles").Select("id", "slug").All()
+	rolesModel, _ := b.table("adm_roles").Select("id", "slug").All()
 
 	for _, v := range rolesModel {
 		roles = append(roles, map[string]string{
@@ -106,7 +158,7 @@ func GetManagerTable() (ManagerTable Table) {
 			"value": strconv.FormatInt(v["id"].(int64), 10),
 		})
 	}
-	permissionsModel, _ := table("adm_permissions").Select("id", "slug").All()
+	permissionsModel, _ := b.table("adm_permissions").Select("id", "slug").All()
 	for _, v := range permissionsModel {
 		permissions = append(permissions, map[string]string{
 			"field": v["slug"].(string),
@@ -122,7 +174,7 @@ func GetManagerTable() (ManagerTable Table) {
 	formList.AddField(lg("Avatar"), "avatar", db.Varchar, form.File)
 	formList.AddField(lg("role"), "role_id", db.Varchar, form.Select).
 		FieldOptions(roles).FieldDisplay(func(model types.FieldModel) interface{} {
-		roleModel, _ := table("adm_role_users").Select("role_id").
+		roleModel, _ := b.table("adm_role_users").Select("role_id").
 			Where("user_id", "=", model.ID).All()
 		var roles []string
 		for _, v := range roleModel {
@@ -133,7 +185,7 @@ func GetManagerTable() (ManagerTable Table) {
 		lg("Create here.") + `</a>`))
 	formList.AddField(lg("permission"), "permission_id", db.Varchar, form.Select).
 		FieldOptions(permissions).FieldDisplay(func(model types.FieldModel) interface{} {
-		permissionModel, _ := table("adm_user_permissions").
+		permissionModel, _ := b.table("adm_user_permissions").
 			Select("permission_id").Where("user_id", "=", model.ID).All()
 		var permissions []string
 		for _, v := range permissionModel {
@@ -158,7 +210,7 @@ func GetManagerTable() (ManagerTable Table) {
 			return errors.New("username and password can not be empty")
 		}
 
-		user := models.UserWithId(values.Get("id")).SetConn(conn())
+		user := models.UserWithId(values.Get("id")).SetConn(b.conn())
 
 		password := values.Get("password")
 
@@ -196,7 +248,7 @@ func GetManagerTable() (ManagerTable Table) {
 			return errors.New("password does not match")
 		}
 
-		user := models.User().SetConn(conn()).New(values.Get("username"),
+		user := models.User().SetConn(b.conn()).New(values.Get("username"),
 			encodePassword([]byte(values.Get("password"))),
 			values.Get("name"),
 			values.Get("avatar"))
@@ -216,8 +268,8 @@ func GetManagerTable() (ManagerTable Table) {
 	return
 }
 
-func GetNormalManagerTable() (ManagerTable Table) {
-	ManagerTable = NewDefaultTable(DefaultConfigWithDriver(config.Get().Databases.GetDefault().Driver))
+func (b Builtin) NormalManagerTable() (ManagerTable Table) {
+	ManagerTable = b.newTable(DefaultConfigWithDriver(b.config.Databases.GetDefault().Driver))
 
 	info := ManagerTable.GetInfo().AddXssJsFilter().HideFilterArea()
 
@@ -225,29 +277,20 @@ func GetNormalManagerTable() (ManagerTable Table) {
 	info.AddField(lg("Name"), "username", db.Varchar).FieldFilterable()
 	info.AddField(lg("Nickname"), "name", db.Varchar).FieldFilterable()
 	info.AddField(lg("role"), "roles", db.Varchar).
+		FieldRelation(types.Relation{
+			Type:       types.ManyToMany,
+			Table:      "adm_roles",
+			Field:      "name",
+			Pivot:      "adm_role_users",
+			ForeignKey: "user_id",
+			RelatedKey: "role_id",
+		}).
+		FieldFilterable().
 		FieldDisplay(func(model types.FieldModel) interface{} {
-			labelModels, _ := table("adm_role_users").
-				Select("adm_roles.name").
-				LeftJoin("adm_roles", "adm_roles.id", "=", "adm_role_users.role_id").
-				Where("user_id", "=", model.ID).
-				All()
-
-			labels := template.HTML("")
-			labelTpl := label().SetType("success")
-
-			for key, label := range labelModels {
-				if key == len(labelModels)-1 {
-					labels += labelTpl.SetContent(template.HTML(label["name"].(string))).GetContent()
-				} else {
-					labels += labelTpl.SetContent(template.HTML(label["name"].(string))).GetContent() + "<br><br>"
-				}
-			}
-
-			if labels == template.HTML("") {
+			if model.Value == "" {
 				return lg("no roles")
 			}
-
-			return labels
+			return template.HTML(model.Value)
 		})
 	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 	info.AddField(lg("updatedAt"), "updated_at", db.Timestamp)
@@ -259,9 +302,9 @@ func GetNormalManagerTable() (ManagerTable Table) {
 
 			var ids = interfaces(idArr)
 
-			_, txErr := connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
+			_, txErr := b.connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
 
-				deleteUserRoleErr := connection().WithTx(tx).
+				deleteUserRoleErr := b.connection().WithTx(tx).
 					Table("adm_role_users").
 					WhereIn("user_id", ids).
 					Delete()
@@ -270,7 +313,7 @@ func GetNormalManagerTable() (ManagerTable Table) {
 					return deleteUserRoleErr, map[string]interface{}{}
 				}
 
-				deleteUserPermissionErr := connection().WithTx(tx).
+				deleteUserPermissionErr := b.connection().WithTx(tx).
 					Table("adm_user_permissions").
 					WhereIn("user_id", ids).
 					Delete()
@@ -279,7 +322,7 @@ func GetNormalManagerTable() (ManagerTable Table) {
 					return deleteUserPermissionErr, map[string]interface{}{}
 				}
 
-				deleteUserErr := connection().WithTx(tx).
+				deleteUserErr := b.connection().WithTx(tx).
 					Table("adm_users").
 					WhereIn("id", ids).
 					Delete()
@@ -295,7 +338,7 @@ func GetNormalManagerTable() (ManagerTable Table) {
 		})
 
 	var roles, permissions []map[string]string
-	rolesModel, _ := table("adm_roles").Select("id", "slug").All()
+	rolesModel, _ := b.table("adm_roles").Select("id", "slug").All()
 
 	for _, v := range rolesModel {
 		roles = append(roles, map[string]string{
@@ -303,7 +346,7 @@ func GetNormalManagerTable() (ManagerTable Table) {
 			"value": strconv.FormatInt(v["id"].(int64), 10),
 		})
 	}
-	permissionsModel, _ := table("adm_permissions").Select("id", "slug").All()
+	permissionsModel, _ := b.table("adm_permissions").Select("id", "slug").All()
 	for _, v := range permissionsModel {
 		permissions = append(permissions, map[string]string{
 			"field": v["slug"].(string),
@@ -333,7 +376,7 @@ func GetNormalManagerTable() (ManagerTable Table) {
 			return errors.New("username and password can not be empty")
 		}
 
-		user := models.UserWithId(values.Get("id")).SetConn(conn())
+		user := models.UserWithId(values.Get("id")).SetConn(b.conn())
 
 		if values.Has("permission", "role") {
 			return errors.New("no permission")
@@ -369,7 +412,7 @@ func GetNormalManagerTable() (ManagerTable Table) {
 			return errors.New("no permission")
 		}
 
-		models.User().SetConn(conn()).New(values.Get("username"),
+		models.User().SetConn(b.conn()).New(values.Get("username"),
 			encodePassword([]byte(values.Get("password"))),
 			values.Get("name"),
 			values.Get("avatar"))
@@ -380,8 +423,8 @@ func GetNormalManagerTable() (ManagerTable Table) {
 	return
 }
 
-func GetPermissionTable() (PermissionTable Table) {
-	PermissionTable = NewDefaultTable(DefaultConfigWithDriver(config.Get().Databases.GetDefault().Driver))
+func (b Builtin) PermissionTable() (PermissionTable Table) {
+	PermissionTable = b.newTable(DefaultConfigWithDriver(b.config.Databases.GetDefault().Driver))
 
 	info := PermissionTable.GetInfo().AddXssJsFilter().HideFilterArea()
 
@@ -400,9 +443,9 @@ func GetPermissionTable() (PermissionTable Table) {
 			res := ""
 			for i := 0; i < len(pathArr); i++ {
 				if i == len(pathArr)-1 {
-					res += string(label().SetContent(template.HTML(pathArr[i])).GetContent())
+					res += string(b.label().SetContent(template.HTML(pathArr[i])).GetContent())
 				} else {
-					res += string(label().SetContent(template.HTML(pathArr[i])).GetContent()) + "<br><br>"
+					res += string(b.label().SetContent(template.HTML(pathArr[i])).GetContent()) + "<br><br>"
 				}
 			}
 			return res
@@ -417,9 +460,9 @@ func GetPermissionTable() (PermissionTable Table) {
 
 			var ids = interfaces(idArr)
 
-			_, txErr := connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
+			_, txErr := b.connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
 
-				deleteRolePermissionErr := connection().WithTx(tx).
+				deleteRolePermissionErr := b.connection().WithTx(tx).
 					Table("adm_role_permissions").
 					WhereIn("permission_id", ids).
 					Delete()
@@ -428,7 +471,7 @@ func GetPermissionTable() (PermissionTable Table) {
 					return deleteRolePermissionErr, map[string]interface{}{}
 				}
 
-				deleteUserPermissionErr := connection().WithTx(tx).
+				deleteUserPermissionErr := b.connection().WithTx(tx).
 					Table("adm_user_permissions").
 					WhereIn("permission_id", ids).
 					Delete()
@@ -437,7 +480,7 @@ func GetPermissionTable() (PermissionTable Table) {
 					return deleteUserPermissionErr, map[string]interface{}{}
 				}
 
-				deletePermissionsErr := connection().WithTx(tx).
+				deletePermissionsErr := b.connection().WithTx(tx).
 					Table("adm_permissions").
 					WhereIn("id", ids).
 					Delete()
@@ -490,12 +533,12 @@ func GetPermissionTable() (PermissionTable Table) {
 				return errors.New("slug or http_path or name should not be empty")
 			}
 
-			if models.Permission().SetConn(conn()).IsSlugExist(values.Get("slug"), values.Get("id")) {
+			if models.Permission().SetConn(b.conn()).IsSlugExist(values.Get("slug"), values.Get("id")) {
 				return errors.New("slug exists")
 			}
 			return nil
 		}).SetPostHook(func(values form2.Values) error {
-		_, err := connection().Table("adm_permissions").
+		_, err := b.connection().Table("adm_permissions").
 			Where("id", "=", values.Get("id")).Update(dialect.H{
 			"updated_at": time.Now().Format("2006-01-02 15:04:05"),
 		})
@@ -505,8 +548,8 @@ func GetPermissionTable() (PermissionTable Table) {
 	return
 }
 
-func GetRolesTable() (RolesTable Table) {
-	RolesTable = NewDefaultTable(DefaultConfigWithDriver(config.Get().Databases.GetDefault().Driver))
+func (b Builtin) RolesTable() (RolesTable Table) {
+	RolesTable = b.newTable(DefaultConfigWithDriver(b.config.Databases.GetDefault().Driver))
 
 	info := RolesTable.GetInfo().AddXssJsFilter().HideFilterArea()
 
@@ -523,9 +566,9 @@ func GetRolesTable() (RolesTable Table) {
 
 			var ids = interfaces(idArr)
 
-			_, txErr := connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
+			_, txErr := b.connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
 
-				deleteRoleUserErr := connection().WithTx(tx).
+				deleteRoleUserErr := b.connection().WithTx(tx).
 					Table("adm_role_users").
 					WhereIn("role_id", ids).
 					Delete()
@@ -534,7 +577,7 @@ func GetRolesTable() (RolesTable Table) {
 					return deleteRoleUserErr, map[string]interface{}{}
 				}
 
-				deleteRoleMenuErr := connection().WithTx(tx).
+				deleteRoleMenuErr := b.connection().WithTx(tx).
 					Table("adm_role_menu").
 					WhereIn("role_id", ids).
 					Delete()
@@ -543,7 +586,7 @@ func GetRolesTable() (RolesTable Table) {
 					return deleteRoleMenuErr, map[string]interface{}{}
 				}
 
-				deleteRolePermissionErr := connection().WithTx(tx).
+				deleteRolePermissionErr := b.connection().WithTx(tx).
 					Table("adm_role_permissions").
 					WhereIn("role_id", ids).
 					Delete()
@@ -552,7 +595,7 @@ func GetRolesTable() (RolesTable Table) {
 					return deleteRolePermissionErr, map[string]interface{}{}
 				}
 
-				deleteRolesErr := connection().WithTx(tx).
+				deleteRolesErr := b.connection().WithTx(tx).
 					Table("adm_roles").
 					WhereIn("id", ids).
 					Delete()
@@ -569,7 +612,7 @@ func GetRolesTable() (RolesTable Table) {
 
 	formList := RolesTable.GetForm().AddXssJsFilter()
 
-	permissionsModel, _ := table("adm_permissions").Select("id", "name").All()
+	permissionsModel, _ := b.table("adm_permissions").Select("id", "name").All()
 	var permissions = make([]map[string]string, len(permissionsModel))
 
 	for k, v := range permissionsModel {
@@ -584,7 +627,7 @@ func GetRolesTable() (RolesTable Table) {
 	formList.AddField(lg("slug"), "slug", db.Varchar, form.Text).FieldHelpMsg(template.HTML(lg("should be unique")))
 	formList.AddField(lg("permission"), "permission_id", db.Varchar, form.SelectBox).
 		FieldOptions(permissions).FieldDisplay(func(model types.FieldModel) interface{} {
-		perModel, _ := table("adm_role_permissions").
+		perModel, _ := b.table("adm_role_permissions").
 			Select("permission_id").
 			Where("role_id", "=", model.ID).
 			All()
@@ -605,11 +648,11 @@ func GetRolesTable() (RolesTable Table) {
 
 	formList.SetUpdateFn(func(values form2.Values) error {
 
-		if models.Role().SetConn(conn()).IsSlugExist(values.Get("slug"), values.Get("id")) {
+		if models.Role().SetConn(b.conn()).IsSlugExist(values.Get("slug"), values.Get("id")) {
 			return errors.New("slug exists")
 		}
 
-		role := models.RoleWithId(values.Get("id")).SetConn(conn())
+		role := models.RoleWithId(values.Get("id")).SetConn(b.conn())
 
 		role.Update(values.Get("name"), values.Get("slug"))
 
@@ -623,11 +666,11 @@ func GetRolesTable() (RolesTable Table) {
 
 	formList.SetInsertFn(func(values form2.Values) error {
 
-		if models.Role().SetConn(conn()).IsSlugExist(values.Get("slug"), "") {
+		if models.Role().SetConn(b.conn()).IsSlugExist(values.Get("slug"), "") {
 			return errors.New("slug exists")
 		}
 
-		role := models.Role().SetConn(conn()).New(values.Get("name"), values.Get("slug"))
+		role := models.Role().SetConn(b.conn()).New(values.Get("name"), values.Get("slug"))
 
 		for i := 0; i < len(values["permission_id[]"]); i++ {
 			role.AddPermission(values["permission_id[]"][i])
@@ -639,9 +682,9 @@ func GetRolesTable() (RolesTable Table) {
 	return
 }
 
-func GetOpTable() (OpTable Table) {
-	OpTable = NewDefaultTable(Config{
-		Driver:     config.Get().Databases.GetDefault().Driver,
+func (b Builtin) OpTable() (OpTable Table) {
+	OpTable = b.newTable(Config{
+		Driver:     b.config.Databases.GetDefault().Driver,
 		CanAdd:     false,
 		Editable:   false,
 		Deletable:  false,
@@ -686,8 +729,8 @@ func GetOpTable() (OpTable Table) {
 	return
 }
 
-func GetMenuTable() (MenuTable Table) {
-	MenuTable = NewDefaultTable(DefaultConfigWithDriver(config.Get().Databases.GetDefault().Driver))
+func (b Builtin) MenuTable() (MenuTable Table) {
+	MenuTable = b.newTable(DefaultConfigWithDriver(b.config.Databases.GetDefault().Driver))
 
 	info := MenuTable.GetInfo().AddXssJsFilter().HideFilterArea()
 
@@ -708,9 +751,9 @@ func GetMenuTable() (MenuTable Table) {
 
 			var ids = interfaces(idArr)
 
-			_, txErr := connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
+			_, txErr := b.connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
 
-				deleteRoleMenuErr := connection().WithTx(tx).
+				deleteRoleMenuErr := b.connection().WithTx(tx).
 					Table("adm_role_menu").
 					WhereIn("menu_id", ids).
 					Delete()
@@ -719,7 +762,7 @@ func GetMenuTable() (MenuTable Table) {
 					return deleteRoleMenuErr, map[string]interface{}{}
 				}
 
-				deleteMenusErr := connection().WithTx(tx).
+				deleteMenusErr := b.connection().WithTx(tx).
 					Table("adm_menu").
 					WhereIn("id", ids).
 					Delete()
@@ -735,7 +778,7 @@ func GetMenuTable() (MenuTable Table) {
 		})
 
 	var roles, parents []map[string]string
-	rolesModel, _ := table("adm_roles").Select("id", "slug").All()
+	rolesModel, _ := b.table("adm_roles").Select("id", "slug").All()
 
 	for _, v := range rolesModel {
 		roles = append(roles, map[string]string{
@@ -744,7 +787,7 @@ func GetMenuTable() (MenuTable Table) {
 		})
 	}
 
-	parentsModel, _ := table("adm_menu").
+	parentsModel, _ := b.table("adm_menu").
 		Select("id", "title").
 		Where("id", ">", 0).
 		OrderBy("order", "asc").
@@ -765,7 +808,7 @@ func GetMenuTable() (MenuTable Table) {
 	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
 	formList.AddField(lg("parent"), "parent_id", db.Int, form.SelectSingle).
 		FieldOptions(parents).FieldDisplay(func(model types.FieldModel) interface{} {
-		menuModel, _ := table("adm_menu").Select("parent_id").Find(model.ID)
+		menuModel, _ := b.table("adm_menu").Select("parent_id").Find(model.ID)
 
 		var menuItem []string
 		menuItem = append(menuItem, strconv.FormatInt(menuModel["parent_id"].(int64), 10))
@@ -777,7 +820,7 @@ func GetMenuTable() (MenuTable Table) {
 	formList.AddField(lg("uri"), "uri", db.Varchar, form.Text)
 	formList.AddField(lg("role"), "roles", db.Int, form.Select).
 		FieldOptions(roles).FieldDisplay(func(model types.FieldModel) interface{} {
-		roleModel, _ := table("adm_role_menu").
+		roleModel, _ := b.table("adm_role_menu").
 			Select("role_id").
 			Where("menu_id", "=", model.ID).
 			All()
@@ -810,24 +853,28 @@ func encodePassword(pwd []byte) string {
 	return string(hash[:])
 }
 
-func label() types.LabelAttribute {
-	return template.Get(config.Get().Theme).Label().SetType("success")
+func (b Builtin) newTable(cfg Config) Table {
+	return bind(NewDefaultTable(cfg), b.services)
+}
+
+func (b Builtin) label() types.LabelAttribute {
+	return template.Get(b.config.Theme).Label().SetType("success")
 }
 
 func lg(v string) string {
 	return language.Get(v)
 }
 
-func table(table string) *db.SQL {
-	return connection().Table(table)
+func (b Builtin) table(table string) *db.SQL {
+	return b.connection().Table(table)
 }
 
-func connection() *db.SQL {
-	return db.WithDriver(db.GetConnection(services))
+func (b Builtin) connection() *db.SQL {
+	return db.WithDriver(db.GetConnection(b.services))
 }
 
-func conn() db.Connection {
-	return db.GetConnection(services)
+func (b Builtin) conn() db.Connection {
+	return db.GetConnection(b.services)
 }
 
 func interfaces(arr []string) []interface{} {
@@ -0,0 +1,205 @@
+package table
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/glvd/go-admin/modules/cache"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/db/dialect"
+	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/plugins/admin/modules"
+	"github.com/glvd/go-admin/plugins/admin/modules/form"
+	"github.com/glvd/go-admin/template/types"
+	"strings"
+)
+
+// hasManyFields return the has many fields of the form.
+func (tb DefaultTable) hasManyFields() types.FormFields {
+	fields := make(types.FormFields, 0)
+	for _, field := range tb.form.FieldList {
+		if field.HasMany != nil && !tb.denied[field.Field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// saveWithHasMany insert or update the row and save the child rows of the
+// has many fields in one transaction, and return the primary key of the row.
+func (tb DefaultTable) saveWithHasMany(dataList form.Values, fields types.FormFields, insert bool) (string, error) {
+	id := dataList.Get(tb.primaryKey.Name)
+
+	_, err := tb.sql().WithTransaction(func(tx *sql.Tx) (error, map[string]interface{}) {
+		value := tb.getInjectValueFromFormValue(dataList)
+
+		if insert {
+			if v, ok := value[tb.primaryKey.Name]; ok && relationKey(v) != "" {
+				if _, err := tb.sql().WithTx(tx).Table(tb.form.Table).Insert(value); err != nil &&
+					!lastInsertIdUnsupported(err) {
+					return err, nil
+				}
+				id = relationKey(v)
+			} else {
+				lastID, err := tb.sql().WithTx(tx).Table(tb.form.Table).InsertReturning(value, tb.primaryKey.Name)
+				if err != nil {
+					return err, nil
+				}
+				id = lastID
+			}
+		} else {
+			// The row is checked in the transaction, so that the child rows
+			// of a row out of the row scopes are not saved.
+			if tb.scope.Valid() {
+				row, err := tb.withScope(tb.sql().WithTx(tx).Table(tb.form.Table).
+					Select(tb.primaryKey.Name).
+					Where(tb.primaryKey.Name, "=", id), tb.form.Table).
+					First()
+				if err != nil && err.Error() != "out of index" {
+					return err, nil
+				}
+				if row == nil {
+					return errors.New(language.Get("permission denied")), nil
+				}
+			}
+			if len(value) > 0 {
+				_, err := tb.withScope(tb.sql().WithTx(tx).Table(tb.form.Table).
+					Where(tb.primaryKey.Name, "=", id), tb.form.Table).
+					Update(value)
+				if err != nil && notNoAffectRow(err) {
+					return err, nil
+				}
+			}
+		}
+
+		for _, field := range fields {
+			if err := tb.saveHasManyRows(tx, field, id, dataList); err != nil {
+				return err, nil
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, field := range fields {
+		cache.InvalidateTables(db.CacheConn(tb.db(), tb.connection), field.HasMany.Table)
+	}
+	return id, nil
+}
+
+// saveHasManyRows save the posted child rows of the field in order: the
+// posted existing rows are updated, the others are inserted, and the
+// existing rows not posted are deleted.
+func (tb DefaultTable) saveHasManyRows(tx *sql.Tx, field types.FormField, id string, dataList form.Values) error {
+	var (
+		rel      = field.HasMany
+		pk       = rel.GetPrimaryKey()
+		existing = make(map[string]bool)
+		posted   = make(map[string]bool)
+	)
+
+	rows, err := tb.sql().WithTx(tx).Table(rel.Table).
+		Select(pk).
+		Where(rel.ForeignKey, "=", id).
+		All()
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		existing[relationKey(row[pk])] = true
+	}
+
+	for i, row := range types.ParseHasMany(field.Field, dataList) {
+		value := hasManyValue(rel, row)
+		value[rel.ForeignKey] = id
+		if rel.OrderField != "" {
+			value[rel.OrderField] = i
+		}
+
+		if key := row.Get(pk); key != "" && existing[key] {
+			posted[key] = true
+			_, err := tb.sql().WithTx(tx).Table(rel.Table).
+				Where(pk, "=", key).
+				Where(rel.ForeignKey, "=", id).
+				Update(value)
+			if err != nil && notNoAffectRow(err) {
+				return err
+			}
+			continue
+		}
+
+		if _, err := tb.sql().WithTx(tx).Table(rel.Table).Insert(value); err != nil && !lastInsertIdUnsupported(err) {
+			return err
+		}
+	}
+
+	removed := make([]interface{}, 0)
+	for key := range existing {
+		if !posted[key] {
+			removed = append(removed, key)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	err = tb.sql().WithTx(tx).Table(rel.Table).
+		Where(rel.ForeignKey, "=", id).
+		WhereIn(pk, removed).
+		Delete()
+	if err != nil && notNoAffectRow(err) {
+		return err
+	}
+	return nil
+}
+
+// hasManyValue return the values of the child fields of the posted child row.
+func hasManyValue(rel *types.HasManyForm, row form.Values) dialect.H {
+	value := make(dialect.H)
+	for _, child := range rel.Fields {
+		if child.Field == rel.GetPrimaryKey() || child.Field == rel.ForeignKey {
+			continue
+		}
+		v, ok := row[child.Field]
+		if !ok {
+			if !child.FormType.IsMultiSelect() {
+				continue
+			}
+			v = []string{""}
+		}
+
+		vv := modules.RemoveBlankFromArray(v)
+		if child.PostFilterFn != nil {
+			value[child.Field] = child.PostFilterFn(types.PostFieldModel{
+				ID:    row.Get(rel.GetPrimaryKey()),
+				Value: vv,
+			})
+			continue
+		}
+		value[child.Field] = strings.Join(vv, modules.SetDefault(child.DefaultOptionDelimiter, ","))
+	}
+	return value
+}
+
+// loadHasMany render the sub form of the has many field with the child rows
+// of the row in order.
+func (tb DefaultTable) loadHasMany(field *types.FormField, id string) error {
+	if field.HasMany == nil {
+		return nil
+	}
+	rel := field.HasMany
+	rows, err := tb.sql().Table(rel.Table).
+		Where(rel.ForeignKey, "=", id).
+		OrderBy(modules.SetDefault(rel.OrderField, rel.GetPrimaryKey()), "asc").
+		All()
+	if err != nil {
+		return err
+	}
+	field.CustomContent = rel.Content(field.Field, rows)
+	return nil
+}
+
+func lastInsertIdUnsupported(err error) bool {
+	return strings.Contains(err.Error(), "LastInsertId is not supported")
+}
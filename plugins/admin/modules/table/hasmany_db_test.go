@@ -0,0 +1,112 @@
+//go:build sqlite
+// +build sqlite
+
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/plugins/admin/modules/form"
+	"github.com/glvd/go-admin/template/types"
+	form2 "github.com/glvd/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSaveWithHasMany(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hasmany")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "admin.db")},
+	})
+	_, err = conn.Exec("create table orders (id integer primary key autoincrement, name text)")
+	assert.Nil(t, err)
+	_, err = conn.Exec("create table items (id integer primary key autoincrement, order_id integer, " +
+		"name text, sort integer)")
+	assert.Nil(t, err)
+
+	tb := DefaultTable{
+		info:             types.NewInfoPanel().SetTable("orders"),
+		form:             types.NewFormPanel().SetTable("orders"),
+		connectionDriver: db.DriverSqlite,
+		connection:       "default",
+		primaryKey:       PrimaryKey{Name: "id", Type: db.Int},
+		srv:              service.List{db.DriverSqlite: conn},
+	}
+	tb.form.AddField("Name", "name", db.Varchar, form2.Text)
+	tb.form.AddHasMany("Items", "items", types.HasManyForm{
+		Table:      "items",
+		ForeignKey: "order_id",
+		OrderField: "sort",
+		Fields: types.FormFields{
+			{Field: "id", TypeName: db.Int, FormType: form2.Default},
+			{Field: "name", TypeName: db.Varchar, FormType: form2.Text},
+		},
+	})
+
+	items := func() []map[string]interface{} {
+		rows, err := conn.Query("select id, order_id, name, sort from items order by sort")
+		assert.Nil(t, err)
+		return rows
+	}
+
+	id, err := tb.saveWithHasMany(form.Values{
+		"name":                          {"first"},
+		types.HasManyOrderName("items"): {"0", "1"},
+		"items[0][name]":                {"a"},
+		"items[1][name]":                {"b"},
+	}, tb.hasManyFields(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", id)
+
+	rows := items()
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "a", rows[0]["name"])
+	assert.Equal(t, int64(1), rows[1]["order_id"])
+
+	// The posted row is updated and moved, the row not posted is deleted.
+	_, err = tb.saveWithHasMany(form.Values{
+		"id":                            {"1"},
+		"name":                          {"second"},
+		types.HasManyOrderName("items"): {"0", "1"},
+		"items[0][id]":                  {relationKey(rows[1]["id"])},
+		"items[0][name]":                {"b2"},
+		"items[1][name]":                {"c"},
+	}, tb.hasManyFields(), false)
+	assert.Nil(t, err)
+
+	rows = items()
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "b2", rows[0]["name"])
+	assert.Equal(t, int64(0), rows[0]["sort"])
+	assert.Equal(t, "c", rows[1]["name"])
+
+	order, err := conn.Query("select name from orders where id = 1")
+	assert.Nil(t, err)
+	assert.Equal(t, "second", order[0]["name"])
+
+	// A failed child row rolls back the row and the other child rows.
+	tb.form.FieldList[1].HasMany.Fields = append(tb.form.FieldList[1].HasMany.Fields,
+		types.FormField{Field: "missing", TypeName: db.Varchar, FormType: form2.Text})
+	_, err = tb.saveWithHasMany(form.Values{
+		"name":                          {"third"},
+		types.HasManyOrderName("items"): {"0", "1"},
+		"items[0][name]":                {"d"},
+		"items[1][name]":                {"e"},
+		"items[1][missing]":             {"x"},
+	}, tb.hasManyFields(), true)
+	assert.NotNil(t, err)
+
+	count, err := conn.Query("select count(*) as count from orders")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count[0]["count"])
+	assert.Equal(t, 2, len(items()))
+}
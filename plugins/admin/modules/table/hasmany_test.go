@@ -0,0 +1,43 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/plugins/admin/modules/form"
+	"github.com/glvd/go-admin/template/types"
+	form2 "github.com/glvd/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestHasManyValue(t *testing.T) {
+	rel := &types.HasManyForm{
+		Table:      "items",
+		ForeignKey: "order_id",
+		Fields: types.FormFields{
+			{Field: "id", TypeName: db.Int, FormType: form2.Default},
+			{Field: "name", TypeName: db.Varchar, FormType: form2.Text},
+			{Field: "tags", TypeName: db.Varchar, FormType: form2.Select, DefaultOptionDelimiter: "|"},
+			{Field: "code", TypeName: db.Varchar, FormType: form2.Text,
+				PostFilterFn: func(value types.PostFieldModel) string {
+					return strings.ToUpper(value.Value.Value())
+				}},
+			{Field: "note", TypeName: db.Varchar, FormType: form2.Text},
+		},
+	}
+
+	value := hasManyValue(rel, form.Values{
+		"id":       {"3"},
+		"order_id": {"9"},
+		"name":     {"first"},
+		"tags":     {"a", "", "b"},
+		"code":     {"abc"},
+	})
+	assert.Equal(t, 3, len(value))
+	assert.Equal(t, "first", value["name"])
+	assert.Equal(t, "a|b", value["tags"])
+	assert.Equal(t, "ABC", value["code"])
+
+	value = hasManyValue(rel, form.Values{"name": {"second"}})
+	assert.Equal(t, "", value["tags"])
+}
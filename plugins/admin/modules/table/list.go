@@ -0,0 +1,139 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/service"
+	"sync"
+)
+
+// List is the tables of an admin instance generated by the generators.
+// The DefaultTable generated are bound to the services of the list.
+type List struct {
+	generators GeneratorList
+	tables     map[string]Table
+	sources    []Source
+	services   service.List
+	lock       sync.RWMutex
+}
+
+// Source provides the generators resolved at runtime, such as the tables
+// defined from the UI. The tables of a source are generated on each Get,
+// so that the changes take effect immediately. The compiled generators
+// take precedence over the sources.
+type Source interface {
+	Generator(key string) (Generator, bool)
+}
+
+// defaultList is the list of the package level functions.
+var defaultList = NewList()
+
+// NewList return an empty table list.
+func NewList() *List {
+	return &List{
+		generators: make(GeneratorList),
+		tables:     make(map[string]Table),
+	}
+}
+
+// SetGenerators update the generators of the list.
+func (l *List) SetGenerators(gens map[string]Generator) *List {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for key, gen := range gens {
+		l.generators[key] = gen
+	}
+	return l
+}
+
+// SetServices set the services of the tables.
+func (l *List) SetServices(srv service.List) *List {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.services = srv
+	return l
+}
+
+// Services return the services of the list.
+func (l *List) Services() service.List {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.services
+}
+
+// AddSource add a source of the generators resolved at runtime.
+func (l *List) AddSource(src Source) *List {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.sources = append(l.sources, src)
+	return l
+}
+
+// Get return the table of the key, which is generated by the compiled
+// generators or the sources, nil if not found.
+func (l *List) Get(key string) Table {
+	l.lock.RLock()
+	tb, ok := l.tables[key]
+	sources, srv := l.sources, l.services
+	l.lock.RUnlock()
+	if ok {
+		return tb
+	}
+	for _, src := range sources {
+		if gen, ok := src.Generator(key); ok {
+			return bind(gen(), srv)
+		}
+	}
+	return nil
+}
+
+// Has return true if the key is of a compiled generator.
+func (l *List) Has(key string) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	if _, ok := l.generators[key]; ok {
+		return true
+	}
+	_, ok := l.tables[key]
+	return ok
+}
+
+// Init generate the tables.
+func (l *List) Init() {
+	l.Refresh()
+}
+
+// Refresh generate the tables again when the table relationship changed.
+func (l *List) Refresh() {
+	l.lock.RLock()
+	gens := make(GeneratorList, len(l.generators))
+	for key, gen := range l.generators {
+		gens[key] = gen
+	}
+	srv := l.services
+	l.lock.RUnlock()
+
+	// the generators may get the other tables, so they are called without
+	// the lock held.
+	tables := make(map[string]Table, len(gens))
+	for key, gen := range gens {
+		tables[key] = bind(gen(), srv)
+	}
+
+	l.lock.Lock()
+	for key, tb := range tables {
+		l.tables[key] = tb
+	}
+	l.lock.Unlock()
+}
+
+func bind(tb Table, srv service.List) Table {
+	if dt, ok := tb.(DefaultTable); ok && dt.srv == nil && srv != nil {
+		dt.srv = srv
+		return dt
+	}
+	return tb
+}
+
+// DefaultList return the list of the package level functions.
+func DefaultList() *List {
+	return defaultList
+}
@@ -0,0 +1,78 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	var (
+		srv   = service.List{}
+		list  = NewList().SetServices(srv)
+		count = 0
+	)
+
+	list.SetGenerators(GeneratorList{
+		"posts": func() Table {
+			count++
+			return NewDefaultTable(DefaultConfig())
+		},
+	})
+
+	assert.Nil(t, list.Get("posts"))
+
+	list.Init()
+	assert.Equal(t, 1, count)
+	assert.Equal(t, srv, list.Get("posts").(DefaultTable).services())
+	assert.Equal(t, srv, list.Get("posts").Copy().(DefaultTable).services())
+
+	list.Refresh()
+	assert.Equal(t, 2, count)
+
+	assert.Nil(t, Get("posts"))
+}
+
+type sourceFunc func(key string) (Generator, bool)
+
+func (f sourceFunc) Generator(key string) (Generator, bool) {
+	return f(key)
+}
+
+func TestList_AddSource(t *testing.T) {
+	var (
+		srv    = service.List{}
+		list   = NewList().SetServices(srv)
+		titles = map[string]string{"dynamic": "Dynamic"}
+	)
+
+	list.SetGenerators(GeneratorList{
+		"posts": func() Table {
+			return NewDefaultTable(DefaultConfig())
+		},
+	})
+	list.Init()
+
+	list.AddSource(sourceFunc(func(key string) (Generator, bool) {
+		title, ok := titles[key]
+		if !ok {
+			return nil, false
+		}
+		return func() Table {
+			tb := NewDefaultTable(DefaultConfig())
+			tb.GetInfo().SetTitle(title)
+			return tb
+		}, true
+	}))
+
+	assert.True(t, list.Has("posts"))
+	assert.False(t, list.Has("dynamic"))
+	assert.Nil(t, list.Get("unknown"))
+
+	tb := list.Get("dynamic")
+	assert.Equal(t, "Dynamic", tb.GetInfo().Title)
+	assert.Equal(t, srv, tb.(DefaultTable).services())
+
+	titles["dynamic"] = "Changed"
+	assert.Equal(t, "Changed", list.Get("dynamic").GetInfo().Title)
+}
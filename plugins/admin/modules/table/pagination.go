@@ -0,0 +1,275 @@
+package table
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
+	"github.com/glvd/go-admin/template/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cursor is the position of a row in the keyset pagination: the value of
+// the sort field and the primary key.
+type cursor struct {
+	Field string `json:"f"`
+	Value string `json:"v"`
+	Key   string `json:"k"`
+}
+
+// encodeCursor return the url safe cursor.
+func encodeCursor(c cursor) string {
+	value, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(value)
+}
+
+// decodeCursor return the cursor of the value, false if the value is not
+// a cursor of the sort field.
+func decodeCursor(value, sortField string) (cursor, bool) {
+	if value == "" {
+		return cursor{}, false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor{}, false
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Field != sortField {
+		return cursor{}, false
+	}
+	return c, true
+}
+
+// cursorValue return the value of the column as the argument of the
+// cursor condition.
+func cursorValue(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return t.Format("2006-01-02 15:04:05.999999")
+	}
+	return relationKey(value)
+}
+
+// notNull return true if the column of the table is declared not null by
+// the columns model. The keyset pagination is only used on the not null
+// columns, as the rows of the null values are not compared by the cursor.
+func (tb DefaultTable) notNull(columnsModel []map[string]interface{}, field string) bool {
+	if field == tb.primaryKey.Name {
+		return true
+	}
+	for _, model := range columnsModel {
+		switch tb.connectionDriver {
+		case db.DriverMysql:
+			if relationKey(model["Field"]) == field {
+				return relationKey(model["Null"]) == "NO"
+			}
+		case db.DriverSqlite:
+			if relationKey(model["name"]) == field {
+				return relationKey(model["notnull"]) == "1"
+			}
+		default:
+			if relationKey(model["column_name"]) == field {
+				return strings.ToUpper(relationKey(model["is_nullable"])) == "NO"
+			}
+		}
+	}
+	return false
+}
+
+// keysetStatement return the statement of the page of the keyset
+// pagination and its arguments. The rows are queried after the cursor of
+// params.After in the sort order, or before the cursor of params.Before in
+// the reverse order when backward is true, one more row than the page size
+// is queried to know whether there are more rows.
+func (tb DefaultTable) keysetStatement(driver, del, fields, joins, wheres, groupBy, sortField string,
+	whereArgs []interface{}, params parameter.Parameters) (statement string, args []interface{}, backward bool) {
+
+	var (
+		sortCol  = tb.info.Table + "." + filterFiled(sortField, del)
+		keyCol   = tb.info.Table + "." + filterFiled(tb.primaryKey.Name, del)
+		desc     = params.SortType != "asc"
+		pageSize = 0
+	)
+
+	c, ok := decodeCursor(params.After, sortField)
+	if !ok {
+		c, ok = decodeCursor(params.Before, sortField)
+		backward = ok
+	}
+	if backward {
+		desc = !desc
+	}
+
+	direction, compare := "asc", ">"
+	if desc {
+		direction, compare = "desc", "<"
+	}
+
+	condArgs := make([]interface{}, 0, 3)
+	if ok {
+		condition := ""
+		if sortField == tb.primaryKey.Name {
+			condition = keyCol + " " + compare + " ?"
+			condArgs = append(condArgs, c.Key)
+		} else {
+			condition = "(" + sortCol + " " + compare + " ? or (" + sortCol + " = ? and " +
+				keyCol + " " + compare + " ?))"
+			condArgs = append(condArgs, c.Value, c.Value, c.Key)
+		}
+		if wheres == "" {
+			wheres = " where " + condition
+		} else {
+			wheres += " and " + condition
+		}
+	}
+
+	orderBy := " order by " + keyCol + " " + direction
+	if sortField != tb.primaryKey.Name {
+		if !inArray(strings.Split(fields, ","), sortCol) {
+			fields += ", " + sortCol
+		}
+		orderBy = " order by " + sortCol + " " + direction + ", " + keyCol + " " + direction
+	}
+
+	pageSize, _ = strconv.Atoi(params.PageSize)
+
+	from := " from " + delimiter(del, tb.info.Table) + joins + wheres + groupBy + orderBy
+	if driver == db.DriverMssql {
+		args = append(append([]interface{}{pageSize + 1}, whereArgs...), condArgs...)
+		return "select top (?) " + fields + from, args, backward
+	}
+	args = append(append(append(make([]interface{}, 0, len(whereArgs)+4), whereArgs...), condArgs...), pageSize+1)
+	return "select " + fields + from + " LIMIT ?", args, backward
+}
+
+// keysetPage trim the extra row of the page, restore the order of the rows
+// queried backward, and return the route parameters of the previous and
+// the next pages, which are empty if there are no more rows.
+func (tb DefaultTable) keysetPage(res []map[string]interface{}, params parameter.Parameters, sortField string,
+	backward bool) ([]map[string]interface{}, string, string) {
+
+	pageSize, _ := strconv.Atoi(params.PageSize)
+	more := len(res) > pageSize
+	if more {
+		res = res[:pageSize]
+	}
+
+	if backward {
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+	}
+
+	hasPrevious, hasNext := params.After != "", more
+	if backward {
+		hasPrevious, hasNext = more, true
+	}
+	if len(res) == 0 {
+		return res, "", ""
+	}
+
+	page, _ := strconv.Atoi(params.Page)
+	if page < 1 || (params.After == "" && !backward) || (backward && !more) {
+		page = 1
+	}
+
+	rowCursor := func(row map[string]interface{}) string {
+		return encodeCursor(cursor{
+			Field: sortField,
+			Value: cursorValue(row[sortField]),
+			Key:   cursorValue(row[tb.primaryKey.Name]),
+		})
+	}
+
+	previous, next := "", ""
+	if hasPrevious && page > 1 {
+		previous = params.SetCursor("", rowCursor(res[0])).SetPage(strconv.Itoa(page - 1)).GetRouteParamStr()
+	}
+	if hasNext {
+		next = params.SetCursor(rowCursor(res[len(res)-1]), "").SetPage(strconv.Itoa(page + 1)).GetRouteParamStr()
+	}
+	return res, previous, next
+}
+
+// count return the total count of the rows of the list by the count mode,
+// -1 means the count is skipped. The approximate count is estimated by the
+// statistics of the table without the conditions, and skipped with the
+// conditions.
+func (tb DefaultTable) count(conn db.Connection, params parameter.Parameters, tables []string,
+	countStatement, wheres string, whereArgs []interface{}, mode types.CountMode) (int, error) {
+
+	if mode == types.CountSkip || (mode == types.CountApproximate && wheres != "") {
+		return -1, nil
+	}
+	if mode == types.CountApproximate && tb.connectionDriver != db.DriverSqlite {
+		return tb.approximateCount(conn, params)
+	}
+
+	// TODO: use the dialect
+
+	countCmd := fmt.Sprintf(countStatement, tb.info.Table, wheres)
+
+	total, err := tb.query(conn, params, tables, countCmd, whereArgs...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	logger.LogSQL(countCmd, nil)
+
+	var size int
+	if tb.connectionDriver == "postgresql" {
+		size = int(total[0]["count"].(int64))
+	} else if tb.connectionDriver == "mssql" {
+		size = int(total[0]["size"].(int64))
+	} else {
+		size = int(total[0]["count(*)"].(int64))
+	}
+	return size, nil
+}
+
+// approximateCount return the count of the rows of the table estimated by
+// the statistics of the database.
+func (tb DefaultTable) approximateCount(conn db.Connection, params parameter.Parameters) (int, error) {
+	var statement string
+	switch tb.connectionDriver {
+	case db.DriverMysql:
+		statement = "select table_rows as count from information_schema.tables " +
+			"where table_schema = database() and table_name = ?"
+	case db.DriverPostgresql:
+		statement = "select cast(reltuples as bigint) as count from pg_class where relname = ?"
+	case db.DriverMssql:
+		statement = "select sum(rows) as count from sys.partitions " +
+			"where object_id = object_id(?) and index_id in (0, 1)"
+	default:
+		return -1, nil
+	}
+
+	res, err := tb.query(conn, params, []string{tb.info.Table}, statement, tb.info.Table)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return -1, nil
+	}
+	size, err := strconv.Atoi(relationKey(res[0]["count"]))
+	if err != nil || size < 0 {
+		// The statistics of postgresql are -1 before the table is analyzed.
+		return -1, nil
+	}
+	return size, nil
+}
+
+// countText return the displayed total count.
+func countText(size int, approximate bool) string {
+	if size < 0 {
+		return "-"
+	}
+	if approximate {
+		return "~" + strconv.Itoa(size)
+	}
+	return strconv.Itoa(size)
+}
@@ -0,0 +1,76 @@
+//go:build sqlite
+// +build sqlite
+
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
+	"github.com/glvd/go-admin/template/types"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestDefaultTable_KeysetPaginationNull(t *testing.T) {
+	config.Set(config.Config{Theme: "permission-theme"})
+
+	dir, err := ioutil.TempDir("", "pagination")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "admin.db")},
+	})
+	_, err = conn.Exec("create table events (id integer primary key autoincrement, " +
+		"seq integer, rank integer not null)")
+	assert.Nil(t, err)
+	_, err = conn.Exec("insert into events (seq, rank) values (1, 5), (null, 4), (2, 3), (null, 2), (3, 1)")
+	assert.Nil(t, err)
+
+	tb := DefaultTable{
+		info:             types.NewInfoPanel().SetTable("events").UseKeysetPagination(),
+		form:             types.NewFormPanel().SetTable("events"),
+		detail:           types.NewDetailPanel(),
+		connectionDriver: db.DriverSqlite,
+		connection:       "default",
+		primaryKey:       PrimaryKey{Name: "id", Type: db.Int},
+		srv:              service.List{db.DriverSqlite: conn},
+	}
+	tb.info.AddField("ID", "id", db.Int)
+	tb.info.AddField("Seq", "seq", db.Int)
+	tb.info.AddField("Rank", "rank", db.Int)
+
+	ids := func(query string) []string {
+		values, _ := url.ParseQuery(query)
+		info, err := tb.GetDataFromDatabase("/info/events",
+			parameter.GetParam(values, 2, "id", "asc"), false)
+		assert.Nil(t, err)
+		res := make([]string, len(info.InfoList))
+		for i, row := range info.InfoList {
+			res[i] = string(row["id"])
+		}
+		return res
+	}
+
+	// The nullable column is paginated by the offset, the rows of the null
+	// values are not skipped.
+	all := make([]string, 0)
+	for _, page := range []string{"1", "2", "3"} {
+		all = append(all, ids("__sort=seq&__sort_type=asc&__pageSize=2&__page="+page)...)
+	}
+	sort.Strings(all)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, all)
+
+	// The not null column is paginated by the cursor, the page without the
+	// cursor is the first one.
+	assert.Equal(t, []string{"5", "4"}, ids("__sort=rank&__sort_type=asc&__pageSize=2&__page=2"))
+}
@@ -0,0 +1,150 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
+	"github.com/glvd/go-admin/template/types"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	c := cursor{Field: "created_at", Value: "2020-01-02 03:04:05", Key: "5"}
+
+	res, ok := decodeCursor(encodeCursor(c), "created_at")
+	assert.True(t, ok)
+	assert.Equal(t, c, res)
+
+	_, ok = decodeCursor(encodeCursor(c), "name")
+	assert.False(t, ok)
+	_, ok = decodeCursor("not a cursor", "created_at")
+	assert.False(t, ok)
+	_, ok = decodeCursor("", "created_at")
+	assert.False(t, ok)
+
+	assert.Equal(t, "2020-01-02 03:04:05.5", cursorValue(time.Date(2020, 1, 2, 3, 4, 5, 5e8, time.UTC)))
+	assert.Equal(t, "5", cursorValue(int64(5)))
+	assert.Equal(t, "abc", cursorValue([]byte("abc")))
+}
+
+func TestKeysetStatement(t *testing.T) {
+	var (
+		tb = DefaultTable{
+			info:       &types.InfoPanel{Table: "users"},
+			primaryKey: PrimaryKey{Name: "id"},
+		}
+		params = parameter.Parameters{Page: "1", PageSize: "10", SortField: "created_at", SortType: "desc"}
+		fields = "users.`name`,users.`id`"
+		wheres = " where users.`state` = ?"
+		args   = []interface{}{"1"}
+		after  = encodeCursor(cursor{Field: "created_at", Value: "2020-01-02 03:04:05", Key: "5"})
+	)
+
+	statement, res, backward := tb.keysetStatement(db.DriverMysql, "`", fields, "", wheres, "", "created_at",
+		args, params)
+	assert.False(t, backward)
+	assert.Equal(t, "select users.`name`,users.`id`, users.`created_at` from `users` where users.`state` = ? "+
+		"order by users.`created_at` desc, users.`id` desc LIMIT ?", statement)
+	assert.Equal(t, []interface{}{"1", 11}, res)
+
+	statement, res, backward = tb.keysetStatement(db.DriverMysql, "`", fields, "", wheres, "", "created_at",
+		args, params.SetCursor(after, ""))
+	assert.False(t, backward)
+	assert.Equal(t, "select users.`name`,users.`id`, users.`created_at` from `users` where users.`state` = ? "+
+		"and (users.`created_at` < ? or (users.`created_at` = ? and users.`id` < ?)) "+
+		"order by users.`created_at` desc, users.`id` desc LIMIT ?", statement)
+	assert.Equal(t, []interface{}{"1", "2020-01-02 03:04:05", "2020-01-02 03:04:05", "5", 11}, res)
+
+	statement, res, backward = tb.keysetStatement(db.DriverMssql, "[", fields, "", "", "", "created_at",
+		nil, params.SetCursor("", after))
+	assert.True(t, backward)
+	assert.Equal(t, "select top (?) users.`name`,users.`id`, users.created_at from [users] "+
+		"where (users.created_at > ? or (users.created_at = ? and users.id > ?)) "+
+		"order by users.created_at asc, users.id asc", statement)
+	assert.Equal(t, []interface{}{11, "2020-01-02 03:04:05", "2020-01-02 03:04:05", "5"}, res)
+
+	params.SortField, params.SortType = "id", "asc"
+	statement, res, _ = tb.keysetStatement(db.DriverMysql, "`", fields, "", "", "", "id",
+		nil, params.SetCursor(encodeCursor(cursor{Field: "id", Key: "5"}), ""))
+	assert.Equal(t, "select users.`name`,users.`id` from `users` where users.`id` > ? "+
+		"order by users.`id` asc LIMIT ?", statement)
+	assert.Equal(t, []interface{}{"5", 11}, res)
+}
+
+func TestKeysetPage(t *testing.T) {
+	var (
+		tb     = DefaultTable{info: &types.InfoPanel{Table: "users"}, primaryKey: PrimaryKey{Name: "id"}}
+		params = parameter.Parameters{Page: "2", PageSize: "2", SortField: "id", SortType: "desc"}
+		rows   = func(ids ...int64) []map[string]interface{} {
+			res := make([]map[string]interface{}, len(ids))
+			for i, id := range ids {
+				res[i] = map[string]interface{}{"id": id}
+			}
+			return res
+		}
+		key = func(route, name string) string {
+			for _, item := range strings.Split(strings.TrimPrefix(route, "?"), "&") {
+				if strings.HasPrefix(item, name+"=") {
+					return strings.TrimPrefix(item, name+"=")
+				}
+			}
+			return ""
+		}
+	)
+
+	res, previous, next := tb.keysetPage(rows(8, 7, 6), params.SetCursor("x", ""), "id", false)
+	assert.Equal(t, rows(8, 7), res)
+	assert.Equal(t, "1", key(previous, "__page"))
+	c, _ := decodeCursor(key(previous, "__before"), "id")
+	assert.Equal(t, "8", c.Key)
+	assert.Equal(t, "3", key(next, "__page"))
+	c, _ = decodeCursor(key(next, "__after"), "id")
+	assert.Equal(t, "7", c.Key)
+
+	res, previous, next = tb.keysetPage(rows(9, 10), params.SetCursor("", "x"), "id", true)
+	assert.Equal(t, rows(10, 9), res)
+	assert.Equal(t, "", previous)
+	assert.Equal(t, "2", key(next, "__page"))
+
+	res, previous, next = tb.keysetPage(rows(8), params.SetPage("1"), "id", false)
+	assert.Equal(t, rows(8), res)
+	assert.Equal(t, "", previous)
+	assert.Equal(t, "", next)
+}
+
+func TestCountText(t *testing.T) {
+	assert.Equal(t, "-", countText(-1, false))
+	assert.Equal(t, "~120", countText(120, true))
+	assert.Equal(t, "120", countText(120, false))
+}
+
+func TestNotNull(t *testing.T) {
+	tb := DefaultTable{primaryKey: PrimaryKey{Name: "id"}, connectionDriver: db.DriverMysql}
+	mysql := []map[string]interface{}{
+		{"Field": "id", "Null": "NO"},
+		{"Field": "created_at", "Null": "NO"},
+		{"Field": "deleted_at", "Null": "YES"},
+	}
+	assert.True(t, tb.notNull(mysql, "id"))
+	assert.True(t, tb.notNull(mysql, "created_at"))
+	assert.False(t, tb.notNull(mysql, "deleted_at"))
+	assert.False(t, tb.notNull(mysql, "name"))
+
+	tb.connectionDriver = db.DriverSqlite
+	sqlite := []map[string]interface{}{
+		{"name": "created_at", "notnull": int64(1)},
+		{"name": "deleted_at", "notnull": int64(0)},
+	}
+	assert.True(t, tb.notNull(sqlite, "created_at"))
+	assert.False(t, tb.notNull(sqlite, "deleted_at"))
+
+	tb.connectionDriver = db.DriverPostgresql
+	postgresql := []map[string]interface{}{
+		{"column_name": "created_at", "is_nullable": "NO"},
+		{"column_name": "deleted_at", "is_nullable": []byte("YES")},
+	}
+	assert.True(t, tb.notNull(postgresql, "created_at"))
+	assert.False(t, tb.notNull(postgresql, "deleted_at"))
+}
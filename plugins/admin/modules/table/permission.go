@@ -0,0 +1,199 @@
+package table
+
+import (
+	"errors"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/glvd/go-admin/template/types"
+	"strings"
+)
+
+// rowScope is the row scopes of the info panel applied to a user.
+type rowScope struct {
+	user   models.UserModel
+	scopes []types.RowScope
+}
+
+// Valid return true if the rows are restricted.
+func (s rowScope) Valid() bool {
+	return len(s.scopes) > 0
+}
+
+// where return the condition of the row scopes of which the columns are
+// qualified by the table and its arguments, the condition is empty if the
+// rows are not restricted.
+func (s rowScope) where(table, del string) (string, []interface{}) {
+	var (
+		column     = func(name string) string { return table + "." + filterFiled(name, del) }
+		conditions = make([]string, 0, len(s.scopes))
+		args       = make([]interface{}, 0)
+	)
+	for _, scope := range s.scopes {
+		condition, scopeArgs := scope(s.user, column)
+		if condition == "" {
+			continue
+		}
+		conditions = append(conditions, "("+condition+")")
+		args = append(args, scopeArgs...)
+	}
+	return strings.Join(conditions, " and "), args
+}
+
+// scopeWhere return the condition of the row scopes of the table and its
+// arguments, see rowScope.where.
+func (tb DefaultTable) scopeWhere(table string) (string, []interface{}) {
+	if !tb.scope.Valid() {
+		return "", nil
+	}
+	return tb.scope.where(table, tb.db().GetDelimiter())
+}
+
+// withScope add the condition of the row scopes of the table to the
+// statement.
+func (tb DefaultTable) withScope(statement *db.SQL, table string) *db.SQL {
+	if condition, args := tb.scopeWhere(table); condition != "" {
+		return statement.WhereRaw(condition, args...)
+	}
+	return statement
+}
+
+// WithUser return the table restricted to the user: the fields the user can
+// not see are removed, the fields the user can not change are read-only and
+// ignored on saving, and the row scopes of the info panel are applied to the
+// queries of the list, the export, the detail, the edit, the update and the
+// deletion. The filters, the sort and the search of the list are restricted
+// to the fields the user can see, see queryable.
+func (tb DefaultTable) WithUser(user models.UserModel) Table {
+	denied := make(map[string]bool)
+
+	info := *tb.info
+	info.FieldList = make(types.FieldList, 0, len(tb.info.FieldList))
+	hidden := make([]string, 0)
+	for _, field := range tb.info.FieldList {
+		if field.VisibleTo.Allow(user) {
+			info.FieldList = append(info.FieldList, field)
+		} else {
+			hidden = append(hidden, field.Field)
+		}
+	}
+	info.TabGroups = withoutFields(tb.info.TabGroups, hidden)
+	tb.info = &info
+
+	form := *tb.form
+	form.FieldList = make(types.FormFields, 0, len(tb.form.FieldList))
+	hidden = make([]string, 0)
+	for _, field := range tb.form.FieldList {
+		if !field.VisibleTo.Allow(user) {
+			denied[field.Field] = true
+			hidden = append(hidden, field.Field)
+			continue
+		}
+		if !field.EditableBy.Allow(user) {
+			denied[field.Field] = true
+			field.Editable = false
+			field.NotAllowAdd = true
+		}
+		form.FieldList = append(form.FieldList, field)
+	}
+	form.TabGroups = withoutFields(tb.form.TabGroups, hidden)
+	tb.form = &form
+
+	detail := *tb.detail
+	detail.FieldList = make(types.FieldList, 0, len(tb.detail.FieldList))
+	hidden = make([]string, 0)
+	for _, field := range tb.detail.FieldList {
+		if field.VisibleTo.Allow(user) {
+			detail.FieldList = append(detail.FieldList, field)
+		} else {
+			hidden = append(hidden, field.Field)
+		}
+	}
+	detail.TabGroups = withoutFields(tb.detail.TabGroups, hidden)
+	tb.detail = &detail
+
+	tb.denied = denied
+	tb.restricted = true
+	tb.scope = rowScope{}
+	if !user.IsSuperAdmin() && len(tb.info.RowScopes) > 0 {
+		tb.scope = rowScope{user: user, scopes: tb.info.RowScopes}
+	}
+
+	return tb
+}
+
+// queryable return the columns which the requests can filter, sort and
+// search by. After WithUser, they are restricted to the primary key and the
+// fields of the list visible to the user, so that the fields the user can
+// not see can not be guessed by the filters.
+func (tb DefaultTable) queryable(columns Columns) Columns {
+	if !tb.restricted {
+		return columns
+	}
+	res := make(Columns, 0, len(columns))
+	for _, column := range columns {
+		if column == tb.primaryKey.Name {
+			res = append(res, column)
+			continue
+		}
+		for _, field := range tb.info.FieldList {
+			if field.Field == column && !field.Join.Valid() {
+				res = append(res, column)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// checkRowScope return an error if any row of the ids is out of the row
+// scopes. The statements of the update and the deletion are restricted by
+// the row scopes too, the check reports the rows out of the scopes.
+func (tb DefaultTable) checkRowScope(ids ...string) error {
+	if !tb.scope.Valid() {
+		return nil
+	}
+
+	var (
+		keys = make([]interface{}, 0, len(ids))
+		seen = make(map[string]bool, len(ids))
+	)
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			keys = append(keys, id)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	res, err := tb.withScope(tb.sql().Table(tb.form.Table).
+		Select(tb.primaryKey.Name).
+		WhereIn(tb.primaryKey.Name, keys), tb.form.Table).
+		All()
+	if err != nil {
+		return err
+	}
+	if len(res) != len(keys) {
+		return errors.New(language.Get("permission denied"))
+	}
+	return nil
+}
+
+// withoutFields return the tab groups without the fields.
+func withoutFields(groups types.TabGroups, fields []string) types.TabGroups {
+	if len(fields) == 0 || len(groups) == 0 {
+		return groups
+	}
+	res := make(types.TabGroups, len(groups))
+	for i, group := range groups {
+		res[i] = make([]string, 0, len(group))
+		for _, field := range group {
+			if !inArray(fields, field) {
+				res[i] = append(res[i], field)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+//go:build sqlite
+// +build sqlite
+
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
+	"github.com/glvd/go-admin/template"
+	"github.com/glvd/go-admin/template/components"
+	"github.com/glvd/go-admin/template/types"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// permissionTheme is the theme of which only the paginator of the list is
+// used.
+type permissionTheme struct {
+	template.Template
+}
+
+func (permissionTheme) Paginator() types.PaginatorAttribute {
+	return new(components.PaginatorAttribute)
+}
+
+func init() {
+	template.Add("permission-theme", permissionTheme{})
+}
+
+func TestDefaultTable_WithUserQueryable(t *testing.T) {
+	config.Set(config.Config{Theme: "permission-theme"})
+
+	dir, err := ioutil.TempDir("", "permission")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "admin.db")},
+	})
+	_, err = conn.Exec("create table orders (id integer primary key autoincrement, amount integer, cost integer)")
+	assert.Nil(t, err)
+	_, err = conn.Exec("insert into orders (amount, cost) values (10, 200), (20, 100)")
+	assert.Nil(t, err)
+
+	tb := DefaultTable{
+		info:             types.NewInfoPanel().SetTable("orders"),
+		form:             types.NewFormPanel().SetTable("orders"),
+		detail:           types.NewDetailPanel(),
+		connectionDriver: db.DriverSqlite,
+		connection:       "default",
+		primaryKey:       PrimaryKey{Name: "id", Type: db.Int},
+		srv:              service.List{db.DriverSqlite: conn},
+	}
+	tb.info.AddField("ID", "id", db.Int)
+	tb.info.AddField("Amount", "amount", db.Int)
+	tb.info.AddField("Cost", "cost", db.Int).FieldVisibleTo(types.GrantRoles("finance"))
+	tb.info.SetSearchFields("cost")
+
+	var (
+		sales   = tb.WithUser(models.UserModel{Id: 2, Roles: []models.RoleModel{{Slug: "sales"}}})
+		finance = tb.WithUser(models.UserModel{Id: 3, Roles: []models.RoleModel{{Slug: "finance"}}})
+		ids     = func(table Table, query string) []string {
+			values, _ := url.ParseQuery(query)
+			info, err := table.GetDataFromDatabase("/info/orders",
+				parameter.GetParam(values, 10, "id", "asc"), false)
+			assert.Nil(t, err)
+			res := make([]string, len(info.InfoList))
+			for i, row := range info.InfoList {
+				res[i] = string(row["id"])
+			}
+			return res
+		}
+	)
+
+	assert.Equal(t, []string{"1", "2"}, ids(sales, "cost=100"))
+	assert.Equal(t, []string{"1", "2"}, ids(sales, "__sort=cost&__sort_type=asc"))
+	assert.Equal(t, []string{"1", "2"}, ids(sales, "__search=100"))
+	assert.Equal(t, []string{"2"}, ids(sales, "amount=20"))
+
+	assert.Equal(t, []string{"2"}, ids(finance, "cost=100"))
+	assert.Equal(t, []string{"2", "1"}, ids(finance, "__sort=cost&__sort_type=asc"))
+	assert.Equal(t, []string{"2"}, ids(finance, "__search=100"))
+}
@@ -0,0 +1,66 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/glvd/go-admin/template/types"
+	form2 "github.com/glvd/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func permissionTable() Table {
+	tb := NewDefaultTable(DefaultConfig())
+
+	info := tb.GetInfo().SetTable("orders")
+	info.AddField("ID", "id", db.Int)
+	info.AddField("Amount", "amount", db.Int)
+	info.AddField("Cost", "cost", db.Int).FieldVisibleTo(types.GrantRoles("finance"))
+	info.AddRowScope(types.ScopeOwner("owner_id"))
+	info.AddRowScope(types.ScopeIn("region", func(user models.UserModel) []interface{} {
+		return []interface{}{"north", "south"}
+	}))
+	info.AddRowScope(func(user models.UserModel, column types.ColumnFunc) (string, []interface{}) {
+		return "", nil
+	})
+
+	formList := tb.GetForm().SetTable("orders")
+	formList.AddField("Amount", "amount", db.Int, form2.Number)
+	formList.AddField("Cost", "cost", db.Int, form2.Number).FieldVisibleTo(types.GrantRoles("finance"))
+	formList.AddField("Status", "status", db.Varchar, form2.Text).FieldEditableBy(types.GrantPermissions("order.status"))
+	formList.SetTabGroups(types.NewTabGroups("amount", "cost").AddGroup("status"))
+
+	return tb
+}
+
+func TestDefaultTable_WithUser(t *testing.T) {
+	tb := permissionTable()
+	user := models.UserModel{Id: 7, Roles: []models.RoleModel{{Slug: "sales"}}}
+
+	restricted := tb.WithUser(user).(DefaultTable)
+	assert.Equal(t, 2, len(restricted.GetInfo().FieldList))
+	assert.Equal(t, 3, len(tb.GetInfo().FieldList))
+
+	assert.Equal(t, 2, len(restricted.GetForm().FieldList))
+	assert.False(t, restricted.GetForm().FieldList[1].Editable)
+	assert.True(t, restricted.GetForm().FieldList[1].NotAllowAdd)
+	assert.True(t, tb.GetForm().FieldList[2].Editable)
+	assert.Equal(t, types.TabGroups{{"amount"}, {"status"}}, restricted.GetForm().TabGroups)
+
+	condition, args := restricted.scope.where("orders", "`")
+	assert.Equal(t, "(orders.`owner_id` = ?) and (orders.`region` in (?,?))", condition)
+	assert.Equal(t, []interface{}{int64(7), "north", "south"}, args)
+	condition, _ = restricted.scope.where("orders", "[")
+	assert.Equal(t, "(orders.owner_id = ?) and (orders.region in (?,?))", condition)
+	assert.Equal(t, 3, len(restricted.Copy().(DefaultTable).scope.scopes))
+
+	assert.Equal(t, true, restricted.denied["cost"])
+	assert.Equal(t, true, restricted.denied["status"])
+	assert.Equal(t, false, restricted.denied["amount"])
+
+	admin := models.UserModel{Id: 1, Permissions: []models.PermissionModel{{HttpPath: []string{"*"}}}}
+	unrestricted := tb.WithUser(admin).(DefaultTable)
+	assert.Equal(t, 3, len(unrestricted.GetInfo().FieldList))
+	assert.False(t, unrestricted.scope.Valid())
+	assert.Equal(t, 0, len(unrestricted.denied))
+}
@@ -0,0 +1,233 @@
+package table
+
+import (
+	"fmt"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/plugins/admin/modules/parameter"
+	"github.com/glvd/go-admin/template"
+	"github.com/glvd/go-admin/template/types"
+	template2 "html/template"
+	"strings"
+)
+
+// relationBatchSize is the max count of the keys queried in one statement,
+// which keeps the arguments under the limit of mssql.
+const relationBatchSize = 1000
+
+// relationSortPrefix is the prefix of the alias of the sort expression of
+// the relation fields.
+const relationSortPrefix = "__goadmin_sort_"
+
+// loadRelations load the related rows of the relation fields for all the
+// rows with one query per field, and store them in the rows by
+// types.RelationRowKey.
+func (tb DefaultTable) loadRelations(conn db.Connection, params parameter.Parameters, rows []map[string]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for _, field := range tb.info.FieldList {
+		if field.Hide || !field.Relation.Valid() {
+			continue
+		}
+
+		var (
+			rel      = field.Relation
+			ownerKey = tb.primaryKey.Name
+		)
+		if rel.Type == types.BelongsTo {
+			ownerKey = rel.ForeignKey
+		}
+
+		keys := make([]interface{}, 0, len(rows))
+		seen := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			key := relationKey(row[ownerKey])
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, row[ownerKey])
+		}
+
+		values := make(map[string]types.RelationValues)
+		for start := 0; start < len(keys); start += relationBatchSize {
+			end := start + relationBatchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+
+			statement, tables := tb.relationStatement(conn.GetDelimiter(), rel, end-start)
+			logger.LogSQL(statement, keys[start:end])
+
+			res, err := tb.query(conn, params, tables, statement, keys[start:end]...)
+			if err != nil {
+				return err
+			}
+			for _, item := range res {
+				owner := relationKey(item["goadmin_owner"])
+				values[owner] = append(values[owner], types.RelationValue{
+					Key:   relationKey(item["goadmin_key"]),
+					Label: relationKey(item["goadmin_label"]),
+				})
+			}
+		}
+
+		for _, row := range rows {
+			row[types.RelationRowKey(field.Field)] = values[relationKey(row[ownerKey])]
+		}
+	}
+
+	return nil
+}
+
+// relationStatement return the statement querying the related rows of count
+// keys, and the tables of the statement.
+func (tb DefaultTable) relationStatement(del string, rel types.Relation, count int) (string, []string) {
+	var (
+		placeholders = strings.TrimSuffix(strings.Repeat("?,", count), ",")
+		related      = filterFiled(rel.Table, del)
+		key          = related + "." + filterFiled(rel.GetKey(), del)
+		label        = related + "." + filterFiled(rel.Field, del)
+	)
+
+	switch rel.Type {
+	case types.HasMany:
+		owner := related + "." + filterFiled(rel.ForeignKey, del)
+		return "select " + owner + " as goadmin_owner, " + key + " as goadmin_key, " + label + " as goadmin_label" +
+			" from " + related + " where " + owner + " in (" + placeholders + ") order by " + key, []string{rel.Table}
+	case types.ManyToMany:
+		pivot := filterFiled(rel.Pivot, del)
+		owner := pivot + "." + filterFiled(rel.ForeignKey, del)
+		return "select " + owner + " as goadmin_owner, " + key + " as goadmin_key, " + label + " as goadmin_label" +
+				" from " + pivot + " inner join " + related + " on " + key + " = " + pivot + "." + filterFiled(rel.RelatedKey, del) +
+				" where " + owner + " in (" + placeholders + ") order by " + key,
+			[]string{rel.Pivot, rel.Table}
+	default:
+		return "select " + key + " as goadmin_owner, " + key + " as goadmin_key, " + label + " as goadmin_label" +
+			" from " + related + " where " + key + " in (" + placeholders + ")", []string{rel.Table}
+	}
+}
+
+// relationFilter return the condition filtering the rows of which a related
+// row matches the operator and the value.
+func (tb DefaultTable) relationFilter(del string, rel types.Relation, op types.FilterOperator) string {
+	var (
+		related = filterFiled(rel.Table, del)
+		key     = related + "." + filterFiled(rel.GetKey(), del)
+		label   = related + "." + filterFiled(rel.Field, del)
+		primary = tb.info.Table + "." + filterFiled(tb.primaryKey.Name, del)
+	)
+
+	switch rel.Type {
+	case types.HasMany:
+		return primary + " in (select " + related + "." + filterFiled(rel.ForeignKey, del) +
+			" from " + related + " where " + label + " " + op.String() + " ?)"
+	case types.ManyToMany:
+		pivot := filterFiled(rel.Pivot, del)
+		return primary + " in (select " + pivot + "." + filterFiled(rel.ForeignKey, del) +
+			" from " + pivot + " inner join " + related + " on " + key + " = " + pivot + "." + filterFiled(rel.RelatedKey, del) +
+			" where " + label + " " + op.String() + " ?)"
+	default:
+		return tb.info.Table + "." + filterFiled(rel.ForeignKey, del) + " in (select " + key +
+			" from " + related + " where " + label + " " + op.String() + " ?)"
+	}
+}
+
+// relationSort return the expression selecting the label of the BelongsTo
+// relation as the alias, by which the rows are ordered. The alias can not
+// be referenced by the window function of mssql, so false is returned for
+// mssql.
+func (tb DefaultTable) relationSort(driver, del string, field types.Field) (string, string, bool) {
+	if !field.Relation.Sortable() || driver == db.DriverMssql {
+		return "", "", false
+	}
+	var (
+		rel     = field.Relation
+		related = filterFiled(rel.Table, del)
+		alias   = relationSortPrefix + field.Field
+	)
+	return "(select " + related + "." + filterFiled(rel.Field, del) + " from " + related +
+		" where " + related + "." + filterFiled(rel.GetKey(), del) + " = " +
+		tb.info.Table + "." + filterFiled(rel.ForeignKey, del) + ") as " + alias, alias, true
+}
+
+// relationLabels return the labels of the related rows, with the links of
+// the rows if set.
+func (tb DefaultTable) relationLabels(rel types.Relation, values types.RelationValues) template2.HTML {
+	if len(values) == 0 {
+		return ""
+	}
+	label := template.Get(config.GetFromServices(tb.services()).Theme).Label().SetType("success")
+
+	labels := make([]string, len(values))
+	for i, value := range values {
+		content := template2.HTML(template2.HTMLEscapeString(value.Label))
+		if rel.Link != "" {
+			content = template2.HTML(`<a href="` +
+				template2.HTMLEscapeString(strings.Replace(rel.Link, "{key}", value.Key, -1)) +
+				`" style="color:inherit">` + string(content) + `</a>`)
+		}
+		labels[i] = string(label.SetContent(content).GetContent())
+	}
+	return template2.HTML(strings.Join(labels, " "))
+}
+
+func relationKey(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// relationColumns return the foreign keys of the BelongsTo relations which
+// are not selected by the other fields, separated and ended by commas.
+func (tb DefaultTable) relationColumns(del string, columns Columns) string {
+	selected := make(map[string]bool)
+	for _, field := range tb.info.FieldList {
+		if !field.Join.Valid() && !field.Relation.Valid() {
+			selected[field.Field] = true
+		}
+	}
+	selected[tb.primaryKey.Name] = true
+
+	fields := ""
+	for _, field := range tb.info.FieldList {
+		key := field.Relation.ForeignKey
+		if field.Hide || !field.Relation.Valid() || field.Relation.Type != types.BelongsTo ||
+			selected[key] || !inArray(columns, key) {
+			continue
+		}
+		selected[key] = true
+		fields += tb.info.Table + "." + filterFiled(key, del) + ","
+	}
+	return fields
+}
+
+// relationSortable return false if the field is a relation field which the
+// rows can not be ordered by.
+func (tb DefaultTable) relationSortable(conn db.Connection, field types.Field) bool {
+	if !field.Relation.Valid() {
+		return true
+	}
+	_, _, ok := tb.relationSort(conn.Name(), conn.GetDelimiter(), field)
+	return ok
+}
+
+// relationSortExpression return the sort expression and its alias if the
+// sort field is a sortable relation field.
+func (tb DefaultTable) relationSortExpression(conn db.Connection, sortField string) (string, string, bool) {
+	field := tb.info.FieldList.GetFieldByFieldName(sortField)
+	if !field.Exist() || !field.Sortable || !field.Relation.Valid() {
+		return "", "", false
+	}
+	return tb.relationSort(conn.Name(), conn.GetDelimiter(), field)
+}
@@ -0,0 +1,106 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/template/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	roleRelation = types.Relation{
+		Type:       types.ManyToMany,
+		Table:      "roles",
+		Field:      "name",
+		Pivot:      "role_users",
+		ForeignKey: "user_id",
+		RelatedKey: "role_id",
+	}
+	groupRelation = types.Relation{
+		Type:       types.BelongsTo,
+		Table:      "groups",
+		Field:      "title",
+		ForeignKey: "group_id",
+	}
+	postRelation = types.Relation{
+		Type:       types.HasMany,
+		Table:      "posts",
+		Field:      "title",
+		ForeignKey: "user_id",
+	}
+)
+
+func relationTable() DefaultTable {
+	tb := NewDefaultTable(DefaultConfigWithDriver(db.DriverMysql)).(DefaultTable)
+	tb.info.AddField("ID", "id", db.Int)
+	tb.info.AddField("Group", "group", db.Varchar).FieldRelation(groupRelation).FieldSortable()
+	tb.info.AddField("Roles", "roles", db.Varchar).FieldRelation(roleRelation).FieldSortable()
+	tb.info.SetTable("users")
+	return tb
+}
+
+func TestRelation_Valid(t *testing.T) {
+	assert.True(t, roleRelation.Valid())
+	assert.True(t, groupRelation.Valid())
+	assert.True(t, postRelation.Valid())
+
+	rel := roleRelation
+	rel.Pivot = ""
+	assert.False(t, rel.Valid())
+	assert.False(t, types.Relation{Table: "roles", Field: "name", ForeignKey: "role_id"}.Valid())
+}
+
+func TestDefaultTable_relationStatement(t *testing.T) {
+	tb := relationTable()
+
+	statement, tables := tb.relationStatement("`", groupRelation, 2)
+	assert.Equal(t, "select `groups`.`id` as goadmin_owner, `groups`.`id` as goadmin_key, `groups`.`title` as goadmin_label"+
+		" from `groups` where `groups`.`id` in (?,?)", statement)
+	assert.Equal(t, []string{"groups"}, tables)
+
+	statement, _ = tb.relationStatement("`", postRelation, 1)
+	assert.Equal(t, "select `posts`.`user_id` as goadmin_owner, `posts`.`id` as goadmin_key, `posts`.`title` as goadmin_label"+
+		" from `posts` where `posts`.`user_id` in (?) order by `posts`.`id`", statement)
+
+	statement, tables = tb.relationStatement("[", roleRelation, 3)
+	assert.Equal(t, "select role_users.user_id as goadmin_owner, roles.id as goadmin_key, roles.name as goadmin_label"+
+		" from role_users inner join roles on roles.id = role_users.role_id where role_users.user_id in (?,?,?) order by roles.id", statement)
+	assert.Equal(t, []string{"role_users", "roles"}, tables)
+}
+
+func TestDefaultTable_relationFilter(t *testing.T) {
+	tb := relationTable()
+
+	assert.Equal(t, "users.`group_id` in (select `groups`.`id` from `groups` where `groups`.`title` like ?)",
+		tb.relationFilter("`", groupRelation, types.FilterOperatorLike))
+	assert.Equal(t, "users.`id` in (select `posts`.`user_id` from `posts` where `posts`.`title` = ?)",
+		tb.relationFilter("`", postRelation, types.FilterOperatorEqual))
+	assert.Equal(t, `users."id" in (select "role_users"."user_id" from "role_users" inner join "roles"`+
+		` on "roles"."id" = "role_users"."role_id" where "roles"."name" = ?)`,
+		tb.relationFilter(`"`, roleRelation, types.FilterOperatorEqual))
+}
+
+func TestDefaultTable_relationSort(t *testing.T) {
+	tb := relationTable()
+
+	expr, alias, ok := tb.relationSort(db.DriverMysql, "`", tb.info.FieldList.GetFieldByFieldName("group"))
+	assert.True(t, ok)
+	assert.Equal(t, "__goadmin_sort_group", alias)
+	assert.Equal(t, "(select `groups`.`title` from `groups` where `groups`.`id` = users.`group_id`) as __goadmin_sort_group", expr)
+
+	_, _, ok = tb.relationSort(db.DriverMssql, "[", tb.info.FieldList.GetFieldByFieldName("group"))
+	assert.False(t, ok)
+
+	_, _, ok = tb.relationSort(db.DriverMysql, "`", tb.info.FieldList.GetFieldByFieldName("roles"))
+	assert.False(t, ok)
+}
+
+func TestDefaultTable_relationColumns(t *testing.T) {
+	tb := relationTable()
+
+	assert.Equal(t, "users.`group_id`,", tb.relationColumns("`", Columns{"id", "group_id"}))
+	assert.Equal(t, "", tb.relationColumns("`", Columns{"id"}))
+
+	tb.info.AddField("Group ID", "group_id", db.Int)
+	assert.Equal(t, "", tb.relationColumns("`", Columns{"id", "group_id"}))
+}
@@ -0,0 +1,191 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/logger"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// searchIndexes caches whether the full-text index of the search fields
+// exists, keyed by the driver, the connection, the table and the fields.
+// The indexes created later take effect after a restart.
+var searchIndexes = struct {
+	sync.RWMutex
+	m map[string]bool
+}{m: make(map[string]bool)}
+
+// searchCondition return the condition of the search box of the list and
+// its arguments. The search fields which are not the columns of the table
+// are ignored. The full-text search of the driver is used when available:
+// MATCH AGAINST of mysql when a FULLTEXT index of exactly the search fields
+// exists, to_tsvector of postgresql, the FTS5 table named "<table>_fts" of
+// sqlite of which the rowid is the primary key, and CONTAINS of mssql when
+// the full-text index covers the search fields. Otherwise the search fields
+// are searched by LIKE.
+func (tb DefaultTable) searchCondition(conn db.Connection, columns Columns, value string) (string, []interface{}) {
+	fields := make([]string, 0, len(tb.info.SearchFields))
+	for _, field := range tb.info.SearchFields {
+		if inArray(columns, field) {
+			fields = append(fields, field)
+		}
+	}
+	if value == "" || len(fields) == 0 {
+		return "", nil
+	}
+
+	fullText := false
+	if len(searchTerms(value)) > 0 {
+		fullText = tb.connectionDriver == db.DriverPostgresql || tb.hasFullTextIndex(conn, fields)
+	}
+
+	return searchExpression(tb.connectionDriver, conn.GetDelimiter(), tb.info.Table,
+		tb.primaryKey.Name, fields, value, fullText)
+}
+
+// searchExpression return the condition searching the value in the fields
+// of the table, by the full-text search of the driver or by LIKE.
+func searchExpression(driver, del, table, primaryKey string, fields []string, value string,
+	fullText bool) (string, []interface{}) {
+
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = table + "." + filterFiled(field, del)
+	}
+	terms := searchTerms(value)
+
+	if fullText && len(terms) > 0 {
+		switch driver {
+		case db.DriverMysql:
+			for i, term := range terms {
+				terms[i] = "+" + term + "*"
+			}
+			return "match(" + strings.Join(columns, ", ") + ") against (? in boolean mode)",
+				[]interface{}{strings.Join(terms, " ")}
+		case db.DriverPostgresql:
+			for i, term := range terms {
+				terms[i] = term + ":*"
+			}
+			return "to_tsvector('simple', concat_ws(' ', " + strings.Join(columns, ", ") + ")) @@ " +
+				"to_tsquery('simple', ?)", []interface{}{strings.Join(terms, " & ")}
+		case db.DriverSqlite:
+			for i, term := range terms {
+				terms[i] = `"` + term + `"*`
+			}
+			fts := filterFiled(table+"_fts", del)
+			return table + "." + filterFiled(primaryKey, del) + " in (select rowid from " + fts +
+				" where " + fts + " match ?)", []interface{}{strings.Join(terms, " ")}
+		case db.DriverMssql:
+			for i, term := range terms {
+				terms[i] = `"` + term + `*"`
+			}
+			return "contains((" + strings.Join(columns, ", ") + "), ?)",
+				[]interface{}{strings.Join(terms, " and ")}
+		}
+	}
+
+	var (
+		conditions = make([]string, len(columns))
+		args       = make([]interface{}, len(columns))
+	)
+	for i, column := range columns {
+		conditions[i] = likeColumn(driver, column) + " like ? escape '!'"
+		args[i] = "%" + escapeLike(driver, value) + "%"
+	}
+	return "(" + strings.Join(conditions, " or ") + ")", args
+}
+
+// searchTerms return the words of the value, the characters other than the
+// letters and the digits are the separators, so that the operators of the
+// full-text searches are not passed through.
+func searchTerms(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// hasFullTextIndex return true if the full-text index of the fields exists.
+func (tb DefaultTable) hasFullTextIndex(conn db.Connection, fields []string) bool {
+	if tb.connectionDriver != db.DriverMysql && tb.connectionDriver != db.DriverSqlite &&
+		tb.connectionDriver != db.DriverMssql {
+		return false
+	}
+
+	key := tb.connectionDriver + "|" + tb.connection + "|" + tb.info.Table + "|" + strings.Join(fields, ",")
+
+	searchIndexes.RLock()
+	exist, ok := searchIndexes.m[key]
+	searchIndexes.RUnlock()
+	if ok {
+		return exist
+	}
+
+	var (
+		res []map[string]interface{}
+		err error
+	)
+	switch tb.connectionDriver {
+	case db.DriverMysql:
+		res, err = conn.QueryWithConnection(tb.connection, "show index from "+
+			filterFiled(tb.info.Table, conn.GetDelimiter())+" where Index_type = 'FULLTEXT'")
+	case db.DriverSqlite:
+		res, err = conn.QueryWithConnection(tb.connection,
+			"select name from sqlite_master where type = 'table' and name = ?", tb.info.Table+"_fts")
+	case db.DriverMssql:
+		res, err = conn.QueryWithConnection(tb.connection, "select c.name as column_name "+
+			"from sys.fulltext_index_columns ic join sys.columns c on c.object_id = ic.object_id "+
+			"and c.column_id = ic.column_id where ic.object_id = object_id(?)", tb.info.Table)
+	}
+	if err != nil {
+		logger.Error("query full-text index error", err)
+		return false
+	}
+
+	switch tb.connectionDriver {
+	case db.DriverMysql:
+		indexes := make(map[string][]string)
+		for _, row := range res {
+			name := relationKey(row["Key_name"])
+			indexes[name] = append(indexes[name], relationKey(row["Column_name"]))
+		}
+		for _, columns := range indexes {
+			if len(columns) == len(fields) && coverFields(columns, fields) {
+				exist = true
+				break
+			}
+		}
+	case db.DriverSqlite:
+		exist = len(res) > 0
+	case db.DriverMssql:
+		columns := make([]string, len(res))
+		for i, row := range res {
+			columns[i] = relationKey(row["column_name"])
+		}
+		exist = len(columns) > 0 && coverFields(columns, fields)
+	}
+
+	searchIndexes.Lock()
+	searchIndexes.m[key] = exist
+	searchIndexes.Unlock()
+
+	return exist
+}
+
+// coverFields return true if the fields are all in the columns, ignoring
+// the case.
+func coverFields(columns, fields []string) bool {
+	for _, field := range fields {
+		found := false
+		for _, column := range columns {
+			if strings.EqualFold(column, field) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package table
+
+import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"foo", "bar", "mail", "com", "中文"}, searchTerms(` +foo* "bar" mail@com (中文)`))
+	assert.Equal(t, 0, len(searchTerms(`+-*"`)))
+}
+
+func TestSearchExpression(t *testing.T) {
+	fields := []string{"title", "content"}
+
+	cond, args := searchExpression(db.DriverMysql, "`", "posts", "id", fields, "go admin", true)
+	assert.Equal(t, "match(posts.`title`, posts.`content`) against (? in boolean mode)", cond)
+	assert.Equal(t, []interface{}{"+go* +admin*"}, args)
+
+	cond, args = searchExpression(db.DriverPostgresql, `"`, "posts", "id", fields, "go admin", true)
+	assert.Equal(t, `to_tsvector('simple', concat_ws(' ', posts."title", posts."content")) @@ `+
+		`to_tsquery('simple', ?)`, cond)
+	assert.Equal(t, []interface{}{"go:* & admin:*"}, args)
+
+	cond, args = searchExpression(db.DriverSqlite, "`", "posts", "id", fields, "go admin", true)
+	assert.Equal(t, "posts.`id` in (select rowid from `posts_fts` where `posts_fts` match ?)", cond)
+	assert.Equal(t, []interface{}{`"go"* "admin"*`}, args)
+
+	cond, args = searchExpression(db.DriverMssql, "[", "posts", "id", fields, "go admin", true)
+	assert.Equal(t, "contains((posts.title, posts.content), ?)", cond)
+	assert.Equal(t, []interface{}{`"go*" and "admin*"`}, args)
+
+	cond, args = searchExpression(db.DriverMysql, "`", "posts", "id", fields, "go admin", false)
+	assert.Equal(t, "(posts.`title` like ? escape '!' or posts.`content` like ? escape '!')", cond)
+	assert.Equal(t, []interface{}{"%go admin%", "%go admin%"}, args)
+
+	cond, args = searchExpression(db.DriverPostgresql, `"`, "posts", "id", fields, "**", true)
+	assert.Equal(t, `(cast(posts."title" as text) like ? escape '!' or `+
+		`cast(posts."content" as text) like ? escape '!')`, cond)
+	assert.Equal(t, []interface{}{"%**%", "%**%"}, args)
+
+	cond, args = searchExpression(db.DriverMssql, "[", "posts", "id", fields, "100%_[a]!", false)
+	assert.Equal(t, "(posts.title like ? escape '!' or posts.content like ? escape '!')", cond)
+	assert.Equal(t, []interface{}{"%100!%!_![a]!!%", "%100!%!_![a]!!%"}, args)
+}
+
+func TestCoverFields(t *testing.T) {
+	assert.True(t, coverFields([]string{"Title", "content", "tags"}, []string{"title", "content"}))
+	assert.False(t, coverFields([]string{"title"}, []string{"title", "content"}))
+}
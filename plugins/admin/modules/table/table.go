@@ -10,9 +10,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/glvd/go-admin/modules/cache"
 	"github.com/glvd/go-admin/modules/db"
 	"github.com/glvd/go-admin/modules/db/dialect"
 	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/modules/metrics"
+	"github.com/glvd/go-admin/plugins/admin/models"
 	"github.com/glvd/go-admin/plugins/admin/modules"
 	"github.com/glvd/go-admin/plugins/admin/modules/form"
 	"github.com/glvd/go-admin/plugins/admin/modules/paginator"
@@ -46,38 +49,30 @@ func (g GeneratorList) CombineAll(ggg []GeneratorList) {
 	}
 }
 
-var (
-	generators = make(GeneratorList)
-	tableList  = map[string]Table{}
-)
-
+// Get return the table of the key from the default list.
 func Get(key string) Table {
-	return tableList[key]
+	return defaultList.Get(key)
 }
 
+// InitTableList generate the tables of the default list.
 func InitTableList() {
-	for prefix, generator := range generators {
-		tableList[prefix] = generator()
-	}
+	defaultList.Init()
 }
 
 // RefreshTableList refresh the table list when the table relationship changed.
 func RefreshTableList() {
-	for k, v := range generators {
-		tableList[k] = v()
-	}
+	defaultList.Refresh()
 }
 
 // SetGenerators update generators.
 func SetGenerators(gens map[string]Generator) {
-	for key, gen := range gens {
-		generators[key] = gen
-	}
+	defaultList.SetGenerators(gens)
 }
 
 type Table interface {
 	GetInfo() *types.InfoPanel
 	GetForm() *types.FormPanel
+	GetDetail() *types.DetailPanel
 	GetCanAdd() bool
 	GetEditable() bool
 	GetDeletable() bool
@@ -86,9 +81,11 @@ type Table interface {
 	GetDataFromDatabase(path string, params parameter.Parameters, isAll bool) (PanelInfo, error)
 	GetDataFromDatabaseWithIds(path string, params parameter.Parameters, ids []string) (PanelInfo, error)
 	GetDataFromDatabaseWithId(id string) ([]types.FormField, [][]types.FormField, []string, string, string, error)
+	GetDetailFromDatabase(id string) (DetailInfo, error)
 	UpdateDataFromDatabase(dataList form.Values) error
 	InsertDataFromDatabase(dataList form.Values) error
 	DeleteDataFromDatabase(id string) error
+	WithUser(user models.UserModel) Table
 	Copy() Table
 }
 
@@ -105,6 +102,7 @@ const (
 type DefaultTable struct {
 	info             *types.InfoPanel
 	form             *types.FormPanel
+	detail           *types.DetailPanel
 	connectionDriver string
 	connection       string
 	canAdd           bool
@@ -112,6 +110,14 @@ type DefaultTable struct {
 	deletable        bool
 	exportable       bool
 	primaryKey       PrimaryKey
+	srv              service.List
+
+	// denied are the fields the user can not change, scope is the row
+	// scopes of the user, and restricted means the columns of the requests
+	// are restricted to the fields of the user, see WithUser.
+	denied     map[string]bool
+	scope      rowScope
+	restricted bool
 }
 
 type PanelInfo struct {
@@ -198,6 +204,12 @@ func (config Config) SetPrimaryKeyType(typ string) Config {
 	return config
 }
 
+// SetPrimaryKey set the name and the type of the primary key.
+func (config Config) SetPrimaryKey(name string, typ db.DatabaseType) Config {
+	config.PrimaryKey = PrimaryKey{Name: name, Type: typ}
+	return config
+}
+
 func (config Config) SetCanAdd(canAdd bool) Config {
 	config.CanAdd = canAdd
 	return config
@@ -257,6 +269,7 @@ func NewDefaultTable(cfg Config) Table {
 	return DefaultTable{
 		info:             types.NewInfoPanel(),
 		form:             types.NewFormPanel(),
+		detail:           types.NewDetailPanel(),
 		connectionDriver: cfg.Driver,
 		connection:       cfg.Connection,
 		canAdd:           cfg.CanAdd,
@@ -275,6 +288,9 @@ func (tb DefaultTable) Copy() Table {
 		info: types.NewInfoPanel().SetTable(tb.info.Table).
 			SetDescription(tb.info.Description).
 			SetTitle(tb.info.Title),
+		detail: types.NewDetailPanel().SetTable(tb.detail.Table).
+			SetDescription(tb.detail.Description).
+			SetTitle(tb.detail.Title),
 		connectionDriver: tb.connectionDriver,
 		connection:       tb.connection,
 		canAdd:           tb.canAdd,
@@ -282,6 +298,9 @@ func (tb DefaultTable) Copy() Table {
 		deletable:        tb.deletable,
 		exportable:       tb.exportable,
 		primaryKey:       tb.primaryKey,
+		srv:              tb.srv,
+		denied:           tb.denied,
+		scope:            tb.scope,
 	}
 }
 
@@ -293,6 +312,11 @@ func (tb DefaultTable) GetForm() *types.FormPanel {
 	return tb.form
 }
 
+// GetDetail return the detail panel, see types.DetailPanel.
+func (tb DefaultTable) GetDetail() *types.DetailPanel {
+	return tb.detail
+}
+
 func (tb DefaultTable) GetCanAdd() bool {
 	return tb.canAdd && !tb.info.IsHideNewButton
 }
@@ -315,6 +339,7 @@ func (tb DefaultTable) GetExportable() bool {
 
 // GetDataFromDatabase query the data set.
 func (tb DefaultTable) GetDataFromDatabase(path string, params parameter.Parameters, isAll bool) (PanelInfo, error) {
+	metrics.TableOperations.Inc(tb.info.Table, metrics.OperationRead)
 	if isAll {
 		return tb.getAllDataFromDatabase(path, params)
 	}
@@ -323,6 +348,7 @@ func (tb DefaultTable) GetDataFromDatabase(path string, params parameter.Paramet
 
 // GetDataFromDatabaseWithIds query the data set.
 func (tb DefaultTable) GetDataFromDatabaseWithIds(path string, params parameter.Parameters, ids []string) (PanelInfo, error) {
+	metrics.TableOperations.Inc(tb.info.Table, metrics.OperationRead)
 	return tb.getDataFromDatabase(path, params, ids)
 }
 
@@ -357,7 +383,14 @@ func (tb DefaultTable) getTempModelData(res map[string]interface{}, params param
 		var combineValue = db.GetValueFromDatabaseType(typeName, res[headField]).String()
 
 		var value interface{}
-		if inArray(columns, headField) || field.Join.Valid() {
+		if field.Relation.Valid() {
+			values, _ := res[types.RelationRowKey(field.Field)].(types.RelationValues)
+			value = field.ToDisplay(types.FieldModel{
+				ID:    primaryKeyValue.String(),
+				Value: string(tb.relationLabels(field.Relation, values)),
+				Row:   res,
+			})
+		} else if inArray(columns, headField) || field.Join.Valid() {
 			value = field.ToDisplay(types.FieldModel{
 				ID:    primaryKeyValue.String(),
 				Value: combineValue,
@@ -385,10 +418,16 @@ func (tb DefaultTable) getAllDataFromDatabase(path string, params parameter.Para
 	var (
 		connection     = tb.db()
 		placeholder    = delimiter(connection.GetDelimiter(), "%s")
-		queryStatement = "select %s from %s %s order by " + placeholder + " %s"
+		queryStatement = "select %s from %s %s%s order by " + placeholder + " %s"
+		wheres         = ""
 	)
 
-	columnsModel, _ := tb.sql().Table(tb.info.Table).ShowColumns()
+	scope, scopeArgs := tb.scopeWhere(tb.info.Table)
+	if scope != "" {
+		wheres = " where " + scope
+	}
+
+	columnsModel, _ := tb.sql().Table(tb.info.Table).Cache().ShowColumns()
 
 	columns, _ := tb.getColumns(columnsModel)
 
@@ -431,22 +470,27 @@ func (tb DefaultTable) getAllDataFromDatabase(path string, params parameter.Para
 		})
 	}
 
+	fields += tb.relationColumns(connection.GetDelimiter(), columns)
 	fields += tb.info.Table + "." + filterFiled(tb.primaryKey.Name, connection.GetDelimiter())
 
-	if !inArray(columns, params.SortField) {
+	if !inArray(tb.queryable(columns), params.SortField) {
 		params.SortField = tb.primaryKey.Name
 	}
 
-	queryCmd := fmt.Sprintf(queryStatement, fields, tb.info.Table, joins, params.SortField, params.SortType)
+	queryCmd := fmt.Sprintf(queryStatement, fields, tb.info.Table, joins, wheres, params.SortField, params.SortType)
 
-	logger.LogSQL(queryCmd, []interface{}{})
+	logger.LogSQL(queryCmd, scopeArgs)
 
-	res, err := connection.QueryWithConnection(tb.connection, queryCmd)
+	res, err := tb.query(connection, params, append(joinTables, tb.info.Table), queryCmd, scopeArgs...)
 
 	if err != nil {
 		return PanelInfo{}, err
 	}
 
+	if err := tb.loadRelations(connection, params, res); err != nil {
+		return PanelInfo{}, err
+	}
+
 	infoList := make([]map[string]template.HTML, 0)
 
 	for i := 0; i < len(res); i++ {
@@ -472,9 +516,15 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 
 	beginTime := time.Now()
 
+	scope, scopeArgs := tb.scopeWhere(tb.info.Table)
+
 	if len(ids) > 0 {
-		queryStatement = "select %s from %s %s where " + tb.primaryKey.Name + " in (%s) %s order by " + placeholder + " %s"
-		countStatement = "select count(*) from " + placeholder + " where " + tb.primaryKey.Name + " in (%s)"
+		idsScope := ""
+		if scope != "" {
+			idsScope = " and " + scope
+		}
+		queryStatement = "select %s from %s %s where " + tb.primaryKey.Name + " in (%s)" + idsScope + " %s order by " + placeholder + " %s"
+		countStatement = "select count(*) from " + placeholder + " where " + tb.primaryKey.Name + " in (%s)" + idsScope
 	} else {
 		queryStatement = "select %s from " + placeholder + "%s %s %s order by " + placeholder + " %s LIMIT ? OFFSET ?"
 		countStatement = "select count(*) from " + placeholder + "%s"
@@ -487,7 +537,7 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 	thead := make([]map[string]string, 0)
 	fields := ""
 
-	columnsModel, _ := tb.sql().Table(tb.info.Table).ShowColumns()
+	columnsModel, _ := tb.sql().Table(tb.info.Table).Cache().ShowColumns()
 
 	columns, _ := tb.getColumns(columnsModel)
 
@@ -565,7 +615,7 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 		if field.Hide {
 			continue
 		}
-		sortable = modules.AorB(field.Sortable, "1", "0")
+		sortable = modules.AorB(field.Sortable && tb.relationSortable(connection, field), "1", "0")
 		editable = modules.AorB(field.EditAble, "true", "false")
 		hide = modules.AorB(modules.InArrayWithoutEmpty(params.Columns, headField), "0", "1")
 		thead = append(thead, map[string]string{
@@ -580,10 +630,18 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 		})
 	}
 
+	fields += tb.relationColumns(connection.GetDelimiter(), columns)
 	fields += tb.info.Table + "." + filterFiled(tb.primaryKey.Name, connection.GetDelimiter())
 
-	if !inArray(columns, params.SortField) {
+	queryable := tb.queryable(columns)
+
+	sortField := params.SortField
+	if expr, alias, ok := tb.relationSortExpression(connection, params.SortField); ok {
+		fields += ", " + expr
+		sortField = alias
+	} else if !inArray(queryable, params.SortField) {
 		params.SortField = tb.primaryKey.Name
+		sortField = params.SortField
 	}
 
 	var (
@@ -591,6 +649,9 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 		whereArgs = make([]interface{}, 0)
 		args      = make([]interface{}, 0)
 		existKeys = make([]string, 0)
+		pageSize  = 0
+		keyset    = len(ids) == 0 && tb.info.IsKeysetPagination && sortField == params.SortField &&
+			inArray(queryable, sortField) && tb.notNull(columnsModel, sortField)
 	)
 
 	if len(ids) > 0 {
@@ -600,6 +661,8 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 			}
 		}
 		wheres = wheres[:len(wheres)-1]
+		args = append(args, scopeArgs...)
+		whereArgs = append(whereArgs, scopeArgs...)
 	} else {
 
 		if len(params.Fields) == 0 && len(tb.info.Wheres) == 0 {
@@ -625,7 +688,7 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 					op = params.GetFieldOperator(key)
 				}
 
-				if inArray(columns, key) {
+				if inArray(queryable, key) {
 					wheres += filterFiled(key, connection.GetDelimiter()) + " " + op.String() + " ? and "
 					if op == types.FilterOperatorLike && !strings.Contains(value, "%") {
 						whereArgs = append(whereArgs, "%"+value+"%")
@@ -634,6 +697,15 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 					}
 				}
 
+				if field := tb.info.FieldList.GetFieldByFieldName(key); field.Exist() && field.Relation.Valid() {
+					wheres += tb.relationFilter(connection.GetDelimiter(), field.Relation, op) + " and "
+					if op == types.FilterOperatorLike && !strings.Contains(value, "%") {
+						whereArgs = append(whereArgs, "%"+value+"%")
+					} else {
+						whereArgs = append(whereArgs, value)
+					}
+				}
+
 				if field := tb.info.FieldList.GetFieldByFieldName(key); field.Exist() && field.Join.Table != "" {
 					wheres += field.Join.Table + "." + filterFiled(key, connection.GetDelimiter()) + " " + op.String() + " ? and "
 					if op == types.FilterOperatorLike && !strings.Contains(value, "%") {
@@ -668,8 +740,42 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 			}
 
 		}
-		pageSize, _ := strconv.Atoi(params.PageSize)
-		if connection.Name() == "mssql" {
+
+		if scope != "" {
+			if wheres == "" {
+				wheres = " where " + scope
+			} else {
+				wheres += " and " + scope
+			}
+			whereArgs = append(whereArgs, scopeArgs...)
+		}
+
+		if search, searchArgs := tb.searchCondition(connection, queryable, params.Search); search != "" {
+			if wheres == "" {
+				wheres = " where " + search
+			} else {
+				wheres += " and " + search
+			}
+			whereArgs = append(whereArgs, searchArgs...)
+		}
+
+		filter, filterArgs, err := tb.advancedFilter(connection.GetDelimiter(), columns, params.Filter)
+		if err != nil {
+			return PanelInfo{}, err
+		}
+		if filter != "" {
+			if wheres == "" {
+				wheres = " where " + filter
+			} else {
+				wheres += " and " + filter
+			}
+			whereArgs = append(whereArgs, filterArgs...)
+		}
+
+		pageSize, _ = strconv.Atoi(params.PageSize)
+		if keyset {
+			args = whereArgs
+		} else if connection.Name() == "mssql" {
 			args = append(whereArgs, (modules.GetPage(params.Page)-1)*pageSize, modules.GetPage(params.Page)*pageSize)
 		} else {
 			args = append(whereArgs, params.PageSize, (modules.GetPage(params.Page)-1)*pageSize)
@@ -681,43 +787,71 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 		groupBy = " GROUP BY " + tb.info.Table + "." + filterFiled(tb.GetPrimaryKey().Name, connection.GetDelimiter())
 	}
 
-	queryCmd := fmt.Sprintf(queryStatement, fields, tb.info.Table, joins, wheres, groupBy, params.SortField, params.SortType)
-	if connection.Name() == "mssql" {
-		queryCmd = fmt.Sprintf(queryStatement, params.SortField, params.SortType, fields, tb.info.Table, joins, wheres, groupBy)
+	var (
+		queryCmd string
+		backward bool
+	)
+	if keyset {
+		queryCmd, args, backward = tb.keysetStatement(connection.Name(), connection.GetDelimiter(), fields, joins, wheres, groupBy, sortField,
+			whereArgs, params)
+	} else if connection.Name() == "mssql" {
+		queryCmd = fmt.Sprintf(queryStatement, sortField, params.SortType, fields, tb.info.Table, joins, wheres, groupBy)
+	} else {
+		queryCmd = fmt.Sprintf(queryStatement, fields, tb.info.Table, joins, wheres, groupBy, sortField, params.SortType)
 	}
 	logger.LogSQL(queryCmd, args)
 
-	res, err := connection.QueryWithConnection(tb.connection, queryCmd, args...)
+	tables := append(joinTables, tb.info.Table)
+
+	res, err := tb.query(connection, params, tables, queryCmd, args...)
 
 	if err != nil {
 		return PanelInfo{}, err
 	}
 
+	var previous, next string
+	if keyset {
+		res, previous, next = tb.keysetPage(res, params, sortField, backward)
+	}
+
+	if err := tb.loadRelations(connection, params, res); err != nil {
+		return PanelInfo{}, err
+	}
+
 	infoList := make([]map[string]template.HTML, 0)
 
 	for i := 0; i < len(res); i++ {
 		infoList = append(infoList, tb.getTempModelData(res[i], params, columns))
 	}
 
-	// TODO: use the dialect
-
-	countCmd := fmt.Sprintf(countStatement, tb.info.Table, wheres)
+	mode := tb.info.CountMode
+	if len(ids) > 0 {
+		mode = types.CountExact
+	}
 
-	total, err := connection.QueryWithConnection(tb.connection, countCmd, whereArgs...)
+	size, err := tb.count(connection, params, tables, countStatement, wheres, whereArgs, mode)
 
 	if err != nil {
 		return PanelInfo{}, err
 	}
 
-	logger.LogSQL(countCmd, nil)
-
-	var size int
-	if tb.connectionDriver == "postgresql" {
-		size = int(total[0]["count"].(int64))
-	} else if tb.connectionDriver == "mssql" {
-		size = int(total[0]["size"].(int64))
+	var (
+		pageSizeList = tb.info.GetPageSizeList()
+		total        = countText(size, mode == types.CountApproximate)
+		page         types.PaginatorAttribute
+	)
+	if keyset {
+		page = paginator.GetWithoutPages(path, params, total, pageSizeList, previous, next)
+	} else if size < 0 {
+		if modules.GetPage(params.Page) > 1 {
+			previous = params.SetPage(strconv.Itoa(modules.GetPage(params.Page) - 1)).GetRouteParamStr()
+		}
+		if len(res) >= pageSize {
+			next = params.SetPage(strconv.Itoa(modules.GetPage(params.Page) + 1)).GetRouteParamStr()
+		}
+		page = paginator.GetWithoutPages(path, params, total, pageSizeList, previous, next)
 	} else {
-		size = int(total[0]["count(*)"].(int64))
+		page = paginator.Get(path, params, size, pageSizeList).SetTotal(total)
 	}
 
 	endTime := time.Now()
@@ -725,9 +859,8 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 	return PanelInfo{
 		Thead:    thead,
 		InfoList: infoList,
-		Paginator: paginator.Get(path, params, size, tb.info.GetPageSizeList()).
-			SetExtraInfo(template.HTML(fmt.Sprintf("<b>" + language.Get("query time") + ": </b>" +
-				fmt.Sprintf("%.3fms", endTime.Sub(beginTime).Seconds()*1000)))),
+		Paginator: page.SetExtraInfo(template.HTML(fmt.Sprintf("<b>" + language.Get("query time") + ": </b>" +
+			fmt.Sprintf("%.3fms", endTime.Sub(beginTime).Seconds()*1000)))),
 		Title:       tb.info.Title,
 		FormData:    filterForm,
 		Description: tb.info.Description,
@@ -736,10 +869,11 @@ func (tb DefaultTable) getDataFromDatabase(path string, params parameter.Paramet
 
 // GetDataFromDatabaseWithId query the single row of data.
 func (tb DefaultTable) GetDataFromDatabaseWithId(id string) ([]types.FormField, [][]types.FormField, []string, string, string, error) {
+	metrics.TableOperations.Inc(tb.form.Table, metrics.OperationRead)
 
 	fields := make([]string, 0)
 
-	columnsModel, err := tb.sql().Table(tb.form.Table).ShowColumns()
+	columnsModel, err := tb.sql().Table(tb.form.Table).Cache().ShowColumns()
 
 	if err != nil {
 		return nil, nil, nil, "", "", err
@@ -755,9 +889,9 @@ func (tb DefaultTable) GetDataFromDatabaseWithId(id string) ([]types.FormField,
 		}
 	}
 
-	res, err := tb.sql().
+	res, err := tb.withScope(tb.sql().
 		Table(tb.form.Table).Select(fields...).
-		Where(tb.primaryKey.Name, "=", id).
+		Where(tb.primaryKey.Name, "=", id), tb.form.Table).
 		First()
 
 	if err != nil {
@@ -778,6 +912,9 @@ func (tb DefaultTable) GetDataFromDatabaseWithId(id string) ([]types.FormField,
 						rowValue := modules.AorB(inArray(columns, field.Field),
 							db.GetValueFromDatabaseType(field.TypeName, res[field.Field]).String(), "")
 						list[j] = field.UpdateValue(id, rowValue, res)
+						if err := tb.loadHasMany(&list[j], id); err != nil {
+							return nil, nil, nil, "", "", err
+						}
 						break
 					}
 				}
@@ -793,6 +930,9 @@ func (tb DefaultTable) GetDataFromDatabaseWithId(id string) ([]types.FormField,
 		rowValue := modules.AorB(inArray(columns, field.Field),
 			db.GetValueFromDatabaseType(field.TypeName, res[field.Field]).String(), "")
 		formList[key] = field.UpdateValue(id, rowValue, res)
+		if err := tb.loadHasMany(&formList[key], id); err != nil {
+			return nil, nil, nil, "", "", err
+		}
 	}
 
 	return formList, groupFormList, groupHeaders, tb.form.Title, tb.form.Description, nil
@@ -800,6 +940,12 @@ func (tb DefaultTable) GetDataFromDatabaseWithId(id string) ([]types.FormField,
 
 // UpdateDataFromDatabase update data.
 func (tb DefaultTable) UpdateDataFromDatabase(dataList form.Values) error {
+	metrics.TableOperations.Inc(tb.form.Table, metrics.OperationUpdate)
+	defer tb.invalidate()
+
+	if err := tb.checkRowScope(dataList.Get(tb.primaryKey.Name)); err != nil {
+		return err
+	}
 
 	if tb.form.Validator != nil {
 		if err := tb.form.Validator(dataList); err != nil {
@@ -818,22 +964,26 @@ func (tb DefaultTable) UpdateDataFromDatabase(dataList form.Values) error {
 		}
 	}
 
-	_, err := tb.sql().Table(tb.form.Table).
-		Where(tb.primaryKey.Name, "=", dataList.Get(tb.primaryKey.Name)).
-		Update(tb.getInjectValueFromFormValue(dataList))
-
-	// TODO: some errors should be ignored.
-	if err != nil && !strings.Contains(err.Error(), "no affect") {
-		if tb.connectionDriver != db.DriverPostgresql {
+	if hasMany := tb.hasManyFields(); len(hasMany) > 0 {
+		if _, err := tb.saveWithHasMany(dataList, hasMany, false); err != nil {
 			return err
 		}
-		if !strings.Contains(err.Error(), "LastInsertId is not supported by this driver") {
-			return err
+	} else {
+		_, err := tb.withScope(tb.sql().Table(tb.form.Table).
+			Where(tb.primaryKey.Name, "=", dataList.Get(tb.primaryKey.Name)), tb.form.Table).
+			Update(tb.getInjectValueFromFormValue(dataList))
+
+		// TODO: some errors should be ignored.
+		if err != nil && !strings.Contains(err.Error(), "no affect") {
+			if tb.connectionDriver != db.DriverPostgresql {
+				return err
+			}
+			if !strings.Contains(err.Error(), "LastInsertId is not supported by this driver") {
+				return err
+			}
 		}
 	}
 
-	// NOTE: Database Transaction may be considered here.
-
 	if tb.form.PostHook != nil {
 		go func() {
 
@@ -857,6 +1007,8 @@ func (tb DefaultTable) UpdateDataFromDatabase(dataList form.Values) error {
 
 // InsertDataFromDatabase insert data.
 func (tb DefaultTable) InsertDataFromDatabase(dataList form.Values) error {
+	metrics.TableOperations.Inc(tb.form.Table, metrics.OperationCreate)
+	defer tb.invalidate()
 
 	if tb.form.Validator != nil {
 		if err := tb.form.Validator(dataList); err != nil {
@@ -875,19 +1027,27 @@ func (tb DefaultTable) InsertDataFromDatabase(dataList form.Values) error {
 		}
 	}
 
-	id, err := tb.sql().Table(tb.form.Table).Insert(tb.getInjectValueFromFormValue(dataList))
-
-	// TODO: some errors should be ignored.
-	if err != nil {
-		if tb.connectionDriver != db.DriverPostgresql {
+	if hasMany := tb.hasManyFields(); len(hasMany) > 0 {
+		id, err := tb.saveWithHasMany(dataList, hasMany, true)
+		if err != nil {
 			return err
 		}
-		if !strings.Contains(err.Error(), "LastInsertId is not supported by this driver") {
-			return err
+		dataList.Add(tb.GetPrimaryKey().Name, id)
+	} else {
+		id, err := tb.sql().Table(tb.form.Table).Insert(tb.getInjectValueFromFormValue(dataList))
+
+		// TODO: some errors should be ignored.
+		if err != nil {
+			if tb.connectionDriver != db.DriverPostgresql {
+				return err
+			}
+			if !strings.Contains(err.Error(), "LastInsertId is not supported by this driver") {
+				return err
+			}
 		}
-	}
 
-	dataList.Add(tb.GetPrimaryKey().Name, strconv.Itoa(int(id)))
+		dataList.Add(tb.GetPrimaryKey().Name, strconv.Itoa(int(id)))
+	}
 
 	if tb.form.PostHook != nil {
 		go func() {
@@ -913,7 +1073,7 @@ func (tb DefaultTable) InsertDataFromDatabase(dataList form.Values) error {
 func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values) dialect.H {
 	value := make(dialect.H)
 
-	columnsModel, _ := tb.sql().Table(tb.form.Table).ShowColumns()
+	columnsModel, _ := tb.sql().Table(tb.form.Table).Cache().ShowColumns()
 
 	columns, auto := tb.getColumns(columnsModel)
 	var (
@@ -940,6 +1100,9 @@ func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values) dialect
 
 	for k, v := range dataList {
 		k = strings.Replace(k, "[]", "", -1)
+		if tb.denied[k] {
+			continue
+		}
 		if !modules.InArray(exceptString, k) {
 			if inArray(columns, k) {
 				delimiter := ","
@@ -980,8 +1143,14 @@ func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values) dialect
 
 // DeleteDataFromDatabase delete data.
 func (tb DefaultTable) DeleteDataFromDatabase(id string) error {
+	metrics.TableOperations.Inc(tb.info.Table, metrics.OperationDelete)
+	defer tb.invalidate()
 	idArr := strings.Split(id, ",")
 
+	if err := tb.checkRowScope(idArr...); err != nil {
+		return err
+	}
+
 	if tb.info.DeleteFn != nil {
 
 		if len(idArr) == 0 {
@@ -1019,25 +1188,69 @@ func (tb DefaultTable) DeleteDataFromDatabase(id string) error {
 }
 
 func (tb DefaultTable) delete(table, key, id string) {
-	_ = tb.sql().Table(table).
-		Where(key, "=", id).
+	_ = tb.withScope(tb.sql().Table(table).
+		Where(key, "=", id), table).
 		Delete()
 }
 
 // db is a helper function return raw db connection.
 func (tb DefaultTable) db() db.Connection {
-	return db.GetConnectionFromService(services.Get(tb.connectionDriver))
+	return db.GetConnectionFromService(tb.services().Get(tb.connectionDriver))
+}
+
+// readConnection return the connection name which the data set is read from.
+func (tb DefaultTable) readConnection(conn db.Connection, params parameter.Parameters) string {
+	if params.ReadPrimary {
+		return tb.connection
+	}
+	return conn.ReadConnection(tb.connection)
 }
 
-var services service.List
+// query query the data set from the read connection. The result is cached
+// with the tables unless the data set should be read from the primary.
+func (tb DefaultTable) query(conn db.Connection, params parameter.Parameters, tables []string,
+	statement string, args ...interface{}) ([]map[string]interface{}, error) {
+
+	readConn := tb.readConnection(conn, params)
 
+	if params.ReadPrimary || !cache.Enabled() {
+		return conn.QueryWithConnection(readConn, statement, args...)
+	}
+
+	namespace := db.CacheConn(conn, tb.connection)
+	res, err := cache.Remember(cache.Key("table", namespace, statement, args),
+		cache.TableTags(namespace, tables...), func() (interface{}, error) {
+			return conn.QueryWithConnection(readConn, statement, args...)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return db.CopyRows(res.([]map[string]interface{})), nil
+}
+
+// invalidate delete the cached data sets of the table.
+func (tb DefaultTable) invalidate() {
+	cache.InvalidateTables(db.CacheConn(tb.db(), tb.connection), tb.info.Table, tb.form.Table)
+}
+
+// SetServices set the services of the default list.
 func SetServices(srv service.List) {
-	services = srv
+	defaultList.SetServices(srv)
+}
+
+// services return the services of the table, which are injected by the
+// list, or the services of the default list.
+func (tb DefaultTable) services() service.List {
+	if tb.srv != nil {
+		return tb.srv
+	}
+	return defaultList.Services()
 }
 
 // sql is a helper function return db sql.
 func (tb DefaultTable) sql() *db.SQL {
-	return db.WithDriverAndConnection(tb.connection, db.GetConnectionFromService(services.Get(tb.connectionDriver)))
+	return db.WithDriverAndConnection(tb.connection, db.GetConnectionFromService(tb.services().Get(tb.connectionDriver)))
 }
 
 func GetNewFormList(groupHeaders []string,
@@ -0,0 +1,63 @@
+package views
+
+import "github.com/glvd/go-admin/modules/db"
+
+// schemas contains the statements which create the table saving the views
+// of the users.
+var schemas = map[string][]string{
+	db.DriverMysql: {
+		"CREATE TABLE IF NOT EXISTS `adm_views` (" +
+			"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+			"`user_id` int(10) unsigned NOT NULL," +
+			"`prefix` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`name` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`params` text COLLATE utf8mb4_unicode_ci NOT NULL," +
+			"`roles` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT ''," +
+			"`is_default` tinyint(1) NOT NULL DEFAULT '0'," +
+			"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+			"PRIMARY KEY (`id`)," +
+			"KEY `admin_views_prefix_index` (`prefix`)" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+	},
+	db.DriverPostgresql: {
+		`CREATE TABLE IF NOT EXISTS adm_views (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL,
+    prefix character varying(100) NOT NULL,
+    name character varying(100) NOT NULL,
+    params text NOT NULL,
+    roles character varying(255) NOT NULL DEFAULT '',
+    is_default smallint NOT NULL DEFAULT 0,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+)`,
+		`CREATE INDEX IF NOT EXISTS admin_views_prefix_index ON adm_views (prefix)`,
+	},
+	db.DriverSqlite: {
+		"CREATE TABLE IF NOT EXISTS `adm_views` (" +
+			"`id` integer PRIMARY KEY AUTOINCREMENT," +
+			"`user_id` integer NOT NULL," +
+			"`prefix` varchar(100) NOT NULL," +
+			"`name` varchar(100) NOT NULL," +
+			"`params` text NOT NULL," +
+			"`roles` varchar(255) NOT NULL DEFAULT ''," +
+			"`is_default` integer NOT NULL DEFAULT 0," +
+			"`created_at` timestamp DEFAULT CURRENT_TIMESTAMP," +
+			"`updated_at` timestamp DEFAULT CURRENT_TIMESTAMP)",
+		"CREATE INDEX IF NOT EXISTS `admin_views_prefix_index` ON `adm_views` (`prefix`)",
+	},
+	db.DriverMssql: {
+		`IF OBJECT_ID('adm_views', 'U') IS NULL CREATE TABLE adm_views (
+    id int IDENTITY(1,1) PRIMARY KEY,
+    user_id int NOT NULL,
+    prefix nvarchar(100) NOT NULL,
+    name nvarchar(100) NOT NULL,
+    params nvarchar(max) NOT NULL,
+    roles nvarchar(255) NOT NULL DEFAULT '',
+    is_default tinyint NOT NULL DEFAULT 0,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP
+)`,
+	},
+}
@@ -0,0 +1,77 @@
+//go:build sqlite
+// +build sqlite
+
+package views
+
+import (
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "views")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "admin.db")},
+	})
+	assert.Nil(t, Migrate(conn))
+
+	var (
+		store = NewStore(conn)
+		owner = models.UserModel{Id: 1}
+		other = models.UserModel{Id: 2}
+	)
+
+	assert.Nil(t, store.Save(View{UserId: 1, Prefix: "posts", Name: "drafts", Params: "state=0"}))
+	assert.Nil(t, store.Save(View{UserId: 1, Prefix: "posts", Name: "published", Params: "state=1",
+		IsDefault: true}))
+
+	list, err := store.List(owner, "posts")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list))
+	assert.Equal(t, "drafts", list[0].Name)
+
+	assert.Nil(t, store.Save(View{UserId: 1, Prefix: "posts", Name: " drafts ", Params: "state=2"}))
+	list, err = store.List(owner, "posts")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list))
+	assert.Equal(t, "state=2", list[0].Params)
+
+	v, ok, err := store.Default(owner, "posts")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "published", v.Name)
+
+	assert.Nil(t, store.SetDefault(owner, "posts", list[0].Id))
+	v, ok, err = store.Default(owner, "posts")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "drafts", v.Name)
+
+	assert.Nil(t, store.SetDefault(owner, "posts", 0))
+	_, ok, err = store.Default(owner, "posts")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, store.Delete(other, list[0].Id))
+	_, ok, err = store.Get(owner, "posts", list[0].Id)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	assert.Nil(t, store.Delete(owner, list[0].Id))
+	_, ok, err = store.Get(owner, "posts", list[0].Id)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.NotNil(t, store.Save(View{UserId: 1, Prefix: "posts", Name: " "}))
+}
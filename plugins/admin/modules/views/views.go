@@ -0,0 +1,289 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package views
+
+import (
+	"errors"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const tableName = "adm_views"
+
+// Key is the parameter of the list choosing the view by the id. The id "0"
+// chooses no view, so the default view is not applied.
+const Key = "__view"
+
+// ignoredKeys are the parameters of the list which are not saved in the
+// views.
+var ignoredKeys = []string{"__page", "__prefix", "_pjax", Key, "__goadmin_edit_pk", "__goadmin_detail_pk",
+	"__after", "__before"}
+
+// View is the named parameters of the list of a table saved by a user: the
+// filters, the columns, the sort and the page size.
+type View struct {
+	Id     int64
+	UserId int64
+	Prefix string
+	Name   string
+	// Params is the encoded query of the list, see Params.
+	Params string
+	// Roles are the slugs of the roles the view is shared with.
+	Roles []string
+	// IsDefault is true if the view is applied when the list is opened
+	// without parameters. Only the own views of a user can be the default.
+	IsDefault bool
+}
+
+// Values return the parameters of the view.
+func (v View) Values() url.Values {
+	values, _ := url.ParseQuery(v.Params)
+	return values
+}
+
+// Own return true if the view is saved by the user.
+func (v View) Own(user models.UserModel) bool {
+	return v.UserId == user.Id
+}
+
+// Visible return true if the view is saved by the user or shared with one
+// of the roles of the user.
+func (v View) Visible(user models.UserModel) bool {
+	if v.Own(user) {
+		return true
+	}
+	for _, slug := range v.Roles {
+		if user.CheckRole(slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// Params return the encoded query of the parameters saved in a view.
+func Params(values url.Values) string {
+	res := make(url.Values)
+	for key, value := range values {
+		if len(value) > 0 && value[0] != "" && !inArray(ignoredKeys, key) {
+			res[key] = value
+		}
+	}
+	return res.Encode()
+}
+
+// IsBare return true if the list is opened without parameters, such as
+// from the menu, when the default view is applied.
+func IsBare(values url.Values) bool {
+	for key := range values {
+		if key != "__prefix" && key != "_pjax" {
+			return false
+		}
+	}
+	return true
+}
+
+// Migrate create the table adm_views if not exist.
+func Migrate(conn db.Connection) error {
+	statements, ok := schemas[conn.Name()]
+	if !ok {
+		return errors.New("views: unsupported driver " + conn.Name())
+	}
+	for _, statement := range statements {
+		if _, err := conn.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Store saves the views into the database.
+type Store struct {
+	conn db.Connection
+}
+
+// NewStore return a store of the connection.
+func NewStore(conn db.Connection) *Store {
+	return &Store{conn: conn}
+}
+
+// List return the views of the table visible to the user, ordered by the
+// id.
+func (s *Store) List(user models.UserModel, prefix string) ([]View, error) {
+	items, err := db.WithDriver(s.conn).Table(tableName).
+		Where("prefix", "=", prefix).
+		OrderBy("id", "asc").
+		All()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]View, 0, len(items))
+	for _, item := range items {
+		v := View{
+			Id:        toInt(item["id"]),
+			UserId:    toInt(item["user_id"]),
+			Prefix:    toString(item["prefix"]),
+			Name:      toString(item["name"]),
+			Params:    toString(item["params"]),
+			IsDefault: toInt(item["is_default"]) == 1,
+		}
+		if roles := toString(item["roles"]); roles != "" {
+			v.Roles = strings.Split(roles, ",")
+		}
+		if v.Visible(user) {
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
+
+// Get return the view of the id visible to the user.
+func (s *Store) Get(user models.UserModel, prefix string, id int64) (View, bool, error) {
+	list, err := s.List(user, prefix)
+	if err != nil {
+		return View{}, false, err
+	}
+	for _, v := range list {
+		if v.Id == id {
+			return v, true, nil
+		}
+	}
+	return View{}, false, nil
+}
+
+// Default return the default view of the table of the user.
+func (s *Store) Default(user models.UserModel, prefix string) (View, bool, error) {
+	list, err := s.List(user, prefix)
+	if err != nil {
+		return View{}, false, err
+	}
+	for _, v := range list {
+		if v.IsDefault && v.Own(user) {
+			return v, true, nil
+		}
+	}
+	return View{}, false, nil
+}
+
+// Save save the view of the user, the own view of the same name is
+// replaced.
+func (s *Store) Save(v View) error {
+	v.Name = strings.TrimSpace(v.Name)
+	if v.Name == "" {
+		return errors.New("views: the name is empty")
+	}
+	if v.Prefix == "" {
+		return errors.New("views: the prefix is empty")
+	}
+
+	if v.IsDefault {
+		if err := s.clearDefault(v.UserId, v.Prefix); err != nil {
+			return err
+		}
+	}
+
+	var (
+		roles     = strings.Join(v.Roles, ",")
+		isDefault = 0
+	)
+	if v.IsDefault {
+		isDefault = 1
+	}
+
+	exist, err := first(db.WithDriver(s.conn).Table(tableName).
+		Where("user_id", "=", v.UserId).
+		Where("prefix", "=", v.Prefix).
+		Where("name", "=", v.Name))
+	if err != nil {
+		return err
+	}
+
+	if exist != nil {
+		_, err = s.conn.Exec("UPDATE "+tableName+" SET params = ?, roles = ?, is_default = ?, updated_at = ? "+
+			"WHERE id = ?", v.Params, roles, isDefault, time.Now().Format("2006-01-02 15:04:05"),
+			toInt(exist["id"]))
+	} else {
+		_, err = s.conn.Exec("INSERT INTO "+tableName+" (user_id, prefix, name, params, roles, is_default) "+
+			"VALUES (?, ?, ?, ?, ?, ?)", v.UserId, v.Prefix, v.Name, v.Params, roles, isDefault)
+	}
+	return err
+}
+
+// SetDefault set the own view of the id as the default view of the table,
+// the id 0 unset the default view.
+func (s *Store) SetDefault(user models.UserModel, prefix string, id int64) error {
+	if err := s.clearDefault(user.Id, prefix); err != nil {
+		return err
+	}
+	if id == 0 {
+		return nil
+	}
+	_, err := s.conn.Exec("UPDATE "+tableName+" SET is_default = 1 WHERE id = ? AND user_id = ? AND prefix = ?",
+		id, user.Id, prefix)
+	return err
+}
+
+// Delete delete the own view of the id.
+func (s *Store) Delete(user models.UserModel, id int64) error {
+	_, err := s.conn.Exec("DELETE FROM "+tableName+" WHERE id = ? AND user_id = ?", id, user.Id)
+	return err
+}
+
+func (s *Store) clearDefault(userId int64, prefix string) error {
+	_, err := s.conn.Exec("UPDATE "+tableName+" SET is_default = 0 WHERE user_id = ? AND prefix = ?",
+		userId, prefix)
+	return err
+}
+
+// first return the first row of the statement, nil if there is no row.
+func first(sql *db.SQL) (map[string]interface{}, error) {
+	item, err := sql.First()
+	if err != nil && err.Error() == "out of index" {
+		return nil, nil
+	}
+	return item, err
+}
+
+func inArray(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
+
+func toInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		i, _ := strconv.ParseInt(toString(value), 10, 64)
+		return i
+	}
+}
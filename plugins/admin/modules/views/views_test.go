@@ -0,0 +1,46 @@
+package views
+
+import (
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestParams(t *testing.T) {
+	values := url.Values{
+		"__page":            {"3"},
+		"__prefix":          {"posts"},
+		"__view":            {"2"},
+		"__pageSize":        {"20"},
+		"__sort":            {"id"},
+		"__sort_type":       {"asc"},
+		"title":             {"go & admin"},
+		"state":             {""},
+		"__search":          {"hello"},
+		"__goadmin_edit_pk": {"1"},
+	}
+	params := Params(values)
+	assert.Equal(t, "__pageSize=20&__search=hello&__sort=id&__sort_type=asc&title=go+%26+admin", params)
+	assert.Equal(t, "go & admin", View{Params: params}.Values().Get("title"))
+}
+
+func TestIsBare(t *testing.T) {
+	assert.True(t, IsBare(url.Values{"__prefix": {"posts"}, "_pjax": {"#pjax-container"}}))
+	assert.False(t, IsBare(url.Values{"__prefix": {"posts"}, "__page": {"1"}}))
+	assert.False(t, IsBare(url.Values{"__prefix": {"posts"}, Key: {"0"}}))
+}
+
+func TestView_Visible(t *testing.T) {
+	var (
+		owner  = models.UserModel{Id: 1}
+		editor = models.UserModel{Id: 2, Roles: []models.RoleModel{{Slug: "editor"}}}
+		guest  = models.UserModel{Id: 3}
+		v      = View{UserId: 1, Roles: []string{"editor"}}
+	)
+	assert.True(t, v.Own(owner))
+	assert.True(t, v.Visible(owner))
+	assert.False(t, v.Own(editor))
+	assert.True(t, v.Visible(editor))
+	assert.False(t, v.Visible(guest))
+}
@@ -4,67 +4,154 @@ import (
 	"github.com/glvd/go-admin/context"
 	"github.com/glvd/go-admin/modules/auth"
 	"github.com/glvd/go-admin/modules/config"
-	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/logger"
+	"github.com/glvd/go-admin/modules/metrics"
+	"github.com/glvd/go-admin/modules/middleware"
 	"github.com/glvd/go-admin/modules/service"
-	"github.com/glvd/go-admin/plugins/admin/controller"
 	"github.com/glvd/go-admin/plugins/admin/modules/guard"
 	"github.com/glvd/go-admin/template"
 )
 
-// InitRouter initialize the router and return the context.
+// InitRouter initialize the router of the global Admin plugin and return
+// the context.
 func InitRouter(prefix string, srv service.List) *context.App {
-	app := context.NewApp()
+	cfg := config.Get()
+	cfg.UrlPrefix = prefix
+	return App.initRouter(config.Normalize(cfg), srv)
+}
+
+// initRouter initialize the router with the controllers and the guards of
+// the admin, and return the context.
+func (admin *Admin) initRouter(cfg config.Config, srv service.List) *context.App {
+	// The proxies are kept by the app, so that the engines do not share them.
+	proxies, err := context.ParseTrustedProxies(cfg.Middleware.TrustedProxies...)
+	if err != nil {
+		logger.Error(err)
+	}
+	app := context.NewApp().Use(context.TrustProxies(proxies)).Use(middleware.FromConfig(cfg.Middleware)...)
 
-	route := app.Group(prefix, globalErrorHandler)
+	var (
+		h     = admin.handler
+		g     = guard.New(config.ServiceFrom(srv), srv, admin.tables)
+		route *context.RouterGroup
+	)
+
+	if h.MetricsEnabled() {
+		route = app.Group(cfg.Prefix(), metrics.Middleware, admin.globalErrorHandler)
+	} else {
+		route = app.Group(cfg.Prefix(), admin.globalErrorHandler)
+	}
+
+	limit := admin.rateLimit(cfg)
+
+	publicRoute := route.Group("/", limit...)
 
 	// auth
-	route.GET("/login", controller.ShowLogin)
-	route.POST("/signin", controller.Auth)
+	publicRoute.GET("/login", h.ShowLogin)
+	publicRoute.POST("/signin", h.Auth)
 
 	// auto install
-	route.GET("/install", controller.ShowInstall)
-	route.POST("/install/database/check", controller.CheckDatabase)
+	install := g.Install(h.Installer())
+	publicRoute.GET("/install", install, h.ShowInstall)
+	publicRoute.POST("/install/database/check", install, h.CheckDatabase)
+	publicRoute.POST("/install/database/init", install, h.InitDatabase)
+	publicRoute.POST("/install/admin", install, h.InstallAdmin)
+	publicRoute.POST("/install/config", install, h.InstallConfig)
 
-	for _, path := range template.Get(config.Get().Theme).GetAssetList() {
-		route.GET("/assets"+path, controller.Assets)
+	// the assets of all the themes are served, so that the theme can be
+	// switched by reloading the config.
+	assets := make(map[string]bool)
+	addAsset := func(paths []string) {
+		for _, path := range paths {
+			if !assets[path] {
+				assets[path] = true
+				publicRoute.GET("/assets"+path, h.Assets)
+			}
+		}
 	}
-
-	for _, path := range template.GetComponentAssetLists() {
-		route.GET("/assets"+path, controller.Assets)
+	for _, theme := range template.Themes() {
+		addAsset(template.Get(theme).GetAssetList())
 	}
+	addAsset(template.GetComponentAssetLists())
 
-	authRoute := route.Group("/", auth.Middleware(db.GetConnection(srv)))
+	authRoute := route.Group("/", append(context.Handlers{auth.NewInvoker(config.ServiceFrom(srv), h.Conn()).Middleware()}, limit...)...)
 
 	// auth
-	authRoute.GET("/logout", controller.Logout)
+	authRoute.GET("/logout", h.Logout)
 
-	conn := db.GetConnection(srv)
+	// metrics, the scrapers use the token instead of a login.
+	if h.MetricsEnabled() {
+		if h.MetricsToken() != "" {
+			publicRoute.GET("/metrics", h.MetricsAuth, h.Metrics)
+		} else {
+			authRoute.GET("/metrics", h.Metrics)
+		}
+	}
 
 	// menus
-	authRoute.POST("/menu/delete", guard.MenuDelete(conn), controller.DeleteMenu)
-	authRoute.POST("/menu/new", guard.MenuNew(srv), controller.NewMenu)
-	authRoute.POST("/menu/edit", guard.MenuEdit(srv), controller.EditMenu)
-	authRoute.POST("/menu/order", controller.MenuOrder)
-	authRoute.GET("/menu", controller.ShowMenu)
-	authRoute.GET("/menu/edit/show", controller.ShowEditMenu)
-	authRoute.GET("/menu/new", controller.ShowNewMenu)
+	authRoute.POST("/menu/delete", g.MenuDelete, h.DeleteMenu)
+	authRoute.POST("/menu/new", g.MenuNew, h.NewMenu)
+	authRoute.POST("/menu/edit", g.MenuEdit, h.EditMenu)
+	authRoute.POST("/menu/order", h.MenuOrder)
+	authRoute.GET("/menu", h.ShowMenu)
+	authRoute.GET("/menu/edit/show", h.ShowEditMenu)
+	authRoute.GET("/menu/new", h.ShowNewMenu)
 
 	// add delete modify query
-	authRoute.GET("/info/:__prefix/edit", guard.ShowForm(conn), controller.ShowForm)
-	authRoute.GET("/info/:__prefix/new", guard.ShowNewForm(conn), controller.ShowNewForm)
-	authRoute.POST("/edit/:__prefix", guard.EditForm(srv), controller.EditForm)
-	authRoute.POST("/new/:__prefix", guard.NewForm(srv), controller.NewForm)
-	authRoute.POST("/delete/:__prefix", guard.Delete(conn), controller.Delete)
-	authRoute.POST("/export/:__prefix", guard.Export(conn), controller.Export)
-	authRoute.GET("/info/:__prefix", controller.ShowInfo)
-	authRoute.GET("/info/:__prefix/detail", controller.ShowDetail)
+	authRoute.GET("/info/:__prefix/edit", g.ShowForm, h.ShowForm)
+	authRoute.GET("/info/:__prefix/new", g.ShowNewForm, h.ShowNewForm)
+	authRoute.POST("/edit/:__prefix", g.EditForm, h.EditForm)
+	authRoute.POST("/new/:__prefix", g.NewForm, h.NewForm)
+	authRoute.POST("/delete/:__prefix", g.Delete, h.Delete)
+	authRoute.POST("/export/:__prefix", g.Export, h.Export)
+	authRoute.GET("/info/:__prefix", h.ShowInfo)
+	authRoute.GET("/info/:__prefix/detail", h.ShowDetail)
 
-	authRoute.POST("/update/:__prefix", guard.Update, controller.Update)
+	authRoute.POST("/update/:__prefix", g.Update, h.Update)
+
+	// database status
+	authRoute.GET("/database", h.ShowDatabaseStatus)
+
+	// plugins
+	authRoute.GET("/plugins", h.ShowPlugins)
+	authRoute.POST("/plugins/enable", h.EnablePlugin)
+	authRoute.POST("/plugins/disable", h.DisablePlugin)
+
+	// dynamic tables
+	authRoute.GET("/dynamic", h.ShowDynamicTables)
+	authRoute.GET("/dynamic/new", h.ShowNewDynamicTable)
+	authRoute.GET("/dynamic/edit", h.ShowEditDynamicTable)
+	authRoute.POST("/dynamic/save", h.SaveDynamicTable)
+	authRoute.POST("/dynamic/delete", h.DeleteDynamicTable)
+
+	// views of the lists
+	authRoute.POST("/views/save", h.SaveView)
+	authRoute.POST("/views/default", h.SetDefaultView)
+	authRoute.POST("/views/delete", h.DeleteView)
 
 	return app
 }
 
-func globalErrorHandler(ctx *context.Context) {
-	defer controller.GlobalDeferHandler(ctx)
+func (admin *Admin) globalErrorHandler(ctx *context.Context) {
+	defer admin.handler.GlobalDeferHandler(ctx)
 	ctx.Next()
 }
+
+// rateLimit return the middleware dispatching the requests to the limiters
+// of the matched routes, it is empty if no limit is set.
+func (admin *Admin) rateLimit(cfg config.Config) context.Handlers {
+	if len(admin.rateLimits) == 0 {
+		return nil
+	}
+	limiters := make(map[string]context.Handler, len(admin.rateLimits))
+	for route, limiter := range admin.rateLimits {
+		limiters[cfg.Url(route)] = limiter
+	}
+	return context.Handlers{func(ctx *context.Context) {
+		if limiter, ok := limiters[ctx.Route()]; ok {
+			limiter(ctx)
+			return
+		}
+		ctx.Next()
+	}}
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/glvd/go-admin/modules/service"
+	"strings"
+)
+
+// The optional interfaces of the plugin lifecycle. A plugin can implement
+// any of them, the Engine checks them when the plugin is added and when
+// the Engine shuts down.
+
+// Info is implemented by the plugins which have a name and a version. The
+// name is used to declare the dependencies.
+type Info interface {
+	Name() string
+	Version() string
+}
+
+// Dependent is implemented by the plugins which depend on other plugins
+// or services. Dependencies return the names of the plugins, see Info,
+// or the keys of the services, such as "mysql". The plugin is initialized
+// after its dependencies.
+type Dependent interface {
+	Dependencies() []string
+}
+
+// Starter is implemented by the plugins which run something after they
+// are initialized, such as the migrations or the background goroutines.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by the plugins which should be stopped when the
+// Engine shuts down. The plugins are stopped in the reverse order of the
+// initialization, and the ctx is done when the shutdown times out.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// HealthChecker is implemented by the plugins which can report their health.
+// Health return nil if the plugin is healthy.
+type HealthChecker interface {
+	Health() error
+}
+
+// Lifecycle is a plugin implementing all the lifecycle interfaces.
+type Lifecycle interface {
+	Plugin
+	Info
+	Dependent
+	Starter
+	Stopper
+	HealthChecker
+}
+
+// Name return the name of the plugin, or empty if the plugin does not
+// implement Info.
+func Name(p Plugin) string {
+	if info, ok := p.(Info); ok {
+		return info.Name()
+	}
+	return ""
+}
+
+// Dependencies return the dependencies of the plugin, or nil if the plugin
+// does not implement Dependent.
+func Dependencies(p Plugin) []string {
+	if dep, ok := p.(Dependent); ok {
+		return dep.Dependencies()
+	}
+	return nil
+}
+
+// Sort sort the plugins so that each plugin comes after the plugins it
+// depends on, otherwise the order is kept. The dependencies are looked up
+// in the plugins, the installed plugins and the services. It return an
+// error if a dependency is missing or the dependencies are circular.
+func Sort(plugs []Plugin, installed []Plugin, services service.List) ([]Plugin, error) {
+	known := make(map[string]bool)
+	for _, p := range installed {
+		if name := Name(p); name != "" {
+			known[name] = true
+		}
+	}
+
+	index := make(map[string]int)
+	for i, p := range plugs {
+		name := Name(p)
+		if name == "" {
+			continue
+		}
+		if _, dup := index[name]; dup || known[name] {
+			return nil, fmt.Errorf("plugin %s added twice", name)
+		}
+		index[name] = i
+	}
+
+	// deps[i] is the indexes of the plugins which plugs[i] depends on.
+	deps := make([][]int, len(plugs))
+	for i, p := range plugs {
+		for _, dep := range Dependencies(p) {
+			if j, ok := index[dep]; ok {
+				if j == i {
+					return nil, fmt.Errorf("plugin %s depends on itself", dep)
+				}
+				deps[i] = append(deps[i], j)
+				continue
+			}
+			if known[dep] {
+				continue
+			}
+			if _, ok := services[dep]; ok {
+				continue
+			}
+			return nil, fmt.Errorf("plugin %s depends on %s which is not found", pluginName(p, i), dep)
+		}
+	}
+
+	var (
+		sorted = make([]Plugin, 0, len(plugs))
+		done   = make([]bool, len(plugs))
+	)
+	for len(sorted) < len(plugs) {
+		progressed := false
+		for i, p := range plugs {
+			if done[i] || !allDone(deps[i], done) {
+				continue
+			}
+			done[i] = true
+			sorted = append(sorted, p)
+			progressed = true
+			break
+		}
+		if !progressed {
+			circle := make([]string, 0)
+			for i, p := range plugs {
+				if !done[i] {
+					circle = append(circle, pluginName(p, i))
+				}
+			}
+			return nil, errors.New("circular plugin dependencies: " + strings.Join(circle, ", "))
+		}
+	}
+
+	return sorted, nil
+}
+
+func allDone(indexes []int, done []bool) bool {
+	for _, i := range indexes {
+		if !done[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func pluginName(p Plugin, i int) string {
+	if name := Name(p); name != "" {
+		return name
+	}
+	return fmt.Sprintf("#%d(%T)", i, p)
+}
@@ -0,0 +1,62 @@
+package plugins
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testPlugin struct {
+	name string
+	deps []string
+}
+
+func (p testPlugin) GetRequest() []context.Path                     { return nil }
+func (p testPlugin) GetHandler(url, method string) context.Handlers { return nil }
+func (p testPlugin) InitPlugin(services service.List)               {}
+func (p testPlugin) Name() string                                   { return p.name }
+func (p testPlugin) Version() string                                { return "v0.0.1" }
+func (p testPlugin) Dependencies() []string                         { return p.deps }
+
+type testService struct{}
+
+func (testService) Name() string { return "mysql" }
+
+func names(plugs []Plugin) []string {
+	list := make([]string, len(plugs))
+	for i, p := range plugs {
+		list[i] = Name(p)
+	}
+	return list
+}
+
+func TestSort(t *testing.T) {
+	services := service.List{"mysql": testService{}}
+
+	sorted, err := Sort([]Plugin{
+		testPlugin{name: "report", deps: []string{"admin", "cms"}},
+		testPlugin{name: "cms", deps: []string{"admin", "mysql"}},
+		testPlugin{name: "admin"},
+		testPlugin{name: "blog"},
+	}, nil, services)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"admin", "cms", "report", "blog"}, names(sorted))
+
+	sorted, err = Sort([]Plugin{testPlugin{name: "cms", deps: []string{"admin"}}},
+		[]Plugin{testPlugin{name: "admin"}}, services)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cms"}, names(sorted))
+
+	_, err = Sort([]Plugin{testPlugin{name: "cms", deps: []string{"redis"}}}, nil, services)
+	assert.EqualError(t, err, "plugin cms depends on redis which is not found")
+
+	_, err = Sort([]Plugin{
+		testPlugin{name: "a", deps: []string{"b"}},
+		testPlugin{name: "b", deps: []string{"a"}},
+	}, nil, services)
+	assert.EqualError(t, err, "circular plugin dependencies: a, b")
+
+	_, err = Sort([]Plugin{testPlugin{name: "admin"}}, []Plugin{testPlugin{name: "admin"}}, services)
+	assert.EqualError(t, err, "plugin admin added twice")
+}
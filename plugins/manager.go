@@ -0,0 +1,151 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package plugins
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/service"
+	"net/http"
+	"sync"
+)
+
+// ManagerName is the key of the plugin manager in the service list.
+const ManagerName = "plugins"
+
+// Manager keeps the installed plugins of an engine and their states, with
+// which the plugin manager page lists and switches the plugins of the
+// engine it is added to.
+type Manager struct {
+	lock      sync.RWMutex
+	installed []Plugin
+	disabled  map[string]bool
+}
+
+// NewManager return an empty plugin manager.
+func NewManager() *Manager {
+	return &Manager{
+		installed: make([]Plugin, 0),
+		disabled:  make(map[string]bool),
+	}
+}
+
+// Name implements the service.Service.
+func (m *Manager) Name() string {
+	return ManagerName
+}
+
+// Add add the plugins to the installed list, which is shown in the plugin
+// manager page. It is called by the Engine when the plugins are added.
+func (m *Manager) Add(plugs ...Plugin) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.installed = append(m.installed, plugs...)
+}
+
+// All return the installed plugins.
+func (m *Manager) All() []Plugin {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return append([]Plugin{}, m.installed...)
+}
+
+// Enabled check the plugin of the name is enabled or not. The plugins are
+// enabled by default.
+func (m *Manager) Enabled(name string) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return !m.disabled[name]
+}
+
+// SetEnabled enable or disable the plugin of the name. The routes of the
+// disabled plugin respond 404 until it is enabled again, see Switchable.
+func (m *Manager) SetEnabled(name string, enabled bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if enabled {
+		delete(m.disabled, name)
+	} else {
+		m.disabled[name] = true
+	}
+}
+
+// Switchable return the plugin whose routes respond 404 when it is disabled
+// in the manager. The plugin without name, see Info, can not be disabled
+// and is returned as it is.
+func (m *Manager) Switchable(p Plugin) Plugin {
+	name := Name(p)
+	if name == "" {
+		return p
+	}
+	return &switchable{Plugin: p, name: name, manager: m}
+}
+
+// global is the plugin manager of the default engine.
+var global = NewManager()
+
+// GlobalManager return the plugin manager of the default engine, with which
+// the package level functions work.
+func GlobalManager() *Manager {
+	return global
+}
+
+// ManagerFrom return the plugin manager of the services, or the global one
+// if the services do not carry one.
+func ManagerFrom(l service.List) *Manager {
+	if m, ok := l[ManagerName].(*Manager); ok {
+		return m
+	}
+	return global
+}
+
+// Add add the plugins to the installed list of the global manager.
+func Add(plugs ...Plugin) {
+	global.Add(plugs...)
+}
+
+// All return the installed plugins of the global manager.
+func All() []Plugin {
+	return global.All()
+}
+
+// Enabled check the plugin of the name is enabled or not in the global
+// manager.
+func Enabled(name string) bool {
+	return global.Enabled(name)
+}
+
+// SetEnabled enable or disable the plugin of the name in the global
+// manager.
+func SetEnabled(name string, enabled bool) {
+	global.SetEnabled(name, enabled)
+}
+
+// Switchable return the plugin whose routes respond 404 when it is disabled
+// in the global manager.
+func Switchable(p Plugin) Plugin {
+	return global.Switchable(p)
+}
+
+// switchable is a Plugin which handlers check the plugin is enabled.
+type switchable struct {
+	Plugin
+	name    string
+	manager *Manager
+}
+
+// GetHandler implements Plugin.GetHandler.
+func (s *switchable) GetHandler(url, method string) context.Handlers {
+	handlers := s.Plugin.GetHandler(url, method)
+	return append(context.Handlers{s.check}, handlers...)
+}
+
+func (s *switchable) check(ctx *context.Context) {
+	if !s.manager.Enabled(s.name) {
+		ctx.Data(http.StatusNotFound, "text/plain; charset=utf-8", []byte("404 page not found"))
+		ctx.Abort()
+		return
+	}
+	ctx.Next()
+}
@@ -0,0 +1,63 @@
+package plugins
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/service"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+type routePlugin struct {
+	testPlugin
+	app *context.App
+}
+
+func (p routePlugin) GetHandler(url, method string) context.Handlers {
+	return GetHandler(url, method, p.app)
+}
+
+func TestSwitchable(t *testing.T) {
+	app := context.NewApp()
+	app.GET("/blog", func(ctx *context.Context) {
+		ctx.WriteString("blog")
+	})
+	plug := Switchable(routePlugin{testPlugin: testPlugin{name: "blog"}, app: app})
+
+	serve := func() *context.Context {
+		ctx := context.NewContext(httptest.NewRequest("GET", "/blog", nil))
+		ctx.SetHandlers(plug.GetHandler("/blog", "get")).Next()
+		return ctx
+	}
+
+	assert.True(t, Enabled("blog"))
+	assert.Equal(t, 200, serve().Response.StatusCode)
+
+	SetEnabled("blog", false)
+	assert.False(t, Enabled("blog"))
+	assert.Equal(t, 404, serve().Response.StatusCode)
+
+	SetEnabled("blog", true)
+	assert.Equal(t, 200, serve().Response.StatusCode)
+
+	unnamed := testPlugin{}
+	assert.Equal(t, Plugin(unnamed), Switchable(unnamed))
+}
+
+func TestManager(t *testing.T) {
+	var (
+		first  = NewManager()
+		second = NewManager()
+	)
+	first.Add(testPlugin{name: "blog"})
+	first.SetEnabled("blog", false)
+
+	assert.Equal(t, 1, len(first.All()))
+	assert.Equal(t, 0, len(second.All()))
+	assert.False(t, first.Enabled("blog"))
+	assert.True(t, second.Enabled("blog"))
+	assert.True(t, Enabled("blog"))
+
+	assert.Equal(t, first, ManagerFrom(service.List{ManagerName: first}))
+	assert.Equal(t, GlobalManager(), ManagerFrom(service.List{}))
+}
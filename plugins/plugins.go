@@ -24,6 +24,13 @@ type Plugin interface {
 	InitPlugin(services service.List)
 }
 
+// ConflictReporter is implemented by the plugins which report the invalid
+// and conflicting routes of their apps, see context.App.Conflicts. They are
+// reported by the Engine when the routes are served.
+type ConflictReporter interface {
+	Conflicts() []error
+}
+
 // GetHandler is a help method for Plugin GetHandler.
 func GetHandler(url, method string, app *context.App) context.Handlers {
 
@@ -36,26 +43,35 @@ func GetHandler(url, method string, app *context.App) context.Handlers {
 	return handler
 }
 
+// LoadFromPlugin load the Plugin from the Go plugin file of the path. It
+// panics if the loading fails, see Load.
 func LoadFromPlugin(mod string) Plugin {
-
-	plug, err := plugin.Open(mod)
+	p, err := Load(mod)
 	if err != nil {
 		logger.Error("LoadFromPlugin err", err)
 		panic(err)
 	}
+	return p
+}
+
+// Load load the Plugin from the Go plugin file of the path, which should
+// export the symbol "Plugin" implementing Plugin.
+func Load(mod string) (Plugin, error) {
+
+	plug, err := plugin.Open(mod)
+	if err != nil {
+		return nil, err
+	}
 
 	symPlugin, err := plug.Lookup("Plugin")
 	if err != nil {
-		logger.Error("LoadFromPlugin err", err)
-		panic(err)
+		return nil, err
 	}
 
-	var p Plugin
 	p, ok := symPlugin.(Plugin)
 	if !ok {
-		logger.Error("LoadFromPlugin err: unexpected type from module symbol")
-		panic(errors.New("LoadFromPlugin err: unexpected type from module symbol"))
+		return nil, errors.New("LoadFromPlugin err: unexpected type from module symbol")
 	}
 
-	return p
+	return p, nil
 }
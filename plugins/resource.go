@@ -0,0 +1,135 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package plugins
+
+import (
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/glvd/go-admin/modules/service"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Menu is a menu item declared by a plugin. The menu items are synced into
+// the table adm_menu when the engine starts, see MenuProvider.
+type Menu struct {
+	// Key identifies the menu item in the plugin, default the Uri, or the
+	// Title if the Uri is empty.
+	Key   string
+	Title string
+	// Icon is the font awesome icon, see the package template/icon.
+	Icon     string
+	Uri      string
+	Header   string
+	Order    int64
+	Children []Menu
+}
+
+// GetKey return the key of the menu item.
+func (m Menu) GetKey() string {
+	if m.Key != "" {
+		return m.Key
+	}
+	if m.Uri != "" {
+		return m.Uri
+	}
+	return m.Title
+}
+
+// Permission is a permission declared by a plugin, which is synced into the
+// table adm_permissions and identified by the Slug. See PermissionProvider.
+type Permission struct {
+	Name       string
+	Slug       string
+	HttpMethod []string
+	HttpPath   []string
+}
+
+// MenuProvider is implemented by the plugins which declare menu items. The
+// plugin should implement Info, the name of which owns the menu items.
+// The new menu items are granted to the administrator role, the existing
+// ones are updated except the order and the parent which may be changed by
+// the administrators, and the menu items no longer declared are removed.
+type MenuProvider interface {
+	Menus() []Menu
+}
+
+// PermissionProvider is implemented by the plugins which declare
+// permissions. The plugin should implement Info, the name of which owns
+// the permissions. The new permissions are granted to the administrator
+// role, and the permissions no longer declared are removed.
+type PermissionProvider interface {
+	Permissions() []Permission
+}
+
+// AssetProvider is implemented by the plugins which have static assets,
+// as the template components do. The plugin should implement Info, and the
+// assets are served at "/assets/plugins/{name}{path}" under the url prefix.
+type AssetProvider interface {
+	GetAssetList() []string
+	GetAsset(path string) ([]byte, error)
+}
+
+// AssetURL return the url of the asset of the plugin under the url prefix
+// of the config.
+func AssetURL(cfg config.Config, name, asset string) string {
+	return cfg.Url("/assets/plugins/" + name + "/" + strings.TrimPrefix(asset, "/"))
+}
+
+// assetPlugin is the Plugin serving the assets of the plugins.
+type assetPlugin struct {
+	app *context.App
+}
+
+// AssetPlugin return a Plugin serving the assets of the plugins which
+// implement AssetProvider and Info under the url prefix of the config, or
+// nil if there is no asset.
+func AssetPlugin(plugs []Plugin, cfg config.Config) Plugin {
+	app := context.NewApp()
+	for _, plug := range plugs {
+		provider, ok := plug.(AssetProvider)
+		name := Name(plug)
+		if !ok || name == "" {
+			continue
+		}
+		for _, asset := range provider.GetAssetList() {
+			app.GET(AssetURL(cfg, name, asset), assetHandler(provider, asset))
+		}
+	}
+	if len(app.Requests) == 0 {
+		return nil
+	}
+	return &assetPlugin{app: app}
+}
+
+func assetHandler(provider AssetProvider, asset string) context.Handler {
+	return func(ctx *context.Context) {
+		data, err := provider.GetAsset(asset)
+		if err != nil {
+			ctx.Data(http.StatusNotFound, "text/plain; charset=utf-8", []byte("404 page not found"))
+			return
+		}
+		contentType := mime.TypeByExtension(path.Ext(asset))
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		ctx.Data(http.StatusOK, contentType, data)
+	}
+}
+
+// InitPlugin implements Plugin.InitPlugin.
+func (p *assetPlugin) InitPlugin(services service.List) {}
+
+// GetRequest implements Plugin.GetRequest.
+func (p *assetPlugin) GetRequest() []context.Path {
+	return p.app.Requests
+}
+
+// GetHandler implements Plugin.GetHandler.
+func (p *assetPlugin) GetHandler(url, method string) context.Handlers {
+	return GetHandler(url, method, p.app)
+}
@@ -0,0 +1,48 @@
+package plugins
+
+import (
+	"errors"
+	"github.com/glvd/go-admin/context"
+	"github.com/glvd/go-admin/modules/config"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+type assetTestPlugin struct {
+	testPlugin
+}
+
+func (p assetTestPlugin) GetAssetList() []string {
+	return []string{"/js/app.js"}
+}
+
+func (p assetTestPlugin) GetAsset(path string) ([]byte, error) {
+	if path == "/js/app.js" {
+		return []byte("alert(1)"), nil
+	}
+	return nil, errors.New(path + " not found")
+}
+
+func TestMenuGetKey(t *testing.T) {
+	assert.Equal(t, "blog", Menu{Key: "blog", Uri: "/info/posts", Title: "Posts"}.GetKey())
+	assert.Equal(t, "/info/posts", Menu{Uri: "/info/posts", Title: "Posts"}.GetKey())
+	assert.Equal(t, "Blog", Menu{Title: "Blog"}.GetKey())
+}
+
+func TestAssetPlugin(t *testing.T) {
+	assert.Nil(t, AssetPlugin([]Plugin{testPlugin{name: "blog"}}, config.Config{}))
+
+	plug := AssetPlugin([]Plugin{assetTestPlugin{testPlugin{name: "blog"}}}, config.Config{})
+	assert.NotNil(t, plug)
+	assert.Equal(t, "/assets/plugins/blog/js/app.js", plug.GetRequest()[0].URL)
+
+	prefixed := AssetPlugin([]Plugin{assetTestPlugin{testPlugin{name: "blog"}}},
+		config.Normalize(config.Config{UrlPrefix: "admin2"}))
+	assert.Equal(t, "/admin2/assets/plugins/blog/js/app.js", prefixed.GetRequest()[0].URL)
+
+	ctx := context.NewContext(httptest.NewRequest("GET", "/assets/plugins/blog/js/app.js", nil))
+	ctx.SetHandlers(plug.GetHandler("/assets/plugins/blog/js/app.js", "get")).Next()
+	assert.Equal(t, 200, ctx.Response.StatusCode)
+	assert.Contains(t, ctx.Response.Header.Get("Content-Type"), "javascript")
+}
@@ -0,0 +1,69 @@
+package install
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/glvd/go-admin/modules/language"
+	"github.com/glvd/go-admin/modules/logger"
+	"html/template"
+)
+
+type Install struct {
+	Name string
+}
+
+func GetInstallComponent() *Install {
+	return &Install{
+		Name: "install",
+	}
+}
+
+func (i *Install) GetTemplate() (*template.Template, string) {
+	tmpl, err := template.New("install_theme1").
+		Funcs(template.FuncMap{
+			"lang":     language.Get,
+			"langHtml": language.GetFromHtml,
+			"link": func(cdnUrl, prefixUrl, assetsUrl string) string {
+				if cdnUrl == "" {
+					return prefixUrl + assetsUrl
+				}
+				return cdnUrl + assetsUrl
+			},
+		}).
+		Parse(List["install/theme1"])
+
+	if err != nil {
+		logger.Error("Install GetTemplate Error: ", err)
+	}
+
+	return tmpl, "install_theme1"
+}
+
+// GetAssetList return nothing, the install page reuses the assets of the
+// login component.
+func (i *Install) GetAssetList() []string {
+	return []string{}
+}
+
+func (i *Install) GetAsset(name string) ([]byte, error) {
+	return nil, errors.New(name + " not found")
+}
+
+func (i *Install) IsAPage() bool {
+	return true
+}
+
+func (i *Install) GetName() string {
+	return "install"
+}
+
+func (i *Install) GetContent() template.HTML {
+	buffer := new(bytes.Buffer)
+	tmpl, defineName := i.GetTemplate()
+	err := tmpl.ExecuteTemplate(buffer, defineName, i)
+	if err != nil {
+		fmt.Println("ComposeHtml Error:", err)
+	}
+	return template.HTML(buffer.String())
+}
@@ -0,0 +1,176 @@
+package install
+
+var List = map[string]string{"install/theme1": `{{define "install_theme1"}}
+    <!DOCTYPE html>
+    <html>
+    <head>
+        <meta charset="utf-8">
+        <meta http-equiv="X-UA-Compatible" content="IE=edge">
+        <title>{{lang "install"}}</title>
+        <meta name="viewport" content="width=device-width, initial-scale=1">
+
+        <link rel="stylesheet" href="{{link .CdnUrl .UrlPrefix "/assets/login/dist/all.min.css"}}">
+
+        <style>
+            .install-step { display: none; }
+            .install-step.active { display: block; }
+            .install-form label { color: #666; font-weight: normal; }
+            .install-message { color: #dd4b39; min-height: 20px; }
+        </style>
+    </head>
+    <body>
+
+    <div class="container">
+        <div class="row" style="margin-top: 60px;">
+            <div class="col-md-6 col-md-offset-3">
+                <form id="install-form" class="fh5co-form install-form" onsubmit="return false;">
+                    <h2>{{lang "install"}}</h2>
+                    <input type="hidden" name="__token" value="{{.Token}}">
+
+                    <div class="install-step active" data-step="1">
+                        <h4>1. {{lang "database"}}</h4>
+                        <div class="form-group">
+                            <label for="driver">{{lang "driver"}}</label>
+                            <select class="form-control" id="driver" name="driver">
+                                {{range .Drivers}}
+                                    <option value="{{.}}">{{.}}</option>
+                                {{end}}
+                            </select>
+                        </div>
+                        <div class="server-field">
+                            <div class="form-group">
+                                <label for="host">{{lang "host"}}</label>
+                                <input type="text" class="form-control" id="host" name="host" value="127.0.0.1">
+                            </div>
+                            <div class="form-group">
+                                <label for="port">{{lang "port"}}</label>
+                                <input type="text" class="form-control" id="port" name="port" value="3306">
+                            </div>
+                            <div class="form-group">
+                                <label for="user">{{lang "username"}}</label>
+                                <input type="text" class="form-control" id="user" name="user" autocomplete="off">
+                            </div>
+                            <div class="form-group">
+                                <label for="pwd">{{lang "password"}}</label>
+                                <input type="password" class="form-control" id="pwd" name="pwd" autocomplete="off">
+                            </div>
+                            <div class="form-group">
+                                <label for="name">{{lang "database name"}}</label>
+                                <input type="text" class="form-control" id="name" name="name">
+                            </div>
+                        </div>
+                        <div class="form-group file-field" style="display: none;">
+                            <label for="file">{{lang "database file"}}</label>
+                            <input type="text" class="form-control" id="file" name="file" value="./admin.db">
+                        </div>
+                        <div class="form-group">
+                            <button class="btn btn-primary" data-action="database/check">{{lang "test connection"}}</button>
+                        </div>
+                    </div>
+
+                    <div class="install-step" data-step="2">
+                        <h4>2. {{lang "create tables"}}</h4>
+                        <p id="table-list"></p>
+                        <div class="form-group">
+                            <button class="btn btn-primary" data-action="database/init">{{lang "create tables"}}</button>
+                        </div>
+                    </div>
+
+                    <div class="install-step" data-step="3">
+                        <h4>3. {{lang "super administrator"}}</h4>
+                        <div class="form-group">
+                            <label for="admin_username">{{lang "username"}}</label>
+                            <input type="text" class="form-control" id="admin_username" name="admin_username" value="admin">
+                        </div>
+                        <div class="form-group">
+                            <label for="admin_name">{{lang "nickname"}}</label>
+                            <input type="text" class="form-control" id="admin_name" name="admin_name" value="admin">
+                        </div>
+                        <div class="form-group">
+                            <label for="admin_password">{{lang "password"}}</label>
+                            <input type="password" class="form-control" id="admin_password" name="admin_password" autocomplete="off">
+                        </div>
+                        <div class="form-group">
+                            <button class="btn btn-primary" data-action="admin">{{lang "create"}}</button>
+                        </div>
+                    </div>
+
+                    <div class="install-step" data-step="4">
+                        <h4>4. {{lang "config file"}}</h4>
+                        <div class="form-group">
+                            <label for="prefix">{{lang "url prefix"}}</label>
+                            <input type="text" class="form-control" id="prefix" name="prefix" value="admin">
+                        </div>
+                        <div class="form-group">
+                            <label for="title">{{lang "title"}}</label>
+                            <input type="text" class="form-control" id="title" name="title" value="GoAdmin">
+                        </div>
+                        <div class="form-group">
+                            <label for="language">{{lang "language"}}</label>
+                            <select class="form-control" id="language" name="language">
+                                <option value="en">English</option>
+                                <option value="zh">简体中文</option>
+                                <option value="zh-Hant">繁體中文</option>
+                                <option value="ja">日本語</option>
+                            </select>
+                        </div>
+                        <p>{{lang "config file"}}: {{.ConfigPath}}</p>
+                        <div class="form-group">
+                            <button class="btn btn-primary" data-action="config">{{lang "finish"}}</button>
+                        </div>
+                    </div>
+
+                    <p class="install-message" id="install-message"></p>
+                </form>
+            </div>
+        </div>
+    </div>
+
+    <script src="{{link .CdnUrl .UrlPrefix "/assets/login/dist/all.min.js"}}"></script>
+
+    <script>
+        let ports = {"mysql": "3306", "postgresql": "5432", "mssql": "1433"};
+        let next = {"database/check": 2, "database/init": 3, "admin": 4};
+
+        $("#driver").change(function () {
+            let driver = $(this).val();
+            if (driver === "sqlite") {
+                $(".server-field").hide();
+                $(".file-field").show();
+            } else {
+                $(".server-field").show();
+                $(".file-field").hide();
+                $("#port").val(ports[driver]);
+            }
+        });
+
+        $("#install-form button").click(function () {
+            let action = $(this).data("action");
+            $("#install-message").text("");
+            $.ajax({
+                dataType: 'json',
+                type: 'POST',
+                url: '{{.UrlPrefix}}/install/' + action,
+                data: $("#install-form").serialize(),
+                success: function (data) {
+                    if (action === "database/check") {
+                        $("#table-list").text(data.data.list.length > 0 ? data.data.list.join(", ") : "");
+                    }
+                    if (action === "config") {
+                        location.href = data.data.url;
+                        return;
+                    }
+                    $(".install-step").removeClass("active");
+                    $(".install-step[data-step=" + next[action] + "]").addClass("active");
+                },
+                error: function (data) {
+                    let msg = data.responseJSON ? data.responseJSON.msg : data.statusText;
+                    $("#install-message").text(msg);
+                }
+            });
+        });
+    </script>
+
+    </body>
+    </html>
+{{end}}`}
@@ -11,6 +11,7 @@ import (
 	"html/template"
 	"path"
 	"plugin"
+	"sort"
 	"strings"
 	"sync"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/glvd/go-admin/modules/logger"
 	"github.com/glvd/go-admin/modules/menu"
 	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/glvd/go-admin/template/install"
 	"github.com/glvd/go-admin/template/login"
 	"github.com/glvd/go-admin/template/types"
 )
@@ -90,6 +92,16 @@ func Default() Template {
 	panic("wrong theme name")
 }
 
+// Themes return the sorted names of the registered templates.
+func Themes() []string {
+	themes := make([]string, 0, len(templateMap))
+	for name := range templateMap {
+		themes = append(themes, name)
+	}
+	sort.Strings(themes)
+	return themes
+}
+
 var (
 	templateMu sync.Mutex
 	compMu     sync.Mutex
@@ -163,7 +175,8 @@ type Component interface {
 }
 
 var compMap = map[string]Component{
-	"login": login.GetLoginComponent(),
+	"login":   login.GetLoginComponent(),
+	"install": install.GetInstallComponent(),
 }
 
 // GetComp gets the component by registered name. If the
@@ -0,0 +1,219 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/modules/utils"
+	"html/template"
+	"net/url"
+	"strings"
+)
+
+// DetailPanel is the read-only view of a single row, which is configured
+// separately from the info list. The detail page falls back to the fields
+// of the info panel when no field is added.
+type DetailPanel struct {
+	FieldList         FieldList
+	curFieldListIndex int
+
+	// Table is the table of the row, default the table of the info panel.
+	Table       string
+	Title       string
+	Description string
+
+	TabGroups  TabGroups
+	TabHeaders TabHeaders
+
+	// Related are the lists of the related rows shown under the fields.
+	Related []DetailRelated
+
+	// Buttons are shown in the header, "{id}" in the html and the js of the
+	// actions is replaced by the primary key of the row.
+	Buttons Buttons
+
+	processChains DisplayProcessFnChains
+}
+
+// DetailRelated is the list of the rows of the other table which reference
+// the row by the foreign key. The columns are the fields of the info panel,
+// and the count of the rows is limited by its default page size.
+type DetailRelated struct {
+	Title string
+	// ForeignKey is the column of the related table referencing the row.
+	ForeignKey string
+	// PrimaryKey is the primary key of the related table by which the rows
+	// are ordered, default "id".
+	PrimaryKey string
+	Info       *InfoPanel
+}
+
+// GetPrimaryKey return the primary key of the related table.
+func (d DetailRelated) GetPrimaryKey() string {
+	if d.PrimaryKey == "" {
+		return "id"
+	}
+	return d.PrimaryKey
+}
+
+func NewDetailPanel() *DetailPanel {
+	return &DetailPanel{
+		curFieldListIndex: -1,
+		Related:           make([]DetailRelated, 0),
+		Buttons:           make(Buttons, 0),
+		processChains:     make(DisplayProcessFnChains, 0),
+	}
+}
+
+// Valid return true if the fields of the detail panel are set.
+func (d *DetailPanel) Valid() bool {
+	return len(d.FieldList) > 0
+}
+
+func (d *DetailPanel) AddField(head, field string, typeName db.DatabaseType) *DetailPanel {
+	d.FieldList = append(d.FieldList, Field{
+		Head:     head,
+		Field:    field,
+		TypeName: typeName,
+		FieldDisplay: FieldDisplay{
+			Display: func(value FieldModel) interface{} {
+				return value.Value
+			},
+			DisplayProcessChains: chooseDisplayProcessChains(d.processChains),
+		},
+	})
+	d.curFieldListIndex++
+	return d
+}
+
+// AddRelated add the list of the related rows.
+func (d *DetailPanel) AddRelated(title, foreignKey string, info *InfoPanel) *DetailPanel {
+	d.Related = append(d.Related, DetailRelated{
+		Title:      title,
+		ForeignKey: foreignKey,
+		Info:       info,
+	})
+	return d
+}
+
+func (d *DetailPanel) AddButton(title template.HTML, icon string, action Action, color ...template.HTML) *DetailPanel {
+	id := "detail-btn-" + utils.Uuid(10)
+	action.SetBtnId(id)
+	btn := Button{Title: title, Id: id, Action: action, Icon: icon}
+	if len(color) > 0 {
+		btn.Color = color[0]
+	}
+	if len(color) > 1 {
+		btn.TextColor = color[1]
+	}
+	d.Buttons = append(d.Buttons, btn)
+	return d
+}
+
+func (d *DetailPanel) AddXssFilter() *DetailPanel {
+	d.processChains = addXssFilter(d.processChains)
+	return d
+}
+
+func (d *DetailPanel) AddXssJsFilter() *DetailPanel {
+	d.processChains = addXssJsFilter(d.processChains)
+	return d
+}
+
+// Field attribute setting functions
+// ====================================================
+
+func (d *DetailPanel) FieldDisplay(filter FieldFilterFn) *DetailPanel {
+	d.FieldList[d.curFieldListIndex].Display = filter
+	return d
+}
+
+// FieldVisibleTo set the grant of the users who can see the field.
+func (d *DetailPanel) FieldVisibleTo(grant Grant) *DetailPanel {
+	d.FieldList[d.curFieldListIndex].VisibleTo = grant
+	return d
+}
+
+// FieldImage display the value as the src of an image, the width and the
+// height are css sizes such as "120px", empty means auto.
+func (d *DetailPanel) FieldImage(width, height string) *DetailPanel {
+	return d.FieldDisplay(func(value FieldModel) interface{} {
+		if value.Value == "" {
+			return template.HTML("")
+		}
+		return template.HTML(`<img src="` + template.HTMLEscapeString(value.Value) + `" style="max-width:100%;` +
+			`width:` + template.HTMLEscapeString(defaultSize(width)) + `;height:` +
+			template.HTMLEscapeString(defaultSize(height)) + `">`)
+	})
+}
+
+// FieldLink display the value as a link. "{value}" in the url is replaced
+// by the escaped value, an empty url means the value is the url. The url
+// with a scheme other than http, https and mailto is replaced by "#".
+func (d *DetailPanel) FieldLink(link string) *DetailPanel {
+	return d.FieldDisplay(func(value FieldModel) interface{} {
+		if value.Value == "" {
+			return template.HTML("")
+		}
+		href := value.Value
+		if link != "" {
+			href = strings.Replace(link, "{value}", url.QueryEscape(value.Value), -1)
+		}
+		return template.HTML(`<a href="` + template.HTMLEscapeString(safeURL(href)) + `" target="_blank">` +
+			template.HTMLEscapeString(value.Value) + `</a>`)
+	})
+}
+
+// FieldJSON display the value as the indented json, the invalid json is
+// displayed as it is.
+func (d *DetailPanel) FieldJSON() *DetailPanel {
+	return d.FieldDisplay(func(value FieldModel) interface{} {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(value.Value), "", "  "); err != nil {
+			return template.HTML(`<pre>` + template.HTMLEscapeString(value.Value) + `</pre>`)
+		}
+		return template.HTML(`<pre>` + template.HTMLEscapeString(buf.String()) + `</pre>`)
+	})
+}
+
+// FieldMarkdown display the value as markdown, see Markdown.
+func (d *DetailPanel) FieldMarkdown() *DetailPanel {
+	return d.FieldDisplay(func(value FieldModel) interface{} {
+		return Markdown(value.Value)
+	})
+}
+
+// DetailPanel attribute setting functions
+// ====================================================
+
+func (d *DetailPanel) SetTable(table string) *DetailPanel {
+	d.Table = table
+	return d
+}
+
+func (d *DetailPanel) SetTitle(title string) *DetailPanel {
+	d.Title = title
+	return d
+}
+
+func (d *DetailPanel) SetDescription(desc string) *DetailPanel {
+	d.Description = desc
+	return d
+}
+
+func (d *DetailPanel) SetTabGroups(groups TabGroups) *DetailPanel {
+	d.TabGroups = groups
+	return d
+}
+
+func (d *DetailPanel) SetTabHeaders(headers ...string) *DetailPanel {
+	d.TabHeaders = headers
+	return d
+}
+
+func defaultSize(size string) string {
+	if size == "" {
+		return "auto"
+	}
+	return size
+}
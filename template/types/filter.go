@@ -0,0 +1,220 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// ConditionOperator is the operator of a condition of the advanced filter.
+type ConditionOperator string
+
+const (
+	ConditionEqual          ConditionOperator = "eq"
+	ConditionNotEqual       ConditionOperator = "ne"
+	ConditionGreater        ConditionOperator = "gr"
+	ConditionGreaterOrEqual ConditionOperator = "gq"
+	ConditionLess           ConditionOperator = "le"
+	ConditionLessOrEqual    ConditionOperator = "lq"
+	ConditionContains       ConditionOperator = "like"
+	ConditionStartsWith     ConditionOperator = "starts"
+	ConditionEndsWith       ConditionOperator = "ends"
+	ConditionBetween        ConditionOperator = "between"
+	ConditionIn             ConditionOperator = "in"
+	ConditionNotIn          ConditionOperator = "not_in"
+	ConditionNull           ConditionOperator = "null"
+	ConditionNotNull        ConditionOperator = "not_null"
+	ConditionToday          ConditionOperator = "today"
+	ConditionLastDays       ConditionOperator = "last_days"
+	ConditionNextDays       ConditionOperator = "next_days"
+)
+
+// ConditionOperators are the operators of the advanced filter in the order
+// of the builder.
+var ConditionOperators = []ConditionOperator{
+	ConditionEqual, ConditionNotEqual, ConditionGreater, ConditionGreaterOrEqual, ConditionLess,
+	ConditionLessOrEqual, ConditionContains, ConditionStartsWith, ConditionEndsWith, ConditionBetween,
+	ConditionIn, ConditionNotIn, ConditionNull, ConditionNotNull, ConditionToday, ConditionLastDays,
+	ConditionNextDays,
+}
+
+// Valid return true if the operator is known.
+func (o ConditionOperator) Valid() bool {
+	for _, op := range ConditionOperators {
+		if op == o {
+			return true
+		}
+	}
+	return false
+}
+
+// Arity return the count of the values of the operator, -1 means a list
+// of at least one value.
+func (o ConditionOperator) Arity() int {
+	switch o {
+	case ConditionNull, ConditionNotNull, ConditionToday:
+		return 0
+	case ConditionBetween:
+		return 2
+	case ConditionIn, ConditionNotIn:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// Label return the language key of the label of the operator.
+func (o ConditionOperator) Label() string {
+	switch o {
+	case ConditionEqual:
+		return "equal to"
+	case ConditionNotEqual:
+		return "not equal to"
+	case ConditionGreater:
+		return "greater than"
+	case ConditionGreaterOrEqual:
+		return "greater than or equal to"
+	case ConditionLess:
+		return "less than"
+	case ConditionLessOrEqual:
+		return "less than or equal to"
+	case ConditionContains:
+		return "contains"
+	case ConditionStartsWith:
+		return "starts with"
+	case ConditionEndsWith:
+		return "ends with"
+	case ConditionBetween:
+		return "between"
+	case ConditionIn:
+		return "in list"
+	case ConditionNotIn:
+		return "not in list"
+	case ConditionNull:
+		return "is null"
+	case ConditionNotNull:
+		return "is not null"
+	case ConditionToday:
+		return "today"
+	case ConditionLastDays:
+		return "in the last days"
+	case ConditionNextDays:
+		return "in the next days"
+	default:
+		return string(o)
+	}
+}
+
+const (
+	// FilterMaxDepth is the max depth of the nested groups.
+	FilterMaxDepth = 5
+	// FilterMaxConditions is the max count of the conditions of a filter.
+	FilterMaxConditions = 100
+	// FilterMaxDays is the max days of the relative dates.
+	FilterMaxDays = 36500
+)
+
+// FilterGroup is the conditions and the nested groups of the advanced
+// filter combined by the logic "and" or "or".
+type FilterGroup struct {
+	Logic      string            `json:"logic"`
+	Conditions []FilterCondition `json:"conditions,omitempty"`
+	Groups     []FilterGroup     `json:"groups,omitempty"`
+}
+
+// FilterCondition compares the field with the values by the operator. The
+// values of the relative dates are the days.
+type FilterCondition struct {
+	Field    string            `json:"field"`
+	Operator ConditionOperator `json:"op"`
+	Values   []string          `json:"values,omitempty"`
+}
+
+// ParseFilterGroup parse the filter group serialized by Encode, and
+// validate the logics, the operators and the values.
+func ParseFilterGroup(value string) (FilterGroup, error) {
+	var g FilterGroup
+	if value == "" {
+		return g, nil
+	}
+	if err := json.Unmarshal([]byte(value), &g); err != nil {
+		return FilterGroup{}, errors.New("invalid filter: " + err.Error())
+	}
+	count := 0
+	if err := g.validate(1, &count); err != nil {
+		return FilterGroup{}, err
+	}
+	return g, nil
+}
+
+// Encode return the serialized filter group, which is the value of the
+// parameter "__filter" of the list.
+func (g FilterGroup) Encode() string {
+	if g.Empty() {
+		return ""
+	}
+	value, _ := json.Marshal(g)
+	return string(value)
+}
+
+// Empty return true if the group has no condition.
+func (g FilterGroup) Empty() bool {
+	if len(g.Conditions) > 0 {
+		return false
+	}
+	for _, group := range g.Groups {
+		if !group.Empty() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsOr return true if the conditions are combined by "or".
+func (g FilterGroup) IsOr() bool {
+	return g.Logic == "or"
+}
+
+func (g FilterGroup) validate(depth int, count *int) error {
+	if depth > FilterMaxDepth {
+		return errors.New("invalid filter: the groups are nested too deep")
+	}
+	if g.Logic != "" && g.Logic != "and" && g.Logic != "or" {
+		return errors.New("invalid filter: unknown logic " + g.Logic)
+	}
+	for _, c := range g.Conditions {
+		*count++
+		if *count > FilterMaxConditions {
+			return errors.New("invalid filter: too many conditions")
+		}
+		if err := c.validate(); err != nil {
+			return err
+		}
+	}
+	for _, group := range g.Groups {
+		if err := group.validate(depth+1, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c FilterCondition) validate() error {
+	if c.Field == "" {
+		return errors.New("invalid filter: the field is empty")
+	}
+	if !c.Operator.Valid() {
+		return errors.New("invalid filter: unknown operator " + string(c.Operator))
+	}
+	arity := c.Operator.Arity()
+	if (arity >= 0 && len(c.Values) != arity) || (arity < 0 && len(c.Values) == 0) {
+		return errors.New("invalid filter: wrong count of the values of " + c.Field)
+	}
+	if c.Operator == ConditionLastDays || c.Operator == ConditionNextDays {
+		days, err := strconv.Atoi(c.Values[0])
+		if err != nil || days < 1 || days > FilterMaxDays {
+			return errors.New("invalid filter: wrong days of " + c.Field)
+		}
+	}
+	return nil
+}
@@ -61,6 +61,14 @@ type FormField struct {
 	HelpMsg   template.HTML
 	OptionExt template.JS
 
+	// HasMany is the sub form of the child rows, see FormPanel.AddHasMany.
+	HasMany *HasManyForm
+
+	// VisibleTo is the grant of the users who can see the field, and
+	// EditableBy is the grant of the users who can change it.
+	VisibleTo  Grant
+	EditableBy Grant
+
 	FieldDisplay
 	PostFilterFn PostFieldFilterFn
 }
@@ -217,6 +225,20 @@ func (f *FormPanel) FieldDefault(def string) *FormPanel {
 	return f
 }
 
+// FieldVisibleTo set the grant of the users who can see the field, the field
+// is removed from the form for the others.
+func (f *FormPanel) FieldVisibleTo(grant Grant) *FormPanel {
+	f.FieldList[f.curFieldListIndex].VisibleTo = grant
+	return f
+}
+
+// FieldEditableBy set the grant of the users who can change the field, the
+// field is read-only for the others.
+func (f *FormPanel) FieldEditableBy(grant Grant) *FormPanel {
+	f.FieldList[f.curFieldListIndex].EditableBy = grant
+	return f
+}
+
 func (f *FormPanel) FieldNotAllowEdit() *FormPanel {
 	f.FieldList[f.curFieldListIndex].Editable = false
 	return f
@@ -0,0 +1,224 @@
+package types
+
+import (
+	"fmt"
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/plugins/admin/modules"
+	"github.com/glvd/go-admin/plugins/admin/modules/form"
+	form2 "github.com/glvd/go-admin/template/types/form"
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+// HasManyForm is the sub form of the rows of the child table which reference
+// the row of the form by the foreign key. The child rows are added,
+// removed and reordered inline in the form, and saved with the row in a
+// transaction.
+type HasManyForm struct {
+	// Table is the child table.
+	Table string
+	// ForeignKey is the column of the child table referencing the row.
+	ForeignKey string
+	// PrimaryKey is the primary key of the child table, default "id".
+	PrimaryKey string
+	// OrderField is the column saving the positions of the child rows,
+	// empty means the positions are not saved.
+	OrderField string
+	// Fields are the fields of the child rows, such as the FieldList of a
+	// FormPanel.
+	Fields FormFields
+}
+
+// GetPrimaryKey return the primary key of the child table.
+func (h HasManyForm) GetPrimaryKey() string {
+	if h.PrimaryKey == "" {
+		return "id"
+	}
+	return h.PrimaryKey
+}
+
+// HasManyInputName return the name of the input of the child field in the
+// row of the has many field.
+func HasManyInputName(field, row, child string) string {
+	return field + "[" + row + "][" + child + "]"
+}
+
+// HasManyOrderName return the name of the inputs posting the keys of the
+// child rows in order.
+func HasManyOrderName(field string) string {
+	return "__goadmin_has_many_" + field
+}
+
+// ParseHasMany return the posted child rows of the has many field in the
+// order of the form.
+func ParseHasMany(field string, values form.Values) []form.Values {
+	rows := make([]form.Values, 0)
+	for _, row := range values[HasManyOrderName(field)] {
+		var (
+			prefix = field + "[" + row + "]["
+			value  = make(form.Values)
+		)
+		for key, v := range values {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			child := strings.TrimSuffix(strings.TrimSuffix(key[len(prefix):], "[]"), "]")
+			value[child] = v
+		}
+		rows = append(rows, value)
+	}
+	return rows
+}
+
+// AddHasMany add the has many field of the child rows.
+func (f *FormPanel) AddHasMany(head, field string, rel HasManyForm) *FormPanel {
+	f.AddField(head, field, db.Varchar, form2.Custom)
+	f.FieldList[f.curFieldListIndex].HasMany = &rel
+	f.FieldList[f.curFieldListIndex].CustomContent = rel.Content(field, nil)
+	f.FieldList[f.curFieldListIndex].CustomJs = hasManyJs
+	return f
+}
+
+// Content return the sub form of the child rows.
+func (h HasManyForm) Content(field string, rows []map[string]interface{}) template.HTML {
+	var (
+		esc  = template.HTMLEscapeString
+		html = `<div class="has-many" style="width:100%"><table class="table table-condensed"><thead><tr>`
+	)
+	for _, child := range h.Fields {
+		if !child.Hide {
+			html += `<th>` + esc(child.Head) + `</th>`
+		}
+	}
+	html += `<th style="width:110px"></th></tr></thead><tbody>`
+	for i, row := range rows {
+		html += h.row(field, strconv.Itoa(i), row)
+	}
+	html += `</tbody></table>` +
+		`<script type="text/template" class="has-many-template">` +
+		strings.Replace(h.row(field, "__ROW__", nil), "</script>", `<\/script>`, -1) + `</script>` +
+		`<button type="button" class="btn btn-sm btn-default has-many-add"><i class="fa fa-plus"></i></button></div>`
+	return template.HTML(html)
+}
+
+func (h HasManyForm) row(field, key string, row map[string]interface{}) string {
+	var (
+		esc    = template.HTMLEscapeString
+		html   = `<tr>`
+		hidden = `<input type="hidden" name="` + esc(HasManyOrderName(field)) + `" value="` + esc(key) + `">`
+	)
+	if row != nil {
+		hidden += `<input type="hidden" name="` + esc(HasManyInputName(field, key, h.GetPrimaryKey())) + `" value="` +
+			esc(hasManyKey(row[h.GetPrimaryKey()])) + `">`
+	}
+	for _, child := range h.Fields {
+		value := string(child.Default)
+		if row != nil {
+			value = db.GetValueFromDatabaseType(child.TypeName, row[child.Field]).String()
+		}
+		name := HasManyInputName(field, key, child.Field)
+		if child.Hide {
+			hidden += `<input type="hidden" name="` + esc(name) + `" value="` + esc(value) + `">`
+			continue
+		}
+		html += `<td>` + hasManyInput(child, name, value, row == nil) + `</td>`
+	}
+	return html + `<td>` + hidden + `<div class="btn-group">` +
+		`<button type="button" class="btn btn-xs btn-default has-many-up"><i class="fa fa-arrow-up"></i></button>` +
+		`<button type="button" class="btn btn-xs btn-default has-many-down"><i class="fa fa-arrow-down"></i></button>` +
+		`<button type="button" class="btn btn-xs btn-danger has-many-remove"><i class="fa fa-trash"></i></button>` +
+		`</div></td></tr>`
+}
+
+func hasManyKey(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// hasManyInput return the input of the child field, the child rows are
+// edited with the plain inputs of the form types.
+func hasManyInput(child FormField, name, value string, isNew bool) string {
+	var (
+		esc      = template.HTMLEscapeString
+		attrs    = ` class="form-control input-sm" name="` + esc(name) + `"`
+		readOnly = (!isNew && !child.Editable) || (isNew && child.NotAllowAdd) || child.FormType == form2.Default
+	)
+	if readOnly {
+		return `<input type="hidden" name="` + esc(name) + `" value="` + esc(value) + `">` + esc(value)
+	}
+	if child.Must {
+		attrs += ` required`
+	}
+
+	switch child.FormType {
+	case form2.TextArea, form2.RichText:
+		return `<textarea rows="1"` + attrs + `>` + esc(value) + `</textarea>`
+	case form2.Select, form2.SelectBox, form2.SelectSingle, form2.Radio, form2.Switch:
+		var (
+			multiple = child.FormType.IsMultiSelect()
+			selected = []string{value}
+			html     = `<select` + attrs
+		)
+		if multiple {
+			selected = strings.Split(value, modules.SetDefault(child.DefaultOptionDelimiter, ","))
+			html = `<select multiple` + strings.Replace(attrs, `name="`+esc(name)+`"`, `name="`+esc(name)+`[]"`, 1)
+		}
+		html += `>`
+		if !multiple && !child.Must {
+			html += `<option value=""></option>`
+		}
+		for _, option := range child.Options {
+			html += `<option value="` + esc(option["value"]) + `"`
+			for _, v := range selected {
+				if v == option["value"] {
+					html += ` selected`
+					break
+				}
+			}
+			html += `>` + esc(option["field"]) + `</option>`
+		}
+		return html + `</select>`
+	}
+
+	typ := "text"
+	switch child.FormType {
+	case form2.Password:
+		typ = "password"
+	case form2.Email:
+		typ = "email"
+	case form2.Url:
+		typ = "url"
+	case form2.Number, form2.Currency:
+		typ = "number"
+	case form2.Color:
+		typ = "color"
+	}
+	return `<input type="` + typ + `" value="` + esc(value) + `"` + attrs + `>`
+}
+
+const hasManyJs = template.JS(`$(document).off('click.hasMany').
+on('click.hasMany', '.has-many-add', function () {
+    let box = $(this).closest('.has-many');
+    let key = 'new' + Date.now() + Math.floor(Math.random() * 1000);
+    box.find('tbody').first().append(box.find('.has-many-template').first().html().replace(/__ROW__/g, key));
+}).
+on('click.hasMany', '.has-many-remove', function () {
+    $(this).closest('tr').remove();
+}).
+on('click.hasMany', '.has-many-up', function () {
+    let tr = $(this).closest('tr');
+    tr.prev().before(tr);
+}).
+on('click.hasMany', '.has-many-down', function () {
+    let tr = $(this).closest('tr');
+    tr.next().after(tr);
+});`)
@@ -265,7 +265,8 @@ type Field struct {
 	Field    string
 	TypeName db.DatabaseType
 
-	Join Join
+	Join     Join
+	Relation Relation
 
 	Width      int
 	Sortable   bool
@@ -284,6 +285,9 @@ type Field struct {
 	FilterHead      string
 	FilterHelpMsg   template.HTML
 
+	// VisibleTo is the grant of the users who can see the field.
+	VisibleTo Grant
+
 	FieldDisplay
 }
 
@@ -327,6 +331,97 @@ func (j Join) Valid() bool {
 
 var JoinFieldValueDelimiter = utils.Uuid(8)
 
+// RelationType is the type of a relation.
+type RelationType uint8
+
+const (
+	// BelongsTo is the relation of which the foreign key is a column of the
+	// table, such as posts.author_id references users.id.
+	BelongsTo RelationType = iota + 1
+	// HasMany is the relation of which the foreign key is a column of the
+	// related table, such as comments.post_id references posts.id.
+	HasMany
+	// ManyToMany is the relation through a pivot table, such as
+	// adm_role_users references adm_users.id and adm_roles.id.
+	ManyToMany
+)
+
+// Relation is a relation field of the info panel. The related rows of all
+// the rows of a page are loaded in one query, and displayed as labels.
+type Relation struct {
+	Type RelationType
+	// Table is the related table.
+	Table string
+	// Field is the column of the related table which is displayed.
+	Field string
+	// Key is the column of the related table referenced, default "id".
+	Key string
+	// ForeignKey is the column of the table for BelongsTo, the column of
+	// the related table for HasMany, and the column of the pivot table
+	// referencing the table for ManyToMany.
+	ForeignKey string
+	// Pivot is the pivot table of ManyToMany.
+	Pivot string
+	// RelatedKey is the column of the pivot table referencing the related
+	// table for ManyToMany.
+	RelatedKey string
+	// Link is the url of a related row, in which "{key}" is replaced by the
+	// key of the row, such as "/admin/info/roles/edit?__goadmin_edit_pk={key}".
+	Link string
+}
+
+func (r Relation) Valid() bool {
+	if r.Table == "" || r.Field == "" || r.ForeignKey == "" {
+		return false
+	}
+	switch r.Type {
+	case BelongsTo, HasMany:
+		return true
+	case ManyToMany:
+		return r.Pivot != "" && r.RelatedKey != ""
+	default:
+		return false
+	}
+}
+
+// GetKey return the key of the related table.
+func (r Relation) GetKey() string {
+	if r.Key == "" {
+		return "id"
+	}
+	return r.Key
+}
+
+// Sortable return true when the rows can be ordered by the relation, which
+// is only the case of BelongsTo.
+func (r Relation) Sortable() bool {
+	return r.Type == BelongsTo
+}
+
+// RelationValue is a related row of a row.
+type RelationValue struct {
+	Key   string
+	Label string
+}
+
+// RelationValues are the related rows of a row.
+type RelationValues []RelationValue
+
+// Labels return the labels of the related rows.
+func (r RelationValues) Labels() []string {
+	labels := make([]string, len(r))
+	for i, value := range r {
+		labels[i] = value.Label
+	}
+	return labels
+}
+
+// RelationRowKey return the key of the row by which the related rows of
+// the relation field are stored, see InfoPanel.FieldRelation.
+func RelationRowKey(field string) string {
+	return "__goadmin_relation_" + field
+}
+
 type TabGroups [][]string
 
 func (t TabGroups) Valid() bool {
@@ -357,6 +452,21 @@ const (
 	SortAsc
 )
 
+// CountMode is how the total count of the rows of the list is got.
+type CountMode uint8
+
+const (
+	// CountExact counts the rows by count(*).
+	CountExact CountMode = iota
+	// CountSkip skips the count, the pages are navigated by the previous
+	// and the next only.
+	CountSkip
+	// CountApproximate estimates the count by the statistics of the table,
+	// the count is skipped when the list is filtered. Sqlite has no
+	// statistics and is counted exactly.
+	CountApproximate
+)
+
 // InfoPanel
 type InfoPanel struct {
 	FieldList         FieldList
@@ -375,6 +485,13 @@ type InfoPanel struct {
 	PageSizeList    []int
 	DefaultPageSize int
 
+	// IsKeysetPagination is true if the pages are navigated by the cursor
+	// of the sort column and the primary key instead of the offset, which
+	// keeps the queries fast on the large tables. The pages sorted by the
+	// nullable columns are navigated by the offset.
+	IsKeysetPagination bool
+	CountMode          CountMode
+
 	IsHideNewButton    bool
 	IsHideExportButton bool
 	IsHideEditButton   bool
@@ -385,8 +502,20 @@ type InfoPanel struct {
 	IsHideFilterArea   bool
 	FilterFormLayout   form.Layout
 
+	// IsShowAdvancedFilter is true if the builder of the nested and/or
+	// conditions of the filterable fields is shown, see FilterGroup.
+	IsShowAdvancedFilter bool
+
 	Wheres []Where
 
+	// RowScopes restrict the rows the user can access in the list, the
+	// export, the detail, the edit, the update and the deletion.
+	RowScopes []RowScope
+
+	// SearchFields are the columns searched by the search box of the list,
+	// which is shown when the fields are set.
+	SearchFields []string
+
 	Buttons Buttons
 
 	DeleteHook  DeleteFn
@@ -482,6 +611,12 @@ func (i *InfoPanel) Where(field string, operator string, arg interface{}) *InfoP
 	return i
 }
 
+// AddRowScope add the row scope restricting the rows the user can access.
+func (i *InfoPanel) AddRowScope(scope RowScope) *InfoPanel {
+	i.RowScopes = append(i.RowScopes, scope)
+	return i
+}
+
 func (i *InfoPanel) AddButton(title template.HTML, icon string, action Action, color ...template.HTML) *InfoPanel {
 	id := "info-btn-" + utils.Uuid(10)
 	action.SetBtnId(id)
@@ -726,6 +861,13 @@ func (i *InfoPanel) FieldFilterOptionExt(m map[string]interface{}) *InfoPanel {
 	return i
 }
 
+// FieldVisibleTo set the grant of the users who can see the field in the
+// list and the export.
+func (i *InfoPanel) FieldVisibleTo(grant Grant) *InfoPanel {
+	i.FieldList[i.curFieldListIndex].VisibleTo = grant
+	return i
+}
+
 func (i *InfoPanel) FieldHide() *InfoPanel {
 	i.FieldList[i.curFieldListIndex].Hide = true
 	return i
@@ -736,6 +878,18 @@ func (i *InfoPanel) FieldJoin(join Join) *InfoPanel {
 	return i
 }
 
+// FieldRelation set the relation of the field, the field is not a column of
+// the table. The value of the field is the escaped labels of the related
+// rows, which are displayed as they are. The related rows are stored in the
+// row by RelationRowKey as RelationValues for a custom FieldDisplay.
+func (i *InfoPanel) FieldRelation(rel Relation) *InfoPanel {
+	i.FieldList[i.curFieldListIndex].Relation = rel
+	i.FieldList[i.curFieldListIndex].Display = func(value FieldModel) interface{} {
+		return template.HTML(value.Value)
+	}
+	return i
+}
+
 func (i *InfoPanel) FieldLimit(limit int) *InfoPanel {
 	i.FieldList[i.curFieldListIndex].DisplayProcessChains = i.FieldList[i.curFieldListIndex].AddLimit(limit)
 	return i
@@ -792,6 +946,19 @@ func (i *InfoPanel) SetDefaultPageSize(defaultPageSize int) *InfoPanel {
 	return i
 }
 
+// UseKeysetPagination navigate the pages by the cursors instead of the
+// offsets.
+func (i *InfoPanel) UseKeysetPagination() *InfoPanel {
+	i.IsKeysetPagination = true
+	return i
+}
+
+// SetCountMode set how the total count of the rows is got.
+func (i *InfoPanel) SetCountMode(mode CountMode) *InfoPanel {
+	i.CountMode = mode
+	return i
+}
+
 func (i *InfoPanel) GetPageSizeList() []string {
 	var pageSizeList = make([]string, len(i.PageSizeList))
 	for j := 0; j < len(i.PageSizeList); j++ {
@@ -829,6 +996,14 @@ func (i *InfoPanel) SetDescription(desc string) *InfoPanel {
 	return i
 }
 
+// SetSearchFields set the columns searched by the search box of the list.
+// The full-text search of the driver is used when available, see the
+// table package for the indexes required.
+func (i *InfoPanel) SetSearchFields(fields ...string) *InfoPanel {
+	i.SearchFields = fields
+	return i
+}
+
 func (i *InfoPanel) SetFilterFormLayout(layout form.Layout) *InfoPanel {
 	i.FilterFormLayout = layout
 	return i
@@ -884,6 +1059,13 @@ func (i *InfoPanel) HidePagination() *InfoPanel {
 	return i
 }
 
+// ShowAdvancedFilter show the builder of the advanced filter, which
+// filters the filterable fields of the table with nested and/or groups.
+func (i *InfoPanel) ShowAdvancedFilter() *InfoPanel {
+	i.IsShowAdvancedFilter = true
+	return i
+}
+
 func (i *InfoPanel) HideFilterArea() *InfoPanel {
 	i.IsHideFilterArea = true
 	return i
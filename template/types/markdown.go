@@ -0,0 +1,127 @@
+package types
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownOrdered = regexp.MustCompile(`^\d+\.\s+(.*)$`)
+	markdownBullet  = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	markdownCode    = regexp.MustCompile("`([^`]+)`")
+	markdownBold    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownItalic  = regexp.MustCompile(`\*([^*]+)\*`)
+	markdownLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// Markdown render the basic markdown: headings, paragraphs, lists, quotes,
+// fenced code, inline code, bold, italic and links. The html in the text
+// is escaped.
+func Markdown(text string) template.HTML {
+	var (
+		out   strings.Builder
+		para  []string
+		list  string
+		code  bool
+		lines = strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+	)
+
+	flush := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + strings.Join(para, "<br>") + "</p>")
+			para = nil
+		}
+		if list != "" {
+			out.WriteString("</" + list + ">")
+			list = ""
+		}
+	}
+	item := func(tag, content string) {
+		if len(para) > 0 || (list != "" && list != tag) {
+			flush()
+		}
+		if list == "" {
+			out.WriteString("<" + tag + ">")
+			list = tag
+		}
+		out.WriteString("<li>" + markdownInline(content) + "</li>")
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if code {
+				out.WriteString("</code></pre>")
+			} else {
+				flush()
+				out.WriteString("<pre><code>")
+			}
+			code = !code
+			continue
+		}
+		if code {
+			out.WriteString(template.HTMLEscapeString(line) + "\n")
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+		} else if m := markdownHeading.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			tag := "h" + string(rune('0'+len(m[1])))
+			out.WriteString("<" + tag + ">" + markdownInline(m[2]) + "</" + tag + ">")
+		} else if m := markdownBullet.FindStringSubmatch(trimmed); m != nil {
+			item("ul", m[1])
+		} else if m := markdownOrdered.FindStringSubmatch(trimmed); m != nil {
+			item("ol", m[1])
+		} else if strings.HasPrefix(trimmed, ">") {
+			flush()
+			out.WriteString("<blockquote>" + markdownInline(strings.TrimSpace(trimmed[1:])) + "</blockquote>")
+		} else {
+			if list != "" {
+				flush()
+			}
+			para = append(para, markdownInline(trimmed))
+		}
+	}
+	if code {
+		out.WriteString("</code></pre>")
+	}
+	flush()
+
+	return template.HTML(out.String())
+}
+
+func markdownInline(text string) string {
+	text = template.HTMLEscapeString(text)
+	text = markdownCode.ReplaceAllString(text, "<code>$1</code>")
+	text = markdownLink.ReplaceAllStringFunc(text, func(s string) string {
+		m := markdownLink.FindStringSubmatch(s)
+		return `<a href="` + safeURL(m[2]) + `" target="_blank">` + m[1] + `</a>`
+	})
+	text = markdownBold.ReplaceAllString(text, "<strong>$1</strong>")
+	return markdownItalic.ReplaceAllString(text, "<em>$1</em>")
+}
+
+// safeURL return the url if it is relative or its scheme is http, https
+// or mailto, otherwise "#", so that the urls such as "javascript:" are not
+// rendered as links. The spaces and the control characters ignored by the
+// browsers are ignored in the scheme.
+func safeURL(href string) string {
+	s := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, href)
+	if i := strings.IndexAny(s, ":/?#"); i >= 0 && s[i] == ':' {
+		switch strings.ToLower(s[:i]) {
+		case "http", "https", "mailto":
+		default:
+			return "#"
+		}
+	}
+	return href
+}
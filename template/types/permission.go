@@ -0,0 +1,82 @@
+package types
+
+import (
+	"github.com/glvd/go-admin/plugins/admin/models"
+)
+
+// Grant is the roles and the permissions of which a user should have one to
+// be granted, an empty grant grants every user. The super administrator is
+// always granted.
+type Grant struct {
+	Roles       []string
+	Permissions []string
+}
+
+// GrantRoles return the grant of the roles of the slugs.
+func GrantRoles(slugs ...string) Grant {
+	return Grant{Roles: slugs}
+}
+
+// GrantPermissions return the grant of the permissions of the slugs.
+func GrantPermissions(slugs ...string) Grant {
+	return Grant{Permissions: slugs}
+}
+
+// Valid return true if the grant restricts the users.
+func (g Grant) Valid() bool {
+	return len(g.Roles) > 0 || len(g.Permissions) > 0
+}
+
+// Allow return true if the user is granted.
+func (g Grant) Allow(user models.UserModel) bool {
+	if !g.Valid() || user.IsSuperAdmin() {
+		return true
+	}
+	for _, slug := range g.Roles {
+		if user.CheckRole(slug) {
+			return true
+		}
+	}
+	for _, slug := range g.Permissions {
+		if user.CheckPermission(slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// RowScope return the condition and its arguments restricting the rows the
+// user can access, such as "orders.`owner_id` = ?" and the id of the user.
+// The columns should be qualified by the column function, so that they are
+// not ambiguous with the columns of the joined tables. An empty condition
+// means the user can access all the rows. The row scopes are not applied to
+// the super administrator.
+type RowScope func(user models.UserModel, column ColumnFunc) (string, []interface{})
+
+// ColumnFunc return the column of the name qualified by the table.
+type ColumnFunc func(name string) string
+
+// ScopeOwner return the row scope of the rows of which the column is the id
+// of the user.
+func ScopeOwner(name string) RowScope {
+	return func(user models.UserModel, column ColumnFunc) (string, []interface{}) {
+		return column(name) + " = ?", []interface{}{user.Id}
+	}
+}
+
+// ScopeIn return the row scope of the rows of which the column is in the
+// values of the user, such as the regions of the user. No row is accessible
+// if the values are empty.
+func ScopeIn(name string, values func(user models.UserModel) []interface{}) RowScope {
+	return func(user models.UserModel, column ColumnFunc) (string, []interface{}) {
+		args := values(user)
+		if len(args) == 0 {
+			return "1 = 0", nil
+		}
+		marks := "?"
+		for i := 1; i < len(args); i++ {
+			marks += ",?"
+		}
+		return column(name) + " in (" + marks + ")", args
+	}
+}
@@ -1,7 +1,13 @@
 package types
 
 import (
+	"github.com/glvd/go-admin/modules/db"
+	"github.com/glvd/go-admin/plugins/admin/models"
+	"github.com/glvd/go-admin/plugins/admin/modules/form"
+	form2 "github.com/glvd/go-admin/template/types/form"
 	"github.com/stretchr/testify/assert"
+	"html/template"
+	"strings"
 	"testing"
 )
 
@@ -20,3 +26,138 @@ func TestFieldOptions_SetSelected(t *testing.T) {
 	fo1.SetSelected("123", []string{"selected", ""})
 	assert.Equal(t, fo1[0]["selected"], "selected")
 }
+
+func TestParseHasMany(t *testing.T) {
+	rows := ParseHasMany("items", form.Values{
+		HasManyOrderName("items"): {"new1", "0"},
+		"items[0][id]":            {"3"},
+		"items[0][name]":          {"first"},
+		"items[new1][name]":       {"second"},
+		"items[new1][tags][]":     {"a", "b"},
+		"name":                    {"parent"},
+	})
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "second", rows[0].Get("name"))
+	assert.Equal(t, []string{"a", "b"}, rows[0]["tags"])
+	assert.Equal(t, "", rows[0].Get("id"))
+	assert.Equal(t, "3", rows[1].Get("id"))
+	assert.Equal(t, "first", rows[1].Get("name"))
+}
+
+func TestHasManyForm_Content(t *testing.T) {
+	rel := HasManyForm{
+		Table:      "items",
+		ForeignKey: "order_id",
+		Fields: FormFields{
+			{Head: "Name", Field: "name", TypeName: db.Varchar, FormType: form2.Text, Editable: true},
+			{Head: "Note", Field: "note", TypeName: db.Varchar, FormType: form2.Text},
+		},
+	}
+	content := string(rel.Content("items", []map[string]interface{}{{"id": int64(3), "name": "<b>", "note": "x"}}))
+
+	assert.True(t, strings.Contains(content, `name="items[0][id]" value="3"`))
+	assert.True(t, strings.Contains(content, `value="&lt;b&gt;" class="form-control input-sm" name="items[0][name]"`))
+	assert.True(t, strings.Contains(content, `<input type="hidden" name="items[0][note]" value="x">x`))
+	assert.True(t, strings.Contains(content, `name="items[__ROW__][note]"`))
+	assert.Equal(t, 2, strings.Count(content, `name="`+HasManyOrderName("items")+`"`))
+}
+
+func TestMarkdown(t *testing.T) {
+	assert.Equal(t, template.HTML(`<h2>Title</h2><p>some <strong>bold</strong> and <em>it</em><br>`+
+		`<a href="https://example.com" target="_blank">link</a></p><ul><li>a &lt;b&gt;</li><li><code>c</code></li></ul>`+
+		`<pre><code>x &lt; y
+</code></pre>`),
+		Markdown("## Title\nsome **bold** and *it*\n[link](https://example.com)\n- a <b>\n* `c`\n```\nx < y\n```"))
+	assert.Equal(t, template.HTML(`<p><a href="#" target="_blank">x</a></p>`), Markdown("[x](javascript:void)"))
+}
+
+func TestDetailPanel_Displays(t *testing.T) {
+	detail := NewDetailPanel()
+	assert.False(t, detail.Valid())
+
+	detail.AddField("Data", "data", db.Text).FieldJSON().
+		AddField("Site", "site", db.Varchar).FieldLink("https://example.com/?q={value}").
+		AddField("Avatar", "avatar", db.Varchar).FieldImage("80px", "").
+		AddField("Home", "home", db.Varchar).FieldLink("")
+	assert.True(t, detail.Valid())
+
+	assert.Equal(t, template.HTML("<pre>{\n  &#34;a&#34;: 1\n}</pre>"),
+		detail.FieldList[0].ToDisplay(FieldModel{Value: `{"a":1}`}))
+	assert.Equal(t, template.HTML(`<a href="https://example.com/?q=a%2Bb" target="_blank">a+b</a>`),
+		detail.FieldList[1].ToDisplay(FieldModel{Value: "a+b"}))
+	assert.Equal(t, template.HTML(`<img src="/a.png" style="max-width:100%;width:80px;height:auto">`),
+		detail.FieldList[2].ToDisplay(FieldModel{Value: "/a.png"}))
+	assert.Equal(t, template.HTML(`<a href="#" target="_blank">javascript:alert(1)</a>`),
+		detail.FieldList[3].ToDisplay(FieldModel{Value: "javascript:alert(1)"}))
+	assert.Equal(t, template.HTML(`<a href="/posts?id=1" target="_blank">/posts?id=1</a>`),
+		detail.FieldList[3].ToDisplay(FieldModel{Value: "/posts?id=1"}))
+}
+
+func TestSafeURL(t *testing.T) {
+	assert.Equal(t, "https://example.com/a:b", safeURL("https://example.com/a:b"))
+	assert.Equal(t, "mailto:a@example.com", safeURL("mailto:a@example.com"))
+	assert.Equal(t, "/a?b=c:d", safeURL("/a?b=c:d"))
+	assert.Equal(t, "a/b:c", safeURL("a/b:c"))
+	assert.Equal(t, "#", safeURL("javascript:alert(1)"))
+	assert.Equal(t, "#", safeURL(" JavaScript:alert(1)"))
+	assert.Equal(t, "#", safeURL("java\tscript:alert(1)"))
+	assert.Equal(t, "#", safeURL("data:text/html,x"))
+	assert.Equal(t, "#", safeURL("ftp://example.com"))
+}
+
+func TestGrant_Allow(t *testing.T) {
+	user := models.UserModel{
+		Roles:       []models.RoleModel{{Slug: "sales"}},
+		Permissions: []models.PermissionModel{{Slug: "order.view"}},
+	}
+	admin := models.UserModel{Permissions: []models.PermissionModel{{HttpPath: []string{"*"}}}}
+
+	assert.True(t, Grant{}.Allow(user))
+	assert.True(t, GrantRoles("finance", "sales").Allow(user))
+	assert.True(t, GrantPermissions("order.view").Allow(user))
+	assert.False(t, GrantRoles("finance").Allow(user))
+	assert.False(t, GrantPermissions("order.edit").Allow(user))
+	assert.True(t, GrantRoles("finance").Allow(admin))
+
+	column := func(name string) string { return "orders.`" + name + "`" }
+
+	condition, args := ScopeIn("region", func(user models.UserModel) []interface{} { return nil })(user, column)
+	assert.Equal(t, "1 = 0", condition)
+	assert.Equal(t, 0, len(args))
+
+	condition, args = ScopeOwner("owner_id")(user, column)
+	assert.Equal(t, "orders.`owner_id` = ?", condition)
+	assert.Equal(t, []interface{}{user.Id}, args)
+}
+
+func TestParseFilterGroup(t *testing.T) {
+	group, err := ParseFilterGroup(`{"logic":"or","conditions":[{"field":"state","op":"in","values":["1","2"]}],` +
+		`"groups":[{"logic":"and","conditions":[{"field":"created_at","op":"last_days","values":["7"]},` +
+		`{"field":"deleted_at","op":"null"}]}]}`)
+	assert.Nil(t, err)
+	assert.True(t, group.IsOr())
+	assert.Equal(t, ConditionIn, group.Conditions[0].Operator)
+	assert.Equal(t, 2, len(group.Groups[0].Conditions))
+
+	again, err := ParseFilterGroup(group.Encode())
+	assert.Nil(t, err)
+	assert.Equal(t, group, again)
+
+	empty, err := ParseFilterGroup("")
+	assert.Nil(t, err)
+	assert.True(t, empty.Empty())
+	assert.Equal(t, "", empty.Encode())
+
+	for _, value := range []string{
+		`{"logic":"xor"}`,
+		`{"conditions":[{"field":"state","op":"like '%'"}]}`,
+		`{"conditions":[{"field":"state","op":"between","values":["1"]}]}`,
+		`{"conditions":[{"field":"state","op":"in"}]}`,
+		`{"conditions":[{"field":"created_at","op":"last_days","values":["-1"]}]}`,
+		`{"groups":[{"groups":[{"groups":[{"groups":[{"groups":[{}]}]}]}]}]}`,
+		`not json`,
+	} {
+		_, err := ParseFilterGroup(value)
+		assert.NotNil(t, err, value)
+	}
+}